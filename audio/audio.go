@@ -1,21 +1,84 @@
 package audio
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
+	"math"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// DefaultMaxVoices is the number of overlapping plays allowed per sound effect.
+const DefaultMaxVoices = 4
+
 type AudioManager struct {
 	Views        []AudioView
-	Volume       float32
+	Volume       float32 // Master volume, 0-1
+	MusicVolume  float32 // Music volume, 0-1, scaled by the master volume
+	SFXVolume    float32 // Sound effect volume, 0-1, scaled by the master volume
 	CurrentMusic *Music
 	IsPlaying    bool
+	MaxVoices    int // Max concurrent instances of a single sound effect
 	embeddedFS   fs.FS
+
+	// Music fades and stinger ducking, applied on top of the music volume
+	musicFade      volumeRamp
+	musicDuck      volumeRamp
+	stopAfterFade  bool // Set by StopMusicFadeOut, the music stops once musicFade ends
+	stinger        rl.Sound
+	stingerPlaying bool
+}
+
+const (
+	// StingerDuckVolume is how loud the music plays while a stinger is playing, 0-1.
+	StingerDuckVolume = 0.3
+	// Time in seconds to duck the music for a stinger, and to bring it back after.
+	stingerDuckTime    = 0.25
+	stingerRestoreTime = 0.5
+)
+
+// volumeRamp moves a volume multiplier linearly from one level to another over a duration.
+// A ramp that was never started stays at full volume.
+type volumeRamp struct {
+	from, to          float32
+	duration, elapsed float32
+	started           bool
+}
+
+// start begins a ramp from the current level to the target level.
+func (r *volumeRamp) start(to, duration float32) {
+	r.startFrom(r.value(), to, duration)
+}
+
+func (r *volumeRamp) startFrom(from, to, duration float32) {
+	*r = volumeRamp{from: from, to: to, duration: duration, started: true}
+}
+
+func (r *volumeRamp) update(dt float32) {
+	if r.started && r.elapsed < r.duration {
+		r.elapsed = min(r.elapsed+dt, r.duration)
+	}
+}
+
+// done reports if a started ramp has reached its target.
+func (r *volumeRamp) done() bool {
+	return r.started && r.elapsed >= r.duration
+}
+
+func (r *volumeRamp) value() float32 {
+	if !r.started {
+		return 1.0
+	}
+	if r.duration <= 0 || r.elapsed >= r.duration {
+		return r.to
+	}
+	return r.from + (r.to-r.from)*(r.elapsed/r.duration)
 }
 
 type AudioView struct {
@@ -37,7 +100,57 @@ type Sound struct {
 	Sound        rl.Sound
 	Loaded       bool
 	EmbeddedData []byte
+	voices       voicePool
 }
+
+// voicePool tracks the instances of a sound that can play at the same time.
+// The first voice is always the source sound, the rest are aliases sharing its data.
+type voicePool struct {
+	voices []rl.Sound
+	next   int
+}
+
+// acquire returns a voice that is free to play. If every voice is busy a new alias
+// is created, until maxVoices is reached. After that the voices are reused in order.
+func (p *voicePool) acquire(source rl.Sound, maxVoices int, isPlaying func(rl.Sound) bool, newAlias func(rl.Sound) rl.Sound) rl.Sound {
+	if len(p.voices) == 0 {
+		p.voices = append(p.voices, source)
+	}
+	for _, voice := range p.voices {
+		if !isPlaying(voice) {
+			return voice
+		}
+	}
+	if len(p.voices) < maxVoices {
+		alias := newAlias(source)
+		p.voices = append(p.voices, alias)
+		return alias
+	}
+	voice := p.voices[p.next%len(p.voices)]
+	p.next = (p.next + 1) % len(p.voices)
+	return voice
+}
+
+// aliases returns the voices created with LoadSoundAlias.
+func (p *voicePool) aliases() []rl.Sound {
+	if len(p.voices) <= 1 {
+		return nil
+	}
+	return p.voices[1:]
+}
+
+// unload releases the sound and stops any aliases created for it.
+// The cgo raylib bindings don't expose UnloadSoundAlias, so aliases are only stopped,
+// they share the source sample data which is freed here.
+func (s *Sound) unload() {
+	for _, alias := range s.voices.aliases() {
+		rl.StopSound(alias)
+	}
+	rl.UnloadSound(s.Sound)
+	s.voices = voicePool{}
+	s.Loaded = false
+}
+
 type Audio struct {
 	Name string
 	Path string
@@ -49,8 +162,11 @@ type Audio struct {
 // You can also add new audio views to the manager, and load/unload them as needed.
 func NewAudioManagerWithGlobal(defaultMusic []Audio, defaultSounds []Audio) *AudioManager {
 	am := &AudioManager{
-		Volume: .5,
-		Views:  make([]AudioView, 0),
+		Volume:      .5,
+		MusicVolume: 1.0,
+		SFXVolume:   1.0,
+		Views:       make([]AudioView, 0),
+		MaxVoices:   DefaultMaxVoices,
 	}
 	am.AddAudioView("default", defaultMusic, defaultSounds)
 	am.init()
@@ -59,9 +175,12 @@ func NewAudioManagerWithGlobal(defaultMusic []Audio, defaultSounds []Audio) *Aud
 
 func NewAudioManagerWithGlobalEmbed(defaultMusic []Audio, defaultSounds []Audio, embeddedFS fs.FS) *AudioManager {
 	am := &AudioManager{
-		Volume:     .5,
-		Views:      make([]AudioView, 0),
-		embeddedFS: embeddedFS,
+		Volume:      .5,
+		MusicVolume: 1.0,
+		SFXVolume:   1.0,
+		Views:       make([]AudioView, 0),
+		MaxVoices:   DefaultMaxVoices,
+		embeddedFS:  embeddedFS,
 	}
 	am.AddAudioView("default", defaultMusic, defaultSounds)
 	am.init()
@@ -73,8 +192,11 @@ func NewAudioManagerWithGlobalEmbed(defaultMusic []Audio, defaultSounds []Audio,
 // You can add new audio views to the manager, and load/unload them as needed.
 func NewAudioManager() *AudioManager {
 	am := &AudioManager{
-		Volume: 1.0,
-		Views:  make([]AudioView, 0),
+		Volume:      1.0,
+		MusicVolume: 1.0,
+		SFXVolume:   1.0,
+		Views:       make([]AudioView, 0),
+		MaxVoices:   DefaultMaxVoices,
 	}
 	am.init()
 	return am
@@ -82,7 +204,8 @@ func NewAudioManager() *AudioManager {
 
 func (am *AudioManager) init() {
 	rl.InitAudioDevice()
-	rl.SetMasterVolume(am.Volume)
+	// The master volume is applied per stream along with the category volume
+	rl.SetMasterVolume(1.0)
 	// Load default audio resources
 	am.LoadAudioView("default")
 }
@@ -191,9 +314,9 @@ func (am *AudioManager) Close() {
 				rl.UnloadMusicStream(track.Stream)
 			}
 		}
-		for _, sfx := range view.SFX {
-			if sfx.Loaded {
-				rl.UnloadSound(sfx.Sound)
+		for i := range view.SFX {
+			if view.SFX[i].Loaded {
+				view.SFX[i].unload()
 			}
 		}
 	}
@@ -250,7 +373,7 @@ func (am *AudioManager) LoadAudioView(viewName string) error {
 					stream, embeddedData := am.LoadMusic(view.Tracks[j].Path)
 					view.Tracks[j].Stream = stream
 					view.Tracks[j].EmbeddedData = embeddedData
-					rl.SetMusicVolume(view.Tracks[j].Stream, am.Volume)
+					rl.SetMusicVolume(view.Tracks[j].Stream, am.EffectiveMusicVolume())
 					rl.SetMusicPitch(view.Tracks[j].Stream, 1.0)
 					view.Tracks[j].Loaded = true
 				}
@@ -260,7 +383,7 @@ func (am *AudioManager) LoadAudioView(viewName string) error {
 					sound, embeddedData := am.LoadSound(view.SFX[j].Path)
 					view.SFX[j].Sound = sound
 					view.SFX[j].EmbeddedData = embeddedData
-					rl.SetSoundVolume(view.SFX[j].Sound, am.Volume)
+					rl.SetSoundVolume(view.SFX[j].Sound, am.EffectiveSFXVolume())
 					rl.SetSoundPitch(view.SFX[j].Sound, 1.0)
 					view.SFX[j].Loaded = true
 				}
@@ -281,9 +404,9 @@ func (am *AudioManager) UnloadAudioView(viewName string) error {
 					rl.UnloadMusicStream(track.Stream)
 				}
 			}
-			for _, sfx := range view.SFX {
-				if sfx.Loaded {
-					rl.UnloadSound(sfx.Sound)
+			for j := range view.SFX {
+				if view.SFX[j].Loaded {
+					view.SFX[j].unload()
 				}
 			}
 			return nil
@@ -302,9 +425,9 @@ func (am *AudioManager) RemoveAudioView(viewName string) error {
 					rl.UnloadMusicStream(track.Stream)
 				}
 			}
-			for _, sfx := range view.SFX {
-				if sfx.Loaded {
-					rl.UnloadSound(sfx.Sound)
+			for j := range view.SFX {
+				if view.SFX[j].Loaded {
+					view.SFX[j].unload()
 				}
 			}
 			am.Views = append(am.Views[:i], am.Views[i+1:]...)
@@ -343,7 +466,9 @@ func (am *AudioManager) PlayMusic(viewName, musicName string) error {
 					fmt.Printf("Playing new music: %s\n", musicName)
 					rl.SeekMusicStream(music.Stream, 0.0)
 					rl.PlayMusicStream(music.Stream)
-					rl.SetMusicVolume(music.Stream, am.Volume)
+					am.musicFade = volumeRamp{}
+					am.stopAfterFade = false
+					rl.SetMusicVolume(music.Stream, am.currentMusicVolume())
 					am.IsPlaying = true
 					fmt.Println("Music started successfully")
 					return nil
@@ -355,15 +480,18 @@ func (am *AudioManager) PlayMusic(viewName, musicName string) error {
 }
 
 // PlaySound immediately plays a sound effect from the given view.
+// If the sound is already playing, a free voice is used so plays can overlap,
+// up to MaxVoices. Past that the oldest voice is restarted.
 func (am *AudioManager) PlaySound(viewName, soundName string) error {
 	for _, view := range am.Views {
 		if view.Name == viewName {
 			for i := range view.SFX {
 				if view.SFX[i].Name == soundName {
-					sound := view.SFX[i]
+					sound := &view.SFX[i]
 					if sound.Loaded {
-						rl.SetSoundVolume(sound.Sound, am.Volume)
-						rl.PlaySound(sound.Sound)
+						voice := sound.voices.acquire(sound.Sound, am.MaxVoices, rl.IsSoundPlaying, rl.LoadSoundAlias)
+						rl.SetSoundVolume(voice, am.EffectiveSFXVolume())
+						rl.PlaySound(voice)
 					}
 					return nil
 				}
@@ -399,20 +527,159 @@ func (am *AudioManager) UpdateMusic() {
 		rl.PlayMusicStream(am.CurrentMusic.Stream)
 	}
 
+	am.updateMusicGain(rl.GetFrameTime(), am.stingerPlaying && rl.IsSoundPlaying(am.stinger))
+	if am.fadedOut() {
+		am.StopMusic()
+		return
+	}
+	rl.SetMusicVolume(am.CurrentMusic.Stream, am.currentMusicVolume())
 	rl.UpdateMusicStream(am.CurrentMusic.Stream)
 }
 
+// PlayMusicFadeIn starts a music track from the given view, ramping its volume up from
+// silence over duration seconds. UpdateMusic must be called each frame for the fade to run.
+func (am *AudioManager) PlayMusicFadeIn(viewName, musicName string, duration float32) error {
+	if err := am.PlayMusic(viewName, musicName); err != nil {
+		return err
+	}
+	am.musicFade.startFrom(0, 1, duration)
+	rl.SetMusicVolume(am.CurrentMusic.Stream, am.currentMusicVolume())
+	return nil
+}
+
+// StopMusicFadeOut ramps the current music down to silence over duration seconds, then stops it
+// and clears CurrentMusic. UpdateMusic must be called each frame for the fade to run.
+func (am *AudioManager) StopMusicFadeOut(duration float32) {
+	if am.CurrentMusic == nil {
+		return
+	}
+	if duration <= 0 {
+		am.StopMusic()
+		return
+	}
+	am.musicFade.start(0, duration)
+	am.stopAfterFade = true
+}
+
+// fadedOut reports if a fade out started by StopMusicFadeOut has finished.
+func (am *AudioManager) fadedOut() bool {
+	return am.stopAfterFade && am.musicFade.done()
+}
+
+// StopMusic stops the current music immediately, and clears CurrentMusic.
+func (am *AudioManager) StopMusic() {
+	if am.CurrentMusic != nil && am.CurrentMusic.Loaded && rl.IsMusicValid(am.CurrentMusic.Stream) {
+		rl.StopMusicStream(am.CurrentMusic.Stream)
+	}
+	am.CurrentMusic = nil
+	am.IsPlaying = false
+	am.musicFade = volumeRamp{}
+	am.stopAfterFade = false
+}
+
+// PlayStinger plays a short musical cue from the given view's sound effects.
+// The current music is ducked while the stinger plays, and restored after it ends.
+func (am *AudioManager) PlayStinger(viewName, soundName string) error {
+	for _, view := range am.Views {
+		if view.Name == viewName {
+			for i := range view.SFX {
+				if view.SFX[i].Name == soundName {
+					sound := &view.SFX[i]
+					if !sound.Loaded {
+						return fmt.Errorf("sound not loaded: %s", soundName)
+					}
+					am.stinger = sound.voices.acquire(sound.Sound, am.MaxVoices, rl.IsSoundPlaying, rl.LoadSoundAlias)
+					rl.SetSoundVolume(am.stinger, am.EffectiveSFXVolume())
+					rl.PlaySound(am.stinger)
+					am.duckMusic()
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("sound not found: %s in view %s", soundName, viewName)
+}
+
+// duckMusic lowers the music while a stinger plays.
+func (am *AudioManager) duckMusic() {
+	am.stingerPlaying = true
+	am.musicDuck.start(StingerDuckVolume, stingerDuckTime)
+}
+
+// updateMusicGain advances any fade or duck, restoring the music once the stinger has finished.
+func (am *AudioManager) updateMusicGain(dt float32, stingerPlaying bool) {
+	if am.stingerPlaying && !stingerPlaying {
+		am.stingerPlaying = false
+		am.musicDuck.start(1.0, stingerRestoreTime)
+	}
+	am.musicFade.update(dt)
+	am.musicDuck.update(dt)
+}
+
+// currentMusicVolume is the effective music volume, with any fade or duck applied.
+func (am *AudioManager) currentMusicVolume() float32 {
+	return am.EffectiveMusicVolume() * am.musicFade.value() * am.musicDuck.value()
+}
+
 // Sets the master volume for all audio.
 // Volume should be a float between 0 and 100.
 func (am *AudioManager) SetMasterVolume(volume float32) {
 	am.Volume = volume / 100.0
-	rl.SetMasterVolume(am.Volume)
 	// Also update current music volume if playing
 	if am.CurrentMusic != nil && am.CurrentMusic.Loaded {
-		rl.SetMusicVolume(am.CurrentMusic.Stream, am.Volume)
+		rl.SetMusicVolume(am.CurrentMusic.Stream, am.currentMusicVolume())
 	}
 }
 
+// Sets the music volume, relative to the master volume.
+// Volume should be a float between 0 and 100.
+func (am *AudioManager) SetMusicVolume(volume float32) {
+	am.MusicVolume = volume / 100.0
+	if am.CurrentMusic != nil && am.CurrentMusic.Loaded {
+		rl.SetMusicVolume(am.CurrentMusic.Stream, am.currentMusicVolume())
+	}
+}
+
+// Sets the sound effect volume, relative to the master volume.
+// Volume should be a float between 0 and 100.
+func (am *AudioManager) SetSFXVolume(volume float32) {
+	am.SFXVolume = volume / 100.0
+}
+
+// EffectiveMusicVolume is the volume music plays at, master x music.
+func (am *AudioManager) EffectiveMusicVolume() float32 {
+	return am.Volume * am.MusicVolume
+}
+
+// EffectiveSFXVolume is the volume sound effects play at, master x sfx.
+func (am *AudioManager) EffectiveSFXVolume() float32 {
+	return am.Volume * am.SFXVolume
+}
+
+// VolumeSettings holds the volume levels, so they can be saved with the game's config.
+// Each level is between 0 and 100.
+type VolumeSettings struct {
+	Master float32 `json:"master"`
+	Music  float32 `json:"music"`
+	SFX    float32 `json:"sfx"`
+}
+
+// VolumeSettings returns the current volume levels.
+func (am *AudioManager) VolumeSettings() VolumeSettings {
+	return VolumeSettings{
+		Master: am.Volume * 100,
+		Music:  am.MusicVolume * 100,
+		SFX:    am.SFXVolume * 100,
+	}
+}
+
+// ApplyVolumeSettings restores previously saved volume levels.
+func (am *AudioManager) ApplyVolumeSettings(settings VolumeSettings) {
+	am.SetMasterVolume(settings.Master)
+	am.SetMusicVolume(settings.Music)
+	am.SetSFXVolume(settings.SFX)
+}
+
 // LoadMusic loads a music file from the given path.
 // The music will be loaded into memory and ready to play.
 func LoadMusic(name string, path string) *Music {
@@ -442,6 +709,9 @@ type NormalizeSettings struct {
 	IntegratedLoudness float64 // Target integrated loudness in LUFS (e.g., -23.0)
 	TruePeak           float64 // Target true peak in dBTP (e.g., -2.0)
 	LoudnessRange      float64 // Target loudness range in LU (e.g., 7.0)
+	// TwoPass measures each file first, then normalizes using the measured values.
+	// More accurate than a single pass, but runs ffmpeg once more per file.
+	TwoPass bool
 }
 
 // DefaultNormalizeSettings provides common normalization parameters (EBU R128).
@@ -451,18 +721,132 @@ var DefaultNormalizeSettings = NormalizeSettings{
 	LoudnessRange:      7.0,
 }
 
+// ErrFFmpegNotFound is returned when ffmpeg isn't installed or isn't in the PATH.
+var ErrFFmpegNotFound = errors.New("ffmpeg command not found in system PATH")
+
+// NormalizeError is returned when ffmpeg fails on a specific input file.
+type NormalizeError struct {
+	Path   string // The input file ffmpeg failed on
+	Reason string // The line ffmpeg reported for the file
+	Err    error  // The underlying execution error
+}
+
+func (e *NormalizeError) Error() string {
+	return fmt.Sprintf("ffmpeg failed to normalize %s: %s", e.Path, e.Reason)
+}
+
+func (e *NormalizeError) Unwrap() error {
+	return e.Err
+}
+
+// NormalizeResult is the outcome of normalizing one file.
+type NormalizeResult struct {
+	Input  string
+	Output string
+	Err    error // A *NormalizeError if ffmpeg failed on this file
+}
+
+// runFFmpeg runs ffmpeg and returns its combined output, tests replace it with a stub.
+var runFFmpeg = func(ffmpegPath string, args ...string) ([]byte, error) {
+	return exec.Command(ffmpegPath, args...).CombinedOutput()
+}
+
+// lookPathFFmpeg finds the ffmpeg binary, tests replace it along with runFFmpeg.
+var lookPathFFmpeg = func() (string, error) {
+	return exec.LookPath("ffmpeg")
+}
+
 // NormalizeAudioFiles processes a list of audio files using ffmpeg's loudnorm filter.
-// It creates new files with the suffix "_normalized" before the extension
+// It creates new files with the suffix "_normalized" before the extension.
+// Every file is attempted, the returned paths are the files that were normalized,
+// and the error joins the NormalizeError of each file that failed.
 func NormalizeAudioFiles(inputFilePaths []string, settings *NormalizeSettings) ([]string, error) {
+	results, err := NormalizeAudioFilesEach(inputFilePaths, settings, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []string
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		outputs = append(outputs, result.Output)
+	}
+	return outputs, errors.Join(errs...)
+}
+
+// NormalizeAudioFilesEach normalizes the files one at a time, so a file that fails doesn't stop the rest.
+// It returns a result for every input, in order. progress, if set, is called after each file.
+// The error is only set if nothing could be run, e.g. ffmpeg isn't installed.
+func NormalizeAudioFilesEach(inputFilePaths []string, settings *NormalizeSettings, progress func(done, total int)) ([]NormalizeResult, error) {
 	if len(inputFilePaths) == 0 {
 		return nil, fmt.Errorf("no input files provided for normalization")
 	}
 
-	ffmpegPath, err := exec.LookPath("ffmpeg")
+	ffmpegPath, err := lookPathFFmpeg()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFFmpegNotFound, err)
+	}
+
+	results := make([]NormalizeResult, len(inputFilePaths))
+	for i, inputPath := range inputFilePaths {
+		results[i] = normalizeFile(ffmpegPath, inputPath, settings)
+		if progress != nil {
+			progress(i+1, len(inputFilePaths))
+		}
+	}
+	return results, nil
+}
+
+// normalizeFile runs ffmpeg on a single file, measuring it first for two-pass normalization.
+func normalizeFile(ffmpegPath, inputPath string, settings *NormalizeSettings) NormalizeResult {
+	// Fall back to a single pass if the file can't be measured
+	var measurements []*loudnormStats
+	if settings != nil && settings.TwoPass {
+		stats, err := measureLoudness(ffmpegPath, inputPath, *settings)
+		if err != nil {
+			fmt.Printf("Falling back to single-pass normalization for %s: %v\n", inputPath, err)
+		} else {
+			measurements = []*loudnormStats{&stats}
+		}
+	}
+
+	cmdArgs, outputs := normalizeArgs([]string{inputPath}, settings, measurements)
+	result := NormalizeResult{Input: inputPath, Output: outputs[0]}
+	cmdOutput, err := runFFmpeg(ffmpegPath, cmdArgs...)
 	if err != nil {
-		return nil, fmt.Errorf("ffmpeg command not found in system PATH: %w", err)
+		_, reason, ok := failedInput(string(cmdOutput), []string{inputPath})
+		if !ok {
+			reason = lastLine(string(cmdOutput))
+		}
+		result.Err = &NormalizeError{Path: inputPath, Reason: reason, Err: err}
+	}
+	return result
+}
+
+// lastLine returns the last non-empty line of ffmpeg's output, which usually says why it stopped.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// NormalizeAudioFilesDryRun returns the ffmpeg arguments to normalize the files in a single run,
+// without running it. NormalizeAudioFiles runs the same filter once per file. ffmpeg doesn't need to be installed.
+// Two-pass measurements aren't run, so the single-pass arguments are returned.
+func NormalizeAudioFilesDryRun(inputFilePaths []string, settings *NormalizeSettings) ([]string, error) {
+	if len(inputFilePaths) == 0 {
+		return nil, fmt.Errorf("no input files provided for normalization")
 	}
+	cmdArgs, _ := normalizeArgs(inputFilePaths, settings, nil)
+	return cmdArgs, nil
+}
 
+// normalizeArgs builds the ffmpeg arguments and output paths for a normalization run.
+// Inputs with a measurement are normalized with the second pass of two-pass loudnorm.
+func normalizeArgs(inputFilePaths []string, settings *NormalizeSettings, measurements []*loudnormStats) ([]string, []string) {
 	currentSettings := DefaultNormalizeSettings
 	if settings != nil {
 		currentSettings = *settings
@@ -486,9 +870,14 @@ func NormalizeAudioFiles(inputFilePaths []string, settings *NormalizeSettings) (
 		outputPath := filepath.Join(dir, fmt.Sprintf("%s_normalized%s", nameWithoutExt, ext))
 		outputNormalizedFiles = append(outputNormalizedFiles, outputPath)
 
-		filterComplexParts = append(filterComplexParts,
-			fmt.Sprintf("[%d:a]loudnorm=I=%.1f:TP=%.1f:LRA=%.1f[norm%d]",
-				i, currentSettings.IntegratedLoudness, currentSettings.TruePeak, currentSettings.LoudnessRange, i))
+		filter := fmt.Sprintf("loudnorm=I=%.1f:TP=%.1f:LRA=%.1f",
+			currentSettings.IntegratedLoudness, currentSettings.TruePeak, currentSettings.LoudnessRange)
+		if i < len(measurements) && measurements[i] != nil {
+			m := measurements[i]
+			filter += fmt.Sprintf(":measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true",
+				m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset)
+		}
+		filterComplexParts = append(filterComplexParts, fmt.Sprintf("[%d:a]%s[norm%d]", i, filter, i))
 	}
 
 	// Add filter_complex argument
@@ -500,13 +889,92 @@ func NormalizeAudioFiles(inputFilePaths []string, settings *NormalizeSettings) (
 	for i, outputPath := range outputNormalizedFiles {
 		cmdArgs = append(cmdArgs, "-map", fmt.Sprintf("[norm%d]", i), outputPath)
 	}
+	return cmdArgs, outputNormalizedFiles
+}
+
+// failedInput scans ffmpeg's output for an error line that names one of the inputs.
+func failedInput(output string, inputFilePaths []string) (string, string, bool) {
+	errorMarkers := []string{"error", "no such file", "invalid data", "permission denied", "does not contain any stream"}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		isError := false
+		for _, marker := range errorMarkers {
+			if strings.Contains(lower, marker) {
+				isError = true
+				break
+			}
+		}
+		if !isError {
+			continue
+		}
+		for _, inputPath := range inputFilePaths {
+			if strings.Contains(line, inputPath) {
+				return inputPath, line, true
+			}
+		}
+	}
+	return "", "", false
+}
 
-	cmd := exec.Command(ffmpegPath, cmdArgs...)
+// loudnormStats are the values measured by the first pass of two-pass loudnorm.
+type loudnormStats struct {
+	InputI       float64
+	InputTP      float64
+	InputLRA     float64
+	InputThresh  float64
+	TargetOffset float64
+}
 
-	cmdOutput, err := cmd.CombinedOutput()
+// measureLoudness runs the first loudnorm pass on a file and parses the measured values.
+func measureLoudness(ffmpegPath, inputPath string, settings NormalizeSettings) (loudnormStats, error) {
+	filter := fmt.Sprintf("loudnorm=I=%.1f:TP=%.1f:LRA=%.1f:print_format=json",
+		settings.IntegratedLoudness, settings.TruePeak, settings.LoudnessRange)
+	output, err := runFFmpeg(ffmpegPath, "-hide_banner", "-nostats", "-i", inputPath, "-af", filter, "-f", "null", "-")
 	if err != nil {
-		return nil, fmt.Errorf("ffmpeg execution failed: %w\nffmpeg output:\n%s", err, string(cmdOutput))
+		return loudnormStats{}, fmt.Errorf("measurement pass failed: %w", err)
 	}
+	return parseLoudnormStats(string(output))
+}
 
-	return outputNormalizedFiles, nil
+// parseLoudnormStats reads the JSON block loudnorm prints at the end of ffmpeg's output.
+func parseLoudnormStats(output string) (loudnormStats, error) {
+	start := strings.LastIndex(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start == -1 || end < start {
+		return loudnormStats{}, fmt.Errorf("no loudnorm JSON found in ffmpeg output")
+	}
+
+	var raw struct {
+		InputI       string `json:"input_i"`
+		InputTP      string `json:"input_tp"`
+		InputLRA     string `json:"input_lra"`
+		InputThresh  string `json:"input_thresh"`
+		TargetOffset string `json:"target_offset"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &raw); err != nil {
+		return loudnormStats{}, fmt.Errorf("failed to parse loudnorm JSON: %w", err)
+	}
+
+	var stats loudnormStats
+	fields := []struct {
+		name  string
+		value string
+		dest  *float64
+	}{
+		{"input_i", raw.InputI, &stats.InputI},
+		{"input_tp", raw.InputTP, &stats.InputTP},
+		{"input_lra", raw.InputLRA, &stats.InputLRA},
+		{"input_thresh", raw.InputThresh, &stats.InputThresh},
+		{"target_offset", raw.TargetOffset, &stats.TargetOffset},
+	}
+	for _, field := range fields {
+		// Silent files measure as -inf, which can't be used for the second pass
+		v, err := strconv.ParseFloat(strings.TrimSpace(field.value), 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return loudnormStats{}, fmt.Errorf("invalid loudnorm value for %s: %q", field.name, field.value)
+		}
+		*field.dest = v
+	}
+	return stats, nil
 }
@@ -1,10 +1,15 @@
 package audio
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // TestNormalizeAudioFiles_Integration tests the NormalizeAudioFiles function by
@@ -49,3 +54,337 @@ func TestNormalizeAudioFiles_Integration(t *testing.T) {
 		})
 	}
 }
+
+// TestNormalizeAudioFiles_MissingFFmpeg runs with a PATH that has no ffmpeg
+// and checks the sentinel error is returned.
+func TestNormalizeAudioFiles_MissingFFmpeg(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := NormalizeAudioFiles([]string{"test.mp3"}, nil)
+	if !errors.Is(err, ErrFFmpegNotFound) {
+		t.Fatalf("Expected ErrFFmpegNotFound, got %v", err)
+	}
+
+	// Dry runs don't need ffmpeg
+	args, err := NormalizeAudioFilesDryRun([]string{"test.mp3"}, &NormalizeSettings{IntegratedLoudness: -16, TruePeak: -1.5, LoudnessRange: 11})
+	if err != nil {
+		t.Fatalf("NormalizeAudioFilesDryRun failed: %v", err)
+	}
+	expected := []string{
+		"-y", "-i", "test.mp3",
+		"-filter_complex", "[0:a]loudnorm=I=-16.0:TP=-1.5:LRA=11.0[norm0]",
+		"-map", "[norm0]", "test_normalized.mp3",
+	}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected args %q, got %q", expected, args)
+	}
+}
+
+// TestFailedInput checks that ffmpeg errors are traced back to the input that caused them.
+func TestFailedInput(t *testing.T) {
+	output := `Input #0, mp3, from 'music/theme.mp3':
+  Duration: 00:01:02.00, start: 0.000000, bitrate: 128 kb/s
+[in#1 @ 0x600003a1c000] Error opening input: No such file or directory
+Error opening input file sfx/missing.wav.
+Error opening input files: No such file or directory`
+
+	path, reason, ok := failedInput(output, []string{"music/theme.mp3", "sfx/missing.wav"})
+	if !ok {
+		t.Fatal("Expected a failed input to be found")
+	}
+	if path != "sfx/missing.wav" {
+		t.Errorf("Expected sfx/missing.wav, got %s", path)
+	}
+	if reason != "Error opening input file sfx/missing.wav." {
+		t.Errorf("Unexpected reason: %s", reason)
+	}
+
+	if _, _, ok := failedInput("Conversion failed!", []string{"music/theme.mp3"}); ok {
+		t.Error("Expected no input to be blamed when none is named")
+	}
+}
+
+// TestParseLoudnormStats parses the JSON block from a loudnorm measurement pass.
+func TestParseLoudnormStats(t *testing.T) {
+	output := `Input #0, mp3, from 'test.mp3':
+  Duration: 00:00:05.02, start: 0.025057, bitrate: 128 kb/s
+[Parsed_loudnorm_0 @ 0x7f8b4c004a80]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}
+[out#0/null @ 0x600002f04000] video:0KiB audio:862KiB`
+
+	stats, err := parseLoudnormStats(output)
+	if err != nil {
+		t.Fatalf("parseLoudnormStats failed: %v", err)
+	}
+	expected := loudnormStats{InputI: -27.61, InputTP: -4.47, InputLRA: 18.06, InputThresh: -39.20, TargetOffset: 0.58}
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	// Silent input can't be used for a second pass
+	silent := strings.Replace(output, `"input_i" : "-27.61"`, `"input_i" : "-inf"`, 1)
+	if _, err := parseLoudnormStats(silent); err == nil {
+		t.Error("Expected an error for -inf input loudness")
+	}
+	if _, err := parseLoudnormStats("Conversion failed!"); err == nil {
+		t.Error("Expected an error when there is no JSON block")
+	}
+}
+
+// TestVoicePool_Cycles checks that overlapping plays grow the pool up to the
+// voice limit and then reuse the existing voices in order.
+func TestVoicePool_Cycles(t *testing.T) {
+	const maxVoices = 3
+	source := rl.Sound{FrameCount: 0}
+	aliasCount := 0
+	newAlias := func(rl.Sound) rl.Sound {
+		aliasCount++
+		return rl.Sound{FrameCount: uint32(aliasCount)}
+	}
+	// Every voice is busy, so each play needs a new alias until the limit is hit
+	allPlaying := func(rl.Sound) bool { return true }
+
+	pool := voicePool{}
+	expected := []uint32{1, 2, 0, 1, 2, 0}
+	for i, want := range expected {
+		voice := pool.acquire(source, maxVoices, allPlaying, newAlias)
+		if voice.FrameCount != want {
+			t.Errorf("Play %d: expected voice %d, got %d", i, want, voice.FrameCount)
+		}
+	}
+	if aliasCount != maxVoices-1 {
+		t.Errorf("Expected %d aliases, got %d", maxVoices-1, aliasCount)
+	}
+	if len(pool.aliases()) != maxVoices-1 {
+		t.Errorf("Expected pool to track %d aliases, got %d", maxVoices-1, len(pool.aliases()))
+	}
+
+	// A voice that finished playing is reused before any other
+	idle := func(s rl.Sound) bool { return s.FrameCount != 1 }
+	if voice := pool.acquire(source, maxVoices, idle, newAlias); voice.FrameCount != 1 {
+		t.Errorf("Expected idle voice 1, got %d", voice.FrameCount)
+	}
+}
+
+// TestEffectiveVolumes checks that music and sound effects play at master x category volume.
+func TestEffectiveVolumes(t *testing.T) {
+	am := &AudioManager{Volume: 1.0, MusicVolume: 1.0, SFXVolume: 1.0}
+	am.SetMasterVolume(50)
+	am.SetMusicVolume(40)
+	am.SetSFXVolume(80)
+
+	if got, want := am.EffectiveMusicVolume(), float32(0.5*0.4); !closeTo(got, want) {
+		t.Errorf("Expected music volume %v, got %v", want, got)
+	}
+	if got, want := am.EffectiveSFXVolume(), float32(0.5*0.8); !closeTo(got, want) {
+		t.Errorf("Expected sfx volume %v, got %v", want, got)
+	}
+
+	// Saved settings restore the same effective volumes
+	restored := &AudioManager{}
+	restored.ApplyVolumeSettings(am.VolumeSettings())
+	if !closeTo(restored.EffectiveMusicVolume(), am.EffectiveMusicVolume()) ||
+		!closeTo(restored.EffectiveSFXVolume(), am.EffectiveSFXVolume()) {
+		t.Errorf("Expected restored volumes to match, got music %v sfx %v", restored.EffectiveMusicVolume(), restored.EffectiveSFXVolume())
+	}
+}
+
+func closeTo(a, b float32) bool {
+	return a-b < 1e-5 && b-a < 1e-5
+}
+
+// TestStingerDucking checks that a stinger ducks the music, and the music returns
+// to its prior volume once the stinger ends.
+func TestStingerDucking(t *testing.T) {
+	am := &AudioManager{Volume: 0.8, MusicVolume: 0.5, SFXVolume: 1.0}
+	prior := am.currentMusicVolume()
+
+	am.duckMusic()
+	for i := 0; i < 60; i++ {
+		am.updateMusicGain(1.0/60.0, true)
+	}
+	if got, want := am.currentMusicVolume(), prior*StingerDuckVolume; !closeTo(got, want) {
+		t.Errorf("Expected ducked volume %v, got %v", want, got)
+	}
+
+	// Stinger ended, give the music time to come back up
+	for i := 0; i < 60; i++ {
+		am.updateMusicGain(1.0/60.0, false)
+	}
+	if got := am.currentMusicVolume(); !closeTo(got, prior) {
+		t.Errorf("Expected music restored to %v, got %v", prior, got)
+	}
+}
+
+// TestMusicFadeIn checks the fade ramps from silence to the full music volume.
+func TestMusicFadeIn(t *testing.T) {
+	am := &AudioManager{Volume: 1.0, MusicVolume: 1.0}
+	am.musicFade.startFrom(0, 1, 2.0)
+	if got := am.currentMusicVolume(); got != 0 {
+		t.Errorf("Expected fade to start silent, got %v", got)
+	}
+	am.updateMusicGain(1.0, false)
+	if got := am.currentMusicVolume(); !closeTo(got, 0.5) {
+		t.Errorf("Expected half volume midway through the fade, got %v", got)
+	}
+	am.updateMusicGain(5.0, false)
+	if got := am.currentMusicVolume(); !closeTo(got, 1.0) {
+		t.Errorf("Expected full volume after the fade, got %v", got)
+	}
+}
+
+// TestMusicFadeOut checks the fade ramps down from the current volume and is silent
+// exactly when the duration ends, which is when the music is stopped.
+func TestMusicFadeOut(t *testing.T) {
+	am := &AudioManager{Volume: 0.5, MusicVolume: 1.0, CurrentMusic: &Music{Name: "theme"}}
+	am.StopMusicFadeOut(2.0)
+	if got := am.currentMusicVolume(); !closeTo(got, 0.5) {
+		t.Errorf("Expected fade to start at the music volume, got %v", got)
+	}
+
+	am.updateMusicGain(1.0, false)
+	if got := am.currentMusicVolume(); !closeTo(got, 0.25) {
+		t.Errorf("Expected half the volume midway through the fade, got %v", got)
+	}
+	if am.fadedOut() {
+		t.Fatal("Expected the music to keep playing until the fade ends")
+	}
+
+	am.updateMusicGain(1.0, false)
+	if got := am.currentMusicVolume(); got != 0 {
+		t.Errorf("Expected silence at the end of the fade, got %v", got)
+	}
+	if !am.fadedOut() {
+		t.Fatal("Expected the fade out to be done at the end of the duration")
+	}
+
+	am.StopMusic()
+	if am.CurrentMusic != nil || am.IsPlaying || am.fadedOut() {
+		t.Errorf("Expected the music to be stopped and cleared")
+	}
+	if got := am.currentMusicVolume(); !closeTo(got, 0.5) {
+		t.Errorf("Expected the next track to start at full volume, got %v", got)
+	}
+}
+
+// TestNormalizeAudioFilesEach runs against a fake ffmpeg that fails on one file,
+// and checks the rest are still normalized with the failure reported against the right file.
+func TestNormalizeAudioFilesEach(t *testing.T) {
+	origRun, origLookPath := runFFmpeg, lookPathFFmpeg
+	defer func() { runFFmpeg, lookPathFFmpeg = origRun, origLookPath }()
+	lookPathFFmpeg = func() (string, error) { return "ffmpeg", nil }
+
+	var ran [][]string
+	runFFmpeg = func(ffmpegPath string, args ...string) ([]byte, error) {
+		ran = append(ran, args)
+		if slices.Contains(args, "music/broken.mp3") {
+			return []byte("music/broken.mp3: Invalid data found when processing input"), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+
+	var progress []int
+	inputs := []string{"music/theme.mp3", "music/broken.mp3", "sfx/hit.wav"}
+	results, err := NormalizeAudioFilesEach(inputs, nil, func(done, total int) {
+		if total != len(inputs) {
+			t.Errorf("Expected a total of %d, got %d", len(inputs), total)
+		}
+		progress = append(progress, done)
+	})
+	if err != nil {
+		t.Fatalf("NormalizeAudioFilesEach failed: %v", err)
+	}
+
+	if len(ran) != 3 {
+		t.Fatalf("Expected ffmpeg to run once per file, ran %d times", len(ran))
+	}
+	if !slices.Equal(progress, []int{1, 2, 3}) {
+		t.Errorf("Expected progress 1, 2, 3, got %v", progress)
+	}
+	wantOutputs := []string{"music/theme_normalized.mp3", "music/broken_normalized.mp3", "sfx/hit_normalized.wav"}
+	for i, result := range results {
+		if result.Input != inputs[i] || result.Output != wantOutputs[i] {
+			t.Errorf("Result %d: expected %s -> %s, got %s -> %s", i, inputs[i], wantOutputs[i], result.Input, result.Output)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("Expected the other files to succeed, got %v and %v", results[0].Err, results[2].Err)
+	}
+	var normErr *NormalizeError
+	if !errors.As(results[1].Err, &normErr) || normErr.Path != "music/broken.mp3" {
+		t.Fatalf("Expected a NormalizeError for music/broken.mp3, got %v", results[1].Err)
+	}
+
+	// NormalizeAudioFiles keeps going too, returning what it could normalize
+	outputs, err := NormalizeAudioFiles(inputs, nil)
+	if !errors.As(err, &normErr) {
+		t.Errorf("Expected the NormalizeError to be returned, got %v", err)
+	}
+	if !slices.Equal(outputs, []string{"music/theme_normalized.mp3", "sfx/hit_normalized.wav"}) {
+		t.Errorf("Expected the two good files to be normalized, got %v", outputs)
+	}
+}
+
+// TestNormalizeTwoPass checks the measured values are fed into the second pass,
+// and a file whose measurement can't be parsed falls back to a single pass.
+func TestNormalizeTwoPass(t *testing.T) {
+	origRun, origLookPath := runFFmpeg, lookPathFFmpeg
+	defer func() { runFFmpeg, lookPathFFmpeg = origRun, origLookPath }()
+	lookPathFFmpeg = func() (string, error) { return "ffmpeg", nil }
+
+	measurement := `[Parsed_loudnorm_0 @ 0x7f8b4c004a80]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}`
+	filters := make(map[string]string)
+	runFFmpeg = func(ffmpegPath string, args ...string) ([]byte, error) {
+		if slices.Contains(args, "-af") {
+			// Measurement pass, the silent file can't be measured
+			if slices.Contains(args, "silence.wav") {
+				return []byte(`{"input_i" : "-inf"}`), nil
+			}
+			return []byte(measurement), nil
+		}
+		filters[args[2]] = args[slices.Index(args, "-filter_complex")+1]
+		return nil, nil
+	}
+
+	settings := DefaultNormalizeSettings
+	settings.TwoPass = true
+	results, err := NormalizeAudioFilesEach([]string{"theme.mp3", "silence.wav"}, &settings, nil)
+	if err != nil {
+		t.Fatalf("NormalizeAudioFilesEach failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Expected %s to be normalized, got %v", result.Input, result.Err)
+		}
+	}
+
+	if want := "measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:measured_thresh=-39.20:offset=0.58:linear=true"; !strings.Contains(filters["theme.mp3"], want) {
+		t.Errorf("Expected the second pass to use the measured values, got %s", filters["theme.mp3"])
+	}
+	if strings.Contains(filters["silence.wav"], "measured_") {
+		t.Errorf("Expected a single pass for the unmeasurable file, got %s", filters["silence.wav"])
+	}
+}
@@ -14,20 +14,44 @@ const (
 	StateHighScores
 )
 
+// Map is a grid of tiles, with the NPCs, items and locations on it.
+//
+// Every type saved with a map names its JSON fields with explicit tags, so renaming a Go field
+// doesn't break saved maps. Fields are written in declaration order, and map keys sorted,
+// so the same map always serializes to the same bytes. See testdata/map.golden.json for the shape.
 type Map struct {
-	Width, Height int
-	Tiles         [][]Tile
-	NPCs          NPCs
-	Items         Items
-	Start         Position
-	Exit          Positions
-	Respawn       Position
-	DungeonEntry  Positions
+	Width        int              `json:"Width"`
+	Height       int              `json:"Height"`
+	Tiles        [][]Tile         `json:"Tiles"`
+	NPCs         NPCs             `json:"NPCs,omitempty"`
+	Items        Items            `json:"Items,omitempty"`
+	Start        Position         `json:"Start"`
+	Exit         Positions        `json:"Exit,omitempty"`
+	Respawn      Position         `json:"Respawn"`
+	DungeonEntry Positions        `json:"DungeonEntry,omitempty"`
+	Factions     FactionRelations `json:"Factions,omitempty"`
+
+	// Triggers are named events fired by stepping on their tiles, see TriggersAt
+	Triggers []Trigger `json:"Triggers,omitempty"`
+
+	// Mode decides if NPCs act every frame, or only when StepTurn is called
+	Mode MapMode `json:"Mode,omitempty"`
 }
 
+// MapMode is how time passes for the NPCs on a map.
+type MapMode int
+
+const (
+	// RealTime NPCs move and attack on their own timers, from NPC.Update.
+	RealTime MapMode = iota
+	// TurnBased NPCs only act once per call to Map.StepTurn.
+	TurnBased
+)
+
 type Positions []Position
 type Position struct {
-	X, Y int
+	X int `json:"X"`
+	Y int `json:"Y"`
 }
 
 func (p Positions) PositionExists(pos Position) bool {
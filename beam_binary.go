@@ -0,0 +1,470 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/*
+Maps can be saved in a compact binary format, for games that ship large maps and want them to load fast.
+JSON stays the interchange format, the binary holds exactly what the JSON would.
+
+The layout is:
+  - The magic "BEAM" and a version byte
+  - A string table of every texture name and tile property
+  - The tiles row by row, as runs of identical tiles
+  - Everything else on the map, NPCs, items, exits and so on, as JSON
+
+Example usage:
+    data, err := currMap.MarshalBinary()
+    ...
+    var loaded beam.Map
+    if err := loaded.UnmarshalBinary(data); err != nil {
+        log.Fatal(err)
+    }
+*/
+
+// BinaryMapVersion is the version of the binary map format written by MarshalBinary.
+// Version 2 added each texture's phase offset and play mode, each frame's span, and each tile's walkable override.
+// Version 1 maps still load, without them.
+const BinaryMapVersion = 2
+
+// MaxBinaryMapTiles is the most tiles a binary map can have, so a corrupt size can't allocate more than that.
+const MaxBinaryMapTiles = 4096 * 4096
+
+var binaryMapMagic = []byte("BEAM")
+
+var (
+	ErrNotBinaryMap     = errors.New("not a binary map")
+	ErrBinaryMapVersion = errors.New("unsupported binary map version")
+	ErrCorruptBinaryMap = errors.New("corrupt binary map")
+	ErrUnevenMapRows    = errors.New("map rows have different widths")
+)
+
+// MarshalBinary encodes the map in the binary map format.
+// Each tile's Pos is implied by where it is in the grid, so it isn't stored.
+func (m *Map) MarshalBinary() ([]byte, error) {
+	height := len(m.Tiles)
+	width := 0
+	if height > 0 {
+		width = len(m.Tiles[0])
+	}
+	for _, row := range m.Tiles {
+		if len(row) != width {
+			return nil, ErrUnevenMapRows
+		}
+	}
+
+	// Everything but the tiles goes in as JSON
+	rest := *m
+	rest.Tiles = nil
+	restJSON, err := json.Marshal(rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal map data: %w", err)
+	}
+
+	strs := newBinaryStrings()
+	for _, row := range m.Tiles {
+		for _, tile := range row {
+			strs.addTile(tile)
+		}
+	}
+
+	buf := bytes.NewBuffer(append([]byte{}, binaryMapMagic...))
+	buf.WriteByte(BinaryMapVersion)
+	writeUvarint(buf, uint64(len(strs.list)))
+	for _, s := range strs.list {
+		writeUvarint(buf, uint64(len(s)))
+		buf.WriteString(s)
+	}
+
+	writeUvarint(buf, uint64(width))
+	writeUvarint(buf, uint64(height))
+	var run []byte
+	runLength := 0
+	flush := func() {
+		if runLength > 0 {
+			writeUvarint(buf, uint64(runLength))
+			buf.Write(run)
+		}
+	}
+	for _, row := range m.Tiles {
+		for _, tile := range row {
+			encoded := strs.encodeTile(tile)
+			if runLength > 0 && bytes.Equal(encoded, run) {
+				runLength++
+				continue
+			}
+			flush()
+			run, runLength = encoded, 1
+		}
+	}
+	flush()
+
+	writeUvarint(buf, uint64(len(restJSON)))
+	buf.Write(restJSON)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a map written by MarshalBinary, replacing the map's contents.
+func (m *Map) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, binaryMapMagic) {
+		return ErrNotBinaryMap
+	}
+	r := &binaryReader{data: data[len(binaryMapMagic):]}
+	if r.version = r.byte(); r.err == nil && (r.version < 1 || r.version > BinaryMapVersion) {
+		return fmt.Errorf("%w: %d", ErrBinaryMapVersion, r.version)
+	}
+
+	strs := make([]string, r.count())
+	for i := range strs {
+		strs[i] = string(r.bytes(r.count()))
+	}
+	r.strs = strs
+
+	width, height := r.count(), r.count()
+	if height > MaxBinaryMapTiles || width*height > MaxBinaryMapTiles {
+		return ErrCorruptBinaryMap
+	}
+	tiles := make([][]Tile, height)
+	for y := range tiles {
+		tiles[y] = make([]Tile, width)
+	}
+	for i := 0; i < width*height && r.err == nil; {
+		runLength := r.count()
+		tile := r.tile()
+		if runLength == 0 || i+runLength > width*height {
+			return ErrCorruptBinaryMap
+		}
+		for end := i + runLength; i < end; i++ {
+			x, y := i%width, i/width
+			tiles[y][x] = copyTile(tile)
+			tiles[y][x].Pos = Position{X: x, Y: y}
+		}
+	}
+
+	restJSON := r.bytes(r.count())
+	if r.err != nil {
+		return r.err
+	}
+	var decoded Map
+	if err := json.Unmarshal(restJSON, &decoded); err != nil {
+		return fmt.Errorf("failed to unmarshal map data: %w", err)
+	}
+	decoded.Tiles = tiles
+	*m = decoded
+	return nil
+}
+
+// copyTile gives each tile in a run its own textures and properties, so editing one doesn't change the rest.
+func copyTile(tile Tile) Tile {
+	if tile.Textures != nil {
+		textures := make([]*AnimatedTexture, len(tile.Textures))
+		for i, tex := range tile.Textures {
+			if tex == nil {
+				continue
+			}
+			copied := *tex
+			if tex.Frames != nil {
+				copied.Frames = append([]Texture{}, tex.Frames...)
+			}
+			textures[i] = &copied
+		}
+		tile.Textures = textures
+	}
+	if tile.Properties != nil {
+		props := make(map[string]string, len(tile.Properties))
+		for k, v := range tile.Properties {
+			props[k] = v
+		}
+		tile.Properties = props
+	}
+	return tile
+}
+
+// binaryStrings is the string table, texture names and properties are stored as an index into it.
+type binaryStrings struct {
+	list  []string
+	index map[string]int
+}
+
+func newBinaryStrings() *binaryStrings {
+	return &binaryStrings{index: make(map[string]int)}
+}
+
+func (s *binaryStrings) add(str string) {
+	if _, ok := s.index[str]; !ok {
+		s.index[str] = len(s.list)
+		s.list = append(s.list, str)
+	}
+}
+
+func (s *binaryStrings) addTile(tile Tile) {
+	for _, tex := range tile.Textures {
+		if tex == nil {
+			continue
+		}
+		for _, frame := range tex.Frames {
+			s.add(frame.Name)
+		}
+	}
+	for k, v := range tile.Properties {
+		s.add(k)
+		s.add(v)
+	}
+}
+
+// encodeTile writes a tile without its position. Nil and empty slices are kept apart,
+// by writing their length plus one, so the tile decodes to exactly what JSON would give.
+func (s *binaryStrings) encodeTile(tile Tile) []byte {
+	buf := &bytes.Buffer{}
+	writeUvarint(buf, uint64(tile.Type))
+
+	if tile.Textures == nil {
+		writeUvarint(buf, 0)
+	} else {
+		writeUvarint(buf, uint64(len(tile.Textures))+1)
+	}
+	for _, tex := range tile.Textures {
+		if tex == nil {
+			buf.WriteByte(0)
+			continue
+		}
+		buf.WriteByte(1)
+		writeBool(buf, tex.IsAnimated)
+		writeFloat64(buf, tex.AnimationTime)
+		writeUvarint(buf, uint64(tex.CurrentFrame))
+		writeUvarint(buf, uint64(tex.Layer))
+		writeFloat64(buf, tex.PhaseOffset)
+		writeUvarint(buf, uint64(tex.PlayMode))
+		if tex.Frames == nil {
+			writeUvarint(buf, 0)
+		} else {
+			writeUvarint(buf, uint64(len(tex.Frames))+1)
+		}
+		for _, frame := range tex.Frames {
+			writeUvarint(buf, uint64(s.index[frame.Name]))
+			writeFloat64(buf, frame.Rotation)
+			writeFloat64(buf, frame.ScaleX)
+			writeFloat64(buf, frame.ScaleY)
+			writeFloat64(buf, frame.OffsetX)
+			writeFloat64(buf, frame.OffsetY)
+			buf.Write([]byte{frame.Tint.R, frame.Tint.G, frame.Tint.B, frame.Tint.A})
+			writeBool(buf, frame.MirrorX)
+			writeBool(buf, frame.MirrorY)
+			writeFloat32(buf, frame.Origin.X)
+			writeFloat32(buf, frame.Origin.Y)
+			writeVarint(buf, int64(frame.SpanX))
+			writeVarint(buf, int64(frame.SpanY))
+		}
+	}
+
+	// Properties are omitted from JSON when empty, so they always decode to nil
+	writeUvarint(buf, uint64(len(tile.Properties)))
+	for _, k := range slices.Sorted(maps.Keys(tile.Properties)) {
+		writeUvarint(buf, uint64(s.index[k]))
+		writeUvarint(buf, uint64(s.index[tile.Properties[k]]))
+	}
+
+	// 0 for no collision override, otherwise 1 for blocked and 2 for walkable
+	switch {
+	case tile.Walkable == nil:
+		buf.WriteByte(0)
+	case *tile.Walkable:
+		buf.WriteByte(2)
+	default:
+		buf.WriteByte(1)
+	}
+	return buf.Bytes()
+}
+
+// binaryReader reads a binary map, the first error stops all further reads.
+type binaryReader struct {
+	data    []byte
+	strs    []string
+	version byte
+	err     error
+}
+
+func (r *binaryReader) fail() {
+	if r.err == nil {
+		r.err = ErrCorruptBinaryMap
+	}
+	r.data = nil
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+// count reads a length, which can't be more than the bytes left since every element takes at least one.
+func (r *binaryReader) count() int {
+	v := r.uvarint()
+	if v > uint64(len(r.data)) {
+		r.fail()
+		return 0
+	}
+	return int(v)
+}
+
+func (r *binaryReader) bytes(n int) []byte {
+	if r.err != nil || n > len(r.data) {
+		r.fail()
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *binaryReader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *binaryReader) bool() bool {
+	return r.byte() == 1
+}
+
+func (r *binaryReader) float64() float64 {
+	if b := r.bytes(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *binaryReader) float32() float32 {
+	if b := r.bytes(4); b != nil {
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *binaryReader) str() string {
+	i := r.uvarint()
+	if i >= uint64(len(r.strs)) {
+		r.fail()
+		return ""
+	}
+	return r.strs[i]
+}
+
+// optionalCount reads a slice length written as its length plus one, -1 for a nil slice.
+func (r *binaryReader) optionalCount() int {
+	return r.count() - 1
+}
+
+func (r *binaryReader) tile() Tile {
+	tile := Tile{Type: TileType(r.uvarint())}
+	if n := r.optionalCount(); n >= 0 {
+		tile.Textures = make([]*AnimatedTexture, n)
+	}
+	for i := range tile.Textures {
+		if !r.bool() {
+			continue
+		}
+		tex := &AnimatedTexture{
+			IsAnimated:    r.bool(),
+			AnimationTime: r.float64(),
+			CurrentFrame:  int(r.uvarint()),
+			Layer:         Layer(r.uvarint()),
+		}
+		if r.version >= 2 {
+			tex.PhaseOffset = r.float64()
+			tex.PlayMode = PlayMode(r.uvarint())
+		}
+		if n := r.optionalCount(); n >= 0 {
+			tex.Frames = make([]Texture, n)
+		}
+		for j := range tex.Frames {
+			tex.Frames[j] = Texture{
+				Name:     r.str(),
+				Rotation: r.float64(),
+				ScaleX:   r.float64(),
+				ScaleY:   r.float64(),
+				OffsetX:  r.float64(),
+				OffsetY:  r.float64(),
+				Tint:     rl.Color{R: r.byte(), G: r.byte(), B: r.byte(), A: r.byte()},
+				MirrorX:  r.bool(),
+				MirrorY:  r.bool(),
+				Origin:   rl.Vector2{X: r.float32(), Y: r.float32()},
+			}
+			if r.version >= 2 {
+				tex.Frames[j].SpanX = int(r.varint())
+				tex.Frames[j].SpanY = int(r.varint())
+			}
+		}
+		tile.Textures[i] = tex
+	}
+
+	if n := r.count(); n > 0 {
+		tile.Properties = make(map[string]string, n)
+		for range n {
+			k := r.str()
+			tile.Properties[k] = r.str()
+		}
+	}
+	if r.version < 2 {
+		return tile
+	}
+	if walkable := r.byte(); walkable > 0 {
+		tile.SetWalkable(walkable == 2)
+	}
+	return tile
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func writeVarint(buf *bytes.Buffer, v int64) {
+	buf.Write(binary.AppendVarint(nil, v))
+}
+
+func writeBool(buf *bytes.Buffer, v bool) {
+	if v {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+}
+
+func writeFloat64(buf *bytes.Buffer, v float64) {
+	buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+}
+
+func writeFloat32(buf *bytes.Buffer, v float32) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)))
+}
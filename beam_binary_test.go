@@ -0,0 +1,189 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// newTestBinaryMap returns a map with runs of plain floor, painted and animated tiles, properties, NPCs, items and a trigger.
+func newTestBinaryMap(width, height int) *Map {
+	m := newFloorMap(width, height)
+	m.Start = Position{X: 1, Y: 1}
+	m.Exit = Positions{{X: 2, Y: 3}}
+	for y := range m.Tiles {
+		for x := range m.Tiles[y] {
+			m.Tiles[y][x].Textures = []*AnimatedTexture{NewSimpleTileTexture("grass")}
+		}
+	}
+	m.Tiles[0][0] = Tile{Type: WallTile, Pos: Position{}, Properties: map[string]string{"solid": "true", "height": "2"}}
+	m.Tiles[0][0].SetWalkable(true)
+	m.Tiles[0][1].SetWalkable(false)
+	m.Tiles[1][2].Textures = append(m.Tiles[1][2].Textures, &AnimatedTexture{
+		Frames: []Texture{
+			{Name: "water_1", Rotation: 90, ScaleX: 1.5, ScaleY: 0.5, OffsetX: 0.25, Tint: rl.Blue, MirrorX: true, Origin: rl.Vector2{X: 0.5, Y: 0.5}, SpanX: 3, SpanY: 2},
+			{Name: "water_2", ScaleX: 1, ScaleY: 1, Tint: rl.White, MirrorY: true},
+		},
+		IsAnimated:    true,
+		AnimationTime: 0.2,
+		Layer:         ForegroundLayer,
+		PhaseOffset:   0.15,
+		PlayMode:      PingPong,
+	})
+	m.Tiles[2][1].Textures = []*AnimatedTexture{}
+	m.Tiles[2][2].Textures = nil
+
+	guard := &NPC{Pos: Position{X: 3, Y: 2}, Data: NPCData{ID: "guard", Name: "Guard", Texture: NewSimpleNPCTexture("guard"), MaxHealth: 20, SpawnPos: Position{X: 3, Y: 2}}}
+	coin := NewItem("coin", "Coin", ItemTypeResource)
+	coin.Pos = Position{X: 1, Y: 2}
+	m.NPCs = NPCs{guard}
+	m.Items = Items{coin}
+	m.Triggers = []Trigger{{Name: "trap", Positions: Positions{{X: 1, Y: 2}, {X: 2, Y: 2}}, Payload: map[string]string{"damage": "5"}}}
+	return m
+}
+
+// TestMapBinaryRoundTrip checks a map loaded from binary is identical to the same map loaded from JSON.
+func TestMapBinaryRoundTrip(t *testing.T) {
+	m := newTestBinaryMap(5, 4)
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON Map
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	binData, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	var fromBinary Map
+	if err := fromBinary.UnmarshalBinary(binData); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromJSON.Tiles, fromBinary.Tiles) {
+		t.Errorf("Expected the binary tiles to match the JSON tiles")
+	}
+	if !reflect.DeepEqual(fromJSON, fromBinary) {
+		t.Errorf("Expected the binary map to match the JSON map")
+	}
+
+	// Tiles in a run don't share textures
+	fromBinary.Tiles[3][0].Textures[0].Frames[0].Name = "changed"
+	if fromBinary.Tiles[3][1].Textures[0].Frames[0].Name != "grass" {
+		t.Errorf("Expected editing one tile not to change the next")
+	}
+}
+
+// TestMapBinaryCompact checks a large, mostly uniform map is much smaller in binary than in JSON.
+func TestMapBinaryCompact(t *testing.T) {
+	m := newTestBinaryMap(128, 128)
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binData, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binData)*20 > len(jsonData) {
+		t.Errorf("Expected the binary map to be a twentieth of the JSON, got %d bytes and %d bytes", len(binData), len(jsonData))
+	}
+}
+
+// TestMapUnmarshalBinaryErrors checks other files, newer versions and truncated data are rejected.
+func TestMapUnmarshalBinaryErrors(t *testing.T) {
+	binData, err := newTestBinaryMap(5, 4).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m Map
+	if err := m.UnmarshalBinary([]byte(`{"Width": 5}`)); !errors.Is(err, ErrNotBinaryMap) {
+		t.Errorf("Expected ErrNotBinaryMap for JSON, got %v", err)
+	}
+	newer := append([]byte{}, binData...)
+	newer[len(binaryMapMagic)] = BinaryMapVersion + 1
+	if err := m.UnmarshalBinary(newer); !errors.Is(err, ErrBinaryMapVersion) {
+		t.Errorf("Expected ErrBinaryMapVersion, got %v", err)
+	}
+	for _, n := range []int{5, len(binData) / 2, len(binData) - 1} {
+		if err := m.UnmarshalBinary(binData[:n]); err == nil {
+			t.Errorf("Expected an error for data truncated to %d bytes", n)
+		}
+	}
+
+	// A few bytes claiming a huge map are rejected before the tiles are allocated
+	huge := bytes.NewBuffer(append([]byte{}, binaryMapMagic...))
+	huge.WriteByte(BinaryMapVersion)
+	writeUvarint(huge, 0)
+	writeUvarint(huge, 60000)
+	writeUvarint(huge, 60000)
+	huge.Write(make([]byte, 60000))
+	if err := m.UnmarshalBinary(huge.Bytes()); !errors.Is(err, ErrCorruptBinaryMap) {
+		t.Errorf("Expected ErrCorruptBinaryMap for a 60000x60000 map, got %v", err)
+	}
+
+	uneven := newTestBinaryMap(5, 4)
+	uneven.Tiles[2] = uneven.Tiles[2][:3]
+	if _, err := uneven.MarshalBinary(); !errors.Is(err, ErrUnevenMapRows) {
+		t.Errorf("Expected ErrUnevenMapRows, got %v", err)
+	}
+}
+
+// TestMapUnmarshalBinaryVersion1 loads a map written in version 1 of the format, before spans, play modes and walkable overrides.
+func TestMapUnmarshalBinaryVersion1(t *testing.T) {
+	buf := bytes.NewBuffer(append([]byte{}, binaryMapMagic...))
+	buf.WriteByte(1)
+	writeUvarint(buf, 1)
+	writeUvarint(buf, uint64(len("grass")))
+	buf.WriteString("grass")
+	writeUvarint(buf, 2) // Width
+	writeUvarint(buf, 1) // Height
+
+	// One run of two tiles, each with a single grass texture
+	writeUvarint(buf, 2)
+	writeUvarint(buf, uint64(FloorTile))
+	writeUvarint(buf, 2)
+	writeBool(buf, true)
+	writeBool(buf, false)
+	writeFloat64(buf, 0)
+	writeUvarint(buf, 0)
+	writeUvarint(buf, uint64(BaseLayer))
+	writeUvarint(buf, 2)
+	writeUvarint(buf, 0)
+	for _, v := range []float64{90, 1, 1, 0, 0} {
+		writeFloat64(buf, v)
+	}
+	buf.Write([]byte{255, 255, 255, 255})
+	writeBool(buf, false)
+	writeBool(buf, false)
+	buf.Write(make([]byte, 8)) // Origin
+	writeUvarint(buf, 0)       // Properties
+
+	rest := []byte(`{"Width":2,"Height":1}`)
+	writeUvarint(buf, uint64(len(rest)))
+	buf.Write(rest)
+
+	var m Map
+	if err := m.UnmarshalBinary(buf.Bytes()); err != nil {
+		t.Fatalf("Expected a version 1 map to load, got %v", err)
+	}
+	if m.Width != 2 || len(m.Tiles) != 1 || len(m.Tiles[0]) != 2 {
+		t.Fatalf("Expected a 2x1 map, got %dx%d", m.Width, len(m.Tiles))
+	}
+	tile := m.Tiles[0][1]
+	if len(tile.Textures) != 1 || tile.Textures[0].Frames[0].Name != "grass" || tile.Textures[0].Frames[0].Rotation != 90 {
+		t.Errorf("Expected the grass texture turned 90 degrees, got %+v", tile.Textures)
+	}
+	if tile.Pos != (Position{X: 1, Y: 0}) || tile.Walkable != nil || tile.Textures[0].PlayMode != 0 {
+		t.Errorf("Expected a tile at (1, 0) without the fields added in version 2, got %+v", tile)
+	}
+}
@@ -0,0 +1,35 @@
+package beam
+
+// InBounds reports if pos is a tile on the map.
+func (m *Map) InBounds(pos Position) bool {
+	return pos.Y >= 0 && pos.Y < len(m.Tiles) && pos.X >= 0 && pos.X < len(m.Tiles[pos.Y])
+}
+
+// TileAt returns the tile at pos, or false if pos is off the map.
+func (m *Map) TileAt(pos Position) (*Tile, bool) {
+	if !m.InBounds(pos) {
+		return nil, false
+	}
+	return &m.Tiles[pos.Y][pos.X], true
+}
+
+// Neighbors returns the tiles next to pos that are on the map, up, right, down and left.
+// With diagonal set, the four corners follow, clockwise from the top right.
+func (m *Map) Neighbors(pos Position, diagonal bool) []Position {
+	candidates := pos.neighbors()
+	if diagonal {
+		candidates = append(candidates,
+			Position{X: pos.X + 1, Y: pos.Y - 1},
+			Position{X: pos.X + 1, Y: pos.Y + 1},
+			Position{X: pos.X - 1, Y: pos.Y + 1},
+			Position{X: pos.X - 1, Y: pos.Y - 1},
+		)
+	}
+	neighbors := make([]Position, 0, len(candidates))
+	for _, next := range candidates {
+		if m.InBounds(next) {
+			neighbors = append(neighbors, next)
+		}
+	}
+	return neighbors
+}
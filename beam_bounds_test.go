@@ -0,0 +1,59 @@
+package beam
+
+import "testing"
+
+// TestMapInBounds checks corners are on the map and anything past the edges isn't.
+func TestMapInBounds(t *testing.T) {
+	m := newTestSpawnMap()
+	for _, pos := range []Position{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 5}, {X: 5, Y: 5}} {
+		if !m.InBounds(pos) {
+			t.Errorf("Expected %v to be on the map", pos)
+		}
+	}
+	for _, pos := range []Position{{X: -1, Y: 0}, {X: 0, Y: -1}, {X: 6, Y: 5}, {X: 5, Y: 6}} {
+		if m.InBounds(pos) {
+			t.Errorf("Expected %v to be off the map", pos)
+		}
+	}
+
+	if tile, ok := m.TileAt(Position{X: 2, Y: 2}); !ok || tile.Type != WallTile {
+		t.Errorf("Expected the wall at 2,2")
+	}
+	if tile, ok := m.TileAt(Position{X: 6, Y: 0}); ok || tile != nil {
+		t.Errorf("Expected no tile off the map")
+	}
+}
+
+// TestMapNeighbors checks orthogonal and diagonal neighbors in the middle, on an edge, and in a corner.
+func TestMapNeighbors(t *testing.T) {
+	m := newTestSpawnMap()
+	tests := []struct {
+		name     string
+		pos      Position
+		diagonal bool
+		want     []Position
+	}{
+		{"middle", Position{X: 3, Y: 3}, false, []Position{{X: 3, Y: 2}, {X: 4, Y: 3}, {X: 3, Y: 4}, {X: 2, Y: 3}}},
+		{"middle diagonal", Position{X: 3, Y: 3}, true, []Position{
+			{X: 3, Y: 2}, {X: 4, Y: 3}, {X: 3, Y: 4}, {X: 2, Y: 3},
+			{X: 4, Y: 2}, {X: 4, Y: 4}, {X: 2, Y: 4}, {X: 2, Y: 2},
+		}},
+		{"top edge", Position{X: 3, Y: 0}, false, []Position{{X: 4, Y: 0}, {X: 3, Y: 1}, {X: 2, Y: 0}}},
+		{"top edge diagonal", Position{X: 3, Y: 0}, true, []Position{{X: 4, Y: 0}, {X: 3, Y: 1}, {X: 2, Y: 0}, {X: 4, Y: 1}, {X: 2, Y: 1}}},
+		{"corner", Position{X: 0, Y: 0}, false, []Position{{X: 1, Y: 0}, {X: 0, Y: 1}}},
+		{"corner diagonal", Position{X: 5, Y: 5}, true, []Position{{X: 5, Y: 4}, {X: 4, Y: 5}, {X: 4, Y: 4}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := m.Neighbors(tc.pos, tc.diagonal)
+			if len(got) != len(tc.want) {
+				t.Fatalf("Expected %v, got %v", tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("Expected %v, got %v", tc.want, got)
+				}
+			}
+		})
+	}
+}
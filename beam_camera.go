@@ -0,0 +1,93 @@
+package beam
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/*
+A Camera maps the world onto the screen, so a game only has to draw the tiles it can see.
+World coordinates are in pixels at a zoom of 1, so tile x starts at x*tileSize.
+
+Example usage:
+    camera := beam.NewCamera()
+    for !rl.WindowShouldClose() {
+        camera.Follow(playerWorldPos, screenW, screenH, 8, rl.GetFrameTime())
+        start, end := camera.VisibleTileBounds(screenW, screenH, tileSize)
+        for y := max(0, start.Y); y <= min(end.Y, currMap.Height-1); y++ {
+            for x := max(0, start.X); x <= min(end.X, currMap.Width-1); x++ {
+                screen := camera.WorldToScreen(rl.Vector2{X: float32(x * tileSize), Y: float32(y * tileSize)})
+                ...
+            }
+        }
+    }
+*/
+
+// Camera is a view of the world. Pos is the world point at the top left of the screen,
+// and Zoom scales the world, 2 draws everything twice as large. A zero Zoom is treated as 1.
+type Camera struct {
+	Pos  rl.Vector2
+	Zoom float32
+}
+
+// NewCamera returns a camera at the world origin with no zoom.
+func NewCamera() *Camera {
+	return &Camera{Zoom: 1}
+}
+
+func (c *Camera) zoom() float32 {
+	if c.Zoom <= 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// WorldToScreen converts a world position to where it's drawn on screen.
+func (c *Camera) WorldToScreen(world rl.Vector2) rl.Vector2 {
+	zoom := c.zoom()
+	return rl.Vector2{X: (world.X - c.Pos.X) * zoom, Y: (world.Y - c.Pos.Y) * zoom}
+}
+
+// ScreenToWorld converts a screen position, like the mouse, to the world position under it.
+func (c *Camera) ScreenToWorld(screen rl.Vector2) rl.Vector2 {
+	zoom := c.zoom()
+	return rl.Vector2{X: screen.X/zoom + c.Pos.X, Y: screen.Y/zoom + c.Pos.Y}
+}
+
+// VisibleTileBounds returns the first and last tile, inclusive, that are at least partly on a screen of the given size.
+// The bounds aren't clamped to a map, so clamp them before indexing the map's tiles.
+func (c *Camera) VisibleTileBounds(screenW, screenH, tileSize int) (start, end Position) {
+	if tileSize <= 0 {
+		return Position{}, Position{X: -1, Y: -1}
+	}
+	topLeft := c.ScreenToWorld(rl.Vector2{})
+	bottomRight := c.ScreenToWorld(rl.Vector2{X: float32(screenW), Y: float32(screenH)})
+	size := float64(tileSize)
+	start = Position{X: int(math.Floor(float64(topLeft.X) / size)), Y: int(math.Floor(float64(topLeft.Y) / size))}
+	// A tile that starts exactly on the far edge isn't visible
+	end = Position{X: int(math.Ceil(float64(bottomRight.X)/size)) - 1, Y: int(math.Ceil(float64(bottomRight.Y)/size)) - 1}
+	return start, end
+}
+
+// CenterOn moves the camera so target is in the middle of a screen of the given size.
+func (c *Camera) CenterOn(target rl.Vector2, screenW, screenH int) {
+	c.Pos = c.centeredPos(target, screenW, screenH)
+}
+
+// Follow eases the camera toward centering target, closing more of the gap the higher speed is.
+// The easing depends only on the time passed, so the camera moves the same at any frame rate.
+func (c *Camera) Follow(target rl.Vector2, screenW, screenH int, speed, dt float32) {
+	if speed <= 0 || dt <= 0 {
+		return
+	}
+	goal := c.centeredPos(target, screenW, screenH)
+	t := float32(1 - math.Exp(-float64(speed*dt)))
+	c.Pos.X += (goal.X - c.Pos.X) * t
+	c.Pos.Y += (goal.Y - c.Pos.Y) * t
+}
+
+func (c *Camera) centeredPos(target rl.Vector2, screenW, screenH int) rl.Vector2 {
+	zoom := c.zoom()
+	return rl.Vector2{X: target.X - float32(screenW)/zoom/2, Y: target.Y - float32(screenH)/zoom/2}
+}
@@ -0,0 +1,87 @@
+package beam
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func vectorsClose(a, b rl.Vector2) bool {
+	return math.Abs(float64(a.X-b.X)) < 0.001 && math.Abs(float64(a.Y-b.Y)) < 0.001
+}
+
+// TestCameraTransforms checks world and screen positions convert both ways at a non 1.0 zoom.
+func TestCameraTransforms(t *testing.T) {
+	c := &Camera{Pos: rl.Vector2{X: 100, Y: 50}, Zoom: 2}
+	world := rl.Vector2{X: 132, Y: 66}
+	screen := c.WorldToScreen(world)
+	if want := (rl.Vector2{X: 64, Y: 32}); !vectorsClose(screen, want) {
+		t.Errorf("Expected %v on screen, got %v", want, screen)
+	}
+	if got := c.ScreenToWorld(screen); !vectorsClose(got, world) {
+		t.Errorf("Expected %v back in the world, got %v", world, got)
+	}
+
+	c.Zoom = 0.5
+	if got := c.ScreenToWorld(rl.Vector2{X: 10, Y: 20}); !vectorsClose(got, rl.Vector2{X: 120, Y: 90}) {
+		t.Errorf("Expected 120,90 at half zoom, got %v", got)
+	}
+	c.Zoom = 0
+	if got := c.WorldToScreen(world); !vectorsClose(got, rl.Vector2{X: 32, Y: 16}) {
+		t.Errorf("Expected no zoom for a zero Zoom, got %v", got)
+	}
+}
+
+// TestCameraVisibleTileBounds checks the tiles partly on screen are included, and tiles just past the edge aren't.
+func TestCameraVisibleTileBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		camera     Camera
+		start, end Position
+	}{
+		{"origin", Camera{Zoom: 1}, Position{X: 0, Y: 0}, Position{X: 9, Y: 7}},
+		{"zoomed in", Camera{Zoom: 2}, Position{X: 0, Y: 0}, Position{X: 4, Y: 3}},
+		{"zoomed out", Camera{Zoom: 0.5}, Position{X: 0, Y: 0}, Position{X: 19, Y: 15}},
+		{"partial tiles", Camera{Pos: rl.Vector2{X: 8, Y: 40}, Zoom: 1}, Position{X: 0, Y: 2}, Position{X: 10, Y: 10}},
+		{"off the map", Camera{Pos: rl.Vector2{X: -24, Y: -8}, Zoom: 2}, Position{X: -2, Y: -1}, Position{X: 3, Y: 3}},
+	}
+	for _, tt := range tests {
+		start, end := tt.camera.VisibleTileBounds(160, 128, 16)
+		if start != tt.start || end != tt.end {
+			t.Errorf("%s: expected %v to %v, got %v to %v", tt.name, tt.start, tt.end, start, end)
+		}
+	}
+}
+
+// TestCameraFollow checks the camera eases toward the target, and ends up centered on it.
+func TestCameraFollow(t *testing.T) {
+	c := &Camera{Zoom: 2}
+	target := rl.Vector2{X: 400, Y: 300}
+	goal := rl.Vector2{X: 400 - 160/2/2, Y: 300 - 128/2/2}
+
+	c.Follow(target, 160, 128, 5, 0.1)
+	if c.Pos.X <= 0 || c.Pos.X >= goal.X || c.Pos.Y <= 0 || c.Pos.Y >= goal.Y {
+		t.Errorf("Expected the camera to move part way to %v, got %v", goal, c.Pos)
+	}
+
+	// Two half steps cover the same distance as one full step
+	a, b := &Camera{Zoom: 2}, &Camera{Zoom: 2}
+	a.Follow(target, 160, 128, 5, 0.2)
+	b.Follow(target, 160, 128, 5, 0.1)
+	b.Follow(target, 160, 128, 5, 0.1)
+	if !vectorsClose(a.Pos, b.Pos) {
+		t.Errorf("Expected the same position at any frame rate, got %v and %v", a.Pos, b.Pos)
+	}
+
+	for range 200 {
+		c.Follow(target, 160, 128, 5, 0.1)
+	}
+	if !vectorsClose(c.Pos, goal) {
+		t.Errorf("Expected the camera to settle at %v, got %v", goal, c.Pos)
+	}
+	c.CenterOn(rl.Vector2{}, 160, 128)
+	if got := c.WorldToScreen(rl.Vector2{}); !vectorsClose(got, rl.Vector2{X: 80, Y: 64}) {
+		t.Errorf("Expected the origin in the middle of the screen, got %v", got)
+	}
+}
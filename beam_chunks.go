@@ -0,0 +1,273 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+/*
+A ChunkedMap streams the tiles of a very large map in square chunks:
+  - Only the chunks around the player are kept in memory
+  - Chunks that fall out of range are saved to a ChunkStore and dropped
+  - Chunks that have never been saved start as floor tiles
+
+Example usage:
+    store := &DirChunkStore{Dir: "world"}
+    world := NewChunkedMap(4096, 4096, DefaultChunkSize, store)
+    if err := world.EnsureLoaded(player.Pos, 64); err != nil {
+        log.Fatal(err)
+    }
+    if tile := world.TileAt(player.Facing()); tile != nil && tile.Type == WallTile {
+        ...
+    }
+*/
+
+// DefaultChunkSize is the width and height of a chunk in tiles.
+const DefaultChunkSize = 32
+
+// ChunkCoord is a chunk's position, in chunks rather than tiles.
+type ChunkCoord struct {
+	X, Y int
+}
+
+// ChunkStore keeps chunks that have been evicted from a ChunkedMap.
+type ChunkStore interface {
+	// LoadChunk returns a saved chunk's tiles, or false if it was never saved.
+	LoadChunk(coord ChunkCoord) ([][]Tile, bool, error)
+	// SaveChunk saves a chunk's tiles, replacing any earlier save.
+	SaveChunk(coord ChunkCoord, tiles [][]Tile) error
+}
+
+// DirChunkStore saves each chunk as a JSON file in Dir.
+type DirChunkStore struct {
+	Dir string
+}
+
+func (s *DirChunkStore) chunkPath(coord ChunkCoord) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("chunk_%d_%d.json", coord.X, coord.Y))
+}
+
+func (s *DirChunkStore) LoadChunk(coord ChunkCoord) ([][]Tile, bool, error) {
+	data, err := os.ReadFile(s.chunkPath(coord))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	var tiles [][]Tile
+	if err := json.Unmarshal(data, &tiles); err != nil {
+		return nil, false, fmt.Errorf("chunk %d,%d: %w", coord.X, coord.Y, err)
+	}
+	return tiles, true, nil
+}
+
+func (s *DirChunkStore) SaveChunk(coord ChunkCoord, tiles [][]Tile) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(tiles)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.chunkPath(coord), data, 0644)
+}
+
+// ChunkedMap is a map's tiles split into ChunkSize x ChunkSize chunks, loaded on demand.
+// Chunks on the right and bottom edges are smaller when the map isn't a multiple of ChunkSize.
+type ChunkedMap struct {
+	Width, Height int
+	ChunkSize     int
+	// Store is where evicted chunks go. Without one, chunks are never evicted.
+	Store ChunkStore
+
+	chunks map[ChunkCoord][][]Tile
+}
+
+// NewChunkedMap creates an empty width x height map, with no chunks loaded yet.
+func NewChunkedMap(width, height, chunkSize int, store ChunkStore) *ChunkedMap {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &ChunkedMap{
+		Width:     width,
+		Height:    height,
+		ChunkSize: chunkSize,
+		Store:     store,
+		chunks:    make(map[ChunkCoord][][]Tile),
+	}
+}
+
+// NewChunkedMapFromMap splits a dense map's tiles into chunks, all of them loaded.
+// Only the tiles are copied, NPCs, items and the special positions stay on the Map.
+func NewChunkedMapFromMap(m *Map, chunkSize int, store ChunkStore) *ChunkedMap {
+	c := NewChunkedMap(m.Width, m.Height, chunkSize, store)
+	for y := 0; y < c.Height; y += c.ChunkSize {
+		for x := 0; x < c.Width; x += c.ChunkSize {
+			coord := c.ChunkOf(Position{X: x, Y: y})
+			tiles := c.newChunk(coord)
+			for ty := range tiles {
+				for tx := range tiles[ty] {
+					if y+ty < len(m.Tiles) && x+tx < len(m.Tiles[y+ty]) {
+						tiles[ty][tx] = m.Tiles[y+ty][x+tx]
+					}
+				}
+			}
+			c.chunks[coord] = tiles
+		}
+	}
+	return c
+}
+
+// ToMap builds a dense map from every chunk, loading any that were evicted.
+// Chunks loaded for the copy are not kept in memory.
+func (c *ChunkedMap) ToMap() (*Map, error) {
+	m := &Map{Width: c.Width, Height: c.Height, Tiles: make([][]Tile, c.Height)}
+	for y := range m.Tiles {
+		m.Tiles[y] = make([]Tile, c.Width)
+	}
+	for y := 0; y < c.Height; y += c.ChunkSize {
+		for x := 0; x < c.Width; x += c.ChunkSize {
+			coord := c.ChunkOf(Position{X: x, Y: y})
+			tiles, ok := c.chunks[coord]
+			if !ok {
+				var err error
+				if tiles, err = c.readChunk(coord); err != nil {
+					return nil, err
+				}
+			}
+			for ty := range tiles {
+				copy(m.Tiles[y+ty][x:], tiles[ty])
+			}
+		}
+	}
+	return m, nil
+}
+
+// ChunkOf returns the chunk a tile is in.
+func (c *ChunkedMap) ChunkOf(pos Position) ChunkCoord {
+	return ChunkCoord{X: floorDiv(pos.X, c.ChunkSize), Y: floorDiv(pos.Y, c.ChunkSize)}
+}
+
+// TileAt returns the tile at pos, loading its chunk if it isn't already.
+// It returns nil if pos is off the map, or the chunk couldn't be loaded from the store.
+func (c *ChunkedMap) TileAt(pos Position) *Tile {
+	if pos.X < 0 || pos.Y < 0 || pos.X >= c.Width || pos.Y >= c.Height {
+		return nil
+	}
+	coord := c.ChunkOf(pos)
+	tiles, ok := c.chunks[coord]
+	if !ok {
+		var err error
+		if tiles, err = c.readChunk(coord); err != nil {
+			return nil
+		}
+		c.chunks[coord] = tiles
+	}
+	return &tiles[pos.Y-coord.Y*c.ChunkSize][pos.X-coord.X*c.ChunkSize]
+}
+
+// EnsureLoaded loads every chunk within radius tiles of center,
+// and saves and evicts the loaded chunks outside of that area.
+func (c *ChunkedMap) EnsureLoaded(center Position, radius int) error {
+	minChunk := c.ChunkOf(Position{X: max(center.X-radius, 0), Y: max(center.Y-radius, 0)})
+	maxChunk := c.ChunkOf(Position{X: min(center.X+radius, c.Width-1), Y: min(center.Y+radius, c.Height-1)})
+	inRange := func(coord ChunkCoord) bool {
+		return coord.X >= minChunk.X && coord.X <= maxChunk.X && coord.Y >= minChunk.Y && coord.Y <= maxChunk.Y
+	}
+
+	if c.Store != nil {
+		for coord, tiles := range c.chunks {
+			if inRange(coord) {
+				continue
+			}
+			if err := c.Store.SaveChunk(coord, tiles); err != nil {
+				return err
+			}
+			delete(c.chunks, coord)
+		}
+	}
+
+	for cy := minChunk.Y; cy <= maxChunk.Y; cy++ {
+		for cx := minChunk.X; cx <= maxChunk.X; cx++ {
+			coord := ChunkCoord{X: cx, Y: cy}
+			if _, ok := c.chunks[coord]; ok {
+				continue
+			}
+			tiles, err := c.readChunk(coord)
+			if err != nil {
+				return err
+			}
+			c.chunks[coord] = tiles
+		}
+	}
+	return nil
+}
+
+// IsLoaded reports if a chunk is in memory.
+func (c *ChunkedMap) IsLoaded(coord ChunkCoord) bool {
+	_, ok := c.chunks[coord]
+	return ok
+}
+
+// LoadedChunks is the number of chunks in memory.
+func (c *ChunkedMap) LoadedChunks() int {
+	return len(c.chunks)
+}
+
+// Flush saves every loaded chunk to the store, keeping them loaded.
+func (c *ChunkedMap) Flush() error {
+	if c.Store == nil {
+		return nil
+	}
+	for coord, tiles := range c.chunks {
+		if err := c.Store.SaveChunk(coord, tiles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readChunk loads a chunk from the store, or creates it if it was never saved.
+func (c *ChunkedMap) readChunk(coord ChunkCoord) ([][]Tile, error) {
+	if c.Store != nil {
+		tiles, ok, err := c.Store.LoadChunk(coord)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return tiles, nil
+		}
+	}
+	return c.newChunk(coord), nil
+}
+
+// newChunk creates a chunk of floor tiles, cut short at the map's edges.
+func (c *ChunkedMap) newChunk(coord ChunkCoord) [][]Tile {
+	originX, originY := coord.X*c.ChunkSize, coord.Y*c.ChunkSize
+	height := min(c.ChunkSize, c.Height-originY)
+	width := min(c.ChunkSize, c.Width-originX)
+	tiles := make([][]Tile, height)
+	for y := range tiles {
+		tiles[y] = make([]Tile, width)
+		for x := range tiles[y] {
+			tiles[y][x] = Tile{
+				Type:     FloorTile,
+				Pos:      Position{X: originX + x, Y: originY + y},
+				Textures: make([]*AnimatedTexture, 0),
+			}
+		}
+	}
+	return tiles
+}
+
+// floorDiv divides, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,138 @@
+package beam
+
+import (
+	"testing"
+)
+
+// memoryChunkStore keeps evicted chunks in a map, and counts the saves.
+type memoryChunkStore struct {
+	chunks map[ChunkCoord][][]Tile
+	saves  int
+}
+
+func (s *memoryChunkStore) LoadChunk(coord ChunkCoord) ([][]Tile, bool, error) {
+	tiles, ok := s.chunks[coord]
+	return tiles, ok, nil
+}
+
+func (s *memoryChunkStore) SaveChunk(coord ChunkCoord, tiles [][]Tile) error {
+	if s.chunks == nil {
+		s.chunks = make(map[ChunkCoord][][]Tile)
+	}
+	s.chunks[coord] = tiles
+	s.saves++
+	return nil
+}
+
+// newTestDenseMap creates a map of floor tiles, with a wall on every tile where x+y is a multiple of 7.
+func newTestDenseMap(width, height int) *Map {
+	m := newFloorMap(width, height)
+	for y := range m.Tiles {
+		for x := range m.Tiles[y] {
+			if (x+y)%7 == 0 {
+				m.Tiles[y][x].Type = WallTile
+			}
+		}
+	}
+	return m
+}
+
+// TestChunkedMapBoundaryAccess checks tiles either side of a chunk edge, and at the map's edges.
+func TestChunkedMapBoundaryAccess(t *testing.T) {
+	dense := newTestDenseMap(70, 40)
+	chunked := NewChunkedMapFromMap(dense, 32, nil)
+
+	positions := []Position{
+		{X: 0, Y: 0}, {X: 31, Y: 0}, {X: 32, Y: 0}, {X: 31, Y: 31}, {X: 32, Y: 32},
+		{X: 63, Y: 31}, {X: 64, Y: 32}, {X: 69, Y: 39}, {X: 0, Y: 39},
+	}
+	for _, pos := range positions {
+		tile := chunked.TileAt(pos)
+		if tile == nil {
+			t.Errorf("Expected a tile at %v", pos)
+			continue
+		}
+		if tile.Pos != pos || tile.Type != dense.Tiles[pos.Y][pos.X].Type {
+			t.Errorf("Expected the tile at %v to be a %s, got a %s at %v", pos, dense.Tiles[pos.Y][pos.X].Type, tile.Type, tile.Pos)
+		}
+	}
+
+	for _, pos := range []Position{{X: -1, Y: 0}, {X: 0, Y: -1}, {X: 70, Y: 0}, {X: 0, Y: 40}} {
+		if tile := chunked.TileAt(pos); tile != nil {
+			t.Errorf("Expected no tile off the map at %v, got %v", pos, tile.Pos)
+		}
+	}
+
+	if coord := chunked.ChunkOf(Position{X: 64, Y: 39}); coord != (ChunkCoord{X: 2, Y: 1}) {
+		t.Errorf("Expected (64, 39) to be in chunk 2,1, got %v", coord)
+	}
+	if coord := chunked.ChunkOf(Position{X: -1, Y: -32}); coord != (ChunkCoord{X: -1, Y: -1}) {
+		t.Errorf("Expected (-1, -32) to be in chunk -1,-1, got %v", coord)
+	}
+}
+
+// TestChunkedMapEviction checks far chunks are saved and dropped, and come back with their changes.
+func TestChunkedMapEviction(t *testing.T) {
+	store := &memoryChunkStore{}
+	chunked := NewChunkedMap(128, 128, 32, store)
+
+	if err := chunked.EnsureLoaded(Position{X: 10, Y: 10}, 5); err != nil {
+		t.Fatal(err)
+	}
+	if chunked.LoadedChunks() != 1 {
+		t.Fatalf("Expected 1 chunk loaded, got %d", chunked.LoadedChunks())
+	}
+	chunked.TileAt(Position{X: 3, Y: 4}).Type = WallTile
+
+	// A radius crossing a chunk edge loads both sides
+	if err := chunked.EnsureLoaded(Position{X: 100, Y: 100}, 5); err != nil {
+		t.Fatal(err)
+	}
+	if chunked.IsLoaded(ChunkCoord{X: 0, Y: 0}) {
+		t.Error("Expected the first chunk to be evicted")
+	}
+	if !chunked.IsLoaded(ChunkCoord{X: 2, Y: 2}) || !chunked.IsLoaded(ChunkCoord{X: 3, Y: 3}) || chunked.LoadedChunks() != 4 {
+		t.Errorf("Expected the 4 chunks around (100, 100) loaded, got %d", chunked.LoadedChunks())
+	}
+	if store.saves != 1 {
+		t.Errorf("Expected the evicted chunk to be saved once, got %d saves", store.saves)
+	}
+
+	if tile := chunked.TileAt(Position{X: 3, Y: 4}); tile == nil || tile.Type != WallTile {
+		t.Error("Expected the wall to be reloaded from the store")
+	}
+	if tile := chunked.TileAt(Position{X: 4, Y: 4}); tile == nil || tile.Type != FloorTile {
+		t.Error("Expected untouched tiles to stay floor")
+	}
+}
+
+// TestChunkedMapRoundTrip checks a dense map comes back the same, including evicted chunks and partial edge chunks.
+func TestChunkedMapRoundTrip(t *testing.T) {
+	dense := newTestDenseMap(50, 35)
+	store := &memoryChunkStore{}
+	chunked := NewChunkedMapFromMap(dense, 16, store)
+	if err := chunked.EnsureLoaded(Position{X: 0, Y: 0}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if chunked.LoadedChunks() != 1 {
+		t.Fatalf("Expected every chunk but the first evicted, got %d loaded", chunked.LoadedChunks())
+	}
+
+	restored, err := chunked.ToMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Width != 50 || restored.Height != 35 {
+		t.Fatalf("Expected a 50x35 map, got %dx%d", restored.Width, restored.Height)
+	}
+	for y := range dense.Tiles {
+		for x := range dense.Tiles[y] {
+			if restored.Tiles[y][x].Type != dense.Tiles[y][x].Type || restored.Tiles[y][x].Pos != dense.Tiles[y][x].Pos {
+				t.Fatalf("Expected tile (%d, %d) to match after a round trip", x, y)
+			}
+		}
+	}
+	if chunked.LoadedChunks() != 1 {
+		t.Errorf("Expected ToMap to leave evicted chunks in the store, got %d loaded", chunked.LoadedChunks())
+	}
+}
@@ -0,0 +1,66 @@
+package beam
+
+// DamageFunc computes the damage of a hit from the attacker's attack and the defender's defense.
+type DamageFunc func(attack, defense int) int
+
+// MinDamage is the least damage a hit can deal, so a high defense can't make an NPC invulnerable.
+var MinDamage = 1
+
+// DamageFormula is used by ResolveAttack, replace it to change how damage is calculated.
+var DamageFormula DamageFunc = SubtractiveDamage
+
+// SubtractiveDamage is the default damage formula, attack minus defense.
+func SubtractiveDamage(attack, defense int) int {
+	return attack - defense
+}
+
+// ResolveAttack applies a hit from attacker to defender with TakeDamage.
+// If the hit kills the defender, their ExperienceReward is awarded to the attacker,
+// otherwise the attacker's HitEffects are applied to the defender.
+func ResolveAttack(attacker, defender *NPC) (damage int, killed bool) {
+	if attacker == nil || defender == nil {
+		return 0, false
+	}
+	damage, killed = defender.TakeDamage(attacker.Runtime.Attack, attacker.Pos)
+	if killed {
+		attacker.GainExperience(defender.Data.ExperienceReward)
+	} else if damage > 0 {
+		for _, effect := range attacker.Data.HitEffects {
+			defender.AddStatusEffect(effect)
+		}
+	}
+	return damage, killed
+}
+
+// TakeDamage hits the NPC for amount, reduced by its defense with DamageFormula and floored at MinDamage.
+// The NPC is flagged as damaged for the hit animation, and knocked back away from the given position
+// on its next Update. If the hit brings its health to zero it is marked dead.
+// Returns the damage dealt, nothing if the NPC was already dead.
+func (npc *NPC) TakeDamage(amount int, from Position) (damage int, killed bool) {
+	if npc.Runtime.Dead {
+		return 0, false
+	}
+
+	damage = max(MinDamage, DamageFormula(amount, npc.Runtime.Defense))
+	npc.Runtime.TookDamageThisFrame = true
+	npc.Runtime.DamageFrames = 0
+	npc.Runtime.KnockbackFrom = &from
+	return damage, npc.loseHealth(damage)
+}
+
+// loseHealth takes damage off the NPC's health, calling OnDamage, and marks it dead
+// calling OnDeath if its health runs out. Returns true if the NPC died.
+func (npc *NPC) loseHealth(damage int) bool {
+	npc.Runtime.Health = max(0, npc.Runtime.Health-damage)
+	if npc.Data.OnDamage != nil {
+		npc.Data.OnDamage(npc, damage)
+	}
+	if npc.Runtime.Health > 0 {
+		return false
+	}
+	npc.Runtime.Dead = true
+	if npc.Data.OnDeath != nil {
+		npc.Data.OnDeath(npc)
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package beam
+
+import "testing"
+
+func newTestFighter(health, attack, defense, reward int) *NPC {
+	npc := &NPC{Data: NPCData{MaxHealth: health, BaseAttack: attack, BaseDefense: defense, ExperienceReward: reward}}
+	npc.ResetRuntime()
+	return npc
+}
+
+// TestResolveAttackMinDamage checks a defense higher than the attack still deals MinDamage.
+func TestResolveAttackMinDamage(t *testing.T) {
+	attacker := newTestFighter(10, 3, 0, 0)
+	defender := newTestFighter(10, 0, 8, 0)
+	damage, killed := ResolveAttack(attacker, defender)
+	if damage != MinDamage || killed {
+		t.Errorf("Expected %d damage without a kill, got %d (killed: %v)", MinDamage, damage, killed)
+	}
+	if defender.Runtime.Health != 10-MinDamage || !defender.Runtime.TookDamageThisFrame {
+		t.Errorf("Expected the defender to be damaged to %d, got %d", 10-MinDamage, defender.Runtime.Health)
+	}
+}
+
+// TestResolveAttackKill checks an exact kill and an overkill both leave the defender dead at zero health.
+func TestResolveAttackKill(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		attack int
+	}{
+		{"exact", 12},
+		{"overkill", 50},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attacker := newTestFighter(10, tc.attack, 0, 0)
+			defender := newTestFighter(10, 0, 2, 25)
+			damage, killed := ResolveAttack(attacker, defender)
+			if !killed || !defender.Runtime.Dead {
+				t.Fatalf("Expected the defender to be killed")
+			}
+			if damage != tc.attack-2 || defender.Runtime.Health != 0 {
+				t.Errorf("Expected %d damage and 0 health, got %d and %d", tc.attack-2, damage, defender.Runtime.Health)
+			}
+			if attacker.Runtime.Experience != 25 {
+				t.Errorf("Expected the attacker to gain 25 experience, got %d", attacker.Runtime.Experience)
+			}
+
+			// Hitting a dead NPC does nothing
+			if damage, killed := ResolveAttack(attacker, defender); damage != 0 || killed {
+				t.Errorf("Expected no damage to a dead NPC, got %d", damage)
+			}
+		})
+	}
+}
+
+// TestResolveAttackKillLeveledUp checks a defender that just leveled up is still worth its full reward,
+// even though its own experience rolled over to almost nothing.
+func TestResolveAttackKillLeveledUp(t *testing.T) {
+	defender := newTestFighter(10, 0, 0, 40)
+	if !defender.GainExperience(105) || defender.Runtime.Experience != 5 {
+		t.Fatalf("Expected the defender to level up with 5 experience left over, got %d", defender.Runtime.Experience)
+	}
+	attacker := newTestFighter(10, 100, 0, 0)
+	if _, killed := ResolveAttack(attacker, defender); !killed {
+		t.Fatalf("Expected the defender to be killed")
+	}
+	if attacker.Runtime.Experience != 40 {
+		t.Errorf("Expected the attacker to gain the 40 experience reward, got %d", attacker.Runtime.Experience)
+	}
+}
+
+// TestTakeDamageMitigation checks defense reduces a hit, and the NPC is set up to be knocked back from the source.
+func TestTakeDamageMitigation(t *testing.T) {
+	npc := newTestFighter(20, 0, 4, 0)
+	var hooked []int
+	npc.Data.OnDamage = func(_ *NPC, amount int) { hooked = append(hooked, amount) }
+
+	from := Position{X: 3, Y: 1}
+	damage, killed := npc.TakeDamage(10, from)
+	if damage != 6 || killed {
+		t.Errorf("Expected 6 damage without a kill, got %d (killed: %v)", damage, killed)
+	}
+	if npc.Runtime.Health != 14 || !npc.Runtime.TookDamageThisFrame {
+		t.Errorf("Expected the NPC to be damaged to 14, got %d", npc.Runtime.Health)
+	}
+	if npc.Runtime.KnockbackFrom == nil || *npc.Runtime.KnockbackFrom != from {
+		t.Errorf("Expected knockback away from %v, got %v", from, npc.Runtime.KnockbackFrom)
+	}
+
+	// A defense higher than the hit still deals MinDamage
+	if damage, _ := npc.TakeDamage(2, from); damage != MinDamage {
+		t.Errorf("Expected %d damage, got %d", MinDamage, damage)
+	}
+	if len(hooked) != 2 || hooked[0] != 6 || hooked[1] != MinDamage {
+		t.Errorf("Expected OnDamage with 6 then %d, got %v", MinDamage, hooked)
+	}
+}
+
+// TestTakeDamageDeath checks OnDeath is called once when a hit or a status effect kills the NPC.
+func TestTakeDamageDeath(t *testing.T) {
+	npc := newTestFighter(10, 0, 0, 0)
+	deaths := 0
+	npc.Data.OnDeath = func(dead *NPC) {
+		if dead != npc || !dead.Runtime.Dead {
+			t.Error("Expected OnDeath to get the dead NPC")
+		}
+		deaths++
+	}
+
+	if _, killed := npc.TakeDamage(25, Position{}); !killed || npc.Runtime.Health != 0 {
+		t.Fatalf("Expected the NPC to be killed at 0 health, got %d", npc.Runtime.Health)
+	}
+	if damage, killed := npc.TakeDamage(5, Position{}); damage != 0 || killed {
+		t.Errorf("Expected no damage to a dead NPC, got %d", damage)
+	}
+	if deaths != 1 {
+		t.Errorf("Expected OnDeath to be called once, got %d", deaths)
+	}
+
+	// Damage over time triggers the hooks too
+	poisoned := newTestFighter(3, 0, 0, 0)
+	poisonDeaths := 0
+	poisoned.Data.OnDeath = func(*NPC) { poisonDeaths++ }
+	poisoned.takeEffectDamage(5)
+	if !poisoned.Runtime.Dead || poisonDeaths != 1 {
+		t.Errorf("Expected effect damage to kill the NPC and call OnDeath, got dead %v and %d calls", poisoned.Runtime.Dead, poisonDeaths)
+	}
+}
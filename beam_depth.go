@@ -0,0 +1,93 @@
+package beam
+
+import "sort"
+
+// Positioned is something drawn on the map, like an NPC or an item, that SortByDepth can order.
+type Positioned interface {
+	// DepthPos is the tile the entity stands on, the bottom row for NPCs that cover several tiles.
+	DepthPos() Position
+	// DepthLayer is the layer the entity is drawn on, which orders entities on the same row.
+	DepthLayer() Layer
+}
+
+// SortByDepth orders entities back to front, so ones lower on screen are drawn over the ones behind them.
+// Entities are sorted by row, then by layer in OrderedLayers order. Ties keep their order.
+func SortByDepth(entities []Positioned) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		if a.DepthPos().Y != b.DepthPos().Y {
+			return a.DepthPos().Y < b.DepthPos().Y
+		}
+		return layerOrder(a.DepthLayer()) < layerOrder(b.DepthLayer())
+	})
+}
+
+// RenderOrder returns the map's NPCs and items, skipping picked up items, sorted with SortByDepth.
+// Dead NPCs are included, so they can fade out.
+func (m *Map) RenderOrder() []Positioned {
+	entities := make([]Positioned, 0, len(m.NPCs)+len(m.Items))
+	for _, item := range m.Items {
+		if item != nil && !item.Removed {
+			entities = append(entities, item)
+		}
+	}
+	for _, npc := range m.NPCs {
+		if npc != nil {
+			entities = append(entities, npc)
+		}
+	}
+	SortByDepth(entities)
+	return entities
+}
+
+// DepthPos is the bottom left tile the NPC covers.
+func (npc *NPC) DepthPos() Position {
+	_, height := npc.Data.Size.GetDimensions()
+	return Position{X: npc.Pos.X, Y: npc.Pos.Y + height - 1}
+}
+
+// DepthLayer is the layer of the NPC's texture for the direction it's facing.
+func (npc *NPC) DepthLayer() Layer {
+	tex := npc.Data.Texture
+	if tex == nil {
+		return BaseLayer
+	}
+	var dirTex *AnimatedTexture
+	switch npc.Runtime.Direction {
+	case DirUp:
+		dirTex = tex.Up
+	case DirLeft:
+		dirTex = tex.Left
+	case DirRight:
+		dirTex = tex.Right
+	default:
+		dirTex = tex.Down
+	}
+	if dirTex == nil {
+		return BaseLayer
+	}
+	return dirTex.Layer
+}
+
+// DepthPos is the tile the item is on.
+func (i *Item) DepthPos() Position {
+	return i.Pos
+}
+
+// DepthLayer is the layer of the item's texture.
+func (i *Item) DepthLayer() Layer {
+	if i.Texture == nil {
+		return BaseLayer
+	}
+	return i.Texture.Layer
+}
+
+// layerOrder is where a layer is drawn in OrderedLayers, unknown layers are drawn last.
+func layerOrder(layer Layer) int {
+	for i, l := range OrderedLayers() {
+		if l == layer {
+			return i
+		}
+	}
+	return len(OrderedLayers())
+}
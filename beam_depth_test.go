@@ -0,0 +1,43 @@
+package beam
+
+import "testing"
+
+// TestSortByDepth checks entities are ordered by row, then by draw layer on the same row.
+func TestSortByDepth(t *testing.T) {
+	item := func(id string, y int, layer Layer) *Item {
+		i := NewItem(id, id, ItemTypeMisc).WithTexture(&AnimatedTexture{Layer: layer})
+		i.Pos = Position{X: 1, Y: y}
+		return i
+	}
+	front := item("front", 3, BackgroundLayer)
+	back := item("back", 1, ForegroundLayer)
+	rowBase := item("row base", 2, BaseLayer)
+	rowForeground := item("row foreground", 2, ForegroundLayer)
+	rowBackground := item("row background", 2, BackgroundLayer)
+
+	entities := []Positioned{front, rowForeground, back, rowBase, rowBackground}
+	SortByDepth(entities)
+	want := []*Item{back, rowBackground, rowBase, rowForeground, front}
+	for i, entity := range entities {
+		if entity != want[i] {
+			t.Errorf("Expected %q at %d, got %q", want[i].ID, i, entity.(*Item).ID)
+		}
+	}
+}
+
+// TestMapRenderOrder checks a large NPC sorts by its bottom row, and picked up items are left out.
+func TestMapRenderOrder(t *testing.T) {
+	npc := &NPC{Pos: Position{X: 0, Y: 0}, Data: NPCData{Size: NPCSize3x3}}
+	above := NewItem("above", "Above", ItemTypeMisc)
+	above.Pos = Position{X: 4, Y: 1}
+	below := NewItem("below", "Below", ItemTypeMisc)
+	below.Pos = Position{X: 4, Y: 3}
+	removed := NewItem("removed", "Removed", ItemTypeMisc)
+	removed.Removed = true
+	m := &Map{NPCs: NPCs{npc}, Items: Items{below, removed, above}}
+
+	order := m.RenderOrder()
+	if len(order) != 3 || order[0] != above || order[1] != npc || order[2] != below {
+		t.Errorf("Expected the item above, the NPC, then the item below, got %v", order)
+	}
+}
@@ -0,0 +1,133 @@
+package beam
+
+// StatusEffectKind is the kind of status effect applied to an NPC or the player.
+type StatusEffectKind int
+
+const (
+	StatusPoison StatusEffectKind = iota
+	StatusBurn
+	StatusSlow
+	StatusStun
+)
+
+// StatusEffect is a debuff that lasts for Remaining seconds.
+// Poison and burn deal Magnitude damage every TickInterval seconds.
+// Slow reduces move and attack speed by Magnitude, as a fraction between 0 and 1.
+// Stun stops the NPC or player from moving and attacking.
+type StatusEffect struct {
+	Kind         StatusEffectKind `json:"Kind"`
+	Magnitude    float32          `json:"Magnitude"`
+	Remaining    float32          `json:"Remaining"`
+	TickInterval float32          `json:"TickInterval,omitempty"`
+
+	sinceTick float32
+
+	// What a slow took off the NPC's speeds, added back when it expires
+	moveSpeedDelta   float64
+	attackSpeedDelta float64
+}
+
+// AddStatusEffect applies a status effect to the NPC, until TickEffects expires it.
+// Slows take effect immediately, damage is dealt on each tick.
+func (npc *NPC) AddStatusEffect(effect StatusEffect) {
+	if npc.Runtime.Dead || effect.Remaining <= 0 {
+		return
+	}
+	if effect.Kind == StatusSlow {
+		slow := float64(max(0, min(1, effect.Magnitude)))
+		effect.moveSpeedDelta = -npc.Runtime.MoveSpeed * slow
+		effect.attackSpeedDelta = -npc.Runtime.AttackSpeed * slow
+		npc.Runtime.MoveSpeed += effect.moveSpeedDelta
+		npc.Runtime.AttackSpeed += effect.attackSpeedDelta
+	}
+	npc.Runtime.StatusEffects = append(npc.Runtime.StatusEffects, effect)
+}
+
+// TickEffects advances the NPC's item buffs and status effects by dt seconds.
+// Poison and burn deal their damage for every tick that falls within their duration.
+// Expired effects are removed, and anything they changed is restored.
+func (npc *NPC) TickEffects(dt float32) {
+	active := npc.Runtime.ActiveEffects[:0]
+	for _, effect := range npc.Runtime.ActiveEffects {
+		effect.TimeRemaining -= float64(dt)
+		if effect.TimeRemaining <= 0 {
+			npc.adjustStat(effect.Type, -effect.Value)
+			continue
+		}
+		active = append(active, effect)
+	}
+	npc.Runtime.ActiveEffects = active
+
+	npc.Runtime.StatusEffects = tickStatusEffects(npc.Runtime.StatusEffects, dt, npc.takeEffectDamage, func(effect StatusEffect) {
+		npc.Runtime.MoveSpeed -= effect.moveSpeedDelta
+		npc.Runtime.AttackSpeed -= effect.attackSpeedDelta
+	})
+}
+
+// tickStatusEffects advances status effects by dt seconds, dealing poison and burn damage for each tick.
+// Expired effects are passed to expire and dropped, the rest are returned.
+func tickStatusEffects(effects []StatusEffect, dt float32, damage func(int), expire func(StatusEffect)) []StatusEffect {
+	active := effects[:0]
+	for _, effect := range effects {
+		if (effect.Kind == StatusPoison || effect.Kind == StatusBurn) && effect.TickInterval > 0 {
+			effect.sinceTick += min(dt, effect.Remaining)
+			for effect.sinceTick >= effect.TickInterval {
+				effect.sinceTick -= effect.TickInterval
+				damage(int(effect.Magnitude))
+			}
+		}
+
+		effect.Remaining -= dt
+		if effect.Remaining <= 0 {
+			expire(effect)
+			continue
+		}
+		active = append(active, effect)
+	}
+	return active
+}
+
+// hasStatusEffect reports if any of the effects is of the given kind.
+func hasStatusEffect(effects []StatusEffect, kind StatusEffectKind) bool {
+	for _, effect := range effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStatusEffect reports if the NPC is under a status effect of the given kind.
+func (npc *NPC) HasStatusEffect(kind StatusEffectKind) bool {
+	return hasStatusEffect(npc.Runtime.StatusEffects, kind)
+}
+
+// takeEffectDamage deals damage over time. Unlike a hit, it doesn't trigger knockback.
+func (npc *NPC) takeEffectDamage(damage int) {
+	if npc.Runtime.Dead || damage <= 0 {
+		return
+	}
+	npc.loseHealth(damage)
+}
+
+// AddStatusEffect applies a status effect to the player, until TickEffects expires it.
+// The player has no speed stats, so a slow only shows up in HasStatusEffect.
+func (p *Player) AddStatusEffect(effect StatusEffect) {
+	if p.Stats.Health <= 0 || effect.Remaining <= 0 {
+		return
+	}
+	p.StatusEffects = append(p.StatusEffects, effect)
+}
+
+// TickEffects advances the player's status effects by dt seconds, dealing poison and burn damage.
+// Call it once per frame, expired effects are removed.
+func (p *Player) TickEffects(dt float32) {
+	p.StatusEffects = tickStatusEffects(p.StatusEffects, dt, func(damage int) {
+		p.Stats.Health = max(0, p.Stats.Health-damage)
+	}, func(StatusEffect) {})
+}
+
+// HasStatusEffect reports if the player is under a status effect of the given kind.
+func (p *Player) HasStatusEffect(kind StatusEffectKind) bool {
+	return hasStatusEffect(p.StatusEffects, kind)
+}
@@ -0,0 +1,123 @@
+package beam
+
+import "testing"
+
+// TestPoisonTicks checks poison deals its damage once per tick for its duration, then expires.
+func TestPoisonTicks(t *testing.T) {
+	npc := newTestTarget()
+	npc.AddStatusEffect(StatusEffect{Kind: StatusPoison, Magnitude: 5, Remaining: 3, TickInterval: 1})
+
+	for i := 0; i < 10; i++ {
+		npc.TickEffects(0.5)
+	}
+	if npc.Runtime.Health != 85 {
+		t.Errorf("Expected 3 ticks of 5 damage, got %d health", npc.Runtime.Health)
+	}
+	if npc.HasStatusEffect(StatusPoison) {
+		t.Errorf("Expected the poison to expire")
+	}
+
+	// A single long frame still deals every tick within the duration
+	npc.AddStatusEffect(StatusEffect{Kind: StatusBurn, Magnitude: 10, Remaining: 2, TickInterval: 0.5})
+	npc.TickEffects(5)
+	if npc.Runtime.Health != 45 {
+		t.Errorf("Expected 4 ticks of 10 damage, got %d health", npc.Runtime.Health)
+	}
+}
+
+// TestPoisonKills checks damage over time marks the NPC dead at zero health.
+func TestPoisonKills(t *testing.T) {
+	npc := newTestTarget()
+	npc.Runtime.Health = 8
+	npc.AddStatusEffect(StatusEffect{Kind: StatusPoison, Magnitude: 5, Remaining: 10, TickInterval: 1})
+	npc.TickEffects(3)
+	if npc.Runtime.Health != 0 || !npc.Runtime.Dead {
+		t.Errorf("Expected the NPC to die at 0 health, got %d, dead %v", npc.Runtime.Health, npc.Runtime.Dead)
+	}
+}
+
+// TestSlowRestoresSpeed checks a slow reduces move and attack speed, and restores them when it expires.
+func TestSlowRestoresSpeed(t *testing.T) {
+	npc := newTestTarget()
+	npc.Data.MoveSpeed = 4
+	npc.Data.BaseAttackSpeed = 2
+	npc.ResetRuntime()
+
+	npc.AddStatusEffect(StatusEffect{Kind: StatusSlow, Magnitude: 0.5, Remaining: 2})
+	if npc.Runtime.MoveSpeed != 2 || npc.Runtime.AttackSpeed != 1 {
+		t.Fatalf("Expected speeds halved to 2 and 1, got %v and %v", npc.Runtime.MoveSpeed, npc.Runtime.AttackSpeed)
+	}
+
+	npc.TickEffects(1)
+	if !npc.HasStatusEffect(StatusSlow) || npc.Runtime.MoveSpeed != 2 {
+		t.Errorf("Expected the slow to still be active")
+	}
+	npc.TickEffects(1)
+	if npc.HasStatusEffect(StatusSlow) {
+		t.Errorf("Expected the slow to expire")
+	}
+	if npc.Runtime.MoveSpeed != 4 || npc.Runtime.AttackSpeed != 2 {
+		t.Errorf("Expected speeds restored to 4 and 2, got %v and %v", npc.Runtime.MoveSpeed, npc.Runtime.AttackSpeed)
+	}
+}
+
+// TestStunStopsActing checks a stunned NPC can't attack, and a stunned player can't move until it wears off.
+func TestStunStopsActing(t *testing.T) {
+	npc := newTestTarget()
+	npc.Data.Hostile = true
+	npc.Runtime.AttackRange = 1
+	npc.AddStatusEffect(StatusEffect{Kind: StatusStun, Remaining: 1})
+	if npc.Attack(Position{X: 1, Y: 0}) {
+		t.Errorf("Expected a stunned NPC not to attack")
+	}
+	if target, _, _ := npc.AttackEnemies(&Map{}); target != nil {
+		t.Errorf("Expected a stunned NPC not to attack enemies")
+	}
+
+	m := newTestSpawnMap()
+	player := NewPlayer(Position{X: 1, Y: 1}, PlayerStats{MaxHealth: 10})
+	player.AddStatusEffect(StatusEffect{Kind: StatusStun, Remaining: 1})
+	if player.Move(DirDown, m) || player.Pos != (Position{X: 1, Y: 1}) {
+		t.Errorf("Expected a stunned player not to move, got %v", player.Pos)
+	}
+	player.TickEffects(1)
+	if player.HasStatusEffect(StatusStun) || !player.Move(DirDown, m) {
+		t.Errorf("Expected the player to move once the stun expires")
+	}
+}
+
+// TestPlayerPoisonTicks checks poison damages the player once per tick, without going below zero health.
+func TestPlayerPoisonTicks(t *testing.T) {
+	player := NewPlayer(Position{}, PlayerStats{MaxHealth: 20})
+	player.AddStatusEffect(StatusEffect{Kind: StatusPoison, Magnitude: 4, Remaining: 2, TickInterval: 0.5})
+	player.TickEffects(1)
+	if player.Stats.Health != 12 || !player.HasStatusEffect(StatusPoison) {
+		t.Errorf("Expected 2 ticks of 4 damage, got %d health", player.Stats.Health)
+	}
+	player.TickEffects(1)
+	if player.Stats.Health != 4 || player.HasStatusEffect(StatusPoison) {
+		t.Errorf("Expected 2 more ticks and the poison to expire, got %d health", player.Stats.Health)
+	}
+
+	player.AddStatusEffect(StatusEffect{Kind: StatusBurn, Magnitude: 10, Remaining: 1, TickInterval: 1})
+	player.TickEffects(1)
+	if player.Stats.Health != 0 {
+		t.Errorf("Expected health to stop at 0, got %d", player.Stats.Health)
+	}
+}
+
+// TestHitEffects checks an attacker's HitEffects are applied to a defender that survives the hit.
+func TestHitEffects(t *testing.T) {
+	attacker := newTestFighter(10, 5, 0, 0)
+	attacker.Data.HitEffects = []StatusEffect{{Kind: StatusPoison, Magnitude: 2, Remaining: 3, TickInterval: 1}}
+	defender := newTestFighter(20, 0, 0, 0)
+	if _, killed := ResolveAttack(attacker, defender); killed {
+		t.Fatal("Expected the defender to survive")
+	}
+	if !defender.HasStatusEffect(StatusPoison) {
+		t.Errorf("Expected the defender to be poisoned")
+	}
+	if attacker.HasStatusEffect(StatusPoison) {
+		t.Errorf("Expected the attacker not to be poisoned")
+	}
+}
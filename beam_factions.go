@@ -0,0 +1,84 @@
+package beam
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FactionRelation is how NPCs of two factions treat each other.
+type FactionRelation int
+
+const (
+	FactionNeutral FactionRelation = iota
+	FactionHostile
+	FactionFriendly
+)
+
+// FactionRelations maps a pair of factions to their relation, see Map.SetFactionRelation.
+type FactionRelations map[string]map[string]FactionRelation
+
+// SetFactionRelation sets how two factions treat each other. Relations are always mutual.
+func (m *Map) SetFactionRelation(a, b string, relation FactionRelation) {
+	if m.Factions == nil {
+		m.Factions = make(FactionRelations)
+	}
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if m.Factions[pair[0]] == nil {
+			m.Factions[pair[0]] = make(map[string]FactionRelation)
+		}
+		m.Factions[pair[0]][pair[1]] = relation
+	}
+}
+
+// FactionRelation returns how two factions treat each other.
+// A faction is always friendly to itself, NPCs without a faction are neutral to everyone,
+// and factions without a relation set are neutral.
+func (m *Map) FactionRelation(a, b string) FactionRelation {
+	if a == "" || b == "" {
+		return FactionNeutral
+	}
+	if a == b {
+		return FactionFriendly
+	}
+	return m.Factions[a][b]
+}
+
+// AreHostile reports if two living NPCs belong to factions that will fight each other.
+func (m *Map) AreHostile(a, b *NPC) bool {
+	if a == nil || b == nil || a == b || a.Runtime.Dead || b.Runtime.Dead {
+		return false
+	}
+	return m.FactionRelation(a.Data.Faction, b.Data.Faction) == FactionHostile
+}
+
+// NearestEnemy returns the closest NPC hostile to npc, within the given number of tiles.
+// Returns nil if there isn't one.
+func (m *Map) NearestEnemy(npc *NPC, within int) *NPC {
+	var nearest *NPC
+	nearestDist := within + 1
+	for _, other := range m.NPCs {
+		if !m.AreHostile(npc, other) {
+			continue
+		}
+		if dist := other.distanceToNPC(npc.Pos.X, npc.Pos.Y); dist < nearestDist {
+			nearest = other
+			nearestDist = dist
+		}
+	}
+	return nearest
+}
+
+// AttackEnemies attacks the nearest hostile NPC within attack range, once the attack cooldown has passed.
+// Returns the NPC that was hit, or nil, along with the result of ResolveAttack.
+func (npc *NPC) AttackEnemies(currMap *Map) (target *NPC, damage int, killed bool) {
+	if npc.Runtime.Dead || npc.Runtime.AttackState != AttackIdle || npc.HasStatusEffect(StatusStun) {
+		return nil, 0, false
+	}
+	target = currMap.NearestEnemy(npc, int(math.Round(npc.Runtime.AttackRange)))
+	if target == nil || !npc.startAttack(target.Pos, float32(rl.GetTime())) {
+		return nil, 0, false
+	}
+	damage, killed = ResolveAttack(npc, target)
+	return target, damage, killed
+}
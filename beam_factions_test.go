@@ -0,0 +1,79 @@
+package beam
+
+import "testing"
+
+func newTestFactionMap(npcs ...*NPC) *Map {
+	m := &Map{NPCs: npcs}
+	m.SetFactionRelation("goblins", "wolves", FactionHostile)
+	m.SetFactionRelation("goblins", "villagers", FactionFriendly)
+	return m
+}
+
+func newTestMember(faction string, x, y int) *NPC {
+	npc := newTestFighter(20, 5, 1, 0)
+	npc.Data.Faction = faction
+	npc.Data.BaseAttackRange = 1
+	npc.Data.BaseAttackSpeed = 1
+	npc.ResetRuntime()
+	npc.Pos = Position{X: x, Y: y}
+	return npc
+}
+
+// TestHostileFactionsEngage checks NPCs from hostile factions target and damage each other.
+func TestHostileFactionsEngage(t *testing.T) {
+	goblin := newTestMember("goblins", 1, 1)
+	wolf := newTestMember("wolves", 2, 1)
+	farWolf := newTestMember("wolves", 8, 8)
+	m := newTestFactionMap(goblin, wolf, farWolf)
+
+	if !m.AreHostile(goblin, wolf) || !m.AreHostile(wolf, goblin) {
+		t.Fatalf("Expected goblins and wolves to be hostile both ways")
+	}
+	if m.NearestEnemy(goblin, 20) != wolf {
+		t.Errorf("Expected the goblin to target the nearest wolf")
+	}
+	if m.NearestEnemy(farWolf, 3) != nil {
+		t.Errorf("Expected no target outside the given range")
+	}
+
+	target := m.NearestEnemy(goblin, int(goblin.Runtime.AttackRange))
+	if target != wolf || !goblin.startAttack(target.Pos, 10) {
+		t.Fatalf("Expected the goblin to attack the wolf in range")
+	}
+	if goblin.Runtime.Direction != DirRight {
+		t.Errorf("Expected the goblin to face the wolf")
+	}
+	if damage, _ := ResolveAttack(goblin, target); damage != 4 || wolf.Runtime.Health != 16 {
+		t.Errorf("Expected 4 damage leaving 16 health, got %d and %d", damage, wolf.Runtime.Health)
+	}
+	if goblin.startAttack(target.Pos, 10.5) {
+		t.Errorf("Expected the attack cooldown to apply")
+	}
+
+	wolf.Runtime.Dead = true
+	if m.AreHostile(goblin, wolf) || m.NearestEnemy(goblin, 20) != farWolf {
+		t.Errorf("Expected dead NPCs to be ignored")
+	}
+}
+
+// TestSameFactionIgnored checks NPCs don't target their own faction, allies, neutrals, or unaligned NPCs.
+func TestSameFactionIgnored(t *testing.T) {
+	goblin := newTestMember("goblins", 1, 1)
+	other := newTestMember("goblins", 2, 1)
+	villager := newTestMember("villagers", 1, 2)
+	bandit := newTestMember("bandits", 3, 1)
+	loner := newTestMember("", 1, 3)
+	m := newTestFactionMap(goblin, other, villager, bandit, loner)
+
+	if m.FactionRelation("goblins", "goblins") != FactionFriendly || m.FactionRelation("villagers", "goblins") != FactionFriendly {
+		t.Errorf("Expected goblins to be friendly with themselves and villagers")
+	}
+	if m.FactionRelation("goblins", "bandits") != FactionNeutral || m.FactionRelation("", "") != FactionNeutral {
+		t.Errorf("Expected unset relations and unaligned NPCs to be neutral")
+	}
+	for _, npc := range m.NPCs {
+		if enemy := m.NearestEnemy(npc, 20); enemy != nil {
+			t.Errorf("Expected %s to have no enemies, got %s", npc.Data.Faction, enemy.Data.Faction)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultInventoryCapacity is the number of carried item slots in a new inventory.
+const DefaultInventoryCapacity = 20
+
+var (
+	ErrNotEquippable = errors.New("item can't be equipped")
+	ErrLevelTooLow   = errors.New("level too low to equip item")
+	ErrNotConsumable = errors.New("item can't be used")
+	ErrInvalidSlot   = errors.New("no item in inventory slot")
+)
+
+// Inventory holds the items a player is carrying, and the item equipped in each slot.
+// Slots are keyed by EquipmentType, so only one weapon, armor, accessory and shield can be worn at once.
+// Capacity limits the number of carried stacks, a Capacity of 0 or less is unlimited.
+type Inventory struct {
+	Items    Items                   `json:"Items"`
+	Equipped map[EquipmentType]*Item `json:"Equipped,omitempty"`
+	Level    int                     `json:"Level"`
+	Capacity int                     `json:"Capacity,omitempty"`
+}
+
+func NewInventory(level int) *Inventory {
+	return &Inventory{
+		Items:    Items{},
+		Equipped: make(map[EquipmentType]*Item),
+		Level:    level,
+		Capacity: DefaultInventoryCapacity,
+	}
+}
+
+// AddItem picks up an item, merging it into existing stacks with the same ID up to MaxStack.
+// Anything left over goes into new stacks, while there's room.
+// Non-stackable items always take a slot each. Returns the quantity that didn't fit.
+func (inv *Inventory) AddItem(item Item) (leftover int) {
+	remaining := max(item.Quantity, 1)
+	maxStack := item.MaxStack
+	if !item.Stackable || maxStack < 1 {
+		maxStack = 1
+	}
+
+	if item.Stackable {
+		for _, stack := range inv.Items {
+			if remaining == 0 {
+				break
+			}
+			if stack.ID != item.ID || !stack.Stackable || stack.Quantity >= maxStack {
+				continue
+			}
+			added := min(remaining, maxStack-stack.Quantity)
+			stack.Quantity += added
+			remaining -= added
+		}
+	}
+
+	for remaining > 0 && !inv.IsFull() {
+		stack := item
+		stack.Quantity = min(remaining, maxStack)
+		inv.Items = append(inv.Items, &stack)
+		remaining -= stack.Quantity
+	}
+	return remaining
+}
+
+// IsFull reports if every carried item slot is taken.
+func (inv *Inventory) IsFull() bool {
+	return inv.Capacity > 0 && len(inv.Items) >= inv.Capacity
+}
+
+// Equip puts the item in the slot for its EquipmentType, and returns the item it replaced, if any.
+// The item is taken out of the carried items, and the replaced item is put back.
+func (inv *Inventory) Equip(item *Item) (*Item, error) {
+	if item == nil || item.Type != ItemTypeEquipment || item.EquipmentType == EquipmentTypeNone {
+		return nil, ErrNotEquippable
+	}
+	if inv.Level < item.Requirements.Level {
+		return nil, fmt.Errorf("%w: %s requires level %d, have %d", ErrLevelTooLow, item.Name, item.Requirements.Level, inv.Level)
+	}
+
+	previous := inv.Equipped[item.EquipmentType]
+	if previous == item {
+		return nil, nil
+	}
+	inv.removeItem(item)
+	inv.Equipped[item.EquipmentType] = item
+	if previous != nil {
+		inv.Items = append(inv.Items, previous)
+	}
+	return previous, nil
+}
+
+// Unequip empties a slot, moving its item back to the carried items.
+// Returns nil if nothing was equipped in the slot.
+func (inv *Inventory) Unequip(slot EquipmentType) *Item {
+	item := inv.Equipped[slot]
+	if item == nil {
+		return nil
+	}
+	delete(inv.Equipped, slot)
+	inv.Items = append(inv.Items, item)
+	return item
+}
+
+// EquippedIn returns the item equipped in a slot, or nil if it's empty.
+func (inv *Inventory) EquippedIn(slot EquipmentType) *Item {
+	return inv.Equipped[slot]
+}
+
+// TotalBonuses sums the stats of every equipped item.
+func (inv *Inventory) TotalBonuses() ItemStats {
+	var total ItemStats
+	for _, slot := range AllEquipmentTypes() {
+		item := inv.Equipped[slot]
+		if item == nil {
+			continue
+		}
+		total.Attack += item.Stats.Attack
+		total.Defense += item.Stats.Defense
+		total.AttackSpeed += item.Stats.AttackSpeed
+		total.AttackRange += item.Stats.AttackRange
+		total.Effects = append(total.Effects, item.Stats.Effects...)
+	}
+	return total
+}
+
+func (inv *Inventory) removeItem(item *Item) {
+	for i := range inv.Items {
+		if inv.Items[i] == item {
+			inv.Items = append(inv.Items[:i], inv.Items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Use consumes one of the item in a carried slot, applying its effects to the target.
+// The item is removed once its quantity reaches zero.
+func (inv *Inventory) Use(slot int, target *NPC) error {
+	if slot < 0 || slot >= len(inv.Items) {
+		return fmt.Errorf("%w: %d", ErrInvalidSlot, slot)
+	}
+	item := inv.Items[slot]
+	if !item.Consumable {
+		return fmt.Errorf("%w: %s is not consumable", ErrNotConsumable, item.Name)
+	}
+	if target == nil {
+		return fmt.Errorf("no target to use %s on", item.Name)
+	}
+
+	for _, effect := range item.Stats.Effects {
+		target.ApplyEffect(effect)
+	}
+	item.Quantity--
+	if item.Quantity <= 0 {
+		inv.Items = append(inv.Items[:slot], inv.Items[slot+1:]...)
+	}
+	return nil
+}
+
+// ApplyEffect applies an item effect to the NPC's runtime stats.
+// Healing is clamped at MaxHealth, buffs with a Duration are tracked until TickEffects expires them.
+func (npc *NPC) ApplyEffect(effect ItemEffect) {
+	if effect.Type == EffectHealth {
+		npc.Runtime.Health = min(npc.Runtime.MaxHealth, npc.Runtime.Health+int(effect.Value))
+		return
+	}
+	npc.adjustStat(effect.Type, effect.Value)
+	if effect.Duration > 0 {
+		effect.TimeRemaining = effect.Duration
+		npc.Runtime.ActiveEffects = append(npc.Runtime.ActiveEffects, effect)
+	}
+}
+
+func (npc *NPC) adjustStat(effectType EffectType, value float64) {
+	switch effectType {
+	case EffectAttack:
+		npc.Runtime.Attack += int(value)
+	case EffectDefense:
+		npc.Runtime.Defense += int(value)
+	case EffectSpeed:
+		npc.Runtime.AttackSpeed += value
+	}
+}
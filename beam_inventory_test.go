@@ -0,0 +1,208 @@
+package beam
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestWeapon(id string, attack, level int) *Item {
+	item := NewItem(id, id, ItemTypeEquipment).
+		WithStats(ItemStats{Attack: attack, AttackSpeed: 1}).
+		WithRequirements(ItemRequirements{Level: level})
+	item.EquipmentType = EquipmentTypeWeapon
+	return item
+}
+
+// TestInventoryEquipSwap equips two weapons in turn, and checks the first is returned and put back.
+func TestInventoryEquipSwap(t *testing.T) {
+	inv := NewInventory(1)
+	dagger := newTestWeapon("dagger", 2, 0)
+	sword := newTestWeapon("sword", 5, 0)
+	inv.Items = Items{dagger, sword}
+
+	previous, err := inv.Equip(dagger)
+	if err != nil || previous != nil {
+		t.Fatalf("Expected an empty slot, got %v, %v", previous, err)
+	}
+	previous, err = inv.Equip(sword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous != dagger {
+		t.Errorf("Expected the dagger to be swapped out, got %v", previous)
+	}
+	if inv.EquippedIn(EquipmentTypeWeapon) != sword {
+		t.Errorf("Expected the sword to be equipped")
+	}
+	if len(inv.Items) != 1 || inv.Items[0] != dagger {
+		t.Errorf("Expected only the dagger to be carried, got %d items", len(inv.Items))
+	}
+
+	if inv.Unequip(EquipmentTypeWeapon) != sword || inv.EquippedIn(EquipmentTypeWeapon) != nil {
+		t.Errorf("Expected unequip to empty the weapon slot")
+	}
+
+	potion := NewItem("potion", "Potion", ItemTypeConsumable)
+	if _, err := inv.Equip(potion); !errors.Is(err, ErrNotEquippable) {
+		t.Errorf("Expected ErrNotEquippable for a consumable, got %v", err)
+	}
+}
+
+// TestInventoryLevelGate checks an item can't be equipped below its level requirement.
+func TestInventoryLevelGate(t *testing.T) {
+	inv := NewInventory(4)
+	axe := newTestWeapon("axe", 8, 5)
+	if _, err := inv.Equip(axe); !errors.Is(err, ErrLevelTooLow) {
+		t.Fatalf("Expected ErrLevelTooLow, got %v", err)
+	}
+	if inv.EquippedIn(EquipmentTypeWeapon) != nil {
+		t.Errorf("Expected the weapon slot to stay empty")
+	}
+
+	inv.Level = 5
+	if _, err := inv.Equip(axe); err != nil {
+		t.Errorf("Expected the axe to be equipped at level 5, got %v", err)
+	}
+}
+
+// TestInventoryTotalBonuses checks stats are summed across every slot.
+func TestInventoryTotalBonuses(t *testing.T) {
+	inv := NewInventory(1)
+	sword := newTestWeapon("sword", 5, 0)
+	armor := NewItem("armor", "Armor", ItemTypeEquipment).WithStats(ItemStats{Defense: 4, AttackSpeed: -1})
+	armor.EquipmentType = EquipmentTypeArmor
+	ring := NewItem("ring", "Ring", ItemTypeEquipment).WithStats(ItemStats{Attack: 1, AttackRange: 2})
+	ring.EquipmentType = EquipmentTypeAccessory
+
+	for _, item := range []*Item{sword, armor, ring} {
+		if _, err := inv.Equip(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := inv.TotalBonuses()
+	want := ItemStats{Attack: 6, Defense: 4, AttackSpeed: 0, AttackRange: 2}
+	if got.Attack != want.Attack || got.Defense != want.Defense || got.AttackSpeed != want.AttackSpeed || got.AttackRange != want.AttackRange {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func newTestPotion(quantity int) Item {
+	potion := NewItem("potion", "Potion", ItemTypeConsumable).AsConsumable(true)
+	potion.MaxStack = 10
+	potion.Quantity = quantity
+	return *potion
+}
+
+// TestInventoryStackMerge checks a pickup tops up an existing stack before starting a new one.
+func TestInventoryStackMerge(t *testing.T) {
+	inv := NewInventory(1)
+	if leftover := inv.AddItem(newTestPotion(4)); leftover != 0 {
+		t.Fatalf("Expected no leftover, got %d", leftover)
+	}
+	if leftover := inv.AddItem(newTestPotion(3)); leftover != 0 {
+		t.Fatalf("Expected no leftover, got %d", leftover)
+	}
+	if len(inv.Items) != 1 || inv.Items[0].Quantity != 7 {
+		t.Fatalf("Expected a single stack of 7, got %d stacks", len(inv.Items))
+	}
+
+	// 3 fit in the first stack, the rest overflow into full stacks of 10, and a partial stack
+	inv.AddItem(newTestPotion(25))
+	var quantities []int
+	for _, stack := range inv.Items {
+		quantities = append(quantities, stack.Quantity)
+	}
+	if !reflect.DeepEqual(quantities, []int{10, 10, 10, 2}) {
+		t.Errorf("Expected stacks of [10 10 10 2], got %v", quantities)
+	}
+
+	// Non-stackable items always take their own slot
+	sword := newTestWeapon("sword", 5, 0)
+	inv.AddItem(*sword)
+	inv.AddItem(*sword)
+	if len(inv.Items) != 6 {
+		t.Errorf("Expected each sword in its own slot, got %d stacks", len(inv.Items))
+	}
+}
+
+// TestInventoryFull checks the remainder is returned once every slot is taken.
+func TestInventoryFull(t *testing.T) {
+	inv := NewInventory(1)
+	inv.Capacity = 2
+	if leftover := inv.AddItem(newTestPotion(26)); leftover != 6 {
+		t.Errorf("Expected 6 potions left over, got %d", leftover)
+	}
+	if leftover := inv.AddItem(*newTestWeapon("sword", 5, 0)); leftover != 1 {
+		t.Errorf("Expected the sword to be rejected, got leftover %d", leftover)
+	}
+	if len(inv.Items) != 2 {
+		t.Errorf("Expected 2 stacks, got %d", len(inv.Items))
+	}
+}
+
+func newTestTarget() *NPC {
+	npc := &NPC{Data: NPCData{MaxHealth: 100, BaseAttack: 10, BaseDefense: 5, BaseAttackSpeed: 1}}
+	npc.ResetRuntime()
+	return npc
+}
+
+// TestInventoryUseHeal checks healing is clamped at MaxHealth, and used items are removed at zero.
+func TestInventoryUseHeal(t *testing.T) {
+	inv := NewInventory(1)
+	potion := newTestPotion(2)
+	potion.WithEffect(ItemEffect{Type: EffectHealth, Value: 30})
+	inv.AddItem(potion)
+
+	target := newTestTarget()
+	target.Runtime.Health = 60
+	if err := inv.Use(0, target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Runtime.Health != 90 {
+		t.Errorf("Expected 90 health, got %d", target.Runtime.Health)
+	}
+	if err := inv.Use(0, target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Runtime.Health != 100 {
+		t.Errorf("Expected health clamped at 100, got %d", target.Runtime.Health)
+	}
+	if len(inv.Items) != 0 {
+		t.Errorf("Expected the empty stack to be removed, got %d items", len(inv.Items))
+	}
+	if err := inv.Use(0, target); !errors.Is(err, ErrInvalidSlot) {
+		t.Errorf("Expected ErrInvalidSlot, got %v", err)
+	}
+
+	inv.AddItem(*newTestWeapon("sword", 5, 0))
+	if err := inv.Use(0, target); !errors.Is(err, ErrNotConsumable) {
+		t.Errorf("Expected ErrNotConsumable, got %v", err)
+	}
+}
+
+// TestInventoryUseBuff checks a buff raises stats, and reverts to the base stats when it expires.
+func TestInventoryUseBuff(t *testing.T) {
+	inv := NewInventory(1)
+	elixir := newTestPotion(1)
+	elixir.WithEffect(ItemEffect{Type: EffectAttack, Value: 5, Duration: 10}).
+		WithEffect(ItemEffect{Type: EffectDefense, Value: 3, Duration: 5})
+	inv.AddItem(elixir)
+
+	target := newTestTarget()
+	if err := inv.Use(0, target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Runtime.Attack != 15 || target.Runtime.Defense != 8 {
+		t.Fatalf("Expected buffed attack 15 and defense 8, got %d and %d", target.Runtime.Attack, target.Runtime.Defense)
+	}
+
+	target.TickEffects(6)
+	if target.Runtime.Attack != 15 || target.Runtime.Defense != 5 {
+		t.Errorf("Expected only the defense buff to expire, got attack %d and defense %d", target.Runtime.Attack, target.Runtime.Defense)
+	}
+	target.TickEffects(4)
+	if target.Runtime.Attack != target.Data.BaseAttack || len(target.Runtime.ActiveEffects) != 0 {
+		t.Errorf("Expected all buffs to expire, got attack %d with %d active", target.Runtime.Attack, len(target.Runtime.ActiveEffects))
+	}
+}
@@ -96,11 +96,11 @@ func AllEquipmentTypes() []EquipmentType {
 }
 
 type ItemStats struct {
-	Attack      int
-	Defense     int
-	AttackSpeed int
-	AttackRange int
-	Effects     []ItemEffect
+	Attack      int          `json:"Attack,omitempty"`
+	Defense     int          `json:"Defense,omitempty"`
+	AttackSpeed int          `json:"AttackSpeed,omitempty"`
+	AttackRange int          `json:"AttackRange,omitempty"`
+	Effects     []ItemEffect `json:"Effects,omitempty"`
 }
 
 type EffectType int
@@ -110,42 +110,46 @@ const (
 	EffectHealth
 	EffectSpeed
 	EffectAttack
+	EffectDefense
 )
 
+// ItemEffect is applied to the target when a consumable is used.
+// Health effects heal instantly, the others are stat buffs that last for Duration seconds.
+// A buff with no Duration is permanent.
 type ItemEffect struct {
-	ID            int64
-	Type          EffectType
-	Value         float64
-	Duration      float64
-	TimeRemaining float64
+	ID            int64      `json:"ID"`
+	Type          EffectType `json:"Type"`
+	Value         float64    `json:"Value"`
+	Duration      float64    `json:"Duration,omitempty"`
+	TimeRemaining float64    `json:"TimeRemaining,omitempty"`
 }
 
 type ItemRequirements struct {
-	Level int
+	Level int `json:"Level,omitempty"`
 }
 
 // Item represents any item in the game world
 type Item struct {
-	ID          string
-	Name        string
-	Description string
-	Pos         Position
-	Texture     *AnimatedTexture
-
-	Type          ItemType
-	EquipmentType EquipmentType
-
-	Blocking   bool
-	Equippable bool
-	Consumable bool
-	Quantity   int
-	Stackable  bool
-	MaxStack   int
-
-	Stats        ItemStats
-	Requirements ItemRequirements
-
-	Removed bool
+	ID          string           `json:"ID"`
+	Name        string           `json:"Name"`
+	Description string           `json:"Description,omitempty"`
+	Pos         Position         `json:"Pos"`
+	Texture     *AnimatedTexture `json:"Texture,omitempty"`
+
+	Type          ItemType      `json:"Type"`
+	EquipmentType EquipmentType `json:"EquipmentType,omitempty"`
+
+	Blocking   bool `json:"Blocking,omitempty"`
+	Equippable bool `json:"Equippable,omitempty"`
+	Consumable bool `json:"Consumable,omitempty"`
+	Quantity   int  `json:"Quantity"`
+	Stackable  bool `json:"Stackable,omitempty"`
+	MaxStack   int  `json:"MaxStack,omitempty"`
+
+	Stats        ItemStats        `json:"Stats"`
+	Requirements ItemRequirements `json:"Requirements"`
+
+	Removed bool `json:"Removed,omitempty"`
 }
 
 // Items is a collection of items with helper methods
@@ -208,6 +212,12 @@ func (i *Item) WithTexture(texture *AnimatedTexture) *Item {
 	return i
 }
 
+// WithEffect adds an effect, applied when the item is used
+func (i *Item) WithEffect(effect ItemEffect) *Item {
+	i.Stats.Effects = append(i.Stats.Effects, effect)
+	return i
+}
+
 // WithDescription sets the item's description
 func (i *Item) WithDescription(desc string) *Item {
 	i.Description = desc
@@ -257,3 +267,35 @@ func (items Items) FindByPosition(pos Position) *Item {
 	}
 	return nil
 }
+
+// ItemsAt returns every item on the tile at pos, skipping items that were picked up.
+func (m *Map) ItemsAt(pos Position) []*Item {
+	return m.ItemsWithin(pos, 0)
+}
+
+// ItemsWithin returns every item within radius tiles of pos, by Manhattan distance.
+// Items that were picked up are skipped.
+func (m *Map) ItemsWithin(pos Position, radius int) []*Item {
+	var items []*Item
+	for _, item := range m.Items {
+		if item == nil || item.Removed {
+			continue
+		}
+		if beam_math.ManhattanDistance(item.Pos.X, item.Pos.Y, pos.X, pos.Y) <= radius {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// RemoveItem marks an item as picked up, so it's no longer on the map until Items.Reset.
+// Returns false if the item isn't on the map, or was already picked up.
+func (m *Map) RemoveItem(item *Item) bool {
+	for _, mapItem := range m.Items {
+		if mapItem == item && !mapItem.Removed {
+			mapItem.Removed = true
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package beam
+
+import "testing"
+
+func newTestItemMap() (*Map, []*Item) {
+	coin := NewItem("coin", "Coin", ItemTypeResource)
+	coin.Pos = Position{X: 2, Y: 2}
+	gem := NewItem("gem", "Gem", ItemTypeResource)
+	gem.Pos = Position{X: 2, Y: 2}
+	key := NewItem("key", "Key", ItemTypeQuestItem)
+	key.Pos = Position{X: 4, Y: 3}
+	return &Map{Width: 6, Height: 6, Items: Items{coin, gem, key}}, []*Item{coin, gem, key}
+}
+
+// TestMapItemsAt checks every item on a tile is returned, and picked up items are skipped.
+func TestMapItemsAt(t *testing.T) {
+	m, items := newTestItemMap()
+	if got := m.ItemsAt(Position{X: 2, Y: 2}); len(got) != 2 || got[0] != items[0] || got[1] != items[1] {
+		t.Errorf("Expected the coin and gem at 2,2, got %d items", len(got))
+	}
+	if got := m.ItemsAt(Position{X: 0, Y: 0}); len(got) != 0 {
+		t.Errorf("Expected no items at 0,0, got %d", len(got))
+	}
+
+	if !m.RemoveItem(items[0]) {
+		t.Fatal("Expected the coin to be removed")
+	}
+	if m.RemoveItem(items[0]) {
+		t.Error("Expected removing the coin twice to fail")
+	}
+	if got := m.ItemsAt(Position{X: 2, Y: 2}); len(got) != 1 || got[0] != items[1] {
+		t.Errorf("Expected only the gem at 2,2 after pickup, got %d items", len(got))
+	}
+}
+
+// TestMapItemsWithin checks radius queries use Manhattan distance.
+func TestMapItemsWithin(t *testing.T) {
+	m, _ := newTestItemMap()
+	tests := []struct {
+		pos    Position
+		radius int
+		want   int
+	}{
+		{Position{X: 2, Y: 2}, 0, 2},
+		{Position{X: 3, Y: 3}, 1, 1},
+		{Position{X: 3, Y: 3}, 2, 3},
+		{Position{X: 0, Y: 5}, 2, 0},
+	}
+	for _, tt := range tests {
+		if got := m.ItemsWithin(tt.pos, tt.radius); len(got) != tt.want {
+			t.Errorf("ItemsWithin(%v, %d): expected %d items, got %d", tt.pos, tt.radius, tt.want, len(got))
+		}
+	}
+}
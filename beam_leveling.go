@@ -0,0 +1,74 @@
+package beam
+
+import "math"
+
+// LevelGrowth is the curve NPCs level up on.
+// Stats grow by a fraction of their current value each level, and each level needs more experience than the last.
+type LevelGrowth struct {
+	BaseExperience   int     // Experience needed to reach level 2
+	ExperienceGrowth float64 // Multiplier on the experience needed for each following level
+	HealthGrowth     float64
+	AttackGrowth     float64
+	DefenseGrowth    float64
+}
+
+var DefaultLevelGrowth = LevelGrowth{
+	BaseExperience:   100,
+	ExperienceGrowth: 1.5,
+	HealthGrowth:     0.1,
+	AttackGrowth:     0.1,
+	DefenseGrowth:    0.1,
+}
+
+// LevelCurve is used by GainExperience, replace it to change how NPCs level up.
+var LevelCurve = DefaultLevelGrowth
+
+// ExperienceFor returns the experience needed to go from level to the next.
+func (g LevelGrowth) ExperienceFor(level int) int {
+	return max(1, int(float64(g.BaseExperience)*math.Pow(g.ExperienceGrowth, float64(max(level, 1)-1))))
+}
+
+// GainExperience adds experience, leveling up as many times as it covers, with any extra rolled over.
+// Each level grows MaxHealth, Attack and Defense along LevelCurve, and current health goes up by the same amount.
+// Levels and grown stats are runtime state, so ResetRuntime puts the NPC back to its authored level.
+func (npc *NPC) GainExperience(amount int) (leveledUp bool) {
+	rt := &npc.Runtime
+	if rt.Level < 1 {
+		rt.Level = max(npc.Data.Level, 1)
+	}
+	if rt.ExperienceToNext <= 0 {
+		rt.ExperienceToNext = LevelCurve.ExperienceFor(rt.Level)
+	}
+
+	rt.Experience += amount
+	for rt.Experience >= rt.ExperienceToNext {
+		rt.Experience -= rt.ExperienceToNext
+		rt.Level++
+		npc.growStats()
+		rt.ExperienceToNext = LevelCurve.ExperienceFor(rt.Level)
+		leveledUp = true
+	}
+	return leveledUp
+}
+
+// growStats adds the growth for the level just reached to the NPC's runtime stats.
+// Grown stats are derived from the authored ones, each level adding a fraction of the last level's value.
+func (npc *NPC) growStats() {
+	gained := npc.Runtime.Level - max(npc.Data.Level, 1)
+	delta := func(base int, rate float64) int {
+		return grownStat(base, rate, gained) - grownStat(base, rate, gained-1)
+	}
+	healthDelta := delta(npc.Data.MaxHealth, LevelCurve.HealthGrowth)
+	npc.Runtime.MaxHealth += healthDelta
+	npc.Runtime.Health += healthDelta
+	npc.Runtime.Attack += delta(npc.Data.BaseAttack, LevelCurve.AttackGrowth)
+	npc.Runtime.Defense += delta(npc.Data.BaseDefense, LevelCurve.DefenseGrowth)
+}
+
+// grownStat is a stat after growing by rate for the given number of levels.
+func grownStat(base int, rate float64, levels int) int {
+	for range levels {
+		base += int(math.Round(float64(base) * rate))
+	}
+	return base
+}
@@ -0,0 +1,66 @@
+package beam
+
+import "testing"
+
+// TestGainExperienceSingleLevel checks a level up rolls over extra experience and grows stats and health.
+func TestGainExperienceSingleLevel(t *testing.T) {
+	npc := newTestFighter(100, 20, 10, 0)
+	npc.Runtime.Health = 50
+
+	if npc.GainExperience(40) {
+		t.Fatal("Expected 40 experience not to level up")
+	}
+	if !npc.GainExperience(80) {
+		t.Fatal("Expected 120 total experience to level up")
+	}
+	if npc.Runtime.Level != 2 || npc.Runtime.Experience != 20 || npc.Runtime.ExperienceToNext != 150 {
+		t.Errorf("Expected level 2 with 20/150 experience, got level %d with %d/%d",
+			npc.Runtime.Level, npc.Runtime.Experience, npc.Runtime.ExperienceToNext)
+	}
+	if npc.Runtime.MaxHealth != 110 || npc.Runtime.Attack != 22 || npc.Runtime.Defense != 11 {
+		t.Errorf("Expected stats 110/22/11, got %d/%d/%d", npc.Runtime.MaxHealth, npc.Runtime.Attack, npc.Runtime.Defense)
+	}
+	if npc.Runtime.Health != 60 {
+		t.Errorf("Expected health 60, got %d", npc.Runtime.Health)
+	}
+}
+
+// TestGainExperienceMultiLevel checks a large grant levels up several times at once.
+func TestGainExperienceMultiLevel(t *testing.T) {
+	npc := newTestFighter(100, 20, 10, 0)
+	if !npc.GainExperience(100 + 150 + 225 + 10) {
+		t.Fatal("Expected to level up")
+	}
+	if npc.Runtime.Level != 4 || npc.Runtime.Experience != 10 || npc.Runtime.ExperienceToNext != 337 {
+		t.Errorf("Expected level 4 with 10/337 experience, got level %d with %d/%d",
+			npc.Runtime.Level, npc.Runtime.Experience, npc.Runtime.ExperienceToNext)
+	}
+	// 100 -> 110 -> 121 -> 133
+	if npc.Runtime.MaxHealth != 133 || npc.Runtime.Health != 133 {
+		t.Errorf("Expected max and current health of 133, got %d and %d", npc.Runtime.MaxHealth, npc.Runtime.Health)
+	}
+}
+
+// TestGainExperienceKeepsAuthoredStats checks leveling up leaves the NPC's saved data alone,
+// so resetting it brings back its authored level and stats.
+func TestGainExperienceKeepsAuthoredStats(t *testing.T) {
+	npc := newTestFighter(100, 20, 10, 0)
+	npc.Data.Level = 3
+	npc.ResetRuntime()
+	authored := npc.Data
+
+	if !npc.GainExperience(LevelCurve.ExperienceFor(3)) || npc.Runtime.Level != 4 || npc.Runtime.MaxHealth != 110 {
+		t.Fatalf("Expected to reach level 4 with 110 max health, got level %d with %d", npc.Runtime.Level, npc.Runtime.MaxHealth)
+	}
+	if npc.Data.Level != authored.Level || npc.Data.MaxHealth != authored.MaxHealth || npc.Data.BaseAttack != authored.BaseAttack {
+		t.Errorf("Expected the NPC's data to be unchanged, got level %d with %d health and %d attack",
+			npc.Data.Level, npc.Data.MaxHealth, npc.Data.BaseAttack)
+	}
+
+	m := &Map{NPCs: NPCs{npc}}
+	m.ResetNPCs()
+	if npc.Runtime.Level != 3 || npc.Runtime.Experience != 0 || npc.Runtime.MaxHealth != 100 || npc.Runtime.Attack != 20 {
+		t.Errorf("Expected level 3 with 100 health and 20 attack after a reset, got level %d with %d health and %d attack",
+			npc.Runtime.Level, npc.Runtime.MaxHealth, npc.Runtime.Attack)
+	}
+}
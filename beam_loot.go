@@ -0,0 +1,76 @@
+package beam
+
+import "math/rand"
+
+// LootEntry is one possible drop in a LootTable.
+// Weight is its chance of being picked relative to the other entries, entries with no weight never drop.
+// An entry with no Item drops nothing, use it to give the table a chance of an empty roll.
+type LootEntry struct {
+	Item   *Item `json:"Item,omitempty"`
+	Weight int   `json:"Weight"`
+	MinQty int   `json:"MinQty"`
+	MaxQty int   `json:"MaxQty"`
+}
+
+// LootTable is what an NPC can drop when it dies, see NPCData.Loot.
+// Each roll picks one entry by weight, Rolls defaults to a single roll.
+type LootTable struct {
+	Entries []LootEntry `json:"Entries"`
+	Rolls   int         `json:"Rolls,omitempty"`
+}
+
+// Roll picks the drops from the table, each a copy of the entry's item
+// with a Quantity between MinQty and MaxQty.
+// Pass a seeded rng for repeatable drops, or nil to use the global source.
+func (lt LootTable) Roll(rng *rand.Rand) []*Item {
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
+
+	total := 0
+	for _, entry := range lt.Entries {
+		total += max(0, entry.Weight)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var drops []*Item
+	for range max(1, lt.Rolls) {
+		pick := intn(total)
+		for _, entry := range lt.Entries {
+			if entry.Weight <= 0 {
+				continue
+			}
+			if pick >= entry.Weight {
+				pick -= entry.Weight
+				continue
+			}
+			if entry.Item != nil {
+				minQty := max(1, entry.MinQty)
+				maxQty := max(minQty, entry.MaxQty)
+				drop := *entry.Item
+				drop.Quantity = minQty + intn(maxQty-minQty+1)
+				drop.Removed = false
+				drops = append(drops, &drop)
+			}
+			break
+		}
+	}
+	return drops
+}
+
+// DropLoot rolls the NPC's loot table and places the drops on the map at the NPC's tile.
+// NPC.Update calls this once when the NPC dies. Returns the items that were dropped.
+func (m *Map) DropLoot(npc *NPC, rng *rand.Rand) []*Item {
+	if npc == nil || npc.Data.Loot == nil {
+		return nil
+	}
+	drops := npc.Data.Loot.Roll(rng)
+	for _, item := range drops {
+		item.Pos = npc.Pos
+		m.Items = append(m.Items, item)
+	}
+	return drops
+}
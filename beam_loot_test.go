@@ -0,0 +1,113 @@
+package beam
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newTestLootTable() LootTable {
+	return LootTable{Entries: []LootEntry{
+		{Item: NewItem("coin", "Coin", ItemTypeResource), Weight: 6, MinQty: 2, MaxQty: 5},
+		{Item: NewItem("gem", "Gem", ItemTypeResource), Weight: 3, MinQty: 1, MaxQty: 1},
+		{Item: nil, Weight: 1},
+	}}
+}
+
+// TestLootTableWeights checks each entry drops in proportion to its weight over many rolls.
+func TestLootTableWeights(t *testing.T) {
+	lt := newTestLootTable()
+	rng := rand.New(rand.NewSource(1))
+	const rolls = 10000
+	counts := map[string]int{}
+	for range rolls {
+		drops := lt.Roll(rng)
+		if len(drops) == 0 {
+			counts["none"]++
+			continue
+		}
+		counts[drops[0].ID]++
+	}
+
+	for id, want := range map[string]float64{"coin": 0.6, "gem": 0.3, "none": 0.1} {
+		got := float64(counts[id]) / rolls
+		if got < want-0.03 || got > want+0.03 {
+			t.Errorf("Expected %s to drop %.2f of the time, got %.3f", id, want, got)
+		}
+	}
+}
+
+// TestLootTableQuantity checks drops stay within the entry's quantity bounds, and are copies of the item.
+func TestLootTableQuantity(t *testing.T) {
+	lt := newTestLootTable()
+	lt.Rolls = 3
+	rng := rand.New(rand.NewSource(7))
+	seen := map[int]bool{}
+	for range 1000 {
+		drops := lt.Roll(rng)
+		if len(drops) > 3 {
+			t.Fatalf("Expected at most 3 drops, got %d", len(drops))
+		}
+		for _, drop := range drops {
+			if drop == lt.Entries[0].Item || drop == lt.Entries[1].Item {
+				t.Fatal("Expected drops to be copies of the table's items")
+			}
+			switch drop.ID {
+			case "coin":
+				if drop.Quantity < 2 || drop.Quantity > 5 {
+					t.Errorf("Expected 2 to 5 coins, got %d", drop.Quantity)
+				}
+				seen[drop.Quantity] = true
+			case "gem":
+				if drop.Quantity != 1 {
+					t.Errorf("Expected 1 gem, got %d", drop.Quantity)
+				}
+			}
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected every coin quantity from 2 to 5 to drop, got %v", seen)
+	}
+}
+
+// TestLootTableDeterministic checks the same seed rolls the same drops, and an empty table drops nothing.
+func TestLootTableDeterministic(t *testing.T) {
+	lt := newTestLootTable()
+	a, b := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for range 100 {
+		first, second := lt.Roll(a), lt.Roll(b)
+		if len(first) != len(second) {
+			t.Fatalf("Expected the same drops from the same seed, got %d and %d items", len(first), len(second))
+		}
+		for i := range first {
+			if first[i].ID != second[i].ID || first[i].Quantity != second[i].Quantity {
+				t.Fatalf("Expected the same drops from the same seed, got %s x%d and %s x%d",
+					first[i].ID, first[i].Quantity, second[i].ID, second[i].Quantity)
+			}
+		}
+	}
+
+	if drops := (LootTable{}).Roll(a); drops != nil {
+		t.Errorf("Expected no drops from an empty table, got %d", len(drops))
+	}
+}
+
+// TestMapDropLoot checks loot is placed on the map at the NPC's tile.
+func TestMapDropLoot(t *testing.T) {
+	m := &Map{Width: 6, Height: 6}
+	npc := newTestFighter(10, 0, 0, 0)
+	npc.Pos = Position{X: 3, Y: 4}
+	if drops := m.DropLoot(npc, nil); drops != nil {
+		t.Errorf("Expected no drops without a loot table, got %d", len(drops))
+	}
+
+	npc.Data.Loot = &LootTable{Entries: []LootEntry{
+		{Item: NewItem("coin", "Coin", ItemTypeResource), Weight: 1, MinQty: 1, MaxQty: 3},
+	}}
+	drops := m.DropLoot(npc, rand.New(rand.NewSource(1)))
+	if len(drops) != 1 {
+		t.Fatalf("Expected 1 drop, got %d", len(drops))
+	}
+	if got := m.ItemsAt(npc.Pos); len(got) != 1 || got[0] != drops[0] {
+		t.Errorf("Expected the coin to be dropped at %v, got %d items", npc.Pos, len(got))
+	}
+}
@@ -1,6 +1,7 @@
 package beam
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 
@@ -39,23 +40,33 @@ Example usage:
 			},
             IdleTexture: NewSimpleNPCTexture("guard"),
             AttackTexture: NewSimpleNPCTexture("guard"),
-            Health: 100,
             MaxHealth: 100,
-            Attack: 10,
-            Defense: 5,
+            BaseAttack: 10,
+            BaseDefense: 5,
             Hostile: true,
             AggroRange: 5,
             WanderRange: 3,
             Impassable: true,
         },
     }
+    // Initialize health, stats and timers from the definition
+    npc.ResetRuntime()
 */
 
 type NPCTexture struct {
-	Up    *AnimatedTexture
-	Down  *AnimatedTexture
-	Left  *AnimatedTexture
-	Right *AnimatedTexture
+	Up    *AnimatedTexture `json:"Up,omitempty"`
+	Down  *AnimatedTexture `json:"Down,omitempty"`
+	Left  *AnimatedTexture `json:"Left,omitempty"`
+	Right *AnimatedTexture `json:"Right,omitempty"`
+}
+
+// Restart plays every direction's animation again from its first frame.
+func (t *NPCTexture) Restart(currentTime float64) {
+	for _, tex := range []*AnimatedTexture{t.Up, t.Down, t.Left, t.Right} {
+		if tex != nil {
+			tex.Restart(currentTime)
+		}
+	}
 }
 
 // AttackState represents the different stages of an NPC's attack.
@@ -98,11 +109,49 @@ const (
 	NPCSize4x4                    // 4x4
 )
 
+func (s NPCSize) String() string {
+	width, height := s.GetDimensions()
+	return fmt.Sprintf("%dx%d", width, height)
+}
+
+func AllNPCSizes() []NPCSize {
+	return []NPCSize{
+		NPCSize1x1,
+		NPCSize2x2,
+		NPCSize3x3,
+		NPCSize4x4,
+	}
+}
+
 type NPCs []*NPC
 
+// IsSpawnable reports if an NPC of the given size can be placed with its top left corner at pos.
+// Every tile it would cover must be on the map and passable, see Tile.IsPassable.
+func (m *Map) IsSpawnable(pos Position, size NPCSize) bool {
+	width, height := size.GetDimensions()
+	for dy := 0; dy < height; dy++ {
+		for dx := 0; dx < width; dx++ {
+			tile, ok := m.TileAt(Position{X: pos.X + dx, Y: pos.Y + dy})
+			if !ok || !tile.IsPassable() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ClampSpawnPos moves pos up and left until an NPC of the given size fits on the map.
+// Positions before the top left corner are clamped to it.
+func (m *Map) ClampSpawnPos(pos Position, size NPCSize) Position {
+	width, height := size.GetDimensions()
+	pos.X = max(0, min(pos.X, m.Width-width))
+	pos.Y = max(0, min(pos.Y, m.Height-height))
+	return pos
+}
+
 func (npcs NPCs) IsBlocked(x, y int) bool {
 	for _, npc := range npcs {
-		if !npc.Data.Dead && npc.Data.Impassable {
+		if !npc.Runtime.Dead && npc.Data.Impassable {
 			if npc.occupiesTile(x, y) {
 				return true
 			}
@@ -111,10 +160,26 @@ func (npcs NPCs) IsBlocked(x, y int) bool {
 	return false
 }
 
+// ResetRuntime reinitializes the runtime state of every NPC, call this after loading a map.
+func (npcs NPCs) ResetRuntime() {
+	for _, npc := range npcs {
+		npc.ResetRuntime()
+	}
+}
+
+// ResetNPCs puts every NPC back in its authored spawn state, to replay a map without reloading it.
+// Each NPC is moved to its SpawnPos and brought back to life, with full health, its base stats and no effects.
+func (m *Map) ResetNPCs() {
+	for _, npc := range m.NPCs {
+		npc.Pos = npc.Data.SpawnPos
+		npc.ResetRuntime()
+	}
+}
+
 func (npcs NPCs) LivingNPCs() NPCs {
 	targets := make(NPCs, 0)
 	for _, e := range npcs {
-		if !e.Data.Dead {
+		if !e.Runtime.Dead {
 			targets = append(targets, e)
 		}
 	}
@@ -123,7 +188,7 @@ func (npcs NPCs) LivingNPCs() NPCs {
 
 func (npcs NPCs) IsInteracting() bool {
 	for _, e := range npcs {
-		if e.Data.IsInteracting {
+		if e.Runtime.IsInteracting {
 			return true
 		}
 	}
@@ -133,7 +198,7 @@ func (npcs NPCs) IsInteracting() bool {
 func (npcs NPCs) InteractableNearby(playerPos Position) NPCs {
 	targets := make(NPCs, 0)
 	for _, e := range npcs {
-		if e.Data.Interactable && !e.Data.Dead && !e.Data.IsInteracting {
+		if e.Data.Interactable && !e.Runtime.Dead && !e.Runtime.IsInteracting {
 			dist := e.distanceToNPC(playerPos.X, playerPos.Y)
 			if dist <= 1 {
 				targets = append(targets, e)
@@ -144,55 +209,125 @@ func (npcs NPCs) InteractableNearby(playerPos Position) NPCs {
 }
 
 type NPC struct {
-	Pos         Position
-	Data        NPCData
-	CurrentChat *chat.Chat
+	Pos         Position   `json:"Pos"`
+	Data        NPCData    `json:"Data"`
+	Runtime     NPCRuntime `json:"-"`
+	CurrentChat *chat.Chat `json:"CurrentChat,omitempty"`
 }
 
+// NPCData is the design-time definition of an NPC.
+// This is what gets saved with a map.
 type NPCData struct {
-	Name string
+	// ID identifies the NPC in the mapmaker, so renaming it doesn't clash with another NPC.
+	ID   string `json:"ID,omitempty"`
+	Name string `json:"Name"`
+
+	Texture       *NPCTexture `json:"Texture"`
+	IdleTexture   *NPCTexture `json:"IdleTexture,omitempty"`
+	AttackTexture *NPCTexture `json:"AttackTexture,omitempty"`
+
+	SpawnPos Position `json:"SpawnPos"`
+	Size     NPCSize  `json:"Size"`
+
+	MaxHealth       int     `json:"MaxHealth"`
+	BaseAttack      int     `json:"BaseAttack"`
+	BaseDefense     int     `json:"BaseDefense"`
+	BaseAttackSpeed float64 `json:"BaseAttackSpeed"`
+	BaseAttackRange float64 `json:"BaseAttackRange"`
+	MoveSpeed       float64 `json:"MoveSpeed"`
+
+	Attackable  bool `json:"Attackable"`
+	Impassable  bool `json:"Impassable"`
+	Hostile     bool `json:"Hostile"`
+	WanderRange int  `json:"WanderRange"`
+	AggroRange  int  `json:"AggroRange"`
+
+	Interactable bool `json:"Interactable"`
 
-	Texture       *NPCTexture
-	IdleTexture   *NPCTexture
-	AttackTexture *NPCTexture
+	// Faction decides who the NPC fights, see Map.SetFactionRelation
+	Faction string `json:"Faction,omitempty"`
 
-	SpawnPos Position
-	Size     NPCSize
+	// Level is the level the NPC starts at, which its stats above are for. See GainExperience for leveling.
+	// ExperienceReward is what the NPC is worth when defeated.
+	Level            int `json:"Level,omitempty"`
+	ExperienceReward int `json:"ExperienceReward,omitempty"`
 
+	// HitEffects are status effects the NPC's attacks inflict, see ResolveAttack
+	HitEffects []StatusEffect `json:"HitEffects,omitempty"`
+
+	// Loot is rolled when the NPC dies, and dropped on its tile
+	Loot *LootTable `json:"Loot,omitempty"`
+
+	// Optional hooks, called when the NPC loses health and when it dies. They aren't saved with the map.
+	OnDamage func(npc *NPC, amount int) `json:"-"`
+	OnDeath  func(npc *NPC)             `json:"-"`
+}
+
+// NPCRuntime is the state of an NPC while the game is running.
+// It is not saved with the map, use ResetRuntime to initialize it from the NPCData.
+type NPCRuntime struct {
 	LastMoveTime     float32
 	LastHealthChange float32
 	LastAttackTime   float32
 
-	Health          int
-	MaxHealth       int
-	Attack          int
-	BaseAttack      int
-	Defense         int
-	BaseDefense     int
-	AttackSpeed     float64
-	BaseAttackSpeed float64
-	AttackRange     float64
-	BaseAttackRange float64
-	MoveSpeed       float64
+	Health      int
+	MaxHealth   int
+	Attack      int
+	Defense     int
+	AttackSpeed float64
+	AttackRange float64
+	MoveSpeed   float64
 
 	Direction Direction
 	IsIdle    bool
 
-	Attackable          bool
-	Impassable          bool
-	Hostile             bool
-	WanderRange         int
-	AggroRange          int
+	// Level gained since spawning, and progress towards the next one. See GainExperience.
+	Level            int
+	Experience       int
+	ExperienceToNext int
+
+	// Smooth movement for rendering, see UpdateVisual.
+	// PrevPos is the tile the NPC is moving from, MoveProgress how far along it is, from 0 to 1.
+	PrevPos      Position
+	VisualPos    rl.Vector2
+	MoveProgress float32
+	visualTarget Position
+	hasVisual    bool
+
 	AttackState         AttackState
 	AttackStateTime     float32
 	TookDamageThisFrame bool
+	KnockbackFrom       *Position // Where the last hit came from, the NPC is knocked back away from it
 	DamageFrames        int
 	DyingFrames         int
 	Dead                bool
 
-	Interactable  bool
 	IsInteracting bool
-	Experience    int
+
+	// Set while Map.StepTurn runs the NPC's turn, bypassing the move and attack timers
+	takingTurn bool
+
+	// Temporary buffs from consumables, removed by TickEffects when they run out
+	ActiveEffects []ItemEffect
+	// Poison, burn and slow, removed by TickEffects when they run out
+	StatusEffects []StatusEffect
+}
+
+// ResetRuntime reinitializes the NPC's runtime state from its definition.
+func (npc *NPC) ResetRuntime() {
+	level := max(npc.Data.Level, 1)
+	npc.Runtime = NPCRuntime{
+		Health:           npc.Data.MaxHealth,
+		MaxHealth:        npc.Data.MaxHealth,
+		Attack:           npc.Data.BaseAttack,
+		Defense:          npc.Data.BaseDefense,
+		AttackSpeed:      npc.Data.BaseAttackSpeed,
+		AttackRange:      npc.Data.BaseAttackRange,
+		MoveSpeed:        npc.Data.MoveSpeed,
+		Direction:        DirDown,
+		Level:            level,
+		ExperienceToNext: LevelCurve.ExperienceFor(level),
+	}
 }
 
 func NewSimpleNPCTexture(name string) *NPCTexture {
@@ -216,7 +351,7 @@ func NewSimpleNPCTexture(name string) *NPCTexture {
 // based on its direction, idle, and attacking state.
 func (npc *NPC) GetCurrentTexture() *AnimatedTexture {
 	var base, idle, attack *AnimatedTexture
-	switch npc.Data.Direction {
+	switch npc.Runtime.Direction {
 	case DirUp:
 		base = npc.Data.Texture.Up
 		if npc.Data.IdleTexture != nil {
@@ -257,11 +392,11 @@ func (npc *NPC) GetCurrentTexture() *AnimatedTexture {
 	currentTime := float32(rl.GetTime())
 
 	// Don't swap to idle immediately after finishing a long attack
-	isAttacking := (npc.Data.AttackState != AttackIdle) || (currentTime-npc.Data.LastAttackTime < 2.0)
+	isAttacking := (npc.Runtime.AttackState != AttackIdle) || (currentTime-npc.Runtime.LastAttackTime < 2.0)
 	if isAttacking && attack != nil {
 		return attack
 	}
-	if npc.Data.IsIdle && idle != nil {
+	if npc.Runtime.IsIdle && idle != nil {
 		return idle
 	}
 	return base
@@ -269,27 +404,41 @@ func (npc *NPC) GetCurrentTexture() *AnimatedTexture {
 
 // Run the NPC update loop.
 func (npc *NPC) Update(playerPos Position, currMap *Map, cm *controls.ControlsManager) (died bool) {
-	if npc.Data.Dead {
+	if !npc.Runtime.Dead {
+		npc.TickEffects(rl.GetFrameTime())
+	}
+
+	if npc.Runtime.Dead {
 		totalDyingFrames := 32
-		npc.Data.DyingFrames++
-		if npc.Data.DyingFrames >= totalDyingFrames {
+		npc.Runtime.DyingFrames++
+		if npc.Runtime.DyingFrames == 1 {
+			currMap.DropLoot(npc, nil)
+		}
+		if npc.Runtime.DyingFrames >= totalDyingFrames {
 			return true
 		}
-	} else if npc.Data.TookDamageThisFrame {
+	} else if npc.Runtime.TookDamageThisFrame {
 		totalDamageFrames := 32
-		npc.Data.DamageFrames++
-		if npc.Data.DamageFrames == 1 {
-			npc.knockback(playerPos, currMap.Tiles, 1)
+		npc.Runtime.DamageFrames++
+		if npc.Runtime.DamageFrames == 1 {
+			from := playerPos
+			if npc.Runtime.KnockbackFrom != nil {
+				from = *npc.Runtime.KnockbackFrom
+				npc.faceTowards(from)
+			}
+			npc.knockback(from, currMap.Tiles, 1)
+			// Later hits that don't say where they came from fall back to the player
+			npc.Runtime.KnockbackFrom = nil
 		}
-		if npc.Data.DamageFrames >= int(totalDamageFrames) {
-			npc.Data.DamageFrames = 0
-			npc.Data.TookDamageThisFrame = false
+		if npc.Runtime.DamageFrames >= int(totalDamageFrames) {
+			npc.Runtime.DamageFrames = 0
+			npc.Runtime.TookDamageThisFrame = false
 		}
 	}
 
-	if npc.Data.IsInteracting {
+	if npc.Runtime.IsInteracting {
 		if npc.CurrentChat.State == chat.DialogFinished || npc.CurrentChat.State == chat.DialogHidden {
-			npc.Data.IsInteracting = false
+			npc.Runtime.IsInteracting = false
 		}
 		npc.CurrentChat.Update(cm)
 		npc.CurrentChat.Draw(cm)
@@ -297,15 +446,24 @@ func (npc *NPC) Update(playerPos Position, currMap *Map, cm *controls.ControlsMa
 	}
 
 	npc.updateAttackState()
-	if npc.Data.AttackState == AttackIdle {
+	if currMap.Mode == TurnBased && !npc.Runtime.takingTurn {
+		return false
+	}
+	if npc.HasStatusEffect(StatusStun) {
+		return false
+	}
+	if npc.Runtime.AttackState == AttackIdle {
+		npc.AttackEnemies(currMap)
+	}
+	if npc.Runtime.AttackState == AttackIdle {
 		npc.Wander(playerPos, currMap)
 	}
 	return false
 }
 
 func (npc *NPC) updateAttackState() {
-	if npc.Data.AttackState != AttackIdle {
-		npc.Data.AttackStateTime += rl.GetFrameTime()
+	if npc.Runtime.AttackState != AttackIdle {
+		npc.Runtime.AttackStateTime += rl.GetFrameTime()
 
 		var currentPhaseExpectedDuration float32
 		calculateAttackPhaseDuration := func(attackSpeed float64, phaseProportion float32) float32 {
@@ -318,24 +476,24 @@ func (npc *NPC) updateAttackState() {
 			return float32(math.Max(float64(MinAttackPhaseDuration), float64(calculatedDuration)))
 		}
 
-		switch npc.Data.AttackState {
+		switch npc.Runtime.AttackState {
 		case AttackStart:
-			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Data.AttackSpeed, AttackStartProportion)
-			if npc.Data.AttackStateTime >= currentPhaseExpectedDuration {
-				npc.Data.AttackState = AttackMid
-				npc.Data.AttackStateTime = 0
+			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Runtime.AttackSpeed, AttackStartProportion)
+			if npc.Runtime.AttackStateTime >= currentPhaseExpectedDuration {
+				npc.Runtime.AttackState = AttackMid
+				npc.Runtime.AttackStateTime = 0
 			}
 		case AttackMid:
-			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Data.AttackSpeed, AttackMidProportion)
-			if npc.Data.AttackStateTime >= currentPhaseExpectedDuration {
-				npc.Data.AttackState = AttackEnd
-				npc.Data.AttackStateTime = 0
+			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Runtime.AttackSpeed, AttackMidProportion)
+			if npc.Runtime.AttackStateTime >= currentPhaseExpectedDuration {
+				npc.Runtime.AttackState = AttackEnd
+				npc.Runtime.AttackStateTime = 0
 			}
 		case AttackEnd:
-			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Data.AttackSpeed, AttackEndProportion)
-			if npc.Data.AttackStateTime >= currentPhaseExpectedDuration {
-				npc.Data.AttackState = AttackIdle
-				npc.Data.AttackStateTime = 0
+			currentPhaseExpectedDuration = calculateAttackPhaseDuration(npc.Runtime.AttackSpeed, AttackEndProportion)
+			if npc.Runtime.AttackStateTime >= currentPhaseExpectedDuration {
+				npc.Runtime.AttackState = AttackIdle
+				npc.Runtime.AttackStateTime = 0
 			}
 		}
 	}
@@ -349,29 +507,29 @@ func (npc *NPC) Interact(playerPos Position, currChat *chat.Chat) {
 
 	dist := npc.distanceToNPC(playerPos.X, playerPos.Y)
 	if dist <= 1 {
-		npc.Data.IsInteracting = true
+		npc.Runtime.IsInteracting = true
 		if currChat == nil {
 			npc.CurrentChat = chat.NewChat()
 		} else {
 			npc.CurrentChat = currChat
 		}
 	} else {
-		npc.Data.IsInteracting = false
+		npc.Runtime.IsInteracting = false
 		return
 	}
 
 	// Turn and face the player
 	if playerPos.X > npc.Pos.X {
-		npc.Data.Direction = DirRight
+		npc.Runtime.Direction = DirRight
 	}
 	if playerPos.X < npc.Pos.X {
-		npc.Data.Direction = DirLeft
+		npc.Runtime.Direction = DirLeft
 	}
 	if playerPos.Y > npc.Pos.Y {
-		npc.Data.Direction = DirDown
+		npc.Runtime.Direction = DirDown
 	}
 	if playerPos.Y < npc.Pos.Y {
-		npc.Data.Direction = DirUp
+		npc.Runtime.Direction = DirUp
 	}
 
 	// Start the chat
@@ -379,12 +537,31 @@ func (npc *NPC) Interact(playerPos Position, currChat *chat.Chat) {
 	return
 }
 
+// InteractWithTree starts a branching conversation with a player.
+// The NPC's chat tracks the current node as the player makes choices.
+func (npc *NPC) InteractWithTree(playerPos Position, tree *chat.DialogTree) {
+	if tree == nil {
+		npc.Interact(playerPos, nil)
+		return
+	}
+	npc.Interact(playerPos, chat.NewChatWithTree(tree))
+}
+
+// CurrentDialogNode returns the node of the NPC's branching conversation, or nil if there isn't one.
+func (npc *NPC) CurrentDialogNode() *chat.DialogNode {
+	if npc.CurrentChat == nil {
+		return nil
+	}
+	return npc.CurrentChat.CurrentDialogNode()
+}
+
 // A simple wandering algo that moves the NPC towards the player if within aggro range.
-// If not, it will wander randomly. The NPC will also check for obstacles.
+// If not, it will chase the nearest NPC from a hostile faction within aggro range,
+// or wander randomly. The NPC will also check for obstacles.
 // The NPC will try to stay within its wander range, if possible.
 func (npc *NPC) Wander(playerPos Position, currMap *Map) {
 	currentTime := float32(rl.GetTime())
-	if npc.Data.MoveSpeed <= 0 || ((currentTime - npc.Data.LastMoveTime) < 1.0/float32(npc.Data.MoveSpeed)) {
+	if npc.Runtime.MoveSpeed <= 0 || (!npc.Runtime.takingTurn && (currentTime-npc.Runtime.LastMoveTime) < 1.0/float32(npc.Runtime.MoveSpeed)) {
 		return
 	}
 
@@ -394,6 +571,16 @@ func (npc *NPC) Wander(playerPos Position, currMap *Map) {
 	distToSpawn := beam_math.ManhattanDistance(npc.Pos.X, npc.Pos.Y, npc.Data.SpawnPos.X, npc.Data.SpawnPos.Y)
 	var dx, dy int
 
+	// Chase the player, or an enemy NPC if the player isn't in range
+	target, distToTarget := playerPos, distToPlayer
+	chasing := distToPlayer <= npc.Data.AggroRange && npc.Data.Hostile
+	if !chasing {
+		if enemy := currMap.NearestEnemy(npc, npc.Data.AggroRange); enemy != nil {
+			target, distToTarget = enemy.Pos, enemy.distanceToNPC(npc.Pos.X, npc.Pos.Y)
+			chasing = true
+		}
+	}
+
 	if distToPlayer == 0 {
 		directions := Positions{
 			{X: 0, Y: -1}, // North
@@ -409,12 +596,12 @@ func (npc *NPC) Wander(playerPos Position, currMap *Map) {
 				break
 			}
 		}
-	} else if distToPlayer <= npc.Data.AggroRange && npc.Data.Hostile {
-		isDiagonal := npc.Pos.X != playerPos.X && npc.Pos.Y != playerPos.Y
-		xDiff := playerPos.X - npc.Pos.X
-		yDiff := playerPos.Y - npc.Pos.Y
+	} else if chasing {
+		isDiagonal := npc.Pos.X != target.X && npc.Pos.Y != target.Y
+		xDiff := target.X - npc.Pos.X
+		yDiff := target.Y - npc.Pos.Y
 
-		if isDiagonal && distToPlayer > 1 {
+		if isDiagonal && distToTarget > 1 {
 			if math.Abs(float64(xDiff)) >= math.Abs(float64(yDiff)) {
 				dx = beam_math.Sign(xDiff)
 				dy = 0
@@ -430,20 +617,20 @@ func (npc *NPC) Wander(playerPos Position, currMap *Map) {
 					dx = beam_math.Sign(xDiff)
 				}
 			}
-		} else if distToPlayer > 1 {
-			if npc.Pos.X < playerPos.X {
+		} else if distToTarget > 1 {
+			if npc.Pos.X < target.X {
 				dx = 1
-			} else if npc.Pos.X > playerPos.X {
+			} else if npc.Pos.X > target.X {
 				dx = -1
 			}
-			if npc.Pos.Y < playerPos.Y {
+			if npc.Pos.Y < target.Y {
 				dy = 1
-			} else if npc.Pos.Y > playerPos.Y {
+			} else if npc.Pos.Y > target.Y {
 				dy = -1
 			}
 		}
 
-		newDist := npc.distanceToNPC(playerPos.X-dx, playerPos.Y-dy)
+		newDist := npc.distanceToNPC(target.X-dx, target.Y-dy)
 		if newDist < 1 {
 			dx, dy = 0, 0
 		}
@@ -495,59 +682,67 @@ func (npc *NPC) Wander(playerPos Position, currMap *Map) {
 	}
 
 	if dx > 0 {
-		npc.Data.Direction = DirRight
+		npc.Runtime.Direction = DirRight
 	} else if dx < 0 {
-		npc.Data.Direction = DirLeft
+		npc.Runtime.Direction = DirLeft
 	} else if dy > 0 {
-		npc.Data.Direction = DirDown
+		npc.Runtime.Direction = DirDown
 	} else if dy < 0 {
-		npc.Data.Direction = DirUp
+		npc.Runtime.Direction = DirUp
 	}
 
 	idleThreshold := float32(3.0)
 	if npc.Pos.X != startPos.X || npc.Pos.Y != startPos.Y {
-		npc.Data.LastMoveTime = currentTime
-		npc.Data.IsIdle = false
-	} else if currentTime-npc.Data.LastMoveTime > idleThreshold {
-		npc.Data.IsIdle = true
+		npc.Runtime.LastMoveTime = currentTime
+		npc.Runtime.IsIdle = false
+	} else if currentTime-npc.Runtime.LastMoveTime > idleThreshold {
+		npc.Runtime.IsIdle = true
 	}
 }
 
 // Attack the player if within attack range and the NPC is hostile.
 func (npc *NPC) Attack(playerPos Position) (hit bool) {
-	if !npc.Data.Hostile || npc.Data.Dead || npc.Data.AttackState != AttackIdle {
+	if !npc.Data.Hostile || npc.Runtime.Dead || npc.Runtime.AttackState != AttackIdle || npc.HasStatusEffect(StatusStun) {
 		return false
 	}
 
 	dist := npc.distanceToNPC(playerPos.X, playerPos.Y)
-	if dist <= int(math.Round(npc.Data.AttackRange)) {
-		// Face the player before attacking
-		if playerPos.X > npc.Pos.X {
-			npc.Data.Direction = DirRight
-		} else if playerPos.X < npc.Pos.X {
-			npc.Data.Direction = DirLeft
-		} else if playerPos.Y > npc.Pos.Y {
-			npc.Data.Direction = DirDown
-		} else if playerPos.Y < npc.Pos.Y {
-			npc.Data.Direction = DirUp
-		}
-
-		currentTime := float32(rl.GetTime())
-		attackCooldown := float32(0.0)
-		if npc.Data.AttackSpeed > 0 {
-			attackCooldown = 1.0 / float32(npc.Data.AttackSpeed)
-		} else {
-			attackCooldown = 60
-		}
+	if dist <= int(math.Round(npc.Runtime.AttackRange)) {
+		return npc.startAttack(playerPos, float32(rl.GetTime()))
+	}
+	return false
+}
 
-		if (currentTime - npc.Data.LastAttackTime) >= attackCooldown {
-			npc.Data.LastAttackTime = currentTime
-			npc.Data.LastMoveTime = currentTime
-			npc.Data.AttackState = AttackStart
-			npc.Data.AttackStateTime = 0
-			npc.Data.IsIdle = false
-			return true
+// startAttack faces the target and starts an attack, if the attack cooldown has passed.
+func (npc *NPC) startAttack(target Position, currentTime float32) bool {
+	if target.X > npc.Pos.X {
+		npc.Runtime.Direction = DirRight
+	} else if target.X < npc.Pos.X {
+		npc.Runtime.Direction = DirLeft
+	} else if target.Y > npc.Pos.Y {
+		npc.Runtime.Direction = DirDown
+	} else if target.Y < npc.Pos.Y {
+		npc.Runtime.Direction = DirUp
+	}
+
+	attackCooldown := float32(0.0)
+	if npc.Runtime.AttackSpeed > 0 {
+		attackCooldown = 1.0 / float32(npc.Runtime.AttackSpeed)
+	} else {
+		attackCooldown = 60
+	}
+
+	if npc.Runtime.takingTurn || (currentTime-npc.Runtime.LastAttackTime) >= attackCooldown {
+		npc.Runtime.LastAttackTime = currentTime
+		npc.Runtime.LastMoveTime = currentTime
+		npc.Runtime.AttackState = AttackStart
+		npc.Runtime.AttackStateTime = 0
+		npc.Runtime.IsIdle = false
+		// A Once attack animation plays from the start for every attack
+		if npc.Data.AttackTexture != nil {
+			npc.Data.AttackTexture.Restart(rl.GetTime())
 		}
+		return true
 	}
 	return false
 }
@@ -565,7 +760,7 @@ func (npc *NPC) knockback(playerPos Position, tiles [][]Tile, dist int) {
 	tempY := npc.Pos.Y
 
 	// Calculate target beam.Position based on direction
-	switch npc.Data.Direction {
+	switch npc.Runtime.Direction {
 	case DirRight:
 		// Check each tile in the knockback path
 		for i := 1; i <= dist; i++ {
@@ -608,14 +803,19 @@ func (npc *NPC) knockback(playerPos Position, tiles [][]Tile, dist int) {
 	npc.Pos.Y = tempY
 
 	// Face the player after knockback
-	if playerPos.X > npc.Pos.X {
-		npc.Data.Direction = DirRight
-	} else if playerPos.X < npc.Pos.X {
-		npc.Data.Direction = DirLeft
-	} else if playerPos.Y > npc.Pos.Y {
-		npc.Data.Direction = DirDown
-	} else if playerPos.Y < npc.Pos.Y {
-		npc.Data.Direction = DirUp
+	npc.faceTowards(playerPos)
+}
+
+// faceTowards turns the NPC to face a position, preferring left and right.
+func (npc *NPC) faceTowards(pos Position) {
+	if pos.X > npc.Pos.X {
+		npc.Runtime.Direction = DirRight
+	} else if pos.X < npc.Pos.X {
+		npc.Runtime.Direction = DirLeft
+	} else if pos.Y > npc.Pos.Y {
+		npc.Runtime.Direction = DirDown
+	} else if pos.Y < npc.Pos.Y {
+		npc.Runtime.Direction = DirUp
 	}
 }
 
@@ -669,34 +869,39 @@ func (npc *NPC) canMoveTo(newX, newY int, currMap *Map) bool {
 	// Check all tiles the NPC would occupy at the new position
 	for dx := 0; dx < width; dx++ {
 		for dy := 0; dy < height; dy++ {
-			checkX := newX + dx
-			checkY := newY + dy
-
-			// Check bounds
-			if checkX <= 0 || checkX >= len(currMap.Tiles[0])-1 ||
-				checkY <= 0 || checkY >= len(currMap.Tiles)-1 {
+			if !currMap.canOccupy(newX+dx, newY+dy, npc) {
 				return false
 			}
+		}
+	}
+	return true
+}
 
-			// Check tile type
-			if currMap.Tiles[checkY][checkX].Type == WallTile ||
-				currMap.Tiles[checkY][checkX].Type == ChestTile {
-				return false
-			}
+// canOccupy reports if something can stand on a tile. The outer edge of the map, impassable tiles,
+// tiles under a spanning texture, living impassable NPCs other than self, and blocking items are all off limits.
+func (m *Map) canOccupy(x, y int, self *NPC) bool {
+	// Check bounds, the outer edge of the map is off limits
+	tile, ok := m.TileAt(Position{X: x, Y: y})
+	if !ok || x == 0 || y == 0 || y == len(m.Tiles)-1 || x == len(m.Tiles[y])-1 {
+		return false
+	}
 
-			// Check for other NPCs (excluding self)
-			for _, otherNPC := range currMap.NPCs {
-				if otherNPC != npc && !otherNPC.Data.Dead &&
-					otherNPC.Data.Impassable && otherNPC.occupiesTile(checkX, checkY) {
-					return false
-				}
-			}
+	// Check the tile's collision, its type unless overridden
+	if !tile.IsPassable() {
+		return false
+	}
+	if _, _, covered := m.SpanAt(Position{X: x, Y: y}); covered {
+		return false
+	}
 
-			// Check for items
-			if currMap.Items.IsBlocked(checkX, checkY) {
-				return false
-			}
+	// Check for other NPCs (excluding self)
+	for _, otherNPC := range m.NPCs {
+		if otherNPC != self && !otherNPC.Runtime.Dead &&
+			otherNPC.Data.Impassable && otherNPC.occupiesTile(x, y) {
+			return false
 		}
 	}
-	return true
+
+	// Check for items
+	return !m.Items.IsBlocked(x, y)
 }
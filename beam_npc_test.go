@@ -0,0 +1,117 @@
+package beam
+
+import "testing"
+
+// newTestSpawnMap returns a 6x6 floor map, with a wall at 2,2 and a chest at 4,1.
+func newTestSpawnMap() *Map {
+	m := newFloorMap(6, 6)
+	m.Tiles[2][2].Type = WallTile
+	m.Tiles[1][4].Type = ChestTile
+	return m
+}
+
+// TestMapIsSpawnable checks spawns off the map, or covering a wall or chest, are rejected.
+func TestMapIsSpawnable(t *testing.T) {
+	m := newTestSpawnMap()
+	tests := []struct {
+		name string
+		pos  Position
+		size NPCSize
+		want bool
+	}{
+		{"open floor", Position{X: 0, Y: 0}, NPCSize1x1, true},
+		{"unset size is 1x1", Position{X: 5, Y: 5}, 0, true},
+		{"left of the map", Position{X: -1, Y: 0}, NPCSize1x1, false},
+		{"below the map", Position{X: 0, Y: 6}, NPCSize1x1, false},
+		{"on a wall", Position{X: 2, Y: 2}, NPCSize1x1, false},
+		{"on a chest", Position{X: 4, Y: 1}, NPCSize1x1, false},
+		{"2x2 covering a wall", Position{X: 1, Y: 1}, NPCSize2x2, false},
+		{"2x2 covering a chest", Position{X: 3, Y: 0}, NPCSize2x2, false},
+		{"2x2 clear of both", Position{X: 0, Y: 3}, NPCSize2x2, true},
+		{"2x2 past the edge", Position{X: 5, Y: 4}, NPCSize2x2, false},
+		{"3x3 in the corner", Position{X: 3, Y: 3}, NPCSize3x3, true},
+	}
+	for _, tt := range tests {
+		if got := m.IsSpawnable(tt.pos, tt.size); got != tt.want {
+			t.Errorf("%s: IsSpawnable(%v, %d) = %v, want %v", tt.name, tt.pos, tt.size, got, tt.want)
+		}
+	}
+}
+
+// TestMapClampSpawnPos checks large NPCs are pulled back onto the map.
+func TestMapClampSpawnPos(t *testing.T) {
+	m := newTestSpawnMap()
+	tests := []struct {
+		pos  Position
+		size NPCSize
+		want Position
+	}{
+		{Position{X: 3, Y: 3}, NPCSize1x1, Position{X: 3, Y: 3}},
+		{Position{X: 5, Y: 5}, NPCSize1x1, Position{X: 5, Y: 5}},
+		{Position{X: 5, Y: 5}, NPCSize2x2, Position{X: 4, Y: 4}},
+		{Position{X: 5, Y: 1}, NPCSize3x3, Position{X: 3, Y: 1}},
+		{Position{X: -2, Y: 9}, NPCSize1x1, Position{X: 0, Y: 5}},
+	}
+	for _, tt := range tests {
+		if got := m.ClampSpawnPos(tt.pos, tt.size); got != tt.want {
+			t.Errorf("ClampSpawnPos(%v, %d) = %v, want %v", tt.pos, tt.size, got, tt.want)
+		}
+	}
+}
+
+// TestNPCOccupiesTiles checks a 2x2 NPC covers exactly the four tiles down and right of its position.
+func TestNPCOccupiesTiles(t *testing.T) {
+	npc := &NPC{Pos: Position{X: 2, Y: 3}, Data: NPCData{Size: NPCSize2x2, Impassable: true}}
+	occupied := 0
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			want := (x == 2 || x == 3) && (y == 3 || y == 4)
+			if npc.occupiesTile(x, y) != want {
+				t.Errorf("Expected occupiesTile(%d, %d) to be %v", x, y, want)
+			}
+			if npc.occupiesTile(x, y) {
+				occupied++
+			}
+		}
+	}
+	if occupied != 4 {
+		t.Errorf("Expected 4 occupied tiles, got %d", occupied)
+	}
+	if !(NPCs{npc}).IsBlocked(3, 4) || (NPCs{npc}).IsBlocked(4, 4) {
+		t.Errorf("Expected the NPC to block 3,4 but not 4,4")
+	}
+	if got := NPCSize2x2.String(); got != "2x2" {
+		t.Errorf("Expected the size to print as 2x2, got %q", got)
+	}
+}
+
+// TestMapResetNPCs checks a moved, damaged and debuffed NPC goes back to its spawn state.
+func TestMapResetNPCs(t *testing.T) {
+	npc := newTestTarget()
+	npc.Data.SpawnPos = Position{X: 1, Y: 1}
+	npc.Data.MoveSpeed = 4
+	m := &Map{NPCs: NPCs{npc}}
+	m.ResetNPCs()
+
+	npc.Pos = Position{X: 4, Y: 3}
+	npc.AddStatusEffect(StatusEffect{Kind: StatusSlow, Magnitude: 0.5, Remaining: 10})
+	npc.Runtime.Attack += 20
+	npc.TakeDamage(500, Position{})
+	if !npc.Runtime.Dead {
+		t.Fatal("Expected the NPC to be dead before the reset")
+	}
+
+	m.ResetNPCs()
+	if npc.Pos != npc.Data.SpawnPos {
+		t.Errorf("Expected the NPC back at %v, got %v", npc.Data.SpawnPos, npc.Pos)
+	}
+	if npc.Runtime.Dead || npc.Runtime.Health != npc.Data.MaxHealth {
+		t.Errorf("Expected the NPC alive at %d health, got %d (dead: %v)", npc.Data.MaxHealth, npc.Runtime.Health, npc.Runtime.Dead)
+	}
+	if npc.Runtime.Attack != npc.Data.BaseAttack || npc.Runtime.MoveSpeed != 4 || npc.Runtime.AttackSpeed != npc.Data.BaseAttackSpeed {
+		t.Errorf("Expected base stats to be restored, got attack %d, move speed %v", npc.Runtime.Attack, npc.Runtime.MoveSpeed)
+	}
+	if len(npc.Runtime.StatusEffects) != 0 || npc.Runtime.TookDamageThisFrame || npc.Runtime.KnockbackFrom != nil {
+		t.Errorf("Expected effects and damage state to be cleared")
+	}
+}
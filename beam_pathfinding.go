@@ -0,0 +1,219 @@
+package beam
+
+import (
+	"container/heap"
+	"fmt"
+
+	beam_math "github.com/ztkent/beam/math"
+)
+
+/*
+The pathfinding system supports:
+  - A* pathfinding across walkable tiles
+  - Walkability that respects walls, chests and blocking items
+  - Reachability validation, to catch objectives the player can't get to
+
+Example usage:
+    path := currMap.FindPath(currMap.Start, currMap.Exit[0])
+    if path == nil {
+        // No route to the exit
+    }
+
+    for _, issue := range currMap.ValidateReachability() {
+        fmt.Println(issue)
+    }
+*/
+
+// IsWalkable reports if the tile at (x, y) can be stood on.
+// Impassable tiles (walls and chests, unless overridden by Tile.Walkable), tiles under
+// a spanning texture and blocking items are not walkable. NPCs are ignored since they move.
+func (m *Map) IsWalkable(x, y int) bool {
+	tile, ok := m.TileAt(Position{X: x, Y: y})
+	if !ok || !tile.IsPassable() {
+		return false
+	}
+	if _, _, covered := m.SpanAt(Position{X: x, Y: y}); covered {
+		return false
+	}
+	return !m.Items.IsBlocked(x, y)
+}
+
+// FindPath returns the shortest walkable path from start to goal, including both ends.
+// Returns nil if the goal can't be reached.
+func (m *Map) FindPath(start, goal Position) Positions {
+	if !m.IsWalkable(goal.X, goal.Y) {
+		return nil
+	}
+	if start == goal {
+		return Positions{start}
+	}
+
+	cameFrom := make(map[Position]Position)
+	costs := map[Position]int{start: 0}
+	open := &pathQueue{}
+	heap.Push(open, &pathNode{pos: start, priority: beam_math.ManhattanDistance(start.X, start.Y, goal.X, goal.Y)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*pathNode).pos
+		if current == goal {
+			path := Positions{goal}
+			for current != start {
+				current = cameFrom[current]
+				path = append(Positions{current}, path...)
+			}
+			return path
+		}
+
+		for _, next := range m.Neighbors(current, false) {
+			if !m.IsWalkable(next.X, next.Y) {
+				continue
+			}
+			cost := costs[current] + 1
+			if prev, seen := costs[next]; seen && cost >= prev {
+				continue
+			}
+			costs[next] = cost
+			cameFrom[next] = current
+			heap.Push(open, &pathNode{pos: next, priority: cost + beam_math.ManhattanDistance(next.X, next.Y, goal.X, goal.Y)})
+		}
+	}
+	return nil
+}
+
+// WalkableRegion returns every walkable tile connected to pos.
+func (m *Map) WalkableRegion(pos Position) Positions {
+	if !m.IsWalkable(pos.X, pos.Y) {
+		return nil
+	}
+	region := Positions{}
+	visited := map[Position]bool{pos: true}
+	stack := Positions{pos}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		region = append(region, current)
+		for _, next := range m.Neighbors(current, false) {
+			if !visited[next] && m.IsWalkable(next.X, next.Y) {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return region
+}
+
+// ReachabilityIssue describes an objective the player can't reach from Start.
+type ReachabilityIssue struct {
+	Objective string
+	Pos       Position
+	Reason    string
+	// Region is the walkable area around the objective, cut off from Start.
+	// Empty if the objective itself is on a blocked tile.
+	Region Positions
+}
+
+func (r ReachabilityIssue) String() string {
+	return fmt.Sprintf("%s at (%d, %d) is unreachable: %s", r.Objective, r.Pos.X, r.Pos.Y, r.Reason)
+}
+
+// ValidateReachability checks that exits, dungeon entries, quest items and interactable NPCs
+// can be reached from Start. Items and NPCs only need to be reached from an adjacent tile.
+func (m *Map) ValidateReachability() []ReachabilityIssue {
+	issues := make([]ReachabilityIssue, 0)
+	if !m.IsWalkable(m.Start.X, m.Start.Y) {
+		return append(issues, ReachabilityIssue{
+			Objective: "Start",
+			Pos:       m.Start,
+			Reason:    "the start position is not on a walkable tile",
+		})
+	}
+
+	reachable := make(map[Position]bool)
+	for _, pos := range m.WalkableRegion(m.Start) {
+		reachable[pos] = true
+	}
+
+	// Exits and entries need to be stood on
+	standOn := func(name string, pos Position) {
+		if reachable[pos] {
+			return
+		}
+		issue := ReachabilityIssue{Objective: name, Pos: pos}
+		if m.IsWalkable(pos.X, pos.Y) {
+			issue.Reason = "it is fully walled off from the start"
+			issue.Region = m.WalkableRegion(pos)
+		} else {
+			issue.Reason = "it is on a wall or blocked tile"
+		}
+		issues = append(issues, issue)
+	}
+	for _, exit := range m.Exit {
+		standOn("Exit", exit)
+	}
+	for _, entry := range m.DungeonEntry {
+		standOn("Dungeon Entry", entry)
+	}
+
+	// Items and NPCs are interacted with from a neighboring tile
+	nextTo := func(name string, pos Position) {
+		if reachable[pos] {
+			return
+		}
+		for _, next := range pos.neighbors() {
+			if reachable[next] {
+				return
+			}
+		}
+		issue := ReachabilityIssue{Objective: name, Pos: pos, Reason: "it is fully walled off from the start"}
+		for _, next := range append(Positions{pos}, pos.neighbors()...) {
+			if region := m.WalkableRegion(next); len(region) > 0 {
+				issue.Region = region
+				break
+			}
+		}
+		if len(issue.Region) == 0 {
+			issue.Reason = "it is surrounded by walls or blocked tiles"
+		}
+		issues = append(issues, issue)
+	}
+	for _, item := range m.Items {
+		if !item.Removed && item.Type == ItemTypeQuestItem {
+			nextTo("Item "+item.Name, item.Pos)
+		}
+	}
+	for _, npc := range m.NPCs {
+		if npc.Data.Interactable {
+			nextTo("NPC "+npc.Data.Name, npc.Pos)
+		}
+	}
+	return issues
+}
+
+// neighbors returns the 4 tiles next to p.
+func (p Position) neighbors() Positions {
+	return Positions{
+		{X: p.X, Y: p.Y - 1},
+		{X: p.X + 1, Y: p.Y},
+		{X: p.X, Y: p.Y + 1},
+		{X: p.X - 1, Y: p.Y},
+	}
+}
+
+type pathNode struct {
+	pos      Position
+	priority int
+}
+
+// pathQueue is a min-heap of nodes ordered by priority.
+type pathQueue []*pathNode
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(*pathNode)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,87 @@
+package beam
+
+import (
+	"slices"
+	"testing"
+)
+
+// newDividedMap returns a 5x3 floor map split by a wall column at x=2, with one gap at (2, 1).
+func newDividedMap() *Map {
+	m := newFloorMap(5, 3)
+	for y := range m.Height {
+		if y != 1 {
+			m.Tiles[y][2] = Tile{Type: WallTile, Pos: Position{X: 2, Y: y}}
+		}
+	}
+	m.Start = Position{X: 0, Y: 1}
+	m.Exit = Positions{{X: 4, Y: 1}}
+	return m
+}
+
+// Test that FindPath walks through the gap in the wall
+func TestFindPath(t *testing.T) {
+	m := newDividedMap()
+	path := m.FindPath(m.Start, m.Exit[0])
+	if len(path) != 5 {
+		t.Fatalf("Expected a path of 5 positions, got %v", path)
+	}
+	if path[0] != m.Start || path[len(path)-1] != m.Exit[0] {
+		t.Errorf("Expected path from %v to %v, got %v", m.Start, m.Exit[0], path)
+	}
+	for i, pos := range path {
+		if !m.IsWalkable(pos.X, pos.Y) {
+			t.Errorf("Expected walkable path, got blocked %v", pos)
+		}
+		if i > 0 && !slices.Contains(path[i-1].neighbors(), pos) {
+			t.Errorf("Expected adjacent steps, got %v then %v", path[i-1], pos)
+		}
+	}
+	if issues := m.ValidateReachability(); len(issues) != 0 {
+		t.Errorf("Expected no reachability issues, got %v", issues)
+	}
+}
+
+// Test that an exit walled off from the start is reported with its region
+func TestValidateReachabilityWalledOffExit(t *testing.T) {
+	m := newDividedMap()
+	m.Tiles[1][2] = Tile{Type: WallTile, Pos: Position{X: 2, Y: 1}}
+	if path := m.FindPath(m.Start, m.Exit[0]); path != nil {
+		t.Errorf("Expected no path, got %v", path)
+	}
+
+	issues := m.ValidateReachability()
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %v", issues)
+	}
+	if issues[0].Objective != "Exit" || issues[0].Pos != m.Exit[0] {
+		t.Errorf("Expected Exit issue at %v, got %+v", m.Exit[0], issues[0])
+	}
+	if len(issues[0].Region) != 6 {
+		t.Errorf("Expected the exit's region to have 6 tiles, got %v", issues[0].Region)
+	}
+}
+
+// Test that a blocking item in the only gap walls off the exit
+func TestValidateReachabilityBlockingItem(t *testing.T) {
+	m := newDividedMap()
+	boulder := NewItem("boulder", "Boulder", ItemTypeMisc)
+	boulder.Pos = Position{X: 2, Y: 1}
+	boulder.Blocking = true
+	m.Items = Items{boulder}
+
+	if path := m.FindPath(m.Start, m.Exit[0]); path != nil {
+		t.Errorf("Expected no path, got %v", path)
+	}
+	issues := m.ValidateReachability()
+	if len(issues) != 1 || issues[0].Objective != "Exit" {
+		t.Fatalf("Expected 1 Exit issue, got %v", issues)
+	}
+	if issues[0].Reason != "it is fully walled off from the start" {
+		t.Errorf("Expected walled off reason, got %q", issues[0].Reason)
+	}
+
+	boulder.Blocking = false
+	if issues := m.ValidateReachability(); len(issues) != 0 {
+		t.Errorf("Expected no issues once the item stops blocking, got %v", issues)
+	}
+}
@@ -0,0 +1,104 @@
+package beam
+
+import (
+	"github.com/ztkent/beam/controls"
+)
+
+/*
+The Player is a ready-made controllable entity:
+  - Grid movement that respects walls, chests, impassable NPCs and blocking items
+  - Health and combat stats
+  - Talking to nearby interactable NPCs
+
+Example usage:
+    player := NewPlayer(currMap.Start, PlayerStats{MaxHealth: 100, Attack: 10, Defense: 5})
+    if cm.IsActionPressed(controls.ActionMoveUp) {
+        player.Move(DirUp, currMap)
+    }
+    player.TryInteract(currMap, cm)
+*/
+
+// Player is a controllable entity that moves a tile at a time.
+type Player struct {
+	Pos       Position    `json:"Pos"`
+	Direction Direction   `json:"Direction"`
+	Stats     PlayerStats `json:"Stats"`
+
+	// Poison, burn, slow and stun, removed by TickEffects when they run out
+	StatusEffects []StatusEffect `json:"StatusEffects,omitempty"`
+}
+
+// PlayerStats are the player's health and combat stats.
+type PlayerStats struct {
+	Health    int `json:"Health"`
+	MaxHealth int `json:"MaxHealth"`
+	Attack    int `json:"Attack"`
+	Defense   int `json:"Defense"`
+
+	Level      int `json:"Level"`
+	Experience int `json:"Experience"`
+}
+
+// NewPlayer creates a player at pos facing down, at full health.
+func NewPlayer(pos Position, stats PlayerStats) *Player {
+	stats.Health = stats.MaxHealth
+	stats.Level = max(stats.Level, 1)
+	return &Player{Pos: pos, Direction: DirDown, Stats: stats}
+}
+
+// Move turns the player to face dir and steps one tile that way, if nothing is in the way.
+// It uses the same collision checks as NPCs, and reports if the player moved.
+// A stunned player can't move or turn.
+func (p *Player) Move(dir Direction, currMap *Map) bool {
+	if p.HasStatusEffect(StatusStun) {
+		return false
+	}
+	p.Direction = dir
+	next := p.Facing()
+	if !currMap.canOccupy(next.X, next.Y, nil) {
+		return false
+	}
+	p.Pos = next
+	return true
+}
+
+// Facing returns the tile in front of the player.
+func (p *Player) Facing() Position {
+	switch p.Direction {
+	case DirRight:
+		return Position{X: p.Pos.X + 1, Y: p.Pos.Y}
+	case DirLeft:
+		return Position{X: p.Pos.X - 1, Y: p.Pos.Y}
+	case DirUp:
+		return Position{X: p.Pos.X, Y: p.Pos.Y - 1}
+	default:
+		return Position{X: p.Pos.X, Y: p.Pos.Y + 1}
+	}
+}
+
+// TryInteract starts a chat with a nearby interactable NPC when the interact action is pressed,
+// preferring the one the player is facing. It returns the NPC, or nil if there was no one to talk to.
+// A nil ControlsManager skips the input check.
+func (p *Player) TryInteract(currMap *Map, cm *controls.ControlsManager) *NPC {
+	if cm != nil && !cm.IsActionPressed(controls.ActionInteract) {
+		return nil
+	}
+	if currMap.NPCs.IsInteracting() {
+		return nil
+	}
+
+	nearby := currMap.NPCs.InteractableNearby(p.Pos)
+	if len(nearby) == 0 {
+		return nil
+	}
+	target := nearby[0]
+	facing := p.Facing()
+	for _, npc := range nearby {
+		if npc.occupiesTile(facing.X, facing.Y) {
+			target = npc
+			break
+		}
+	}
+	target.Interact(p.Pos, target.CurrentChat)
+	return target
+}
@@ -0,0 +1,63 @@
+package beam
+
+import "testing"
+
+// TestPlayerMoveBlockedByTiles checks walls, chests and the edge of the map stop the player, who still turns to face them.
+func TestPlayerMoveBlockedByTiles(t *testing.T) {
+	m := newTestSpawnMap()
+	for _, tc := range []struct {
+		name  string
+		start Position
+		dir   Direction
+		want  Position
+	}{
+		{"wall", Position{X: 2, Y: 3}, DirUp, Position{X: 2, Y: 3}},
+		{"chest", Position{X: 3, Y: 1}, DirRight, Position{X: 3, Y: 1}},
+		{"edge", Position{X: 1, Y: 1}, DirLeft, Position{X: 1, Y: 1}},
+		{"floor", Position{X: 1, Y: 1}, DirDown, Position{X: 1, Y: 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			player := NewPlayer(tc.start, PlayerStats{MaxHealth: 10})
+			moved := player.Move(tc.dir, m)
+			if player.Pos != tc.want || moved != (tc.want != tc.start) {
+				t.Errorf("Expected to end at %v (moved: %v), got %v (moved: %v)", tc.want, tc.want != tc.start, player.Pos, moved)
+			}
+			if player.Direction != tc.dir {
+				t.Errorf("Expected the player to face %v, got %v", tc.dir, player.Direction)
+			}
+		})
+	}
+}
+
+// TestPlayerMoveBlockedByNPCs checks living impassable NPCs and blocking items stop the player, and nothing else does.
+func TestPlayerMoveBlockedByNPCs(t *testing.T) {
+	m := newTestSpawnMap()
+	guard := &NPC{Pos: Position{X: 3, Y: 3}, Data: NPCData{Impassable: true}}
+	m.NPCs = NPCs{guard}
+	m.Items = Items{{Pos: Position{X: 4, Y: 4}, Blocking: true}}
+
+	player := NewPlayer(Position{X: 3, Y: 4}, PlayerStats{MaxHealth: 10})
+	if player.Move(DirUp, m) {
+		t.Errorf("Expected the impassable NPC to block the player, moved to %v", player.Pos)
+	}
+	if player.Move(DirRight, m) {
+		t.Errorf("Expected the blocking item to block the player, moved to %v", player.Pos)
+	}
+
+	guard.Runtime.Dead = true
+	if !player.Move(DirUp, m) || player.Pos != (Position{X: 3, Y: 3}) {
+		t.Errorf("Expected the player to walk over a dead NPC, got %v", player.Pos)
+	}
+
+	// A large NPC blocks every tile it covers
+	player.Pos = Position{X: 1, Y: 4}
+	m.NPCs = NPCs{{Pos: Position{X: 1, Y: 2}, Data: NPCData{Impassable: true, Size: NPCSize2x2}}}
+	if player.Move(DirUp, m) {
+		t.Errorf("Expected the 2x2 NPC to block the player, moved to %v", player.Pos)
+	}
+
+	m.NPCs[0].Data.Impassable = false
+	if !player.Move(DirUp, m) {
+		t.Errorf("Expected the player to walk through a passable NPC")
+	}
+}
@@ -0,0 +1,154 @@
+package beam
+
+import "errors"
+
+/*
+Resize changes the size of a map, keeping everything that still fits.
+The anchor is the part of the map that stays put, e.g. AnchorCenter grows
+or shrinks the map evenly on every side, AnchorTopLeft only on the right and bottom.
+
+Example usage:
+    // Add a 10 tile border around the map
+    if err := currMap.Resize(currMap.Width+20, currMap.Height+20, AnchorCenter); err != nil {
+        ...
+    }
+*/
+
+var ErrInvalidMapSize = errors.New("map size must be at least 1x1")
+
+// Anchor is the part of a map that stays in place when it's resized.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// offset returns how far content moves when a map of oldW x oldH is resized to newW x newH.
+// Centered content is rounded toward the top left when the change is odd.
+func (a Anchor) offset(oldW, oldH, newW, newH int) (dx, dy int) {
+	switch a % 3 {
+	case 1:
+		dx = (newW - oldW) / 2
+	case 2:
+		dx = newW - oldW
+	}
+	switch a / 3 {
+	case 1:
+		dy = (newH - oldH) / 2
+	case 2:
+		dy = newH - oldH
+	}
+	return dx, dy
+}
+
+// Resize changes the map to newW x newH tiles, with the anchor deciding which side tiles are added to
+// or cut from. Tiles that still fit keep their content and get their new Pos, added tiles are floor.
+// NPCs and items are moved with their tiles, and dropped if they no longer fit. The start and respawn
+// are clamped onto the map, exits, dungeon entries and trigger tiles that fall off it are removed,
+// along with any trigger left without tiles.
+func (m *Map) Resize(newW, newH int, anchor Anchor) error {
+	if newW < 1 || newH < 1 {
+		return ErrInvalidMapSize
+	}
+	oldH := len(m.Tiles)
+	oldW := 0
+	if oldH > 0 {
+		oldW = len(m.Tiles[0])
+	}
+	dx, dy := anchor.offset(oldW, oldH, newW, newH)
+	shift := func(pos Position) Position {
+		return Position{X: pos.X + dx, Y: pos.Y + dy}
+	}
+
+	tiles := make([][]Tile, newH)
+	for y := range tiles {
+		tiles[y] = make([]Tile, newW)
+		for x := range tiles[y] {
+			pos := Position{X: x, Y: y}
+			old := Position{X: x - dx, Y: y - dy}
+			if m.InBounds(old) {
+				tiles[y][x] = m.Tiles[old.Y][old.X]
+			} else {
+				tiles[y][x] = Tile{Type: FloorTile, Textures: []*AnimatedTexture{}}
+			}
+			tiles[y][x].Pos = pos
+		}
+	}
+	m.Tiles = tiles
+	m.Width, m.Height = newW, newH
+
+	npcs := m.NPCs[:0]
+	for _, npc := range m.NPCs {
+		npc.Pos = shift(npc.Pos)
+		width, height := npc.Data.Size.GetDimensions()
+		if !m.InBounds(npc.Pos) || !m.InBounds(Position{X: npc.Pos.X + width - 1, Y: npc.Pos.Y + height - 1}) {
+			continue
+		}
+		npc.Data.SpawnPos = m.ClampSpawnPos(shift(npc.Data.SpawnPos), npc.Data.Size)
+		npc.Runtime.PrevPos = shift(npc.Runtime.PrevPos)
+		npc.Runtime.KnockbackFrom = nil
+		npc.Runtime.hasVisual = false
+		npcs = append(npcs, npc)
+	}
+	clear(m.NPCs[len(npcs):])
+	m.NPCs = npcs
+
+	items := m.Items[:0]
+	for _, item := range m.Items {
+		item.Pos = shift(item.Pos)
+		if m.InBounds(item.Pos) {
+			items = append(items, item)
+		}
+	}
+	clear(m.Items[len(items):])
+	m.Items = items
+
+	m.Start = m.clampToMap(shift(m.Start))
+	m.Respawn = m.clampToMap(shift(m.Respawn))
+	m.Exit = m.shiftPositions(m.Exit, shift)
+	m.DungeonEntry = m.shiftPositions(m.DungeonEntry, shift)
+
+	triggers := m.Triggers[:0]
+	for _, trigger := range m.Triggers {
+		trigger.Positions = m.shiftPositions(trigger.Positions, shift)
+		if len(trigger.Positions) > 0 {
+			triggers = append(triggers, trigger)
+		}
+	}
+	clear(m.Triggers[len(triggers):])
+	if len(triggers) == 0 {
+		triggers = nil
+	}
+	m.Triggers = triggers
+	return nil
+}
+
+// shiftPositions moves each position, dropping those that end up off the map. A nil slice stays nil.
+func (m *Map) shiftPositions(positions Positions, shift func(Position) Position) Positions {
+	if positions == nil {
+		return nil
+	}
+	shifted := Positions{}
+	for _, pos := range positions {
+		if pos = shift(pos); m.InBounds(pos) {
+			shifted = append(shifted, pos)
+		}
+	}
+	return shifted
+}
+
+// clampToMap moves pos onto the nearest tile of the map.
+func (m *Map) clampToMap(pos Position) Position {
+	return Position{
+		X: min(max(pos.X, 0), len(m.Tiles[0])-1),
+		Y: min(max(pos.Y, 0), len(m.Tiles)-1),
+	}
+}
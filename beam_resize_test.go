@@ -0,0 +1,120 @@
+package beam
+
+import "testing"
+
+// newTestResizeMap returns a 4x4 floor map, with a wall at 1,1, an NPC at 2,2, an item at 3,3 and an exit at 3,0.
+func newTestResizeMap() *Map {
+	m := newTestSpawnMap()
+	m.Resize(4, 4, AnchorTopLeft)
+	m.Tiles[1][1].Type = WallTile
+	m.NPCs = NPCs{{Data: NPCData{Name: "guard", SpawnPos: Position{X: 2, Y: 2}}, Pos: Position{X: 2, Y: 2}}}
+	m.Items = Items{{ID: "coin", Pos: Position{X: 3, Y: 3}}}
+	m.Start = Position{X: 1, Y: 2}
+	m.Respawn = Position{X: 3, Y: 3}
+	m.Exit = Positions{{X: 3, Y: 0}}
+	m.Triggers = []Trigger{{Name: "trap", Positions: Positions{{X: 0, Y: 0}, {X: 3, Y: 3}}}}
+	return m
+}
+
+// checkTilePositions checks every tile's Pos matches where it is in the grid.
+func checkTilePositions(t *testing.T, m *Map) {
+	t.Helper()
+	for y, row := range m.Tiles {
+		for x, tile := range row {
+			if tile.Pos != (Position{X: x, Y: y}) {
+				t.Fatalf("Expected the tile at %d,%d to have that Pos, got %v", x, y, tile.Pos)
+			}
+		}
+	}
+}
+
+// TestMapResizeGrow grows the map from the top left, keeping everything where it was.
+func TestMapResizeGrow(t *testing.T) {
+	m := newTestResizeMap()
+	if err := m.Resize(6, 5, AnchorTopLeft); err != nil {
+		t.Fatal(err)
+	}
+	if m.Width != 6 || m.Height != 5 || len(m.Tiles) != 5 || len(m.Tiles[4]) != 6 {
+		t.Fatalf("Expected a 6x5 map, got %dx%d", m.Width, m.Height)
+	}
+	checkTilePositions(t, m)
+	if m.Tiles[1][1].Type != WallTile || m.Tiles[4][5].Type != FloorTile {
+		t.Error("Expected the wall to stay put, and new tiles to be floor")
+	}
+	if len(m.NPCs) != 1 || m.NPCs[0].Pos != (Position{X: 2, Y: 2}) || len(m.Items) != 1 || m.Items[0].Pos != (Position{X: 3, Y: 3}) {
+		t.Error("Expected the NPC and item to stay put")
+	}
+}
+
+// TestMapResizeShrink shrinks the map from the top left, dropping what falls off it.
+func TestMapResizeShrink(t *testing.T) {
+	m := newTestResizeMap()
+	if err := m.Resize(3, 3, AnchorTopLeft); err != nil {
+		t.Fatal(err)
+	}
+	checkTilePositions(t, m)
+	if len(m.NPCs) != 1 || len(m.Items) != 0 {
+		t.Errorf("Expected the NPC kept and the item dropped, got %d NPCs and %d items", len(m.NPCs), len(m.Items))
+	}
+	if len(m.Exit) != 0 || m.Exit == nil {
+		t.Errorf("Expected the exit to be removed, got %v", m.Exit)
+	}
+	if m.Respawn != (Position{X: 2, Y: 2}) {
+		t.Errorf("Expected the respawn clamped onto the map, got %v", m.Respawn)
+	}
+	if len(m.Triggers) != 1 || len(m.Triggers[0].Positions) != 1 {
+		t.Errorf("Expected the trap to keep only its tile on the map, got %v", m.Triggers)
+	}
+
+	if err := m.Resize(2, 2, AnchorTopLeft); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.NPCs) != 0 {
+		t.Error("Expected the NPC to be dropped once its tile is gone")
+	}
+	if err := m.Resize(0, 2, AnchorTopLeft); err != ErrInvalidMapSize {
+		t.Errorf("Expected %v, got %v", ErrInvalidMapSize, err)
+	}
+}
+
+// TestMapResizeCenter grows then shrinks the map around its center, moving everything with its tile.
+func TestMapResizeCenter(t *testing.T) {
+	m := newTestResizeMap()
+	if err := m.Resize(8, 6, AnchorCenter); err != nil {
+		t.Fatal(err)
+	}
+	checkTilePositions(t, m)
+	// Two columns are added on each side, and one row above and below
+	if m.Tiles[2][3].Type != WallTile {
+		t.Error("Expected the wall to move to 3,2")
+	}
+	npc := m.NPCs[0]
+	if npc.Pos != (Position{X: 4, Y: 3}) || npc.Data.SpawnPos != (Position{X: 4, Y: 3}) {
+		t.Errorf("Expected the NPC and its spawn to move to 4,3, got %v and %v", npc.Pos, npc.Data.SpawnPos)
+	}
+	if m.Items[0].Pos != (Position{X: 5, Y: 4}) || m.Start != (Position{X: 3, Y: 3}) || m.Exit[0] != (Position{X: 5, Y: 1}) {
+		t.Errorf("Expected the item, start and exit to move with their tiles, got %v, %v and %v", m.Items[0].Pos, m.Start, m.Exit)
+	}
+	if !m.Triggers[0].Contains(Position{X: 2, Y: 1}) {
+		t.Errorf("Expected the trap to move with its tiles, got %v", m.Triggers[0].Positions)
+	}
+
+	// Shrinking back around the center undoes the move
+	if err := m.Resize(4, 4, AnchorCenter); err != nil {
+		t.Fatal(err)
+	}
+	if m.Tiles[1][1].Type != WallTile || m.NPCs[0].Pos != (Position{X: 2, Y: 2}) || m.Items[0].Pos != (Position{X: 3, Y: 3}) {
+		t.Error("Expected shrinking around the center to put everything back")
+	}
+}
+
+// TestMapResizeBottomRight grows the map from the bottom right, adding tiles above and to the left.
+func TestMapResizeBottomRight(t *testing.T) {
+	m := newTestResizeMap()
+	if err := m.Resize(5, 6, AnchorBottomRight); err != nil {
+		t.Fatal(err)
+	}
+	if m.Tiles[3][2].Type != WallTile || m.NPCs[0].Pos != (Position{X: 3, Y: 4}) {
+		t.Errorf("Expected the wall and NPC to move one right and two down, got NPC at %v", m.NPCs[0].Pos)
+	}
+}
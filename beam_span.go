@@ -0,0 +1,98 @@
+package beam
+
+import "errors"
+
+/*
+Large decorations, like a 3x2 tree, are a single texture that spans several tiles.
+The texture lives on its anchor, the top left tile, and its first frame's SpanX and SpanY
+say how many tiles it covers. Every covered tile blocks movement.
+
+Example usage:
+    tree := NewSimpleTileTexture("tree")
+    tree.Frames[0].SpanX, tree.Frames[0].SpanY = 3, 2
+    if err := currMap.PlaceSpanTexture(Position{X: 4, Y: 6}, tree); err != nil {
+        ...
+    }
+*/
+
+// MaxTextureSpan is the most tiles a texture can span in either direction.
+const MaxTextureSpan = 8
+
+var (
+	ErrSpanOutOfBounds = errors.New("span doesn't fit on the map")
+	ErrSpanOverlap     = errors.New("span overlaps another span")
+)
+
+// Span returns how many tiles the texture covers across and down from its anchor, from its first frame.
+// Textures without a span cover just their own tile.
+func (t *AnimatedTexture) Span() (int, int) {
+	if len(t.Frames) == 0 {
+		return 1, 1
+	}
+	return min(max(1, t.Frames[0].SpanX), MaxTextureSpan), min(max(1, t.Frames[0].SpanY), MaxTextureSpan)
+}
+
+// IsSpanning reports if the texture covers more than its own tile.
+func (t *AnimatedTexture) IsSpanning() bool {
+	w, h := t.Span()
+	return w > 1 || h > 1
+}
+
+// SpanPositions returns the tiles a w by h span anchored at anchor covers, row by row from the anchor.
+func SpanPositions(anchor Position, w, h int) Positions {
+	positions := make(Positions, 0, w*h)
+	for y := anchor.Y; y < anchor.Y+h; y++ {
+		for x := anchor.X; x < anchor.X+w; x++ {
+			positions = append(positions, Position{X: x, Y: y})
+		}
+	}
+	return positions
+}
+
+// SpanAt returns the spanning texture covering pos and the anchor it's placed on, or false if there isn't one.
+func (m *Map) SpanAt(pos Position) (Position, *AnimatedTexture, bool) {
+	for dy := range MaxTextureSpan {
+		for dx := range MaxTextureSpan {
+			anchor := Position{X: pos.X - dx, Y: pos.Y - dy}
+			tile, ok := m.TileAt(anchor)
+			if !ok {
+				continue
+			}
+			for _, tex := range tile.Textures {
+				if tex == nil || !tex.IsSpanning() {
+					continue
+				}
+				if w, h := tex.Span(); dx < w && dy < h {
+					return anchor, tex, true
+				}
+			}
+		}
+	}
+	return Position{}, nil, false
+}
+
+// CanPlaceSpan reports why a w by h span can't be anchored at anchor, or nil if it can.
+// The span has to fit on the map, and can't cover a tile another span already covers.
+func (m *Map) CanPlaceSpan(anchor Position, w, h int) error {
+	for _, pos := range SpanPositions(anchor, w, h) {
+		if !m.InBounds(pos) {
+			return ErrSpanOutOfBounds
+		}
+		if _, _, covered := m.SpanAt(pos); covered {
+			return ErrSpanOverlap
+		}
+	}
+	return nil
+}
+
+// PlaceSpanTexture adds a spanning texture to the anchor tile, if the whole span can be placed.
+// Nothing is changed when it can't, see CanPlaceSpan.
+func (m *Map) PlaceSpanTexture(anchor Position, tex *AnimatedTexture) error {
+	w, h := tex.Span()
+	if err := m.CanPlaceSpan(anchor, w, h); err != nil {
+		return err
+	}
+	tile := &m.Tiles[anchor.Y][anchor.X]
+	tile.Textures = append(tile.Textures, tex)
+	return nil
+}
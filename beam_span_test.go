@@ -0,0 +1,90 @@
+package beam
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestSpanTexture(name string, w, h int) *AnimatedTexture {
+	tex := NewSimpleTileTexture(name)
+	tex.Frames[0].SpanX, tex.Frames[0].SpanY = w, h
+	return tex
+}
+
+// TestMapPlaceSpanBounds checks spans have to fit on the map, and can't overlap another span.
+func TestMapPlaceSpanBounds(t *testing.T) {
+	m := newTestSpawnMap()
+	if err := m.PlaceSpanTexture(Position{X: 3, Y: 3}, newTestSpanTexture("tree", 3, 2)); err != nil {
+		t.Fatalf("Expected the tree to fit, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		anchor Position
+		w, h   int
+		want   error
+	}{
+		{"fits in the corner", Position{X: 0, Y: 0}, 2, 2, nil},
+		{"reaches the edge", Position{X: 0, Y: 4}, 3, 2, nil},
+		{"past the right edge", Position{X: 5, Y: 0}, 2, 1, ErrSpanOutOfBounds},
+		{"past the bottom edge", Position{X: 0, Y: 5}, 1, 2, ErrSpanOutOfBounds},
+		{"anchored off the map", Position{X: -1, Y: 0}, 2, 2, ErrSpanOutOfBounds},
+		{"on the tree's anchor", Position{X: 3, Y: 3}, 1, 1, ErrSpanOverlap},
+		{"covers the tree's corner", Position{X: 4, Y: 2}, 2, 2, ErrSpanOverlap},
+		{"reaches under the tree", Position{X: 1, Y: 4}, 3, 1, ErrSpanOverlap},
+		{"just left of the tree", Position{X: 1, Y: 3}, 2, 2, nil},
+	}
+	for _, tt := range tests {
+		if err := m.CanPlaceSpan(tt.anchor, tt.w, tt.h); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// A rejected span leaves the map alone
+	if err := m.PlaceSpanTexture(Position{X: 4, Y: 4}, newTestSpanTexture("rock", 2, 2)); !errors.Is(err, ErrSpanOverlap) {
+		t.Errorf("Expected ErrSpanOverlap, got %v", err)
+	}
+	if len(m.Tiles[4][4].Textures) != 0 {
+		t.Errorf("Expected the rejected rock not to be placed")
+	}
+}
+
+// TestMapSpanCollision checks every tile a span covers blocks movement, and the tiles around it don't.
+func TestMapSpanCollision(t *testing.T) {
+	m := newTestSpawnMap()
+	tree := newTestSpanTexture("tree", 3, 2)
+	if err := m.PlaceSpanTexture(Position{X: 1, Y: 3}, tree); err != nil {
+		t.Fatal(err)
+	}
+
+	covered := SpanPositions(Position{X: 1, Y: 3}, 3, 2)
+	if len(covered) != 6 {
+		t.Fatalf("Expected a 3x2 span to cover 6 tiles, got %d", len(covered))
+	}
+	for _, pos := range covered {
+		anchor, tex, ok := m.SpanAt(pos)
+		if !ok || anchor != (Position{X: 1, Y: 3}) || tex != tree {
+			t.Errorf("Expected the tree to cover %v, got %v", pos, anchor)
+		}
+		if m.IsWalkable(pos.X, pos.Y) {
+			t.Errorf("Expected %v under the tree not to be walkable", pos)
+		}
+		if pos.X > 0 && pos.Y < 5 && m.canOccupy(pos.X, pos.Y, nil) {
+			t.Errorf("Expected %v under the tree not to be occupiable", pos)
+		}
+	}
+	for _, pos := range []Position{{X: 0, Y: 3}, {X: 4, Y: 3}, {X: 1, Y: 2}, {X: 3, Y: 5}} {
+		if _, _, ok := m.SpanAt(pos); ok {
+			t.Errorf("Expected %v next to the tree not to be covered", pos)
+		}
+		if !m.IsWalkable(pos.X, pos.Y) {
+			t.Errorf("Expected %v next to the tree to be walkable", pos)
+		}
+	}
+
+	// Plain textures only cover their own tile
+	m.Tiles[0][0].Textures = []*AnimatedTexture{NewSimpleTileTexture("grass")}
+	if _, _, ok := m.SpanAt(Position{X: 0, Y: 0}); ok || !m.IsWalkable(0, 0) {
+		t.Errorf("Expected a plain texture not to block")
+	}
+}
@@ -0,0 +1,161 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const goldenMapPath = "testdata/map.golden.json"
+
+// newFloorMap returns a w x h map of plain floor tiles, for tests to build their walls and objects on.
+func newFloorMap(w, h int) *Map {
+	m := &Map{Width: w, Height: h, Tiles: make([][]Tile, h)}
+	for y := range m.Tiles {
+		m.Tiles[y] = make([]Tile, w)
+		for x := range m.Tiles[y] {
+			m.Tiles[y][x] = Tile{Type: FloorTile, Pos: Position{X: x, Y: y}}
+		}
+	}
+	return m
+}
+
+// newTestGoldenMap returns a small map using every part of the saved format.
+func newTestGoldenMap() *Map {
+	m := &Map{
+		Width:        2,
+		Height:       2,
+		Start:        Position{X: 0, Y: 0},
+		Respawn:      Position{X: 0, Y: 1},
+		Exit:         Positions{{X: 1, Y: 1}},
+		DungeonEntry: Positions{{X: 1, Y: 0}},
+		Mode:         TurnBased,
+	}
+	m.Tiles = [][]Tile{
+		{
+			{Type: FloorTile, Pos: Position{X: 0, Y: 0}, Textures: []*AnimatedTexture{NewSimpleTileTexture("grass")}},
+			{Type: WallTile, Pos: Position{X: 1, Y: 0}, Textures: []*AnimatedTexture{}, Properties: map[string]string{"solid": "true", "height": "2"}},
+		},
+		{
+			{Type: FloorTile, Pos: Position{X: 0, Y: 1}, Textures: []*AnimatedTexture{{
+				Frames: []Texture{
+					{Name: "water_1", Rotation: 90, ScaleX: 1, ScaleY: 1, Tint: rl.Blue, MirrorX: true, SpanX: 2},
+					{Name: "water_2", ScaleX: 1, ScaleY: 1, Tint: rl.White},
+				},
+				IsAnimated:    true,
+				AnimationTime: 0.5,
+				Layer:         ForegroundLayer,
+				PlayMode:      PingPong,
+			}}},
+			{Type: FloorTile, Pos: Position{X: 1, Y: 1}},
+		},
+	}
+	m.Tiles[1][1].SetWalkable(false)
+
+	guard := &NPC{Pos: Position{X: 1, Y: 1}, Data: NPCData{
+		ID:        "guard",
+		Name:      "Guard",
+		Texture:   NewSimpleNPCTexture("guard"),
+		SpawnPos:  Position{X: 1, Y: 1},
+		MaxHealth: 20,
+		Hostile:   true,
+		Faction:   "bandits",
+		Loot:      &LootTable{Entries: []LootEntry{{Item: NewItem("coin", "Coin", ItemTypeResource), Weight: 1, MinQty: 1, MaxQty: 3}}},
+	}}
+	potion := NewItem("potion", "Potion", ItemTypeConsumable)
+	potion.Pos = Position{X: 0, Y: 1}
+	potion.Stats.Effects = []ItemEffect{{ID: 1, Type: EffectHealth, Value: 10}}
+	m.NPCs = NPCs{guard}
+	m.Items = Items{potion}
+	m.SetFactionRelation("bandits", "guards", FactionHostile)
+	m.Triggers = []Trigger{{Name: "ambush", Positions: Positions{{X: 1, Y: 0}}, Payload: map[string]string{"spawn": "bandit"}}}
+	return m
+}
+
+// TestMapJSONGolden checks a known map serializes to exactly the documented shape in testdata.
+// Run with -update to rewrite the golden file after an intended format change.
+func TestMapJSONGolden(t *testing.T) {
+	data, err := json.MarshalIndent(newTestGoldenMap(), "", "  ")
+	if err != nil {
+		t.Fatalf("Expected no error marshaling, got %v", err)
+	}
+	data = append(data, '\n')
+
+	if *updateGolden {
+		if err := os.WriteFile(goldenMapPath, data, 0644); err != nil {
+			t.Fatalf("Expected no error writing %s, got %v", goldenMapPath, err)
+		}
+	}
+	golden, err := os.ReadFile(goldenMapPath)
+	if err != nil {
+		t.Fatalf("Expected no error reading %s, got %v", goldenMapPath, err)
+	}
+	if !bytes.Equal(data, golden) {
+		t.Errorf("Expected the map to match %s, got\n%s", goldenMapPath, data)
+	}
+}
+
+// TestMapJSONStable loads the golden file and checks it saves back byte for byte.
+func TestMapJSONStable(t *testing.T) {
+	golden, err := os.ReadFile(goldenMapPath)
+	if err != nil {
+		t.Fatalf("Expected no error reading %s, got %v", goldenMapPath, err)
+	}
+	var m Map
+	if err := json.Unmarshal(golden, &m); err != nil {
+		t.Fatalf("Expected no error unmarshaling, got %v", err)
+	}
+	data, err := json.MarshalIndent(&m, "", "  ")
+	if err != nil {
+		t.Fatalf("Expected no error marshaling, got %v", err)
+	}
+	if !bytes.Equal(append(data, '\n'), golden) {
+		t.Errorf("Expected the reloaded map to save the same bytes, got\n%s", data)
+	}
+}
+
+// TestMapJSONTags checks every exported field saved with a map names itself with a json tag,
+// so renaming a field can't silently change the format.
+func TestMapJSONTags(t *testing.T) {
+	pkg := reflect.TypeOf(Map{}).PkgPath()
+	seen := map[reflect.Type]bool{}
+	var check func(typ reflect.Type)
+	check = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || typ.PkgPath() != pkg || seen[typ] {
+			return
+		}
+		seen[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			if name, _, _ := strings.Cut(tag, ","); name != field.Name {
+				t.Errorf("Expected %s.%s to have the tag json:%q, got %q", typ.Name(), field.Name, field.Name, tag)
+			}
+			check(field.Type)
+		}
+	}
+	check(reflect.TypeOf(Map{}))
+	check(reflect.TypeOf(Player{}))
+	check(reflect.TypeOf(Inventory{}))
+
+	if !seen[reflect.TypeOf(Texture{})] || !seen[reflect.TypeOf(NPCData{})] || !seen[reflect.TypeOf(LootEntry{})] {
+		t.Errorf("Expected the check to reach the textures, NPCs and loot, got %d types", len(seen))
+	}
+}
@@ -1,6 +1,8 @@
 package beam
 
 import (
+	"math/rand"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -36,19 +38,29 @@ Example usage:
         AnimationTime: 0.1, // 10 frames per second
         Layer: ForegroundLayer,
     }
+
+    // Animate it on its own clock, started at a random point so copies don't move in step
+    animatedTexture.RandomizePhase(nil)
+    animatedTexture.Tick(rl.GetFrameTime())
+    frame := animatedTexture.Frame()
 */
 
 type Texture struct {
-	Name     string
-	Rotation float64
-	ScaleX   float64
-	ScaleY   float64
-	OffsetX  float64
-	OffsetY  float64
-	Tint     rl.Color
-	MirrorX  bool
-	MirrorY  bool
-	Origin   rl.Vector2
+	Name     string     `json:"Name"`
+	Rotation float64    `json:"Rotation,omitempty"`
+	ScaleX   float64    `json:"ScaleX"`
+	ScaleY   float64    `json:"ScaleY"`
+	OffsetX  float64    `json:"OffsetX,omitempty"`
+	OffsetY  float64    `json:"OffsetY,omitempty"`
+	Tint     rl.Color   `json:"Tint"`
+	MirrorX  bool       `json:"MirrorX,omitempty"`
+	MirrorY  bool       `json:"MirrorY,omitempty"`
+	Origin   rl.Vector2 `json:"Origin"`
+
+	// SpanX and SpanY make the texture cover several tiles, across and down from the tile it's on.
+	// Zero is the same as 1, see Map.PlaceSpanTexture.
+	SpanX int `json:"SpanX,omitempty"`
+	SpanY int `json:"SpanY,omitempty"`
 }
 
 // Layers for rendering -
@@ -83,30 +95,178 @@ func OrderedLayers() []Layer {
 	}
 }
 
+// PlayMode is how an AnimatedTexture steps through its frames.
+type PlayMode int
+
+const (
+	// LoopForward plays the frames in order, then starts over.
+	LoopForward PlayMode = iota
+	// PingPong plays the frames forward then backward, e.g. for an idle bob.
+	PingPong
+	// Once plays the frames in order and holds the last one, e.g. for an attack.
+	Once
+)
+
+func (p PlayMode) String() string {
+	switch p {
+	case LoopForward:
+		return "Loop"
+	case PingPong:
+		return "Ping-Pong"
+	case Once:
+		return "Once"
+	default:
+		return "Unknown"
+	}
+}
+
+// FrameAt returns the index of the frame shown after elapsed seconds, in this mode,
+// for an animation of frameCount frames that each last animationTime seconds.
+func (p PlayMode) FrameAt(elapsed, animationTime float64, frameCount int) int {
+	if frameCount <= 1 || animationTime <= 0 || elapsed <= 0 {
+		return 0
+	}
+	step := int(elapsed / animationTime)
+	switch p {
+	case PingPong:
+		// 0 1 2 3 2 1, then again from 0
+		step %= 2*frameCount - 2
+		if step >= frameCount {
+			return 2*frameCount - 2 - step
+		}
+		return step
+	case Once:
+		return min(step, frameCount-1)
+	default:
+		return step % frameCount
+	}
+}
+
+// Finished reports whether an animation played in this mode has ended after elapsed seconds.
+// Only Once animations end, after the last frame has shown for its full animationTime.
+func (p PlayMode) Finished(elapsed, animationTime float64, frameCount int) bool {
+	return p == Once && elapsed >= animationTime*float64(frameCount)
+}
+
 type AnimatedTexture struct {
-	Frames []Texture
+	Frames []Texture `json:"Frames"`
+
+	IsAnimated    bool    `json:"IsAnimated,omitempty"`
+	AnimationTime float64 `json:"AnimationTime,omitempty"`
+	CurrentFrame  int     `json:"CurrentFrame,omitempty"`
+	Layer         Layer   `json:"Layer"`
 
-	IsAnimated    bool
-	AnimationTime float64
-	CurrentFrame  int
-	Layer         Layer
+	// PhaseOffset starts the texture's own clock this many seconds in, see Tick and Frame.
+	PhaseOffset float64 `json:"PhaseOffset,omitempty"`
+	// PlayMode is how the frames play, looping forward by default.
+	PlayMode PlayMode `json:"PlayMode,omitempty"`
 
 	lastFrameTime float64
+	elapsed       float64
+	reversing     bool // PingPong is stepping backward, for GetCurrentFrame
+	finished      bool // A Once animation has played through, for GetCurrentFrame
 }
 
+// GetCurrentFrame returns the frame for the wall clock, usually rl.GetTime(), stepping in the texture's PlayMode.
+// Every texture sharing the clock animates in step, use Tick and Frame to animate each on its own.
 func (t *AnimatedTexture) GetCurrentFrame(currentTime float64) Texture {
 	if len(t.Frames) == 0 {
 		return Texture{ScaleX: 1.0, ScaleY: 1.0, Tint: rl.White}
 	}
 	if len(t.Frames) > 1 {
-		if currentTime-t.lastFrameTime >= t.AnimationTime {
-			t.CurrentFrame = (t.CurrentFrame + 1) % len(t.Frames)
-			t.lastFrameTime = currentTime
-		}
 		if t.CurrentFrame >= len(t.Frames) {
 			t.CurrentFrame = 0
 		}
+		if !t.finished && currentTime-t.lastFrameTime >= t.AnimationTime {
+			t.CurrentFrame = t.nextFrame()
+			t.lastFrameTime = currentTime
+		}
 		return t.Frames[t.CurrentFrame]
 	}
 	return t.Frames[0]
 }
+
+// nextFrame returns the frame after CurrentFrame in the texture's PlayMode,
+// turning a PingPong around at either end and finishing a Once at the last frame.
+func (t *AnimatedTexture) nextFrame() int {
+	last := len(t.Frames) - 1
+	switch t.PlayMode {
+	case PingPong:
+		if t.CurrentFrame == last {
+			t.reversing = true
+		} else if t.CurrentFrame == 0 {
+			t.reversing = false
+		}
+		if t.reversing {
+			return t.CurrentFrame - 1
+		}
+		return t.CurrentFrame + 1
+	case Once:
+		if t.CurrentFrame == last {
+			t.finished = true
+			return last
+		}
+		return t.CurrentFrame + 1
+	default:
+		return (t.CurrentFrame + 1) % len(t.Frames)
+	}
+}
+
+// IsFinished reports whether a Once animation has played through and is holding its last frame,
+// on either the wall clock or the texture's own clock. Looping animations never finish.
+func (t *AnimatedTexture) IsFinished() bool {
+	if t.PlayMode != Once {
+		return false
+	}
+	return t.finished || t.PlayMode.Finished(t.elapsed+t.PhaseOffset, t.AnimationTime, len(t.Frames))
+}
+
+// Restart plays the animation again from its first frame, e.g. at the start of each attack.
+// currentTime is the wall clock GetCurrentFrame is called with.
+func (t *AnimatedTexture) Restart(currentTime float64) {
+	t.CurrentFrame = 0
+	t.lastFrameTime = currentTime
+	t.elapsed = 0
+	t.reversing = false
+	t.finished = false
+}
+
+// Tick advances the texture's own clock by dt seconds, usually rl.GetFrameTime().
+// Tick each texture once a frame, and stop ticking it to pause the animation.
+func (t *AnimatedTexture) Tick(dt float32) {
+	if dt <= 0 {
+		return
+	}
+	t.elapsed += float64(dt)
+	t.CurrentFrame = t.PlayMode.FrameAt(t.elapsed+t.PhaseOffset, t.AnimationTime, len(t.Frames))
+}
+
+// Frame returns the frame for the texture's own clock, the time it has been ticked plus its PhaseOffset.
+func (t *AnimatedTexture) Frame() Texture {
+	return t.FrameAt(t.elapsed + t.PhaseOffset)
+}
+
+// RandomizePhase sets PhaseOffset to a random point in the animation's loop.
+// Pass a seeded rng for repeatable offsets, or nil to use the global source.
+func (t *AnimatedTexture) RandomizePhase(rng *rand.Rand) {
+	float := rand.Float64
+	if rng != nil {
+		float = rng.Float64
+	}
+	t.PhaseOffset = float() * t.AnimationTime * float64(len(t.Frames))
+}
+
+// FrameAt returns the frame shown after elapsed seconds of playback in the texture's PlayMode, without touching the texture's own timing.
+// Use it to preview an animation on a separate clock, e.g. paused or stepped frame by frame.
+func (t *AnimatedTexture) FrameAt(elapsed float64) Texture {
+	if len(t.Frames) == 0 {
+		return Texture{ScaleX: 1.0, ScaleY: 1.0, Tint: rl.White}
+	}
+	return t.Frames[t.PlayMode.FrameAt(elapsed, t.AnimationTime, len(t.Frames))]
+}
+
+// AnimationFrameAt returns the index of the frame shown after elapsed seconds,
+// for an animation of frameCount frames that each last animationTime seconds. Playback loops.
+func AnimationFrameAt(elapsed, animationTime float64, frameCount int) int {
+	return LoopForward.FrameAt(elapsed, animationTime, frameCount)
+}
@@ -0,0 +1,200 @@
+package beam
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAnimationFrameAt(t *testing.T) {
+	tests := []struct {
+		elapsed       float64
+		animationTime float64
+		frameCount    int
+		want          int
+	}{
+		{0, 0.5, 4, 0},
+		{0.49, 0.5, 4, 0},
+		{0.5, 0.5, 4, 1},
+		{1.75, 0.5, 4, 3},
+		{2.0, 0.5, 4, 0}, // loops back to the first frame
+		{3.0, 0.5, 1, 0},
+		{3.0, 0, 4, 0},
+	}
+	for _, tt := range tests {
+		if got := AnimationFrameAt(tt.elapsed, tt.animationTime, tt.frameCount); got != tt.want {
+			t.Errorf("AnimationFrameAt(%v, %v, %d) = %d, want %d", tt.elapsed, tt.animationTime, tt.frameCount, got, tt.want)
+		}
+	}
+}
+
+// TestFrameAtLeavesTimingAlone checks previewing a frame doesn't advance the texture's own animation.
+func TestFrameAtLeavesTimingAlone(t *testing.T) {
+	tex := &AnimatedTexture{
+		Frames:        []Texture{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		IsAnimated:    true,
+		AnimationTime: 0.25,
+	}
+	if frame := tex.FrameAt(0.5); frame.Name != "c" {
+		t.Errorf("Expected frame c after 0.5s, got %s", frame.Name)
+	}
+	if tex.CurrentFrame != 0 || tex.lastFrameTime != 0 {
+		t.Errorf("Expected FrameAt to leave the animation state alone, got frame %d at %v", tex.CurrentFrame, tex.lastFrameTime)
+	}
+}
+
+// TestAnimatedTextureTick checks textures on their own clock advance with Tick, and stay put when not ticked.
+func TestAnimatedTextureTick(t *testing.T) {
+	tex := &AnimatedTexture{
+		Frames:        []Texture{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		IsAnimated:    true,
+		AnimationTime: 0.25,
+	}
+	if frame := tex.Frame(); frame.Name != "a" {
+		t.Errorf("Expected frame a before ticking, got %s", frame.Name)
+	}
+	for range 3 {
+		tex.Tick(0.1)
+	}
+	if frame := tex.Frame(); frame.Name != "b" || tex.CurrentFrame != 1 {
+		t.Errorf("Expected frame b after 0.3s, got %s", frame.Name)
+	}
+	tex.Tick(0)
+	tex.Tick(-1)
+	if frame := tex.Frame(); frame.Name != "b" {
+		t.Errorf("Expected no change without time passing, got %s", frame.Name)
+	}
+	if (&AnimatedTexture{}).Frame().ScaleX != 1 {
+		t.Error("Expected a default frame for a texture without frames")
+	}
+}
+
+// TestAnimatedTexturePhaseOffset checks two copies with different phase offsets show different frames after the same ticks.
+func TestAnimatedTexturePhaseOffset(t *testing.T) {
+	newTex := func(offset float64) *AnimatedTexture {
+		return &AnimatedTexture{
+			Frames:        []Texture{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+			IsAnimated:    true,
+			AnimationTime: 0.25,
+			PhaseOffset:   offset,
+		}
+	}
+	first, second := newTex(0), newTex(0.5)
+	for range 10 {
+		first.Tick(1.0 / 60)
+		second.Tick(1.0 / 60)
+		if first.Frame().Name == second.Frame().Name {
+			t.Fatalf("Expected different frames, both show %s", first.Frame().Name)
+		}
+	}
+	if first.Frame().Name != "a" || second.Frame().Name != "c" {
+		t.Errorf("Expected frames a and c, got %s and %s", first.Frame().Name, second.Frame().Name)
+	}
+
+	// Random offsets fall within one loop, and a seeded source repeats them
+	a, b := newTex(0), newTex(0)
+	a.RandomizePhase(rand.New(rand.NewSource(3)))
+	b.RandomizePhase(rand.New(rand.NewSource(3)))
+	if a.PhaseOffset != b.PhaseOffset {
+		t.Errorf("Expected the same offset from the same seed, got %v and %v", a.PhaseOffset, b.PhaseOffset)
+	}
+	if a.PhaseOffset < 0 || a.PhaseOffset >= 1 {
+		t.Errorf("Expected an offset within the 1s loop, got %v", a.PhaseOffset)
+	}
+}
+
+// TestPlayModeFrameAt checks the frame sequence each play mode steps through.
+func TestPlayModeFrameAt(t *testing.T) {
+	tests := []struct {
+		mode PlayMode
+		want []int
+	}{
+		{LoopForward, []int{0, 1, 2, 3, 0, 1, 2, 3, 0}},
+		{PingPong, []int{0, 1, 2, 3, 2, 1, 0, 1, 2}},
+		{Once, []int{0, 1, 2, 3, 3, 3, 3, 3, 3}},
+	}
+	for _, tt := range tests {
+		for step, want := range tt.want {
+			elapsed := float64(step)*0.5 + 0.25
+			if got := tt.mode.FrameAt(elapsed, 0.5, 4); got != want {
+				t.Errorf("%s: expected frame %d at step %d, got %d", tt.mode, want, step, got)
+			}
+		}
+	}
+	// Two frames ping-pong back and forth without repeating either
+	for step, want := range []int{0, 1, 0, 1} {
+		if got := PingPong.FrameAt(float64(step)+0.5, 1, 2); got != want {
+			t.Errorf("Expected two-frame ping-pong frame %d at step %d, got %d", want, step, got)
+		}
+	}
+}
+
+// TestGetCurrentFramePlayModes checks the wall clock steps through frames in each play mode.
+func TestGetCurrentFramePlayModes(t *testing.T) {
+	tests := []struct {
+		mode PlayMode
+		want string
+	}{
+		{LoopForward, "bcabcab"},
+		{PingPong, "bcbabcb"},
+		{Once, "bcccccc"},
+	}
+	for _, tt := range tests {
+		tex := &AnimatedTexture{
+			Frames:        []Texture{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			IsAnimated:    true,
+			AnimationTime: 1,
+			PlayMode:      tt.mode,
+		}
+		got := ""
+		for second := 1; second <= len(tt.want); second++ {
+			got += tex.GetCurrentFrame(float64(second)).Name
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected frames %s, got %s", tt.mode, tt.want, got)
+		}
+	}
+}
+
+// TestAnimatedTextureIsFinished checks a Once animation finishes on either clock, and restarts.
+func TestAnimatedTextureIsFinished(t *testing.T) {
+	newTex := func(mode PlayMode) *AnimatedTexture {
+		return &AnimatedTexture{
+			Frames:        []Texture{{Name: "a"}, {Name: "b"}},
+			IsAnimated:    true,
+			AnimationTime: 0.5,
+			PlayMode:      mode,
+		}
+	}
+
+	tex := newTex(Once)
+	tex.Tick(0.75)
+	if tex.IsFinished() {
+		t.Errorf("Expected a Once animation not to be finished on its last frame")
+	}
+	tex.Tick(0.25)
+	if !tex.IsFinished() || tex.Frame().Name != "b" {
+		t.Errorf("Expected a Once animation to finish holding frame b, got finished=%v on %s", tex.IsFinished(), tex.Frame().Name)
+	}
+	tex.Restart(0)
+	if tex.IsFinished() || tex.Frame().Name != "a" {
+		t.Errorf("Expected a restarted animation to play from frame a")
+	}
+
+	tex = newTex(Once)
+	for _, now := range []float64{0.5, 1.0} {
+		tex.GetCurrentFrame(now)
+	}
+	if !tex.IsFinished() {
+		t.Errorf("Expected a Once animation to finish on the wall clock")
+	}
+	tex.Restart(1.0)
+	if frame := tex.GetCurrentFrame(1.25); frame.Name != "a" || tex.IsFinished() {
+		t.Errorf("Expected a restarted animation to show frame a, got %s", frame.Name)
+	}
+
+	loop := newTex(LoopForward)
+	loop.Tick(10)
+	if loop.IsFinished() {
+		t.Errorf("Expected a looping animation never to finish")
+	}
+}
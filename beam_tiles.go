@@ -1,6 +1,9 @@
 package beam
 
 import (
+	"strconv"
+	"strings"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -16,10 +19,70 @@ const (
 	ChestTile
 )
 
+func (tile TileType) String() string {
+	switch tile {
+	case WallTile:
+		return "Wall"
+	case FloorTile:
+		return "Floor"
+	case ChestTile:
+		return "Chest"
+	default:
+		return "Unknown"
+	}
+}
+
 type Tile struct {
-	Type     TileType
-	Pos      Position
-	Textures []*AnimatedTexture
+	Type     TileType           `json:"Type"`
+	Pos      Position           `json:"Pos"`
+	Textures []*AnimatedTexture `json:"Textures"`
+
+	// Properties is custom data for the game, like "water" or "damage_per_step".
+	// Values are strings, use PropBool and PropInt to read them.
+	Properties map[string]string `json:"Properties,omitempty"`
+
+	// Walkable overrides the collision that comes from the tile's Type when set,
+	// e.g. to make a decorative floor tile solid, or let something walk over a wall.
+	Walkable *bool `json:"Walkable,omitempty"`
+}
+
+// IsPassable reports if the tile itself can be walked on, ignoring what's on it.
+// Walkable decides when it's set, otherwise walls and chests block and everything else is passable.
+func (t Tile) IsPassable() bool {
+	if t.Walkable != nil {
+		return *t.Walkable
+	}
+	return t.Type != WallTile && t.Type != ChestTile
+}
+
+// SetWalkable overrides the tile's collision, see Walkable.
+func (t *Tile) SetWalkable(walkable bool) {
+	t.Walkable = &walkable
+}
+
+// ClearWalkable goes back to the collision from the tile's Type.
+func (t *Tile) ClearWalkable() {
+	t.Walkable = nil
+}
+
+// PropBool reports if the property is set to a true value, like "true" or "1".
+// Missing or invalid values are false.
+func (t Tile) PropBool(key string) bool {
+	value, err := strconv.ParseBool(t.Properties[key])
+	return err == nil && value
+}
+
+// PropInt returns the property as an int, and if it was set to a valid integer.
+func (t Tile) PropInt(key string) (int, bool) {
+	value, ok := t.Properties[key]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
 }
 
 func NewSimpleTileTexture(name ...string) *AnimatedTexture {
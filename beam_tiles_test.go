@@ -0,0 +1,141 @@
+package beam
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestTilePropertiesJSON checks properties survive a round trip, and tiles without any leave them out.
+func TestTilePropertiesJSON(t *testing.T) {
+	tile := Tile{
+		Type:       FloorTile,
+		Pos:        Position{X: 3, Y: 4},
+		Properties: map[string]string{"water": "true", "damage_per_step": "5"},
+	}
+	data, err := json.Marshal(tile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Tile
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Properties) != 2 || loaded.Properties["water"] != "true" || loaded.Properties["damage_per_step"] != "5" {
+		t.Errorf("Expected both properties after a round trip, got %v", loaded.Properties)
+	}
+
+	plain, err := json.Marshal(Tile{Type: WallTile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), "Properties") {
+		t.Errorf("Expected no Properties key for a tile without any, got %s", plain)
+	}
+}
+
+// TestTilePropertiesOldFormat checks maps saved before properties existed still load, without any.
+func TestTilePropertiesOldFormat(t *testing.T) {
+	var m Map
+	data := `{"Width":1,"Height":1,"Tiles":[[{"Type":1,"Pos":{"X":0,"Y":0},"Textures":null}]]}`
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Tiles[0][0].Properties != nil {
+		t.Errorf("Expected nil properties, got %v", m.Tiles[0][0].Properties)
+	}
+}
+
+// TestTileProps checks the typed property helpers.
+func TestTileProps(t *testing.T) {
+	tile := Tile{Properties: map[string]string{
+		"water":  "true",
+		"lava":   "1",
+		"dry":    "false",
+		"odd":    "maybe",
+		"damage": " 5 ",
+		"nope":   "five",
+	}}
+	for key, want := range map[string]bool{"water": true, "lava": true, "dry": false, "odd": false, "missing": false} {
+		if got := tile.PropBool(key); got != want {
+			t.Errorf("PropBool(%q) = %v, want %v", key, got, want)
+		}
+	}
+	if n, ok := tile.PropInt("damage"); !ok || n != 5 {
+		t.Errorf("PropInt(damage) = %d, %v, want 5, true", n, ok)
+	}
+	if _, ok := tile.PropInt("nope"); ok {
+		t.Error("Expected PropInt to fail for a non-number")
+	}
+	if _, ok := tile.PropInt("missing"); ok {
+		t.Error("Expected PropInt to fail for a missing property")
+	}
+	if (Tile{}).PropBool("water") {
+		t.Error("Expected a tile without properties to be false")
+	}
+}
+
+// TestTileWalkableOverride checks an explicit Walkable takes precedence over the collision from the tile's Type.
+func TestTileWalkableOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		tileType TileType
+		walkable *bool
+		want     bool
+	}{
+		{"floor", FloorTile, nil, true},
+		{"wall", WallTile, nil, false},
+		{"chest", ChestTile, nil, false},
+		{"solid floor", FloorTile, new(bool), false},
+		{"walkable wall", WallTile, ptrTo(true), true},
+		{"walkable chest", ChestTile, ptrTo(true), true},
+		{"blocked wall", WallTile, new(bool), false},
+	}
+	for _, tt := range tests {
+		tile := Tile{Type: tt.tileType, Walkable: tt.walkable}
+		if got := tile.IsPassable(); got != tt.want {
+			t.Errorf("%s: expected passable %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	tile := Tile{Type: WallTile}
+	tile.SetWalkable(true)
+	if !tile.IsPassable() {
+		t.Error("Expected SetWalkable to make a wall passable")
+	}
+	tile.ClearWalkable()
+	if tile.IsPassable() {
+		t.Error("Expected ClearWalkable to go back to the wall blocking")
+	}
+}
+
+// TestMapWalkableOverride checks walking, NPC movement, spawning and pathfinding all follow the override.
+func TestMapWalkableOverride(t *testing.T) {
+	m := newTestSpawnMap()
+	m.Tiles[2][2].SetWalkable(true)  // The wall
+	m.Tiles[3][3].SetWalkable(false) // A floor tile
+
+	if !m.IsWalkable(2, 2) || !m.canOccupy(2, 2, nil) || !m.IsSpawnable(Position{X: 2, Y: 2}, NPCSize1x1) {
+		t.Error("Expected the walkable wall to be walkable")
+	}
+	if m.IsWalkable(3, 3) || m.canOccupy(3, 3, nil) || m.IsSpawnable(Position{X: 3, Y: 3}, NPCSize1x1) {
+		t.Error("Expected the solid floor to block")
+	}
+	npc := &NPC{Data: NPCData{Size: NPCSize1x1}}
+	if npc.canMoveTo(3, 3, m) || !npc.canMoveTo(2, 2, m) {
+		t.Error("Expected the NPC to move onto the walkable wall, but not the solid floor")
+	}
+
+	// The direct route along row 3 is blocked, so the path goes around
+	path := m.FindPath(Position{X: 1, Y: 3}, Position{X: 4, Y: 3})
+	if path == nil || path.PositionExists(Position{X: 3, Y: 3}) {
+		t.Errorf("Expected a path around the solid floor, got %v", path)
+	}
+	if path := m.FindPath(Position{X: 2, Y: 1}, Position{X: 2, Y: 3}); len(path) != 3 {
+		t.Errorf("Expected a straight path through the walkable wall, got %v", path)
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,96 @@
+package beam
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+/*
+Triggers are named events that fire when something steps on one of their tiles,
+like a trap, a cutscene, or a transition to another zone. Triggers can overlap,
+a tile can be part of any number of them.
+
+Example usage:
+    currMap.AddTrigger(Trigger{
+        Name:      "boss_intro",
+        Positions: Positions{{X: 10, Y: 4}, {X: 11, Y: 4}},
+        Payload:   map[string]string{"cutscene": "boss"},
+    })
+
+    // After the player moves
+    for _, trigger := range currMap.TriggersAt(player.Pos) {
+        game.Fire(trigger.Name, trigger.Payload)
+    }
+*/
+
+var (
+	ErrTriggerName    = errors.New("trigger needs a name")
+	ErrTriggerExists  = errors.New("a trigger with that name already exists")
+	ErrTriggerNoTiles = errors.New("trigger needs at least one tile")
+	ErrTriggerOffMap  = errors.New("trigger tile is off the map")
+)
+
+// Trigger is a named event fired when something steps on any of its positions.
+type Trigger struct {
+	Name      string    `json:"Name"`
+	Positions Positions `json:"Positions"`
+
+	// Payload is custom data for the game, like the cutscene to play or the map to load.
+	Payload map[string]string `json:"Payload,omitempty"`
+}
+
+// Contains reports if the trigger fires on pos.
+func (t Trigger) Contains(pos Position) bool {
+	return t.Positions.PositionExists(pos)
+}
+
+// TriggersAt returns every trigger that fires on pos, in the order they were added.
+func (m *Map) TriggersAt(pos Position) []Trigger {
+	var triggers []Trigger
+	for _, trigger := range m.Triggers {
+		if trigger.Contains(pos) {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// Trigger returns the trigger with the name, and if there was one.
+func (m *Map) Trigger(name string) (Trigger, bool) {
+	i := slices.IndexFunc(m.Triggers, func(t Trigger) bool { return t.Name == name })
+	if i < 0 {
+		return Trigger{}, false
+	}
+	return m.Triggers[i], true
+}
+
+// AddTrigger adds a trigger to the map. Names are unique, and every position has to be on the map.
+func (m *Map) AddTrigger(trigger Trigger) error {
+	if strings.TrimSpace(trigger.Name) == "" {
+		return ErrTriggerName
+	}
+	if _, exists := m.Trigger(trigger.Name); exists {
+		return ErrTriggerExists
+	}
+	if len(trigger.Positions) == 0 {
+		return ErrTriggerNoTiles
+	}
+	for _, pos := range trigger.Positions {
+		if _, ok := m.TileAt(pos); !ok {
+			return ErrTriggerOffMap
+		}
+	}
+	m.Triggers = append(m.Triggers, trigger)
+	return nil
+}
+
+// RemoveTrigger removes the trigger with the name, reporting if there was one.
+func (m *Map) RemoveTrigger(name string) bool {
+	i := slices.IndexFunc(m.Triggers, func(t Trigger) bool { return t.Name == name })
+	if i < 0 {
+		return false
+	}
+	m.Triggers = slices.Delete(m.Triggers, i, i+1)
+	return true
+}
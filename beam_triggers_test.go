@@ -0,0 +1,107 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestTriggersAt checks overlapping triggers all fire on the tiles they share, in the order they were added.
+func TestTriggersAt(t *testing.T) {
+	m := newTestSpawnMap()
+	for _, trigger := range []Trigger{
+		{Name: "trap", Positions: Positions{{X: 1, Y: 1}, {X: 2, Y: 1}}, Payload: map[string]string{"damage": "5"}},
+		{Name: "zone", Positions: Positions{{X: 2, Y: 1}, {X: 3, Y: 1}}},
+	} {
+		if err := m.AddTrigger(trigger); err != nil {
+			t.Fatalf("Expected to add %s, got %v", trigger.Name, err)
+		}
+	}
+
+	names := func(triggers []Trigger) []string {
+		var names []string
+		for _, trigger := range triggers {
+			names = append(names, trigger.Name)
+		}
+		return names
+	}
+	tests := []struct {
+		pos  Position
+		want []string
+	}{
+		{Position{X: 1, Y: 1}, []string{"trap"}},
+		{Position{X: 2, Y: 1}, []string{"trap", "zone"}},
+		{Position{X: 3, Y: 1}, []string{"zone"}},
+		{Position{X: 4, Y: 4}, nil},
+		{Position{X: -1, Y: 1}, nil},
+	}
+	for _, tt := range tests {
+		if got := names(m.TriggersAt(tt.pos)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expected triggers %v at %v, got %v", tt.want, tt.pos, got)
+		}
+	}
+	if trap := m.TriggersAt(Position{X: 1, Y: 1})[0]; trap.Payload["damage"] != "5" {
+		t.Errorf("Expected the trap's payload, got %v", trap.Payload)
+	}
+
+	if !m.RemoveTrigger("trap") || m.RemoveTrigger("trap") {
+		t.Error("Expected to remove the trap once")
+	}
+	if got := names(m.TriggersAt(Position{X: 2, Y: 1})); !reflect.DeepEqual(got, []string{"zone"}) {
+		t.Errorf("Expected only the zone after removing the trap, got %v", got)
+	}
+}
+
+// TestAddTriggerErrors checks triggers need a unique name and tiles on the map.
+func TestAddTriggerErrors(t *testing.T) {
+	m := newTestSpawnMap()
+	m.AddTrigger(Trigger{Name: "door", Positions: Positions{{X: 1, Y: 1}}})
+	tests := []struct {
+		name    string
+		trigger Trigger
+		want    error
+	}{
+		{"no name", Trigger{Name: " ", Positions: Positions{{X: 1, Y: 1}}}, ErrTriggerName},
+		{"taken name", Trigger{Name: "door", Positions: Positions{{X: 2, Y: 1}}}, ErrTriggerExists},
+		{"no tiles", Trigger{Name: "empty"}, ErrTriggerNoTiles},
+		{"off the map", Trigger{Name: "far", Positions: Positions{{X: 1, Y: 1}, {X: 6, Y: 0}}}, ErrTriggerOffMap},
+	}
+	for _, tt := range tests {
+		if err := m.AddTrigger(tt.trigger); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+	if len(m.Triggers) != 1 {
+		t.Errorf("Expected only the door to be added, got %d triggers", len(m.Triggers))
+	}
+}
+
+// TestTriggersJSON checks triggers survive saving, and maps without any leave them out.
+func TestTriggersJSON(t *testing.T) {
+	m := newTestSpawnMap()
+	data, _ := json.Marshal(m)
+	if containsKey(data, "Triggers") {
+		t.Error("Expected a map without triggers to leave them out")
+	}
+
+	m.AddTrigger(Trigger{Name: "exit", Positions: Positions{{X: 5, Y: 5}}, Payload: map[string]string{"map": "cave"}})
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Map
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Triggers, m.Triggers) {
+		t.Errorf("Expected triggers %v, got %v", m.Triggers, loaded.Triggers)
+	}
+}
+
+func containsKey(data []byte, key string) bool {
+	var fields map[string]json.RawMessage
+	json.Unmarshal(data, &fields)
+	_, ok := fields[key]
+	return ok
+}
@@ -0,0 +1,29 @@
+package beam
+
+import "github.com/ztkent/beam/controls"
+
+// StepTurn advances every NPC on a TurnBased map by exactly one turn.
+// Each living NPC attacks the player or an enemy in range, ignoring its attack cooldown,
+// or takes at most one step, ignoring its move speed. NPCs in a conversation sit the turn out.
+//
+// Call it instead of NPC.Update on the frame the player acts, and NPC.Update on every other frame,
+// which keeps animations, knockback and chats running without the NPCs acting.
+// It returns the NPCs that attacked the player, and the NPCs that finished dying, like NPC.Update.
+func (m *Map) StepTurn(playerPos Position, cm *controls.ControlsManager) (attackers, died NPCs) {
+	for _, npc := range m.NPCs {
+		npc.Runtime.takingTurn = true
+		if !npc.Runtime.Dead && !npc.Runtime.IsInteracting {
+			// A new turn cuts short the last turn's attack animation
+			npc.Runtime.AttackState = AttackIdle
+			npc.Runtime.AttackStateTime = 0
+			if npc.Attack(playerPos) {
+				attackers = append(attackers, npc)
+			}
+		}
+		if npc.Update(playerPos, m, cm) {
+			died = append(died, npc)
+		}
+		npc.Runtime.takingTurn = false
+	}
+	return attackers, died
+}
@@ -0,0 +1,63 @@
+package beam
+
+import (
+	"testing"
+
+	beam_math "github.com/ztkent/beam/math"
+)
+
+// newTestTurnMap returns a 10x10 turn based map with walls around the edge.
+func newTestTurnMap() *Map {
+	m := newFloorMap(10, 10)
+	m.Mode = TurnBased
+	for y := range m.Tiles {
+		for x := range m.Tiles[y] {
+			if x == 0 || y == 0 || x == 9 || y == 9 {
+				m.Tiles[y][x].Type = WallTile
+			}
+		}
+	}
+	return m
+}
+
+// TestStepTurnMovesOneTile checks each StepTurn moves every NPC at most one tile, with no time passing between turns.
+func TestStepTurnMovesOneTile(t *testing.T) {
+	m := newTestTurnMap()
+	chaser := &NPC{Pos: Position{X: 8, Y: 8}, Data: NPCData{MoveSpeed: 1, Hostile: true, AggroRange: 20, Impassable: true}}
+	wanderer := &NPC{Pos: Position{X: 2, Y: 7}, Data: NPCData{MoveSpeed: 1, WanderRange: 3, SpawnPos: Position{X: 2, Y: 7}, Impassable: true}}
+	m.NPCs = NPCs{chaser, wanderer}
+	m.NPCs.ResetRuntime()
+	playerPos := Position{X: 1, Y: 1}
+
+	for turn := 1; turn <= 5; turn++ {
+		before := []Position{chaser.Pos, wanderer.Pos}
+		m.StepTurn(playerPos, nil)
+		for i, npc := range m.NPCs {
+			if dist := beam_math.ManhattanDistance(before[i].X, before[i].Y, npc.Pos.X, npc.Pos.Y); dist > 1 {
+				t.Fatalf("Turn %d: expected NPC %d to move at most one tile, moved %d from %v to %v", turn, i, dist, before[i], npc.Pos)
+			}
+		}
+		if got := beam_math.ManhattanDistance(chaser.Pos.X, chaser.Pos.Y, 8, 8); got != turn {
+			t.Errorf("Turn %d: expected the chaser to take a step every turn, it's %d tiles from its start", turn, got)
+		}
+	}
+}
+
+// TestStepTurnAttackCooldown checks an NPC next to the player attacks every turn, despite a long attack cooldown.
+func TestStepTurnAttackCooldown(t *testing.T) {
+	m := newTestTurnMap()
+	npc := &NPC{Pos: Position{X: 4, Y: 4}, Data: NPCData{Hostile: true, BaseAttackRange: 1, BaseAttackSpeed: 0.01, MoveSpeed: 1}}
+	m.NPCs = NPCs{npc}
+	m.NPCs.ResetRuntime()
+	playerPos := Position{X: 5, Y: 4}
+
+	for turn := 1; turn <= 3; turn++ {
+		attackers, _ := m.StepTurn(playerPos, nil)
+		if len(attackers) != 1 || attackers[0] != npc {
+			t.Errorf("Turn %d: expected the NPC to attack the player, got %d attackers", turn, len(attackers))
+		}
+		if npc.Pos != (Position{X: 4, Y: 4}) {
+			t.Errorf("Turn %d: expected an attacking NPC to stay put, moved to %v", turn, npc.Pos)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package beam
+
+import "fmt"
+
+// Severity is how serious a ValidationIssue is.
+type Severity int
+
+const (
+	// SeverityWarning is something that's probably a mistake, but the map still works.
+	SeverityWarning Severity = iota
+	// SeverityError breaks the map, like an exit the player can't reach.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// ValidationIssue is a problem found by Map.Validate.
+type ValidationIssue struct {
+	Severity Severity
+	Pos      Position
+	Message  string
+	// Region is the walkable area an unreachable objective is cut off in, if there is one.
+	Region Positions
+}
+
+func (v ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", v.Severity, v.Message)
+}
+
+// Validate checks the map is ready to ship. It reports:
+//   - Start, Respawn, exits and dungeon entries that are off the map
+//   - Objectives that can't be reached from Start, see ValidateReachability
+//   - NPCs placed on a wall, a chest, or partly off the map
+//   - Walls and chests without a texture, which are invisible in game
+//
+// Errors come before warnings, otherwise issues are in the order they were found.
+func (m *Map) Validate() []ValidationIssue {
+	var errs, warnings []ValidationIssue
+
+	// Special positions have to be on the map
+	offMap := make(map[Position]bool)
+	checkBounds := func(name string, pos Position) {
+		if !m.InBounds(pos) {
+			offMap[pos] = true
+			errs = append(errs, ValidationIssue{
+				Severity: SeverityError,
+				Pos:      pos,
+				Message:  fmt.Sprintf("%s at (%d, %d) is off the %dx%d map", name, pos.X, pos.Y, m.Width, m.Height),
+			})
+		}
+	}
+	checkBounds("Start", m.Start)
+	checkBounds("Respawn", m.Respawn)
+	for _, exit := range m.Exit {
+		checkBounds("Exit", exit)
+	}
+	for _, entry := range m.DungeonEntry {
+		checkBounds("Dungeon Entry", entry)
+	}
+
+	// A start off the map is already reported, and everything would be unreachable from it
+	if !offMap[m.Start] {
+		for _, issue := range m.ValidateReachability() {
+			if offMap[issue.Pos] {
+				continue
+			}
+			errs = append(errs, ValidationIssue{
+				Severity: SeverityError,
+				Pos:      issue.Pos,
+				Message:  issue.String(),
+				Region:   issue.Region,
+			})
+		}
+	}
+
+	for _, npc := range m.NPCs {
+		if !m.IsSpawnable(npc.Pos, npc.Data.Size) {
+			errs = append(errs, ValidationIssue{
+				Severity: SeverityError,
+				Pos:      npc.Pos,
+				Message:  fmt.Sprintf("NPC %s at (%d, %d) is on a wall, a chest, or off the map", npc.Data.Name, npc.Pos.X, npc.Pos.Y),
+			})
+		}
+	}
+
+	for y := range m.Tiles {
+		for x, tile := range m.Tiles[y] {
+			if tile.Type != FloorTile && len(tile.Textures) == 0 {
+				warnings = append(warnings, ValidationIssue{
+					Severity: SeverityWarning,
+					Pos:      Position{X: x, Y: y},
+					Message:  fmt.Sprintf("%s at (%d, %d) has no texture", tile.Type, x, y),
+				})
+			}
+		}
+	}
+	return append(errs, warnings...)
+}
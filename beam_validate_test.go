@@ -0,0 +1,81 @@
+package beam
+
+import "testing"
+
+// newTestValidateMap returns a 7x5 floor map split in two by a textured wall down x = 3,
+// starting on the left with the exit on the right.
+func newTestValidateMap() *Map {
+	m := newFloorMap(7, 5)
+	m.Start = Position{X: 1, Y: 1}
+	m.Exit = Positions{{X: 5, Y: 1}}
+	for y := range m.Tiles {
+		m.Tiles[y][3] = Tile{Type: WallTile, Pos: Position{X: 3, Y: y}, Textures: []*AnimatedTexture{NewSimpleTileTexture("wall")}}
+	}
+	return m
+}
+
+// TestValidateDisconnectedExit checks an exit behind a wall is an error, with the region it's cut off in.
+func TestValidateDisconnectedExit(t *testing.T) {
+	m := newTestValidateMap()
+	issues := m.Validate()
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %v", issues)
+	}
+	if issues[0].Severity != SeverityError || issues[0].Pos != (Position{X: 5, Y: 1}) {
+		t.Errorf("Expected an error for the exit at (5, 1), got %v", issues[0])
+	}
+	if len(issues[0].Region) != 15 {
+		t.Errorf("Expected the exit to be cut off in the 15 tiles right of the wall, got %d", len(issues[0].Region))
+	}
+
+	// Opening the wall fixes it
+	m.Tiles[2][3].Type = FloorTile
+	if issues := m.Validate(); len(issues) != 0 {
+		t.Errorf("Expected no issues once the wall is opened, got %v", issues)
+	}
+}
+
+// TestValidateNPCOnWall checks NPCs on walls are errors, and untextured walls are warnings after them.
+func TestValidateNPCOnWall(t *testing.T) {
+	m := newTestValidateMap()
+	m.Tiles[2][3].Type = FloorTile
+	m.NPCs = NPCs{
+		{Pos: Position{X: 3, Y: 0}, Data: NPCData{Name: "Stuck"}},
+		{Pos: Position{X: 1, Y: 3}, Data: NPCData{Name: "Fine"}},
+		{Pos: Position{X: 6, Y: 3}, Data: NPCData{Name: "Big", Size: NPCSize2x2}},
+	}
+	m.Tiles[4][0].Type = ChestTile
+
+	issues := m.Validate()
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %v", issues)
+	}
+	want := []struct {
+		severity Severity
+		pos      Position
+	}{
+		{SeverityError, Position{X: 3, Y: 0}},
+		{SeverityError, Position{X: 6, Y: 3}},
+		{SeverityWarning, Position{X: 0, Y: 4}},
+	}
+	for i, w := range want {
+		if issues[i].Severity != w.severity || issues[i].Pos != w.pos {
+			t.Errorf("Expected issue %d to be a %v at %v, got %v", i, w.severity, w.pos, issues[i])
+		}
+	}
+}
+
+// TestValidateOffMap checks special positions off the map are reported once, without reachability noise.
+func TestValidateOffMap(t *testing.T) {
+	m := newTestValidateMap()
+	m.Exit = Positions{{X: 1, Y: 3}, {X: 9, Y: 9}}
+	m.Respawn = Position{X: -1, Y: 0}
+
+	issues := m.Validate()
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %v", issues)
+	}
+	if issues[0].Pos != (Position{X: -1, Y: 0}) || issues[1].Pos != (Position{X: 9, Y: 9}) {
+		t.Errorf("Expected the respawn and second exit to be off the map, got %v", issues)
+	}
+}
@@ -0,0 +1,44 @@
+package beam
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// UpdateVisual slides the NPC's VisualPos from the tile it last moved from towards its current Pos.
+// Each step takes as long as the NPC waits between moves, 1/MoveSpeed seconds.
+// NPCs that jump more than a tile, or can't move, snap into place.
+// It only affects rendering, draw the NPC at Runtime.VisualPos instead of Pos*tileSize.
+func (npc *NPC) UpdateVisual(dt float32, tileSize int) {
+	rt := &npc.Runtime
+	if !rt.hasVisual || npc.Pos != rt.visualTarget {
+		if rt.hasVisual && isOneStep(rt.visualTarget, npc.Pos) {
+			rt.PrevPos = rt.visualTarget
+			rt.MoveProgress = 0
+		} else {
+			rt.PrevPos = npc.Pos
+			rt.MoveProgress = 1
+		}
+		rt.visualTarget = npc.Pos
+		rt.hasVisual = true
+	}
+
+	if rt.MoveSpeed > 0 {
+		rt.MoveProgress = min(1, rt.MoveProgress+dt*float32(rt.MoveSpeed))
+	} else {
+		rt.MoveProgress = 1
+	}
+	rt.VisualPos = LerpTiles(rt.PrevPos, npc.Pos, rt.MoveProgress, tileSize)
+}
+
+// LerpTiles returns the pixel position a fraction of the way from one tile to another.
+func LerpTiles(from, to Position, fraction float32, tileSize int) rl.Vector2 {
+	fraction = max(0, min(1, fraction))
+	return rl.Vector2{
+		X: (float32(from.X) + float32(to.X-from.X)*fraction) * float32(tileSize),
+		Y: (float32(from.Y) + float32(to.Y-from.Y)*fraction) * float32(tileSize),
+	}
+}
+
+// isOneStep reports if b is next to a, including diagonally.
+func isOneStep(a, b Position) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
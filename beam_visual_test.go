@@ -0,0 +1,43 @@
+package beam
+
+import "testing"
+
+// TestUpdateVisualLerp checks a one tile step slides over 1/MoveSpeed seconds, and longer jumps snap.
+func TestUpdateVisualLerp(t *testing.T) {
+	npc := &NPC{Pos: Position{X: 2, Y: 2}, Data: NPCData{MoveSpeed: 2}}
+	npc.ResetRuntime()
+	const tileSize = 32
+
+	npc.UpdateVisual(0, tileSize)
+	if got := npc.Runtime.VisualPos; got.X != 64 || got.Y != 64 {
+		t.Fatalf("Expected the NPC to start on its tile at (64, 64), got %v", got)
+	}
+
+	// A step right takes half a second at 2 moves per second
+	npc.Pos.X = 3
+	for _, tc := range []struct {
+		dt       float32
+		progress float32
+		x        float32
+	}{
+		{0, 0, 64},
+		{0.125, 0.25, 72},
+		{0.125, 0.5, 80},
+		{0.5, 1, 96},
+	} {
+		npc.UpdateVisual(tc.dt, tileSize)
+		if npc.Runtime.MoveProgress != tc.progress || npc.Runtime.VisualPos.X != tc.x || npc.Runtime.VisualPos.Y != 64 {
+			t.Errorf("Expected progress %v at x %v, got %v at %v", tc.progress, tc.x, npc.Runtime.MoveProgress, npc.Runtime.VisualPos)
+		}
+	}
+	if npc.Runtime.PrevPos != (Position{X: 2, Y: 2}) {
+		t.Errorf("Expected the previous position to be (2, 2), got %v", npc.Runtime.PrevPos)
+	}
+
+	// Jumping further than a tile, like respawning, snaps
+	npc.Pos = Position{X: 7, Y: 7}
+	npc.UpdateVisual(0, tileSize)
+	if got := npc.Runtime.VisualPos; got.X != 224 || got.Y != 224 {
+		t.Errorf("Expected the NPC to snap to (224, 224), got %v", got)
+	}
+}
@@ -29,6 +29,21 @@ type Chat struct {
 	StartTime     time.Time
 	Font          rl.Font
 	Dialogs       []Dialog
+
+	// Typewriter effect, in characters per second. 0 shows the text instantly.
+	RevealSpeed float32
+	skipReveal  bool
+
+	// Callbacks for game logic. OnFinish is called once when the conversation ends,
+	// OnChoice is called with the ID of each choice the player picks.
+	OnFinish       func()
+	OnChoice       func(choiceID string)
+	finishNotified bool
+
+	// Branching conversations, used instead of Dialogs when set
+	Tree           *DialogTree
+	CurrentNode    string
+	SelectedChoice int
 }
 
 func NewChat() *Chat {
@@ -51,6 +66,17 @@ func NewChatWithDialogs(dialogs []Dialog) *Chat {
 	return chat
 }
 
+// NewChatWithTree creates a chat that walks a branching dialog tree.
+func NewChatWithTree(tree *DialogTree) *Chat {
+	chat := &Chat{
+		State:       DialogHidden,
+		Tree:        tree,
+		CurrentNode: tree.StartNodeID,
+		Font:        rl.GetFontDefault(),
+	}
+	return chat
+}
+
 func DefaultDialog() []Dialog {
 	return []Dialog{
 		{
@@ -69,15 +95,24 @@ func DefaultDialog() []Dialog {
 }
 
 func (c *Chat) Update(cm *controls.ControlsManager) {
+	if c.Tree != nil {
+		c.updateTree(cm)
+		return
+	}
+
 	switch c.State {
 	case DialogVisible:
-		// Check if duration has passed
-		if time.Since(c.StartTime) >= c.Dialogs[c.CurrentDialog].Duration {
+		// Check if duration has passed, once all the text is showing
+		if time.Since(c.StartTime) >= c.Dialogs[c.CurrentDialog].Duration && c.IsFullyRevealed() {
 			c.State = DialogWaiting
 		}
-		// Check for continue input
+		// Check for continue input, the first press skips to the full text
 		if cm.IsActionPressed(controls.ActionConfirm) {
-			c.NextDialog()
+			if !c.IsFullyRevealed() {
+				c.RevealAll()
+			} else {
+				c.NextDialog()
+			}
 		}
 
 	case DialogWaiting:
@@ -90,6 +125,12 @@ func (c *Chat) Update(cm *controls.ControlsManager) {
 func (c *Chat) Show() {
 	c.State = DialogVisible
 	c.StartTime = time.Now()
+	c.skipReveal = false
+	c.finishNotified = false
+	if c.Tree != nil {
+		c.CurrentNode = c.Tree.StartNodeID
+		c.SelectedChoice = 0
+	}
 }
 
 func (c *Chat) Hide() {
@@ -99,18 +140,145 @@ func (c *Chat) Hide() {
 func (c *Chat) NextDialog() {
 	c.CurrentDialog++
 	if c.CurrentDialog >= len(c.Dialogs) {
-		c.State = DialogFinished
-		c.Hide()
+		c.finish()
 		return
 	}
 	c.StartTime = time.Now()
+	c.skipReveal = false
 	c.State = DialogVisible
 }
 
+// CurrentDialogNode returns the node the conversation is on, or nil if the chat has no tree.
+func (c *Chat) CurrentDialogNode() *DialogNode {
+	if c.Tree == nil {
+		return nil
+	}
+	return c.Tree.Node(c.CurrentNode)
+}
+
+// Choose picks a choice at the current node and moves to the node it leads to.
+// The chat finishes when the choice ends the conversation.
+// Out of range choices, or choices made while the chat isn't showing, are ignored.
+func (c *Chat) Choose(choice int) {
+	if c.State == DialogHidden || c.State == DialogFinished {
+		return
+	}
+	node := c.CurrentDialogNode()
+	if node == nil {
+		c.finish()
+		return
+	}
+	if choice < 0 || choice >= len(node.Choices) {
+		return
+	}
+	if c.OnChoice != nil {
+		c.OnChoice(node.Choices[choice].ID)
+	}
+
+	next := c.Tree.Next(c.CurrentNode, choice)
+	if next == nil {
+		c.finish()
+		return
+	}
+	c.CurrentNode = next.ID
+	c.SelectedChoice = 0
+	c.StartTime = time.Now()
+	c.skipReveal = false
+}
+
+// currentText returns the full text of the dialog or node being shown.
+func (c *Chat) currentText() string {
+	if c.Tree != nil {
+		if node := c.CurrentDialogNode(); node != nil {
+			return node.Text
+		}
+		return ""
+	}
+	if c.CurrentDialog < 0 || c.CurrentDialog >= len(c.Dialogs) {
+		return ""
+	}
+	return c.Dialogs[c.CurrentDialog].Text
+}
+
+// VisibleText returns the part of the current text revealed so far.
+func (c *Chat) VisibleText() string {
+	text := []rune(c.currentText())
+	if c.skipReveal || c.RevealSpeed <= 0 {
+		return string(text)
+	}
+	return string(text[:min(len(text), revealedChars(time.Since(c.StartTime), c.RevealSpeed))])
+}
+
+// IsFullyRevealed reports if all of the current text is showing.
+func (c *Chat) IsFullyRevealed() bool {
+	return c.VisibleText() == c.currentText()
+}
+
+// RevealAll skips the typewriter effect, showing the full text.
+func (c *Chat) RevealAll() {
+	c.skipReveal = true
+}
+
+// revealedChars is the number of characters shown after elapsed time at the given speed.
+func revealedChars(elapsed time.Duration, speed float32) int {
+	return int(elapsed.Seconds() * float64(speed))
+}
+
+// finish ends the conversation, calling OnFinish once.
+func (c *Chat) finish() {
+	c.State = DialogFinished
+	c.Hide()
+	if c.OnFinish != nil && !c.finishNotified {
+		c.finishNotified = true
+		c.OnFinish()
+	}
+}
+
+func (c *Chat) updateTree(cm *controls.ControlsManager) {
+	if c.State != DialogVisible {
+		return
+	}
+	node := c.CurrentDialogNode()
+	if node == nil {
+		c.finish()
+		return
+	}
+
+	// The first press skips to the full text
+	if !c.IsFullyRevealed() {
+		if cm.IsActionPressed(controls.ActionConfirm) {
+			c.RevealAll()
+		}
+		return
+	}
+
+	// Terminal nodes end the conversation once confirmed
+	if node.IsTerminal() {
+		if cm.IsActionPressed(controls.ActionConfirm) {
+			c.finish()
+		}
+		return
+	}
+
+	if cm.IsActionPressed(controls.ActionMenuUp) || cm.IsActionPressed(controls.ActionMoveUp) {
+		c.SelectedChoice = (c.SelectedChoice - 1 + len(node.Choices)) % len(node.Choices)
+	}
+	if cm.IsActionPressed(controls.ActionMenuDown) || cm.IsActionPressed(controls.ActionMoveDown) {
+		c.SelectedChoice = (c.SelectedChoice + 1) % len(node.Choices)
+	}
+	if cm.IsActionPressed(controls.ActionConfirm) {
+		c.Choose(c.SelectedChoice)
+	}
+}
+
 func (c *Chat) Draw(cm *controls.ControlsManager) {
 	if c.State == DialogHidden || c.State == DialogFinished {
 		return
 	}
+	if c.Tree != nil {
+		c.drawTree(cm)
+		return
+	}
 
 	dialog := c.Dialogs[c.CurrentDialog]
 
@@ -154,7 +322,7 @@ func (c *Chat) Draw(cm *controls.ControlsManager) {
 	// Draw text
 	rl.DrawTextEx(
 		c.Font,
-		dialog.Text,
+		c.VisibleText(),
 		rl.Vector2{
 			X: boxX + padding,
 			Y: boxY + (boxHeight-textSize.Y)/2,
@@ -165,40 +333,106 @@ func (c *Chat) Draw(cm *controls.ControlsManager) {
 	)
 
 	// Draw continue prompt if in waiting state
-	if c.State == DialogWaiting {
-		activeScheme := cm.GetActiveScheme()
-		interactBinding := activeScheme.Bindings[controls.ActionConfirm]
-		if len(interactBinding) == 0 {
-			return
-		}
+	if c.State == DialogWaiting && c.IsFullyRevealed() {
+		c.drawContinuePrompt(cm, boxX, boxY, boxWidth, boxHeight, padding)
+	}
+}
 
-		keyOptions := []string{}
-		for _, key := range interactBinding {
-			switch key.Type {
-			case controls.InputKeyboard:
-				keyOptions = append(keyOptions, controls.KeyCodeToString(key.Key))
-			case controls.InputGamepad:
-				if key.Axis >= 0 {
-					keyOptions = append(keyOptions, controls.GamepadAxisToString(key.Axis))
-				}
-				keyOptions = append(keyOptions, controls.GamepadButtonToString(key.Button))
-			case controls.InputMouse:
-				keyOptions = append(keyOptions, controls.MouseButtonToString(key.Button))
+// drawContinuePrompt draws the confirm bindings in the bottom right of the dialog box.
+func (c *Chat) drawContinuePrompt(cm *controls.ControlsManager, boxX, boxY, boxWidth, boxHeight, padding float32) {
+	activeScheme := cm.GetActiveScheme()
+	interactBinding := activeScheme.Bindings[controls.ActionConfirm]
+	if len(interactBinding) == 0 {
+		return
+	}
+
+	keyOptions := []string{}
+	for _, key := range interactBinding {
+		switch key.Type {
+		case controls.InputKeyboard:
+			keyOptions = append(keyOptions, controls.KeyCodeToString(key.Key))
+		case controls.InputGamepad:
+			if key.Axis >= 0 {
+				keyOptions = append(keyOptions, controls.GamepadAxisToString(key.Axis))
 			}
+			keyOptions = append(keyOptions, controls.GamepadButtonToString(key.Button))
+		case controls.InputMouse:
+			keyOptions = append(keyOptions, controls.MouseButtonToString(key.Button))
 		}
+	}
+
+	promptText := fmt.Sprintf("Press %s to continue", strings.Join(keyOptions, "/"))
+	promptSize := rl.MeasureTextEx(c.Font, promptText, 16, 1)
+	rl.DrawTextEx(
+		c.Font,
+		promptText,
+		rl.Vector2{
+			X: boxX + boxWidth - promptSize.X - padding,
+			Y: boxY + boxHeight - promptSize.Y - 5,
+		},
+		16,
+		1,
+		rl.NewColor(200, 200, 200, 255),
+	)
+}
+
+// drawTree draws the current node of a dialog tree, with its choices as a selectable list.
+func (c *Chat) drawTree(cm *controls.ControlsManager) {
+	node := c.CurrentDialogNode()
+	if node == nil {
+		return
+	}
+
+	screenWidth := float32(rl.GetScreenWidth())
+	screenHeight := float32(rl.GetScreenHeight())
+
+	padding := float32(20)
+	choiceHeight := float32(24)
+	textSize := rl.MeasureTextEx(c.Font, node.Text, 20, 1)
+
+	// Size the box to fit the text and the longest choice
+	contentWidth := textSize.X
+	for _, choice := range node.Choices {
+		contentWidth = max(contentWidth, rl.MeasureTextEx(c.Font, "> "+choice.Text, 18, 1).X)
+	}
+	boxWidth := contentWidth + (padding * 2)
+	boxHeight := textSize.Y + (padding * 2) + float32(len(node.Choices))*choiceHeight
+	if node.IsTerminal() {
+		boxHeight = max(boxHeight, 80)
+	}
+
+	boxX := (screenWidth - boxWidth) / 2
+	boxY := screenHeight - boxHeight - padding
 
-		promptText := fmt.Sprintf("Press %s to continue", strings.Join(keyOptions, "/"))
-		promptSize := rl.MeasureTextEx(c.Font, promptText, 16, 1)
+	rl.DrawRectangle(int32(boxX), int32(boxY), int32(boxWidth), int32(boxHeight), rl.NewColor(0, 0, 0, 200))
+	rl.DrawRectangleLinesEx(rl.Rectangle{X: boxX, Y: boxY, Width: boxWidth, Height: boxHeight}, 2, rl.White)
+	rl.DrawTextEx(c.Font, c.VisibleText(), rl.Vector2{X: boxX + padding, Y: boxY + padding}, 20, 1, rl.White)
+	if !c.IsFullyRevealed() {
+		return
+	}
+
+	// Draw the choices, highlighting the selected one
+	for i, choice := range node.Choices {
+		text := "  " + choice.Text
+		color := rl.NewColor(200, 200, 200, 255)
+		if i == c.SelectedChoice {
+			text = "> " + choice.Text
+			color = rl.Yellow
+		}
 		rl.DrawTextEx(
 			c.Font,
-			promptText,
+			text,
 			rl.Vector2{
-				X: boxX + boxWidth - promptSize.X - padding,
-				Y: boxY + boxHeight - promptSize.Y - 5,
+				X: boxX + padding,
+				Y: boxY + padding + textSize.Y + 8 + float32(i)*choiceHeight,
 			},
-			16,
+			18,
 			1,
-			rl.NewColor(200, 200, 200, 255),
+			color,
 		)
 	}
+
+	if node.IsTerminal() {
+		c.drawContinuePrompt(cm, boxX, boxY, boxWidth, boxHeight, padding)
+	}
 }
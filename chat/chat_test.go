@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTypewriterReveal checks how much of the dialog is visible after a given time.
+func TestTypewriterReveal(t *testing.T) {
+	const text = "Welcome, traveler!" // 18 characters
+	testCases := []struct {
+		elapsed  time.Duration
+		speed    float32
+		expected int
+	}{
+		{elapsed: 0, speed: 10, expected: 0},
+		{elapsed: 500 * time.Millisecond, speed: 10, expected: 5},
+		{elapsed: 1500 * time.Millisecond, speed: 10, expected: 15},
+		{elapsed: 5 * time.Second, speed: 10, expected: 18},
+		{elapsed: 250 * time.Millisecond, speed: 40, expected: 10},
+		{elapsed: 0, speed: 0, expected: 18},
+	}
+
+	for _, tc := range testCases {
+		c := &Chat{Dialogs: []Dialog{{Text: text}}, RevealSpeed: tc.speed}
+		c.Show()
+		c.StartTime = time.Now().Add(-tc.elapsed)
+
+		visible := c.VisibleText()
+		if len(visible) != tc.expected || visible != text[:tc.expected] {
+			t.Errorf("After %v at %v chars/sec: expected %q, got %q", tc.elapsed, tc.speed, text[:tc.expected], visible)
+		}
+		if c.IsFullyRevealed() != (tc.expected == len(text)) {
+			t.Errorf("After %v at %v chars/sec: unexpected IsFullyRevealed %v", tc.elapsed, tc.speed, c.IsFullyRevealed())
+		}
+	}
+
+	// Skipping shows the full text, until the next dialog
+	c := &Chat{Dialogs: []Dialog{{Text: text}, {Text: "Farewell."}}, RevealSpeed: 10}
+	c.Show()
+	c.RevealAll()
+	if !c.IsFullyRevealed() || c.VisibleText() != text {
+		t.Errorf("Expected the full text after RevealAll, got %q", c.VisibleText())
+	}
+	c.NextDialog()
+	if c.IsFullyRevealed() {
+		t.Errorf("Expected the next dialog to start hidden, got %q", c.VisibleText())
+	}
+}
+
+// TestChatCallbacks checks OnFinish fires once per conversation, and OnChoice gets each picked choice's ID.
+func TestChatCallbacks(t *testing.T) {
+	finished := 0
+	c := &Chat{Dialogs: []Dialog{{Text: "Hello"}, {Text: "Goodbye"}}, OnFinish: func() { finished++ }}
+	c.Show()
+	c.NextDialog()
+	if finished != 0 {
+		t.Fatalf("Expected OnFinish to wait for the last dialog, got %d calls", finished)
+	}
+	c.NextDialog()
+	c.NextDialog()
+	if finished != 1 {
+		t.Errorf("Expected OnFinish to be called once, got %d", finished)
+	}
+
+	tree, err := LoadDialogTree([]byte(`{
+		"startNodeId": "shop",
+		"nodes": [
+			{"id": "shop", "text": "Want to trade?", "choices": [
+				{"id": "browse", "text": "Show me your wares.", "nextNodeId": "wares"},
+				{"id": "leave", "text": "No thanks."}
+			]},
+			{"id": "wares", "text": "Take a look.", "choices": [
+				{"id": "buy_sword", "text": "I'll take the sword."}
+			]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("LoadDialogTree failed: %v", err)
+	}
+
+	var choices []string
+	finished = 0
+	c = &Chat{Tree: tree, OnFinish: func() { finished++ }, OnChoice: func(id string) { choices = append(choices, id) }}
+	c.Show()
+	c.Choose(0)
+	c.Choose(0)
+	c.Choose(0) // The chat is over, this is ignored
+	if strings.Join(choices, ",") != "browse,buy_sword" {
+		t.Errorf("Expected choices browse,buy_sword, got %v", choices)
+	}
+	if finished != 1 {
+		t.Errorf("Expected OnFinish to be called once, got %d", finished)
+	}
+
+	// Starting the conversation again can finish it again
+	c.Show()
+	c.Choose(1)
+	if finished != 2 || choices[len(choices)-1] != "leave" {
+		t.Errorf("Expected a second finish after leaving, got %d finishes and choices %v", finished, choices)
+	}
+}
@@ -0,0 +1,100 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Choice is an option the player can pick at a dialog node.
+// An empty NextNodeID ends the conversation. The ID is passed to Chat.OnChoice.
+type Choice struct {
+	ID         string `json:"id,omitempty"`
+	Text       string `json:"text"`
+	NextNodeID string `json:"nextNodeId"`
+}
+
+// DialogNode is a single line of a branching conversation.
+// Nodes without choices end the conversation once confirmed.
+type DialogNode struct {
+	ID      string   `json:"id"`
+	Text    string   `json:"text"`
+	Choices []Choice `json:"choices,omitempty"`
+}
+
+// IsTerminal reports if the conversation ends at this node.
+func (n *DialogNode) IsTerminal() bool {
+	return len(n.Choices) == 0
+}
+
+// DialogTree is a branching conversation, starting from StartNodeID.
+type DialogTree struct {
+	StartNodeID string       `json:"startNodeId"`
+	Nodes       []DialogNode `json:"nodes"`
+}
+
+// LoadDialogTree parses and validates a dialog tree from JSON.
+func LoadDialogTree(data []byte) (*DialogTree, error) {
+	var tree DialogTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse dialog tree: %w", err)
+	}
+	if err := tree.Validate(); err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+// LoadDialogTreeFile reads a dialog tree from a JSON file.
+func LoadDialogTreeFile(path string) (*DialogTree, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dialog tree %s: %w", path, err)
+	}
+	return LoadDialogTree(data)
+}
+
+// Validate checks node IDs are unique, and that the start node and every choice target exist.
+func (t *DialogTree) Validate() error {
+	ids := make(map[string]bool, len(t.Nodes))
+	for _, node := range t.Nodes {
+		if node.ID == "" {
+			return fmt.Errorf("dialog node is missing an id")
+		}
+		if ids[node.ID] {
+			return fmt.Errorf("duplicate dialog node id %q", node.ID)
+		}
+		ids[node.ID] = true
+	}
+	if !ids[t.StartNodeID] {
+		return fmt.Errorf("start node %q not found", t.StartNodeID)
+	}
+	for _, node := range t.Nodes {
+		for _, choice := range node.Choices {
+			if choice.NextNodeID != "" && !ids[choice.NextNodeID] {
+				return fmt.Errorf("choice %q on node %q leads to missing node %q", choice.Text, node.ID, choice.NextNodeID)
+			}
+		}
+	}
+	return nil
+}
+
+// Node returns the node with the given ID, or nil if it doesn't exist.
+func (t *DialogTree) Node(id string) *DialogNode {
+	for i := range t.Nodes {
+		if t.Nodes[i].ID == id {
+			return &t.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// Next returns the node reached by picking a choice at the given node.
+// Returns nil when the choice ends the conversation, or the choice is out of range.
+func (t *DialogTree) Next(nodeID string, choice int) *DialogNode {
+	node := t.Node(nodeID)
+	if node == nil || choice < 0 || choice >= len(node.Choices) {
+		return nil
+	}
+	return t.Node(node.Choices[choice].NextNodeID)
+}
@@ -0,0 +1,88 @@
+package chat
+
+import (
+	"testing"
+)
+
+const testTree = `{
+	"startNodeId": "greeting",
+	"nodes": [
+		{"id": "greeting", "text": "Halt! Who goes there?", "choices": [
+			{"text": "A friend.", "nextNodeId": "friend"},
+			{"text": "None of your business.", "nextNodeId": "hostile"},
+			{"text": "Leave", "nextNodeId": ""}
+		]},
+		{"id": "friend", "text": "Then you may pass.", "choices": [
+			{"text": "Any news?", "nextNodeId": "news"},
+			{"text": "Thanks.", "nextNodeId": ""}
+		]},
+		{"id": "news", "text": "Wolves in the east woods."},
+		{"id": "hostile", "text": "Then begone!"}
+	]
+}`
+
+// TestDialogTreeTraversal walks the tree with sequences of choices and checks where each ends up.
+func TestDialogTreeTraversal(t *testing.T) {
+	tree, err := LoadDialogTree([]byte(testTree))
+	if err != nil {
+		t.Fatalf("LoadDialogTree failed: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		choices      []int
+		expectedNode string
+		finished     bool
+	}{
+		{name: "start", choices: nil, expectedNode: "greeting"},
+		{name: "friend then news", choices: []int{0, 0}, expectedNode: "news"},
+		{name: "hostile", choices: []int{1}, expectedNode: "hostile"},
+		{name: "leave ends the chat", choices: []int{2}, finished: true},
+		{name: "thanks ends the chat", choices: []int{0, 1}, finished: true},
+		{name: "out of range is ignored", choices: []int{5, -1}, expectedNode: "greeting"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Chat{Tree: tree}
+			c.Show()
+			for _, choice := range tc.choices {
+				c.Choose(choice)
+			}
+
+			if tc.finished {
+				if c.State != DialogHidden {
+					t.Errorf("Expected the chat to be finished, got state %v on node %q", c.State, c.CurrentNode)
+				}
+				return
+			}
+			node := c.CurrentDialogNode()
+			if node == nil || node.ID != tc.expectedNode {
+				t.Fatalf("Expected node %q, got %v", tc.expectedNode, node)
+			}
+			if c.State != DialogVisible {
+				t.Errorf("Expected the chat to still be visible, got state %v", c.State)
+			}
+		})
+	}
+
+	// Leaf nodes are terminal
+	if !tree.Node("news").IsTerminal() || tree.Node("greeting").IsTerminal() {
+		t.Error("Expected only nodes without choices to be terminal")
+	}
+}
+
+// TestDialogTreeValidate rejects trees with missing or duplicate nodes.
+func TestDialogTreeValidate(t *testing.T) {
+	invalid := map[string]string{
+		"missing start":  `{"startNodeId": "nope", "nodes": [{"id": "a", "text": "hi"}]}`,
+		"missing target": `{"startNodeId": "a", "nodes": [{"id": "a", "text": "hi", "choices": [{"text": "go", "nextNodeId": "b"}]}]}`,
+		"duplicate id":   `{"startNodeId": "a", "nodes": [{"id": "a", "text": "hi"}, {"id": "a", "text": "again"}]}`,
+		"bad json":       `{"startNodeId": `,
+	}
+	for name, data := range invalid {
+		if _, err := LoadDialogTree([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/ztkent/beam"
@@ -95,10 +96,10 @@ func (rm *ResourceManager) RenderTexture(texture *beam.AnimatedTexture, pos rl.R
 }
 
 func (rm *ResourceManager) RenderNPC(npc *beam.NPC, pos rl.Rectangle, tileSize int) {
-	if npc.Data.Dead {
+	if npc.Runtime.Dead {
 		// Calculate alpha based on dying frames (fade out over 32 frames)
 		totalDyingFrames := 32
-		alpha := uint8(255 * (1.0 - (float32(npc.Data.DyingFrames) / float32(totalDyingFrames))))
+		alpha := uint8(255 * (1.0 - (float32(npc.Runtime.DyingFrames) / float32(totalDyingFrames))))
 		fadeColor := rl.NewColor(255, 255, 255, alpha)
 		rm.RenderTexture(&beam.AnimatedTexture{
 			Frames: []beam.Texture{
@@ -115,11 +116,11 @@ func (rm *ResourceManager) RenderNPC(npc *beam.NPC, pos rl.Rectangle, tileSize i
 			IsAnimated: false,
 		}, pos, tileSize)
 
-	} else if npc.Data.TookDamageThisFrame {
+	} else if npc.Runtime.TookDamageThisFrame {
 		// Calculate damage flash alpha
 		const totalDamageFrames = 32.0
 		const peakAlpha = 0.8
-		progress := float32(npc.Data.DamageFrames) / totalDamageFrames
+		progress := float32(npc.Runtime.DamageFrames) / totalDamageFrames
 
 		// Start at peak and fade out using cosine for smooth transition
 		alpha := peakAlpha * float32(math.Cos(float64(progress)*math.Pi/2))
@@ -143,22 +144,22 @@ func (rm *ResourceManager) RenderNPC(npc *beam.NPC, pos rl.Rectangle, tileSize i
 			IsAnimated: false,
 		}, pos, tileSize)
 
-		if npc.Data.DamageFrames >= int(totalDamageFrames) {
-			npc.Data.DamageFrames = 0
-			npc.Data.TookDamageThisFrame = false
+		if npc.Runtime.DamageFrames >= int(totalDamageFrames) {
+			npc.Runtime.DamageFrames = 0
+			npc.Runtime.TookDamageThisFrame = false
 		}
 	} else {
 		rm.RenderTexture(npc.GetCurrentTexture(), pos, tileSize)
 	}
 
 	// Only show health bar for 5 seconds after health changes
-	if !npc.Data.Dead {
+	if !npc.Runtime.Dead {
 		currentTime := float32(rl.GetTime())
-		if npc.Data.LastHealthChange != 0 && currentTime-npc.Data.LastHealthChange < 5.0 {
+		if npc.Runtime.LastHealthChange != 0 && currentTime-npc.Runtime.LastHealthChange < 5.0 {
 			// Draw health bar
 			barWidth := float32(tileSize)
 			barHeight := float32(4)
-			healthPercent := float32(npc.Data.Health) / float32(npc.Data.MaxHealth)
+			healthPercent := float32(npc.Runtime.Health) / float32(npc.Runtime.MaxHealth)
 
 			// Background (gray)
 			rl.DrawRectangle(
@@ -210,3 +211,175 @@ func (rm *ResourceManager) RenderItem(item *beam.Item, pos rl.Rectangle, tileSiz
 		}
 	}
 }
+
+// NineSlice is a texture region split into a 3x3 grid by its insets.
+// The corners keep their size when drawn, the edges and center stretch to fill.
+type NineSlice struct {
+	Texture rl.Texture2D
+	Region  rl.Rectangle
+	Left    int32
+	Right   int32
+	Top     int32
+	Bottom  int32
+}
+
+// GetNineSlice returns a nine-slice for a texture or sprite in the scene.
+// Insets are in pixels, ordered left, right, top, bottom.
+func (rm *ResourceManager) GetNineSlice(sceneName, textureName string, insets [4]int32) (NineSlice, error) {
+	info, err := rm.GetTexture(sceneName, textureName)
+	if err != nil {
+		return NineSlice{}, err
+	}
+	return NineSlice{
+		Texture: info.Texture,
+		Region:  info.Region,
+		Left:    insets[0],
+		Right:   insets[1],
+		Top:     insets[2],
+		Bottom:  insets[3],
+	}, nil
+}
+
+// Draw stretches the nine-slice to fill dest, keeping the corners crisp.
+func (n NineSlice) Draw(dest rl.Rectangle, tint rl.Color) {
+	rl.DrawTextureNPatch(n.Texture, n.patchInfo(), dest, rl.Vector2{}, 0, tint)
+}
+
+// patchInfo builds the raylib n-patch info, clamping the insets to fit inside the region.
+func (n NineSlice) patchInfo() rl.NPatchInfo {
+	width := int32(math.Abs(float64(n.Region.Width)))
+	height := int32(math.Abs(float64(n.Region.Height)))
+	left, right := clampInsets(n.Left, n.Right, width)
+	top, bottom := clampInsets(n.Top, n.Bottom, height)
+	return rl.NPatchInfo{
+		Source: n.Region,
+		Left:   left,
+		Top:    top,
+		Right:  right,
+		Bottom: bottom,
+		Layout: rl.NPatchNinePatch,
+	}
+}
+
+// clampInsets keeps a pair of opposite insets non-negative and within size,
+// shrinking them proportionally if they overlap.
+func clampInsets(a, b, size int32) (int32, int32) {
+	a, b = max(a, 0), max(b, 0)
+	if a+b <= size {
+		return a, b
+	}
+	if size <= 0 {
+		return 0, 0
+	}
+	scaledA := a * size / (a + b)
+	return scaledA, size - scaledA
+}
+
+// Sprite is one texture draw, with the same arguments as rl.DrawTexturePro.
+// Depth orders sprites in a SpriteBatch, higher depths are drawn over lower ones.
+type Sprite struct {
+	Texture  rl.Texture2D
+	Source   rl.Rectangle
+	Dest     rl.Rectangle
+	Origin   rl.Vector2
+	Rotation float32
+	Tint     rl.Color
+	Depth    int
+}
+
+// Draw draws the sprite right away.
+func (s Sprite) Draw() {
+	rl.DrawTexturePro(s.Texture, s.Source, s.Dest, s.Origin, s.Rotation, s.Tint)
+}
+
+// SpriteBatch collects sprites between Begin and End, and draws them grouped by texture,
+// so the GPU switches textures as rarely as possible.
+// Sprites at the same depth can be drawn in any order, so give overlapping sprites,
+// like textures stacked on a tile, different depths.
+type SpriteBatch struct {
+	groups  []spriteGroup
+	index   map[spriteGroupKey]int
+	drawing bool
+	draw    func(Sprite)
+
+	// switches counts the texture changes in the last End, for benchmarks
+	switches int
+}
+
+type spriteGroupKey struct {
+	depth   int
+	texture uint32
+}
+
+// spriteGroup is the sprites in a batch sharing a depth and texture, in the order they were added.
+type spriteGroup struct {
+	key     spriteGroupKey
+	sprites []Sprite
+}
+
+// NewSpriteBatch returns an empty sprite batch.
+func NewSpriteBatch() *SpriteBatch {
+	return &SpriteBatch{index: make(map[spriteGroupKey]int), draw: Sprite.Draw}
+}
+
+// Begin starts collecting sprites, dropping any left from a batch that wasn't ended.
+func (b *SpriteBatch) Begin() {
+	b.reset()
+	b.drawing = true
+}
+
+// Drawing reports if the batch is between Begin and End. A nil batch never is.
+func (b *SpriteBatch) Drawing() bool {
+	return b != nil && b.drawing
+}
+
+// Draw adds a sprite to the batch, or draws it right away outside of Begin and End.
+func (b *SpriteBatch) Draw(s Sprite) {
+	if !b.Drawing() {
+		s.Draw()
+		return
+	}
+	key := spriteGroupKey{depth: s.Depth, texture: s.Texture.ID}
+	i, ok := b.index[key]
+	if !ok {
+		// Reuse the groups from earlier frames, so a steady scene doesn't allocate
+		i = len(b.index)
+		b.index[key] = i
+		if i == len(b.groups) {
+			b.groups = append(b.groups, spriteGroup{})
+		}
+		b.groups[i].key = key
+	}
+	b.groups[i].sprites = append(b.groups[i].sprites, s)
+}
+
+// End draws every sprite collected since Begin, depth by depth.
+// Within a depth, sprites are grouped by texture in the order each texture was first drawn.
+func (b *SpriteBatch) End() {
+	if !b.drawing {
+		return
+	}
+	b.drawing = false
+
+	groups := b.groups[:len(b.index)]
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].key.depth < groups[j].key.depth })
+	b.switches = 0
+	var last uint32
+	for i, group := range groups {
+		if i == 0 || group.key.texture != last {
+			b.switches++
+			last = group.key.texture
+		}
+		for _, s := range group.sprites {
+			b.draw(s)
+		}
+	}
+	b.reset()
+}
+
+func (b *SpriteBatch) reset() {
+	for i := range b.groups {
+		b.groups[i].sprites = b.groups[i].sprites[:0]
+	}
+	clear(b.index)
+}
@@ -0,0 +1,114 @@
+package resources
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func newTestSpriteBatch(drawn *[]Sprite) *SpriteBatch {
+	b := NewSpriteBatch()
+	b.draw = func(s Sprite) { *drawn = append(*drawn, s) }
+	return b
+}
+
+// TestSpriteBatchOrder checks sprites are drawn depth by depth, grouped by texture,
+// and in the order they were added within a group.
+func TestSpriteBatchOrder(t *testing.T) {
+	var drawn []Sprite
+	b := newTestSpriteBatch(&drawn)
+	grass, stone, flower := rl.Texture2D{ID: 1}, rl.Texture2D{ID: 2}, rl.Texture2D{ID: 3}
+
+	b.Begin()
+	b.Draw(Sprite{Texture: grass, Dest: rl.Rectangle{X: 0}})
+	b.Draw(Sprite{Texture: flower, Dest: rl.Rectangle{X: 0}, Depth: 1})
+	b.Draw(Sprite{Texture: stone, Dest: rl.Rectangle{X: 1}})
+	b.Draw(Sprite{Texture: grass, Dest: rl.Rectangle{X: 2}})
+	b.Draw(Sprite{Texture: stone, Dest: rl.Rectangle{X: 3}})
+	b.Draw(Sprite{Texture: grass, Dest: rl.Rectangle{X: 4}})
+	if len(drawn) != 0 {
+		t.Fatalf("Expected nothing drawn before End, got %d", len(drawn))
+	}
+	b.End()
+
+	want := []struct {
+		id uint32
+		x  float32
+	}{{1, 0}, {1, 2}, {1, 4}, {2, 1}, {2, 3}, {3, 0}}
+	if len(drawn) != len(want) {
+		t.Fatalf("Expected %d sprites drawn, got %d", len(want), len(drawn))
+	}
+	for i, w := range want {
+		if drawn[i].Texture.ID != w.id || drawn[i].Dest.X != w.x {
+			t.Errorf("Expected texture %d at x=%v to be drawn %dth, got texture %d at x=%v",
+				w.id, w.x, i, drawn[i].Texture.ID, drawn[i].Dest.X)
+		}
+	}
+	if b.switches != 3 {
+		t.Errorf("Expected 3 texture switches, got %d", b.switches)
+	}
+
+	// Ending again doesn't redraw anything
+	drawn = nil
+	b.End()
+	if len(drawn) != 0 || b.Drawing() {
+		t.Errorf("Expected a second End to do nothing, got %d sprites", len(drawn))
+	}
+	if (*SpriteBatch)(nil).Drawing() {
+		t.Error("Expected a nil batch not to be drawing")
+	}
+}
+
+// benchmarkScreen returns the sprites for a full 1920x1080 screen of 32px tiles,
+// ground from 3 textures with a decoration from 2 others on every third tile.
+func benchmarkScreen() []Sprite {
+	const tileSize, cols, rows = 32, 60, 34
+	var sprites []Sprite
+	for y := range rows {
+		for x := range cols {
+			dest := rl.Rectangle{X: float32(x * tileSize), Y: float32(y * tileSize), Width: tileSize, Height: tileSize}
+			sprites = append(sprites, Sprite{Texture: rl.Texture2D{ID: uint32(1 + (x+y)%3)}, Dest: dest})
+			if (x+y*cols)%3 == 0 {
+				sprites = append(sprites, Sprite{Texture: rl.Texture2D{ID: uint32(4 + x%2)}, Dest: dest, Depth: 1})
+			}
+		}
+	}
+	return sprites
+}
+
+// BenchmarkDrawTilesUnbatched draws a screen of tiles in map order, reporting the texture switches it costs.
+func BenchmarkDrawTilesUnbatched(b *testing.B) {
+	sprites := benchmarkScreen()
+	var last uint32
+	switches := 0
+	draw := func(s Sprite) {
+		if s.Texture.ID != last {
+			switches++
+			last = s.Texture.ID
+		}
+	}
+	b.ResetTimer()
+	for range b.N {
+		switches, last = 0, 0
+		for _, s := range sprites {
+			draw(s)
+		}
+	}
+	b.ReportMetric(float64(switches), "switches/op")
+}
+
+// BenchmarkDrawTilesBatched draws the same screen through a SpriteBatch.
+func BenchmarkDrawTilesBatched(b *testing.B) {
+	sprites := benchmarkScreen()
+	batch := NewSpriteBatch()
+	batch.draw = func(Sprite) {}
+	b.ResetTimer()
+	for range b.N {
+		batch.Begin()
+		for _, s := range sprites {
+			batch.Draw(s)
+		}
+		batch.End()
+	}
+	b.ReportMetric(float64(batch.switches), "switches/op")
+}
@@ -1,11 +1,17 @@
 package resources
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image/png"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
+	"unsafe"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -13,12 +19,12 @@ import (
 /*
 The resource system supports:
   - Scene-based resource management for organized loading/unloading
-  - Texture loading from individual files and sprite sheets
+  - Texture loading from individual files and sprite sheets (png, jpg, bmp, tga, qoi, gif)
   - Automatic sprite sheet slicing with configurable grid sizes
-  - Font loading and management
+  - Font loading and management, with fonts rendered at several sizes and a fallback for missing glyphs
   - Resource state persistence
   - Multi-layer rendering support
-  - Memory efficient resource handling
+  - Memory efficient resource handling, textures with the same path are shared across scenes
 
 Example usage:
     // Create a resource manager with default resources
@@ -62,13 +68,39 @@ Example usage:
 const (
 	DefaultGridSize int32 = 16
 	DefaultMargin   int32 = 1
+	DefaultFontSize       = 32
 )
 
+// ErrUnsupportedImageFormat is returned when a texture has an extension we can't decode.
+// Supported formats are png, jpg/jpeg, bmp, tga, qoi and gif (first frame only).
+var ErrUnsupportedImageFormat = errors.New("unsupported image format")
+
 type ResourceManager struct {
 	Scenes     []Scene
 	embeddedFS fs.FS
+	// Textures are shared by path across scenes, and only unloaded when no scene uses them
+	sharedTextures map[string]*sharedTexture
+
+	// At most sceneBudget unpinned scenes stay loaded, the least recently used is unloaded first, see SetSceneBudget
+	sceneBudget  int
+	pinnedScenes map[string]bool
+	sceneUses    map[string]uint64 // When each scene was last loaded or read from, by useClock
+	useClock     uint64
+}
+
+type sharedTexture struct {
+	texture rl.Texture2D
+	refs    int
 }
 
+// These are swapped in tests, so shared textures can be checked without a GPU.
+var (
+	uploadTexture = func(rm *ResourceManager, path string) rl.Texture2D { return rm.LoadTexture(path) }
+	unloadTexture = rl.UnloadTexture
+	loadFont      = func(rm *ResourceManager, path string, size int) rl.Font { return rm.LoadFontSize(path, size) }
+	unloadFont    = rl.UnloadFont
+)
+
 type Scene struct {
 	Name         string
 	Textures     []Texture
@@ -80,8 +112,13 @@ type Scene struct {
 type Font struct {
 	Name   string
 	Path   string
-	Font   rl.Font
+	Font   rl.Font // Rendered at DefaultFontSize
 	Loaded bool
+	// Sizes are also rendered when the scene loads, other sizes are rendered the first time they're asked for
+	Sizes []int
+	// Fallback is used for text with glyphs this font is missing, see FontForText
+	Fallback *Font
+	variants map[int]rl.Font
 }
 
 type Texture struct {
@@ -110,9 +147,11 @@ type Rectangle struct {
 }
 
 type Resource struct {
-	Name        string             `json:"Name"`
-	Path        string             `json:"Path"`
-	IsSheet     bool               `json:"IsSheet"`
+	Name    string `json:"Name"`
+	Path    string `json:"Path"`
+	IsSheet bool   `json:"IsSheet"`
+	// SheetData maps each sprite to its grid cell as a column and row,
+	// or to its exact pixels as an x, y, width and height, for sprites trimmed smaller than their cell.
 	SheetData   map[string][]int32 `json:"SheetData,omitempty"`
 	SheetMargin int32              `json:"SheetMargin"`
 	GridSizeX   int32              `json:"GridSizeX"`
@@ -128,6 +167,8 @@ type SceneState struct {
 	Textures     []Resource `json:"textures"`
 	SpriteSheets []Resource `json:"spriteSheets"`
 	Font         *Resource  `json:"font,omitempty"`
+	FontSizes    []int      `json:"fontSizes,omitempty"`
+	FallbackFont *Resource  `json:"fallbackFont,omitempty"`
 	Loaded       bool       `json:"loaded"`
 }
 
@@ -173,6 +214,33 @@ func NewResourceManager() *ResourceManager {
 	return rm
 }
 
+// acquireTexture returns the texture for a path, loading it only if no other scene has it loaded.
+func (rm *ResourceManager) acquireTexture(path string) rl.Texture2D {
+	if rm.sharedTextures == nil {
+		rm.sharedTextures = make(map[string]*sharedTexture)
+	}
+	if shared, ok := rm.sharedTextures[path]; ok {
+		shared.refs++
+		return shared.texture
+	}
+	texture := uploadTexture(rm, path)
+	rm.sharedTextures[path] = &sharedTexture{texture: texture, refs: 1}
+	return texture
+}
+
+// releaseTexture drops a reference to a path, unloading the texture once nothing uses it.
+func (rm *ResourceManager) releaseTexture(path string) {
+	shared, ok := rm.sharedTextures[path]
+	if !ok {
+		return
+	}
+	shared.refs--
+	if shared.refs <= 0 {
+		unloadTexture(shared.texture)
+		delete(rm.sharedTextures, path)
+	}
+}
+
 func (rm *ResourceManager) init() {
 	rm.LoadView("default")
 }
@@ -184,21 +252,13 @@ func (rm *ResourceManager) Close() {
 
 	for _, scene := range rm.Scenes {
 		if scene.Loaded {
-			if scene.Font != nil && scene.Font.Loaded {
-				rl.UnloadFont(scene.Font.Font)
-			}
-			for _, tex := range scene.Textures {
-				if tex.Loaded {
-					rl.UnloadTexture(tex.Texture)
-				}
-			}
-			for _, sheet := range scene.SpriteSheets {
-				if sheet.Loaded {
-					rl.UnloadTexture(sheet.Texture)
-				}
-			}
+			rm.unloadSceneFont(scene.Font)
 		}
 	}
+	for _, shared := range rm.sharedTextures {
+		unloadTexture(shared.texture)
+	}
+	rm.sharedTextures = nil
 }
 
 func (rm *ResourceManager) GetEmbeddedFS() *fs.FS {
@@ -210,54 +270,89 @@ func (rm *ResourceManager) GetEmbeddedFS() *fs.FS {
 
 // LoadTexture wrapper that handles both embedded and file system loading
 func (rm *ResourceManager) LoadTexture(path string) rl.Texture2D {
+	var data []byte
+	var err error
 	if rm.embeddedFS != nil {
-		return rm.loadTextureFromEmbedded(path)
+		data, err = fs.ReadFile(rm.embeddedFS, path)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Printf("Failed to load texture %s: %v\n", path, err)
+		return rl.Texture2D{}
 	}
-	return rl.LoadTexture(path)
+
+	img, err := decodeImage(filepath.Ext(path), data)
+	if err != nil {
+		fmt.Printf("Failed to load texture %s: %v\n", path, err)
+		return rl.Texture2D{}
+	}
+
+	texture := rl.LoadTextureFromImage(img)
+	rl.UnloadImage(img)
+	return texture
 }
 
 // LoadFont wrapper that handles both embedded and file system loading
 func (rm *ResourceManager) LoadFont(path string) rl.Font {
 	if rm.embeddedFS != nil {
-		return rm.loadFontFromEmbedded(path)
+		return rm.loadFontFromEmbedded(path, DefaultFontSize)
 	}
 	return rl.LoadFont(path)
 }
 
-func (rm *ResourceManager) loadTextureFromEmbedded(path string) rl.Texture2D {
-	data, err := fs.ReadFile(rm.embeddedFS, path)
-	if err != nil {
-		fmt.Printf("Failed to load embedded texture %s: %v\n", path, err)
-		return rl.Texture2D{}
+// LoadFontSize loads a font rendered at the given size, so text drawn at that size stays sharp.
+// Only ttf and otf fonts can be rendered at other sizes, other fonts load as LoadFont does.
+func (rm *ResourceManager) LoadFontSize(path string, size int) rl.Font {
+	if rm.embeddedFS != nil {
+		return rm.loadFontFromEmbedded(path, size)
 	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttf", ".otf":
+		return rl.LoadFontEx(path, int32(size), nil)
+	}
+	return rl.LoadFont(path)
+}
 
-	// Determine file extension for proper loading
-	ext := strings.ToLower(filepath.Ext(path))
+// decodeImage decodes raw image data based on its file extension.
+// Animated GIFs are decoded to their first frame.
+func decodeImage(ext string, data []byte) (*rl.Image, error) {
+	ext = strings.ToLower(ext)
 	var img *rl.Image
 
 	switch ext {
-	case ".png":
-		img = rl.LoadImageFromMemory(".png", data, int32(len(data)))
+	case ".png", ".bmp", ".qoi":
+		img = rl.LoadImageFromMemory(ext, data, int32(len(data)))
+	case ".tga":
+		// Decoded here, then handed to raylib as an uncompressed png so it owns the pixel memory
+		decoded, err := decodeTGA(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode .tga image data: %w", err)
+		}
+		var buf bytes.Buffer
+		encoder := png.Encoder{CompressionLevel: png.NoCompression}
+		if err := encoder.Encode(&buf, decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode .tga image data: %w", err)
+		}
+		img = rl.LoadImageFromMemory(".png", buf.Bytes(), int32(buf.Len()))
 	case ".jpg", ".jpeg":
 		img = rl.LoadImageFromMemory(".jpg", data, int32(len(data)))
-	case ".bmp":
-		img = rl.LoadImageFromMemory(".bmp", data, int32(len(data)))
+	case ".gif":
+		// Animated images are loaded with all frames stacked in the data buffer,
+		// the image height is a single frame so only the first frame is used.
+		var frames int32
+		img = rl.LoadImageAnimFromMemory(".gif", data, int32(len(data)), &frames)
 	default:
-		fmt.Printf("Unsupported image format for %s\n", path)
-		return rl.Texture2D{}
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedImageFormat, ext)
 	}
 
-	if img.Data == nil {
-		fmt.Printf("Failed to decode embedded texture %s\n", path)
-		return rl.Texture2D{}
+	if img == nil || img.Data == nil {
+		return nil, fmt.Errorf("failed to decode %s image data", ext)
 	}
-
-	texture := rl.LoadTextureFromImage(img)
-	rl.UnloadImage(img)
-	return texture
+	return img, nil
 }
 
-func (rm *ResourceManager) loadFontFromEmbedded(path string) rl.Font {
+func (rm *ResourceManager) loadFontFromEmbedded(path string, size int) rl.Font {
 	data, err := fs.ReadFile(rm.embeddedFS, path)
 	if err != nil {
 		fmt.Printf("Failed to load embedded font %s: %v\n", path, err)
@@ -270,9 +365,9 @@ func (rm *ResourceManager) loadFontFromEmbedded(path string) rl.Font {
 
 	switch ext {
 	case ".ttf":
-		font = rl.LoadFontFromMemory(".ttf", data, 32, nil)
+		font = rl.LoadFontFromMemory(".ttf", data, int32(size), nil)
 	case ".otf":
-		font = rl.LoadFontFromMemory(".otf", data, 32, nil)
+		font = rl.LoadFontFromMemory(".otf", data, int32(size), nil)
 	default:
 		fmt.Printf("Unsupported font format for %s\n", path)
 		return rl.GetFontDefault()
@@ -326,12 +421,7 @@ func (rm *ResourceManager) AddScene(sceneName string, textureDefs []Resource, fo
 
 			// Initialize sprite regions
 			for spriteName, pos := range def.SheetData {
-				spriteSheet.Sprites[spriteName] = Rectangle{
-					X:      pos[0] * (gridSizeX + def.SheetMargin),
-					Y:      pos[1] * (gridSizeY + def.SheetMargin),
-					Width:  gridSizeX,
-					Height: gridSizeY,
-				}
+				spriteSheet.Sprites[spriteName] = sheetSpriteRect(pos, gridSizeX, gridSizeY, def.SheetMargin)
 			}
 			spriteSheets = append(spriteSheets, spriteSheet)
 		} else {
@@ -389,27 +479,28 @@ func (rm *ResourceManager) ScanSpriteSheetEmbedded(name string, fileName string,
 func (rm *ResourceManager) LoadView(viewName string) error {
 	for i := range rm.Scenes {
 		if rm.Scenes[i].Name == viewName {
+			rm.touchScene(viewName)
+			if !rm.Scenes[i].Loaded && !rm.isPinned(viewName) {
+				rm.evictScenes(rm.sceneBudget-1, viewName)
+			}
 			view := &rm.Scenes[i]
 
 			// Load sprite sheets if present
 			for _, sheet := range view.SpriteSheets {
 				if !sheet.Loaded {
-					sheet.Texture = rm.LoadTexture(sheet.Path)
+					sheet.Texture = rm.acquireTexture(sheet.Path)
 					sheet.Loaded = true
 				}
 			}
 
 			// Load font if specified
-			if view.Font != nil && !view.Font.Loaded {
-				view.Font.Font = rm.LoadFont(view.Font.Path)
-				view.Font.Loaded = true
-			}
+			rm.loadSceneFont(view.Font)
 
 			// Load textures
 			for j := range view.Textures {
 				tex := &view.Textures[j]
 				if !tex.Loaded {
-					tex.Texture = rm.LoadTexture(tex.Path)
+					tex.Texture = rm.acquireTexture(tex.Path)
 					tex.Loaded = true
 				}
 			}
@@ -428,20 +519,17 @@ func (rm *ResourceManager) UnloadView(viewName string) error {
 
 			for _, sheet := range view.SpriteSheets {
 				if sheet.Loaded {
-					rl.UnloadTexture(sheet.Texture)
+					rm.releaseTexture(sheet.Path)
 					sheet.Loaded = false
 				}
 			}
 
-			if view.Font != nil && view.Font.Loaded {
-				rl.UnloadFont(view.Font.Font)
-				view.Font.Loaded = false
-			}
+			rm.unloadSceneFont(view.Font)
 
 			for j := range view.Textures {
 				tex := &view.Textures[j]
 				if tex.Loaded {
-					rl.UnloadTexture(tex.Texture)
+					rm.releaseTexture(tex.Path)
 					tex.Loaded = false
 				}
 			}
@@ -460,12 +548,73 @@ func (rm *ResourceManager) RemoveView(viewName string) error {
 			rm.UnloadView(viewName)
 			// Remove view from slice
 			rm.Scenes = append(rm.Scenes[:i], rm.Scenes[i+1:]...)
+			delete(rm.sceneUses, viewName)
+			delete(rm.pinnedScenes, viewName)
 			return nil
 		}
 	}
 	return fmt.Errorf("view not found: %s", viewName)
 }
 
+// SetSceneBudget limits how many scenes can be loaded at once, not counting pinned scenes.
+// When LoadView would go over the budget, the least recently used scene is unloaded first.
+// Scenes are used by LoadView and GetTexture. A budget of 0 or less is unlimited, which is the default.
+func (rm *ResourceManager) SetSceneBudget(maxLoaded int) {
+	rm.sceneBudget = maxLoaded
+	rm.evictScenes(maxLoaded, "")
+}
+
+// PinScene keeps a scene loaded, it is never unloaded to stay within the scene budget.
+// The "default" scene is always pinned.
+func (rm *ResourceManager) PinScene(sceneName string) {
+	if rm.pinnedScenes == nil {
+		rm.pinnedScenes = make(map[string]bool)
+	}
+	rm.pinnedScenes[sceneName] = true
+}
+
+// UnpinScene lets a pinned scene be unloaded to stay within the scene budget again.
+func (rm *ResourceManager) UnpinScene(sceneName string) {
+	delete(rm.pinnedScenes, sceneName)
+}
+
+func (rm *ResourceManager) isPinned(sceneName string) bool {
+	return sceneName == "default" || rm.pinnedScenes[sceneName]
+}
+
+// touchScene marks a scene as the most recently used.
+func (rm *ResourceManager) touchScene(sceneName string) {
+	if rm.sceneUses == nil {
+		rm.sceneUses = make(map[string]uint64)
+	}
+	rm.useClock++
+	rm.sceneUses[sceneName] = rm.useClock
+}
+
+// evictScenes unloads the least recently used scenes until at most keep unpinned scenes are loaded,
+// never unloading the scene named except. Nothing is unloaded without a scene budget.
+func (rm *ResourceManager) evictScenes(keep int, except string) {
+	if rm.sceneBudget <= 0 {
+		return
+	}
+	for {
+		loaded, oldest := 0, ""
+		for _, scene := range rm.Scenes {
+			if !scene.Loaded || rm.isPinned(scene.Name) {
+				continue
+			}
+			loaded++
+			if scene.Name != except && (oldest == "" || rm.sceneUses[scene.Name] < rm.sceneUses[oldest]) {
+				oldest = scene.Name
+			}
+		}
+		if loaded <= keep || oldest == "" {
+			return
+		}
+		rm.UnloadView(oldest)
+	}
+}
+
 type TextureInfo struct {
 	Name    string
 	Texture rl.Texture2D
@@ -476,6 +625,7 @@ type TextureInfo struct {
 func (rm *ResourceManager) GetTexture(viewName, textureName string) (TextureInfo, error) {
 	for _, view := range rm.Scenes {
 		if view.Name == viewName {
+			rm.touchScene(viewName)
 			// Check regular textures
 			for _, tex := range view.Textures {
 				if tex.Name == textureName && tex.Loaded {
@@ -587,6 +737,68 @@ func (rm *ResourceManager) GetAllSpritesheets(sceneName string) ([]SpriteSheet,
 	return nil, fmt.Errorf("scene not found: %s", sceneName)
 }
 
+// SceneStats is a summary of the textures a scene holds, and roughly how much GPU memory they take.
+type SceneStats struct {
+	Textures     int // Individual textures, loaded or not
+	SpriteSheets int // Sprite sheets, loaded or not
+	Loaded       int // Textures and sprite sheets on the GPU
+	Unloaded     int // Textures and sprite sheets that aren't loaded
+	// VRAMBytes approximates the GPU memory of the loaded textures and sheets, at 4 bytes a pixel.
+	// Fonts and mipmaps aren't counted.
+	VRAMBytes int64
+}
+
+// textureBytes approximates the GPU memory a texture takes, as if it was uncompressed RGBA.
+func textureBytes(texture rl.Texture2D) int64 {
+	return int64(texture.Width) * int64(texture.Height) * 4
+}
+
+// MemoryStats returns the texture counts and approximate VRAM use of a scene, or empty stats if there's no such scene.
+// A texture shared with another scene is counted in both, see TotalMemoryStats for what's actually on the GPU.
+func (rm *ResourceManager) MemoryStats(sceneName string) SceneStats {
+	var stats SceneStats
+	for _, scene := range rm.Scenes {
+		if scene.Name != sceneName {
+			continue
+		}
+		count := func(texture rl.Texture2D, loaded bool) {
+			if !loaded {
+				stats.Unloaded++
+				return
+			}
+			stats.Loaded++
+			stats.VRAMBytes += textureBytes(texture)
+		}
+		for _, tex := range scene.Textures {
+			stats.Textures++
+			count(tex.Texture, tex.Loaded)
+		}
+		for _, sheet := range scene.SpriteSheets {
+			stats.SpriteSheets++
+			count(sheet.Texture, sheet.Loaded)
+		}
+		break
+	}
+	return stats
+}
+
+// TotalMemoryStats adds up the stats of every scene. VRAMBytes counts each shared texture once,
+// so it's the memory actually held, and a leaked texture that no scene references still shows up in it.
+func (rm *ResourceManager) TotalMemoryStats() SceneStats {
+	var total SceneStats
+	for _, scene := range rm.Scenes {
+		stats := rm.MemoryStats(scene.Name)
+		total.Textures += stats.Textures
+		total.SpriteSheets += stats.SpriteSheets
+		total.Loaded += stats.Loaded
+		total.Unloaded += stats.Unloaded
+	}
+	for _, shared := range rm.sharedTextures {
+		total.VRAMBytes += textureBytes(shared.texture)
+	}
+	return total
+}
+
 func (rm *ResourceManager) GetFont(viewName string) (rl.Font, error) {
 	for _, view := range rm.Scenes {
 		if view.Name == viewName && view.Font != nil && view.Font.Loaded {
@@ -596,6 +808,125 @@ func (rm *ResourceManager) GetFont(viewName string) (rl.Font, error) {
 	return rl.Font{}, fmt.Errorf("font not found or not loaded in view: %s", viewName)
 }
 
+// GetFontAtSize returns the scene's font rendered at the given size, rendering and caching it the first time a size is asked for.
+// Without a loaded font it returns an empty font, which raylib draws with its default font.
+func (rm *ResourceManager) GetFontAtSize(viewName string, size int) rl.Font {
+	for _, view := range rm.Scenes {
+		if view.Name == viewName && view.Font != nil && view.Font.Loaded {
+			return rm.fontAtSize(view.Font, size)
+		}
+	}
+	return rl.Font{}
+}
+
+// FontForText returns the font to draw text with at the given size: the scene's font,
+// or its fallback font when the text has a glyph the scene's font is missing.
+func (rm *ResourceManager) FontForText(viewName, text string, size int) rl.Font {
+	for _, view := range rm.Scenes {
+		if view.Name != viewName || view.Font == nil || !view.Font.Loaded {
+			continue
+		}
+		font := rm.fontAtSize(view.Font, size)
+		if fallback := view.Font.Fallback; fallback != nil && fallback.Loaded {
+			for _, r := range text {
+				// Control characters like newlines are handled by the text drawing, not the font
+				if !unicode.IsControl(r) && !hasGlyph(font, r) {
+					return rm.fontAtSize(fallback, size)
+				}
+			}
+		}
+		return font
+	}
+	return rl.Font{}
+}
+
+// SetFontSizes sets the sizes the scene's font is rendered at when the scene loads, rendering them now if it's loaded.
+func (rm *ResourceManager) SetFontSizes(viewName string, sizes ...int) error {
+	for i := range rm.Scenes {
+		if font := rm.Scenes[i].Font; rm.Scenes[i].Name == viewName && font != nil {
+			font.Sizes = sizes
+			if font.Loaded {
+				for _, size := range sizes {
+					rm.fontAtSize(font, size)
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("font not found in view: %s", viewName)
+}
+
+// SetFallbackFont sets the font used for text with glyphs the scene's font is missing, loading it now if the scene is loaded.
+func (rm *ResourceManager) SetFallbackFont(viewName string, fontDef Resource) error {
+	for i := range rm.Scenes {
+		if font := rm.Scenes[i].Font; rm.Scenes[i].Name == viewName && font != nil {
+			rm.unloadSceneFont(font.Fallback)
+			font.Fallback = &Font{Name: fontDef.Name, Path: fontDef.Path}
+			if font.Loaded {
+				rm.loadSceneFont(font.Fallback)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("font not found in view: %s", viewName)
+}
+
+// fontAtSize returns a loaded font rendered at the given size, rendering it if it isn't cached yet.
+func (rm *ResourceManager) fontAtSize(font *Font, size int) rl.Font {
+	if size <= 0 || size == DefaultFontSize {
+		return font.Font
+	}
+	if variant, ok := font.variants[size]; ok {
+		return variant
+	}
+	if font.variants == nil {
+		font.variants = make(map[int]rl.Font)
+	}
+	variant := loadFont(rm, font.Path, size)
+	font.variants[size] = variant
+	return variant
+}
+
+// loadSceneFont loads a font, the sizes it's rendered at and its fallback.
+func (rm *ResourceManager) loadSceneFont(font *Font) {
+	if font == nil || font.Loaded {
+		return
+	}
+	font.Font = loadFont(rm, font.Path, DefaultFontSize)
+	font.Loaded = true
+	for _, size := range font.Sizes {
+		rm.fontAtSize(font, size)
+	}
+	rm.loadSceneFont(font.Fallback)
+}
+
+// unloadSceneFont unloads a font, every size it was rendered at and its fallback.
+func (rm *ResourceManager) unloadSceneFont(font *Font) {
+	if font == nil || !font.Loaded {
+		return
+	}
+	unloadFont(font.Font)
+	for _, variant := range font.variants {
+		unloadFont(variant)
+	}
+	font.variants = nil
+	font.Loaded = false
+	rm.unloadSceneFont(font.Fallback)
+}
+
+// hasGlyph reports if the font has a glyph for r. A font without glyph data is drawn with raylib's default font, so it's assumed to have it.
+func hasGlyph(font rl.Font, r rune) bool {
+	if font.Chars == nil || font.CharsCount <= 0 {
+		return true
+	}
+	for _, glyph := range unsafe.Slice(font.Chars, font.CharsCount) {
+		if glyph.Value == r {
+			return true
+		}
+	}
+	return false
+}
+
 func (rm *ResourceManager) getSpriteFromSheets(view *Scene, spriteName string) (rl.Texture2D, Rectangle, bool) {
 	for _, sheet := range view.SpriteSheets {
 		if sheet.Loaded {
@@ -655,18 +986,13 @@ func (rm *ResourceManager) AddResource(sceneName string, resource Resource) erro
 				}
 
 				for spriteName, pos := range resource.SheetData {
-					spriteSheet.Sprites[spriteName] = Rectangle{
-						X:      pos[0] * (gridSizeX + resource.SheetMargin),
-						Y:      pos[1] * (gridSizeY + resource.SheetMargin),
-						Width:  gridSizeX,
-						Height: gridSizeY,
-					}
+					spriteSheet.Sprites[spriteName] = sheetSpriteRect(pos, gridSizeX, gridSizeY, resource.SheetMargin)
 				}
 				view.SpriteSheets = append(view.SpriteSheets, spriteSheet)
 
 				// Load the sheet if the scene is currently loaded
 				if view.Loaded {
-					spriteSheet.Texture = rm.LoadTexture(spriteSheet.Path)
+					spriteSheet.Texture = rm.acquireTexture(spriteSheet.Path)
 					spriteSheet.Loaded = true
 				}
 			} else {
@@ -679,7 +1005,7 @@ func (rm *ResourceManager) AddResource(sceneName string, resource Resource) erro
 
 				// Load the texture if the scene is currently loaded
 				if view.Loaded {
-					texture.Texture = rm.LoadTexture(texture.Path)
+					texture.Texture = rm.acquireTexture(texture.Path)
 					texture.Loaded = true
 					view.Textures[len(view.Textures)-1] = texture
 				}
@@ -699,7 +1025,7 @@ func (rm *ResourceManager) RemoveResource(sceneName string, resourceName string)
 			for j := range view.Textures {
 				if view.Textures[j].Name == resourceName {
 					if view.Textures[j].Loaded {
-						rl.UnloadTexture(view.Textures[j].Texture)
+						rm.releaseTexture(view.Textures[j].Path)
 					}
 					view.Textures = append(view.Textures[:j], view.Textures[j+1:]...)
 					return nil
@@ -710,7 +1036,7 @@ func (rm *ResourceManager) RemoveResource(sceneName string, resourceName string)
 			for j := range view.SpriteSheets {
 				if view.SpriteSheets[j].Name == resourceName {
 					if view.SpriteSheets[j].Loaded {
-						rl.UnloadTexture(view.SpriteSheets[j].Texture)
+						rm.releaseTexture(view.SpriteSheets[j].Path)
 					}
 					view.SpriteSheets = append(view.SpriteSheets[:j], view.SpriteSheets[j+1:]...)
 					return nil
@@ -723,6 +1049,114 @@ func (rm *ResourceManager) RemoveResource(sceneName string, resourceName string)
 	return fmt.Errorf("scene not found: %s", sceneName)
 }
 
+// RenameResource renames a texture or sprite sheet in a scene.
+// Sprites named after a sheet, like <sheet>_<row>_<col>, are renamed with it, see RenamedSpriteName.
+// Fails if the new name, or any renamed sprite, is already used in the scene.
+func (rm *ResourceManager) RenameResource(sceneName, oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("resource name is required")
+	}
+	for i := range rm.Scenes {
+		if rm.Scenes[i].Name != sceneName {
+			continue
+		}
+		view := &rm.Scenes[i]
+		if oldName == newName {
+			return nil
+		}
+
+		// Collect every name in use, so renamed textures and sprites can't collide
+		taken := make(map[string]bool)
+		for _, tex := range view.Textures {
+			taken[tex.Name] = true
+		}
+		for _, sheet := range view.SpriteSheets {
+			taken[sheet.Name] = true
+			for spriteName := range sheet.Sprites {
+				taken[spriteName] = true
+			}
+		}
+		if taken[newName] {
+			return fmt.Errorf("resource name conflict: %s. Name already exists", newName)
+		}
+
+		for j := range view.Textures {
+			if view.Textures[j].Name == oldName {
+				view.Textures[j].Name = newName
+				return nil
+			}
+		}
+
+		for _, sheet := range view.SpriteSheets {
+			if sheet.Name != oldName {
+				continue
+			}
+			sprites := make(map[string]Rectangle, len(sheet.Sprites))
+			for spriteName, region := range sheet.Sprites {
+				if renamed, ok := RenamedSpriteName(oldName, newName, spriteName); ok {
+					if taken[renamed] {
+						return fmt.Errorf("resource name conflict: %s. Name already exists", renamed)
+					}
+					spriteName = renamed
+				}
+				sprites[spriteName] = region
+			}
+			sheet.Name = newName
+			sheet.Sprites = sprites
+			return nil
+		}
+
+		return fmt.Errorf("resource not found: %s", oldName)
+	}
+	return fmt.Errorf("scene not found: %s", sceneName)
+}
+
+// RenamedSpriteName returns the new name of a sprite when its sheet is renamed.
+// Only sprites prefixed with the sheet name are renamed, others keep their name and return false.
+func RenamedSpriteName(oldSheetName, newSheetName, spriteName string) (string, bool) {
+	suffix, ok := strings.CutPrefix(spriteName, oldSheetName+"_")
+	if !ok {
+		return spriteName, false
+	}
+	return newSheetName + "_" + suffix, true
+}
+
+// Resource returns the definition that loads this sheet with the same sprite names and regions.
+// Each sprite is stored in SheetData as its grid cell, or as its exact pixels if it doesn't fill the cell.
+func (sheet *SpriteSheet) Resource() Resource {
+	sheetData := make(map[string][]int32)
+	for name, rect := range sheet.Sprites {
+		cell := []int32{rect.X / (sheet.GridSizeX + sheet.Margin), rect.Y / (sheet.GridSizeY + sheet.Margin)}
+		if sheetSpriteRect(cell, sheet.GridSizeX, sheet.GridSizeY, sheet.Margin) == rect {
+			sheetData[name] = cell
+		} else {
+			sheetData[name] = []int32{rect.X, rect.Y, rect.Width, rect.Height}
+		}
+	}
+	return Resource{
+		Name:        sheet.Name,
+		Path:        sheet.Path,
+		IsSheet:     true,
+		SheetData:   sheetData,
+		SheetMargin: sheet.Margin,
+		GridSizeX:   sheet.GridSizeX,
+		GridSizeY:   sheet.GridSizeY,
+	}
+}
+
+// sheetSpriteRect returns a sprite's region from its SheetData entry, either a grid cell or exact pixels.
+func sheetSpriteRect(pos []int32, gridSizeX, gridSizeY, margin int32) Rectangle {
+	if len(pos) == 4 {
+		return Rectangle{X: pos[0], Y: pos[1], Width: pos[2], Height: pos[3]}
+	}
+	return Rectangle{
+		X:      pos[0] * (gridSizeX + margin),
+		Y:      pos[1] * (gridSizeY + margin),
+		Width:  gridSizeX,
+		Height: gridSizeY,
+	}
+}
+
 func (rm *ResourceManager) SaveState() ResourceState {
 	state := ResourceState{
 		Scenes: make([]SceneState, len(rm.Scenes)),
@@ -744,19 +1178,7 @@ func (rm *ResourceManager) SaveState() ResourceState {
 
 		// Save sprite sheets
 		for _, sheet := range scene.SpriteSheets {
-			sheetData := make(map[string][]int32)
-			for name, rect := range sheet.Sprites {
-				sheetData[name] = []int32{rect.X / (sheet.GridSizeX + sheet.Margin), rect.Y / (sheet.GridSizeY + sheet.Margin)}
-			}
-			sceneState.SpriteSheets = append(sceneState.SpriteSheets, Resource{
-				Name:        sheet.Name,
-				Path:        sheet.Path,
-				IsSheet:     true,
-				SheetData:   sheetData,
-				SheetMargin: sheet.Margin,
-				GridSizeX:   sheet.GridSizeX,
-				GridSizeY:   sheet.GridSizeY,
-			})
+			sceneState.SpriteSheets = append(sceneState.SpriteSheets, sheet.Resource())
 		}
 
 		// Save font if present
@@ -765,6 +1187,13 @@ func (rm *ResourceManager) SaveState() ResourceState {
 				Name: scene.Font.Name,
 				Path: scene.Font.Path,
 			}
+			sceneState.FontSizes = scene.Font.Sizes
+			if scene.Font.Fallback != nil {
+				sceneState.FallbackFont = &Resource{
+					Name: scene.Font.Fallback.Name,
+					Path: scene.Font.Fallback.Path,
+				}
+			}
 		}
 
 		state.Scenes[i] = sceneState
@@ -814,6 +1243,12 @@ func InitFromState(state ResourceState) *ResourceManager {
 		}
 
 		rm.AddScene(sceneState.Name, textureDefs, fontDef)
+		if fontDef != nil {
+			rm.SetFontSizes(sceneState.Name, sceneState.FontSizes...)
+			if sceneState.FallbackFont != nil {
+				rm.SetFallbackFont(sceneState.Name, *sceneState.FallbackFont)
+			}
+		}
 		if sceneState.Loaded {
 			rm.LoadView(sceneState.Name)
 		}
@@ -0,0 +1,592 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TestDecodeImage feeds tiny generated images of each supported format through
+// decodeImage and checks the decoded dimensions and pixels.
+func TestDecodeImage(t *testing.T) {
+	const w, h = 2, 3
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x * 100), G: uint8(y * 80), B: 50, A: 255})
+		}
+	}
+
+	testCases := []struct {
+		name string
+		ext  string
+		data []byte
+	}{
+		{name: "png", ext: ".png", data: encodePNG(t, src)},
+		{name: "bmp", ext: ".bmp", data: encodeBMP(src)},
+		{name: "tga", ext: ".tga", data: encodeTGA(src)},
+		{name: "tga rle", ext: ".tga", data: encodeTGARLE(src)},
+		{name: "qoi", ext: ".qoi", data: encodeQOI(src)},
+		{name: "gif", ext: ".gif", data: encodeGIF(t, src)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := decodeImage(tc.ext, tc.data)
+			if err != nil {
+				t.Fatalf("decodeImage failed for %s: %v", tc.name, err)
+			}
+			defer rl.UnloadImage(img)
+			if img.Width != w || img.Height != h {
+				t.Fatalf("Expected %dx%d image, got %dx%d", w, h, img.Width, img.Height)
+			}
+			pixels := rl.LoadImageColors(img)
+			defer rl.UnloadImageColors(pixels)
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					if got, expected := pixels[y*w+x], src.RGBAAt(x, y); got != expected {
+						t.Errorf("Expected %v at (%d, %d), got %v", expected, x, y, got)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := decodeImage(".tga", encodeTGA(src)[:30]); err == nil {
+		t.Errorf("Expected an error for truncated .tga data")
+	}
+
+	if _, err := decodeImage(".psd", []byte{0}); !errors.Is(err, ErrUnsupportedImageFormat) {
+		t.Errorf("Expected ErrUnsupportedImageFormat for .psd, got %v", err)
+	}
+}
+
+func encodePNG(t *testing.T, img *image.RGBA) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// encodeGIF writes two frames, with a palette of exactly the image's colors so it decodes losslessly.
+func encodeGIF(t *testing.T, img *image.RGBA) []byte {
+	var colors color.Palette
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if c := img.RGBAAt(x, y); !slices.Contains(colors, color.Color(c)) {
+				colors = append(colors, c)
+			}
+		}
+	}
+	frame := image.NewPaletted(img.Bounds(), colors)
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			frame.Set(x, y, img.At(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	anim := &gif.GIF{Image: []*image.Paletted{frame, frame}, Delay: []int{10, 10}}
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("gif encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// encodeTGA writes an uncompressed 32-bit top-left origin TGA.
+func encodeTGA(img *image.RGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	header := make([]byte, 18)
+	header[2] = 2 // uncompressed true-color
+	binary.LittleEndian.PutUint16(header[12:], uint16(w))
+	binary.LittleEndian.PutUint16(header[14:], uint16(h))
+	header[16] = 32
+	header[17] = 0x28 // top-left origin, 8 alpha bits
+
+	data := header
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			data = append(data, c.B, c.G, c.R, c.A)
+		}
+	}
+	return data
+}
+
+// encodeTGARLE writes a run length encoded 24-bit TGA with rows bottom to top, the default origin.
+// Each row is one repeated pixel packet for its first pixel, then a raw packet for the rest.
+func encodeTGARLE(img *image.RGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	header := make([]byte, 18)
+	header[2] = 10 // run length encoded true-color
+	binary.LittleEndian.PutUint16(header[12:], uint16(w))
+	binary.LittleEndian.PutUint16(header[14:], uint16(h))
+	header[16] = 24
+
+	data := header
+	for y := h - 1; y >= 0; y-- {
+		c := img.RGBAAt(0, y)
+		data = append(data, 0x80, c.B, c.G, c.R)
+		data = append(data, byte(w-2))
+		for x := 1; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			data = append(data, c.B, c.G, c.R)
+		}
+	}
+	return data
+}
+
+// encodeBMP writes an uncompressed 24-bit BMP, rows bottom to top and padded to 4 bytes.
+func encodeBMP(img *image.RGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	rowSize := (w*3 + 3) &^ 3
+	data := make([]byte, 54, 54+rowSize*h)
+	copy(data, "BM")
+	binary.LittleEndian.PutUint32(data[2:], uint32(54+rowSize*h))
+	binary.LittleEndian.PutUint32(data[10:], 54)
+	binary.LittleEndian.PutUint32(data[14:], 40)
+	binary.LittleEndian.PutUint32(data[18:], uint32(w))
+	binary.LittleEndian.PutUint32(data[22:], uint32(h))
+	binary.LittleEndian.PutUint16(data[26:], 1)
+	binary.LittleEndian.PutUint16(data[28:], 24)
+	for y := h - 1; y >= 0; y-- {
+		row := make([]byte, rowSize)
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			row[x*3], row[x*3+1], row[x*3+2] = c.B, c.G, c.R
+		}
+		data = append(data, row...)
+	}
+	return data
+}
+
+// encodeQOI writes every pixel as a QOI_OP_RGBA chunk.
+func encodeQOI(img *image.RGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	data := []byte("qoif")
+	data = binary.BigEndian.AppendUint32(data, uint32(w))
+	data = binary.BigEndian.AppendUint32(data, uint32(h))
+	data = append(data, 4, 0)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			data = append(data, 0xff, c.R, c.G, c.B, c.A)
+		}
+	}
+	return append(data, 0, 0, 0, 0, 0, 0, 0, 1)
+}
+
+// TestSharedTextures loads the same path in two scenes and checks that it is
+// uploaded once, and only unloaded after both scenes are done with it.
+func TestSharedTextures(t *testing.T) {
+	loads, unloads := 0, 0
+	origUpload, origUnload := uploadTexture, unloadTexture
+	uploadTexture = func(rm *ResourceManager, path string) rl.Texture2D {
+		loads++
+		return rl.Texture2D{ID: uint32(loads), Width: 64, Height: 64}
+	}
+	unloadTexture = func(rl.Texture2D) { unloads++ }
+	defer func() { uploadTexture, unloadTexture = origUpload, origUnload }()
+
+	rm := &ResourceManager{}
+	sheet := func(gridSize int32) []Resource {
+		return []Resource{{
+			Name:      "tiles",
+			Path:      "shared.png",
+			IsSheet:   true,
+			SheetData: map[string][]int32{"tile_0_1": {1, 0}},
+			GridSizeX: gridSize,
+			GridSizeY: gridSize,
+		}}
+	}
+	if err := rm.AddScene("town", sheet(16), nil); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	if err := rm.AddScene("dungeon", sheet(32), nil); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	rm.LoadView("town")
+	rm.LoadView("dungeon")
+
+	if loads != 1 {
+		t.Fatalf("Expected 1 texture load, got %d", loads)
+	}
+
+	// Each sheet keeps its own regions over the shared texture
+	for scene, wantX := range map[string]float32{"town": 16, "dungeon": 32} {
+		info, err := rm.GetTexture(scene, "tile_0_1")
+		if err != nil {
+			t.Fatalf("GetTexture failed for %s: %v", scene, err)
+		}
+		if info.Texture.ID != 1 || info.Region.X != wantX {
+			t.Errorf("%s: expected texture 1 at x=%v, got texture %d at x=%v", scene, wantX, info.Texture.ID, info.Region.X)
+		}
+	}
+
+	rm.UnloadView("town")
+	if unloads != 0 {
+		t.Fatalf("Texture unloaded while still used by another scene")
+	}
+	rm.UnloadView("dungeon")
+	if unloads != 1 {
+		t.Fatalf("Expected 1 texture unload, got %d", unloads)
+	}
+}
+
+// TestNineSlicePatchInfo checks the insets passed to raylib for a nine-slice.
+func TestNineSlicePatchInfo(t *testing.T) {
+	region := rl.Rectangle{X: 16, Y: 32, Width: 48, Height: 24}
+	testCases := []struct {
+		name                     string
+		left, right, top, bottom int32
+		want                     [4]int32 // left, right, top, bottom
+	}{
+		{name: "fits", left: 4, right: 6, top: 3, bottom: 5, want: [4]int32{4, 6, 3, 5}},
+		{name: "negative", left: -2, right: 6, top: 3, bottom: -1, want: [4]int32{0, 6, 3, 0}},
+		{name: "overlapping", left: 40, right: 40, top: 30, bottom: 10, want: [4]int32{24, 24, 18, 6}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := NineSlice{Region: region, Left: tc.left, Right: tc.right, Top: tc.top, Bottom: tc.bottom}
+			info := n.patchInfo()
+			got := [4]int32{info.Left, info.Right, info.Top, info.Bottom}
+			if got != tc.want {
+				t.Errorf("Expected insets %v, got %v", tc.want, got)
+			}
+			if info.Source != region {
+				t.Errorf("Expected source %v, got %v", region, info.Source)
+			}
+			if info.Layout != rl.NPatchNinePatch {
+				t.Errorf("Expected nine-patch layout, got %v", info.Layout)
+			}
+		})
+	}
+}
+
+// TestRenameResource renames a sheet and a texture, and checks sprites are rekeyed and conflicts rejected.
+func TestRenameResource(t *testing.T) {
+	rm := &ResourceManager{Scenes: []Scene{{
+		Name:     "default",
+		Textures: []Texture{{Name: "grass"}},
+		SpriteSheets: []*SpriteSheet{{
+			Name:    "dungeon",
+			Sprites: map[string]Rectangle{"dungeon_0_0": {}, "dungeon_0_1": {X: 16}, "door": {X: 32}},
+		}},
+	}}}
+
+	if err := rm.RenameResource("default", "dungeon", "grass"); err == nil {
+		t.Error("Expected renaming to an existing name to fail")
+	}
+	if err := rm.RenameResource("default", "dungeon", "cave"); err != nil {
+		t.Fatal(err)
+	}
+	sheet := rm.Scenes[0].SpriteSheets[0]
+	if sheet.Name != "cave" {
+		t.Errorf("Expected the sheet to be renamed, got %s", sheet.Name)
+	}
+	for _, name := range []string{"cave_0_0", "cave_0_1", "door"} {
+		if _, ok := sheet.Sprites[name]; !ok {
+			t.Errorf("Expected sprite %s after rename", name)
+		}
+	}
+	if sheet.Sprites["cave_0_1"].X != 16 {
+		t.Errorf("Expected sprite regions to be kept")
+	}
+
+	if err := rm.RenameResource("default", "grass", "door"); err == nil {
+		t.Error("Expected renaming a texture to a sprite name to fail")
+	}
+	if err := rm.RenameResource("default", "grass", "moss"); err != nil || rm.Scenes[0].Textures[0].Name != "moss" {
+		t.Errorf("Expected the texture to be renamed, got %v", err)
+	}
+	if err := rm.RenameResource("default", "missing", "other"); err == nil {
+		t.Error("Expected renaming a missing resource to fail")
+	}
+}
+
+// TestSpriteSheetResourceRoundTrip exports a sheet to JSON and reloads it through InitFromState and AddResource,
+// checking every sprite comes back with the same region, including one trimmed smaller than its cell.
+func TestSpriteSheetResourceRoundTrip(t *testing.T) {
+	sheet := &SpriteSheet{
+		Name:      "cave",
+		Path:      "cave.png",
+		GridSizeX: 16,
+		GridSizeY: 8,
+		Margin:    2,
+		Sprites: map[string]Rectangle{
+			"cave_0_0": {X: 0, Y: 0, Width: 16, Height: 8},
+			"cave_2_1": {X: 36, Y: 10, Width: 16, Height: 8},
+			"cave_1_3": {X: 18, Y: 30, Width: 16, Height: 8},
+			"cave_3_0": {X: 57, Y: 2, Width: 9, Height: 5},
+		},
+	}
+
+	data, err := json.Marshal(sheet.Resource())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res Resource
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if cell := res.SheetData["cave_0_0"]; len(cell) != 2 {
+		t.Errorf("Expected a sprite filling its cell to be stored as the cell, got %v", cell)
+	}
+
+	rm := InitFromState(ResourceState{Scenes: []SceneState{{Name: "default", SpriteSheets: []Resource{res}}}})
+	added := &ResourceManager{}
+	if err := added.AddScene("default", nil, nil); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	if err := added.AddResource("default", res); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	for how, rm := range map[string]*ResourceManager{"InitFromState": rm, "AddResource": added} {
+		if len(rm.Scenes) != 1 || len(rm.Scenes[0].SpriteSheets) != 1 {
+			t.Fatalf("%s: expected one scene with one sheet, got %+v", how, rm.Scenes)
+		}
+		loaded := rm.Scenes[0].SpriteSheets[0]
+		if len(loaded.Sprites) != len(sheet.Sprites) {
+			t.Fatalf("%s: expected %d sprites, got %d", how, len(sheet.Sprites), len(loaded.Sprites))
+		}
+		for name, want := range sheet.Sprites {
+			if got := loaded.Sprites[name]; got != want {
+				t.Errorf("%s: %s: expected region %+v, got %+v", how, name, want, got)
+			}
+		}
+	}
+}
+
+// TestMemoryStats checks the texture counts and VRAM bytes for textures of known sizes,
+// with a texture shared between scenes only counted once in the total.
+func TestMemoryStats(t *testing.T) {
+	origUpload, origUnload := uploadTexture, unloadTexture
+	uploadTexture = func(rm *ResourceManager, path string) rl.Texture2D {
+		sizes := map[string][2]int32{"hero.png": {32, 48}, "tiles.png": {256, 128}, "sky.png": {1024, 512}}
+		return rl.Texture2D{ID: 1, Width: sizes[path][0], Height: sizes[path][1]}
+	}
+	unloadTexture = func(rl.Texture2D) {}
+	defer func() { uploadTexture, unloadTexture = origUpload, origUnload }()
+
+	rm := &ResourceManager{}
+	tiles := Resource{Name: "tiles", Path: "tiles.png", IsSheet: true, SheetData: map[string][]int32{"tile_0_0": {0, 0}}, GridSizeX: 16, GridSizeY: 16}
+	if err := rm.AddScene("town", []Resource{{Name: "hero", Path: "hero.png"}, tiles}, nil); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	if err := rm.AddScene("sky", []Resource{{Name: "sky", Path: "sky.png"}, tiles}, nil); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	rm.LoadView("town")
+
+	heroBytes, tilesBytes, skyBytes := int64(32*48*4), int64(256*128*4), int64(1024*512*4)
+	town := rm.MemoryStats("town")
+	if town != (SceneStats{Textures: 1, SpriteSheets: 1, Loaded: 2, VRAMBytes: heroBytes + tilesBytes}) {
+		t.Errorf("Expected 1 texture and 1 sheet using %d bytes, got %+v", heroBytes+tilesBytes, town)
+	}
+	if sky := rm.MemoryStats("sky"); sky != (SceneStats{Textures: 1, SpriteSheets: 1, Unloaded: 2}) {
+		t.Errorf("Expected the unloaded scene to use no memory, got %+v", sky)
+	}
+	if missing := rm.MemoryStats("missing"); missing != (SceneStats{}) {
+		t.Errorf("Expected empty stats for a missing scene, got %+v", missing)
+	}
+
+	rm.LoadView("sky")
+	if sky := rm.MemoryStats("sky"); sky.VRAMBytes != skyBytes+tilesBytes || sky.Loaded != 2 {
+		t.Errorf("Expected the sky scene to use %d bytes, got %+v", skyBytes+tilesBytes, sky)
+	}
+	total := rm.TotalMemoryStats()
+	if total.VRAMBytes != heroBytes+tilesBytes+skyBytes {
+		t.Errorf("Expected the shared sheet to be counted once, for %d bytes, got %d", heroBytes+tilesBytes+skyBytes, total.VRAMBytes)
+	}
+	if total.Textures != 2 || total.SpriteSheets != 2 || total.Loaded != 4 || total.Unloaded != 0 {
+		t.Errorf("Expected 2 textures and 2 sheets loaded, got %+v", total)
+	}
+
+	rm.UnloadView("town")
+	if total := rm.TotalMemoryStats(); total.VRAMBytes != tilesBytes+skyBytes || total.Unloaded != 2 {
+		t.Errorf("Expected the sheet to stay loaded for the sky, for %d bytes, got %+v", tilesBytes+skyBytes, total)
+	}
+}
+
+// newBudgetTestManager returns a manager with one texture in each of the named scenes, with uploads faked.
+func newBudgetTestManager(t *testing.T, scenes ...string) *ResourceManager {
+	t.Helper()
+	origUpload, origUnload := uploadTexture, unloadTexture
+	uploadTexture = func(rm *ResourceManager, path string) rl.Texture2D {
+		return rl.Texture2D{ID: 1, Width: 16, Height: 16}
+	}
+	unloadTexture = func(rl.Texture2D) {}
+	t.Cleanup(func() { uploadTexture, unloadTexture = origUpload, origUnload })
+
+	rm := &ResourceManager{}
+	for _, name := range scenes {
+		if err := rm.AddScene(name, []Resource{{Name: name, Path: name + ".png"}}, nil); err != nil {
+			t.Fatalf("AddScene failed: %v", err)
+		}
+	}
+	return rm
+}
+
+// loadedScenes returns the names of the loaded scenes, in order.
+func loadedScenes(rm *ResourceManager) []string {
+	var loaded []string
+	for _, scene := range rm.Scenes {
+		if scene.Loaded {
+			loaded = append(loaded, scene.Name)
+		}
+	}
+	return loaded
+}
+
+// TestSceneBudget checks loading past the budget unloads the least recently used scene,
+// where reading a texture counts as using its scene.
+func TestSceneBudget(t *testing.T) {
+	rm := newBudgetTestManager(t, "a", "b", "c")
+	rm.SetSceneBudget(2)
+	rm.LoadView("a")
+	rm.LoadView("b")
+	if _, err := rm.GetTexture("a", "a"); err != nil {
+		t.Fatalf("GetTexture failed: %v", err)
+	}
+
+	if err := rm.LoadView("c"); err != nil {
+		t.Fatalf("LoadView failed: %v", err)
+	}
+	if loaded := loadedScenes(rm); !slices.Equal(loaded, []string{"a", "c"}) {
+		t.Errorf("Expected b to be unloaded, got %v loaded", loaded)
+	}
+
+	// Loading a scene that's already loaded only marks it used
+	rm.LoadView("a")
+	rm.LoadView("b")
+	if loaded := loadedScenes(rm); !slices.Equal(loaded, []string{"a", "b"}) {
+		t.Errorf("Expected c to be unloaded, got %v loaded", loaded)
+	}
+
+	// Lowering the budget unloads straight away
+	rm.SetSceneBudget(1)
+	if loaded := loadedScenes(rm); !slices.Equal(loaded, []string{"b"}) {
+		t.Errorf("Expected only b to stay loaded, got %v", loaded)
+	}
+}
+
+// TestSceneBudgetPinned checks pinned scenes, and the default scene, are never unloaded or counted against the budget.
+func TestSceneBudgetPinned(t *testing.T) {
+	rm := newBudgetTestManager(t, "default", "a", "b", "c")
+	rm.SetSceneBudget(1)
+	rm.PinScene("a")
+	rm.LoadView("default")
+	rm.LoadView("a")
+	rm.LoadView("b")
+	rm.LoadView("c")
+	if loaded := loadedScenes(rm); !slices.Equal(loaded, []string{"default", "a", "c"}) {
+		t.Errorf("Expected only b to be unloaded, got %v loaded", loaded)
+	}
+
+	// Once unpinned, a is the least recently used
+	rm.UnpinScene("a")
+	rm.LoadView("b")
+	if loaded := loadedScenes(rm); !slices.Equal(loaded, []string{"default", "b"}) {
+		t.Errorf("Expected a and c to be unloaded, got %v loaded", loaded)
+	}
+}
+
+// TestFontSizes checks fonts are rendered at each size once, cached, and all unloaded with their scene.
+func TestFontSizes(t *testing.T) {
+	loads := map[int]int{}
+	unloads := 0
+	origLoad, origUnload := loadFont, unloadFont
+	loadFont = func(rm *ResourceManager, path string, size int) rl.Font {
+		loads[size]++
+		return rl.Font{BaseSize: int32(size)}
+	}
+	unloadFont = func(rl.Font) { unloads++ }
+	defer func() { loadFont, unloadFont = origLoad, origUnload }()
+
+	rm := &ResourceManager{}
+	if err := rm.AddScene("menu", nil, &Resource{Name: "title", Path: "title.ttf"}); err != nil {
+		t.Fatalf("AddScene failed: %v", err)
+	}
+	if err := rm.SetFontSizes("menu", 16); err != nil {
+		t.Fatalf("SetFontSizes failed: %v", err)
+	}
+	if font := rm.GetFontAtSize("menu", 48); font.BaseSize != 0 || len(loads) != 0 {
+		t.Errorf("Expected no font before the scene loads, got %+v", font)
+	}
+	rm.LoadView("menu")
+	if loads[DefaultFontSize] != 1 || loads[16] != 1 {
+		t.Errorf("Expected the default and 16 sizes loaded with the scene, got %v", loads)
+	}
+
+	for range 3 {
+		if font := rm.GetFontAtSize("menu", 48); font.BaseSize != 48 {
+			t.Errorf("Expected the font at size 48, got %d", font.BaseSize)
+		}
+	}
+	if loads[48] != 1 {
+		t.Errorf("Expected size 48 to be loaded once, got %d", loads[48])
+	}
+	if font := rm.GetFontAtSize("menu", DefaultFontSize); font.BaseSize != DefaultFontSize || loads[DefaultFontSize] != 1 {
+		t.Errorf("Expected the default size to be the scene's font, got %+v after %v", font, loads)
+	}
+
+	rm.UnloadView("menu")
+	if unloads != 3 {
+		t.Errorf("Expected all 3 sizes unloaded, got %d", unloads)
+	}
+	rm.LoadView("menu")
+	if loads[48] != 1 {
+		t.Errorf("Expected size 48 not to be loaded again until it's asked for, got %d", loads[48])
+	}
+}
+
+// TestFontFallback checks text with a glyph the scene's font is missing is drawn with the fallback font.
+func TestFontFallback(t *testing.T) {
+	latin := []rl.GlyphInfo{{Value: 'H'}, {Value: 'i'}}
+	kana := []rl.GlyphInfo{{Value: 'H'}, {Value: 'i'}, {Value: 'あ'}}
+	origLoad, origUnload := loadFont, unloadFont
+	loadFont = func(rm *ResourceManager, path string, size int) rl.Font {
+		glyphs := latin
+		if path == "kana.ttf" {
+			glyphs = kana
+		}
+		return rl.Font{BaseSize: int32(size), CharsCount: int32(len(glyphs)), Chars: &glyphs[0]}
+	}
+	unloadFont = func(rl.Font) {}
+	defer func() { loadFont, unloadFont = origLoad, origUnload }()
+
+	rm := &ResourceManager{}
+	rm.AddScene("menu", nil, &Resource{Name: "title", Path: "title.ttf"})
+	rm.LoadView("menu")
+	if font := rm.FontForText("menu", "Hiあ", 24); font.Chars != &latin[0] {
+		t.Errorf("Expected the scene's font without a fallback")
+	}
+	if err := rm.SetFallbackFont("menu", Resource{Name: "kana", Path: "kana.ttf"}); err != nil {
+		t.Fatalf("SetFallbackFont failed: %v", err)
+	}
+
+	if font := rm.FontForText("menu", "Hi", 24); font.Chars != &latin[0] || font.BaseSize != 24 {
+		t.Errorf("Expected the scene's font at size 24 for text it has glyphs for, got %+v", font)
+	}
+	if font := rm.FontForText("menu", "Hi\nHi", 24); font.Chars != &latin[0] {
+		t.Errorf("Expected the scene's font for multi-line text, got %+v", font)
+	}
+	if font := rm.FontForText("menu", "Hiあ", 24); font.Chars != &kana[0] || font.BaseSize != 24 {
+		t.Errorf("Expected the fallback font at size 24 for a missing glyph, got %+v", font)
+	}
+
+	state := rm.SaveState()
+	if fallback := state.Scenes[0].FallbackFont; fallback == nil || fallback.Path != "kana.ttf" {
+		t.Errorf("Expected the fallback font to be saved, got %+v", fallback)
+	}
+}
@@ -0,0 +1,108 @@
+package resources
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// The raylib build we link against doesn't include its TGA loader, so TGA files are decoded here.
+// Uncompressed and run-length encoded true-color and grayscale images are supported, which covers
+// what image editors like Aseprite export. Color-mapped images are not.
+
+const tgaHeaderSize = 18
+
+var errTGATruncated = errors.New("tga: truncated image data")
+
+// decodeTGA decodes a TGA image to 8-bit RGBA.
+func decodeTGA(data []byte) (*image.NRGBA, error) {
+	if len(data) < tgaHeaderSize {
+		return nil, errTGATruncated
+	}
+	idLength := int(data[0])
+	colorMapType := data[1]
+	imageType := data[2]
+	width := int(binary.LittleEndian.Uint16(data[12:]))
+	height := int(binary.LittleEndian.Uint16(data[14:]))
+	depth := int(data[16])
+	descriptor := data[17]
+
+	if colorMapType != 0 {
+		return nil, errors.New("tga: color-mapped images are not supported")
+	}
+	rle := imageType >= 8
+	gray := imageType == 3 || imageType == 11
+	switch {
+	case imageType != 2 && imageType != 3 && imageType != 10 && imageType != 11:
+		return nil, fmt.Errorf("tga: unsupported image type %d", imageType)
+	case gray && depth != 8, !gray && depth != 24 && depth != 32:
+		return nil, fmt.Errorf("tga: unsupported pixel depth %d", depth)
+	case width == 0 || height == 0:
+		return nil, errors.New("tga: empty image")
+	}
+
+	pixelSize := depth / 8
+	pixels := data[min(len(data), tgaHeaderSize+idLength):]
+	// A packet covers at most 128 pixels, so this bounds the allocation by the input size
+	if !rle && len(pixels) < width*height*pixelSize || rle && len(pixels) < (width*height+127)/128 {
+		return nil, errTGATruncated
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	setPixel := func(i int, p []byte) {
+		// Rows are stored bottom to top unless the descriptor says otherwise
+		x, y := i%width, i/width
+		if descriptor&0x10 != 0 {
+			x = width - 1 - x
+		}
+		if descriptor&0x20 == 0 {
+			y = height - 1 - y
+		}
+		o := img.PixOffset(x, y)
+		switch {
+		case gray:
+			img.Pix[o], img.Pix[o+1], img.Pix[o+2], img.Pix[o+3] = p[0], p[0], p[0], 255
+		case pixelSize == 3:
+			img.Pix[o], img.Pix[o+1], img.Pix[o+2], img.Pix[o+3] = p[2], p[1], p[0], 255
+		default:
+			img.Pix[o], img.Pix[o+1], img.Pix[o+2], img.Pix[o+3] = p[2], p[1], p[0], p[3]
+		}
+	}
+
+	count := width * height
+	if !rle {
+		for i := 0; i < count; i++ {
+			setPixel(i, pixels[i*pixelSize:])
+		}
+		return img, nil
+	}
+
+	// Each packet is a header byte and either one pixel repeated, or a run of raw pixels
+	for i, pos := 0, 0; i < count; {
+		if pos >= len(pixels) {
+			return nil, errTGATruncated
+		}
+		header := pixels[pos]
+		pos++
+		n := int(header&0x7f) + 1
+		repeat := header&0x80 != 0
+		need := n * pixelSize
+		if repeat {
+			need = pixelSize
+		}
+		if pos+need > len(pixels) {
+			return nil, errTGATruncated
+		}
+		for j := 0; j < n && i < count; j++ {
+			if repeat {
+				setPixel(i, pixels[pos:])
+			} else {
+				setPixel(i, pixels[pos+j*pixelSize:])
+			}
+			i++
+		}
+		pos += need
+	}
+	return img, nil
+}
@@ -15,6 +15,8 @@ func main() {
 			println("Error loading last map:", err.Error())
 		}
 	}
+	// Offer to recover edits that were autosaved, but never saved
+	mapMaker.RecoverAutosave()
 
 	mapMaker.Run()
 }
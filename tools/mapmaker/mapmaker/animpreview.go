@@ -0,0 +1,127 @@
+package mapmaker
+
+import (
+	"fmt"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// animPreviewSize is the size of the animation preview in the advanced texture editor.
+const animPreviewSize = 80
+
+// stepPreview pauses the animation preview and moves it by delta frames, wrapping around.
+func (editor *TextureEditorState) stepPreview(delta int, animTime float64) {
+	frameCount := len(editor.advSelectedFrames)
+	if frameCount == 0 {
+		return
+	}
+	frame := beam.AnimationFrameAt(editor.previewElapsed, animTime, frameCount)
+	frame = ((frame+delta)%frameCount + frameCount) % frameCount
+	editor.previewPlaying = false
+	// Land halfway through the frame, so rounding can't show the one before it
+	editor.previewElapsed = (float64(frame) + 0.5) * animTime
+}
+
+// onionSkinFrames returns the frames either side of frame, wrapping around, to ghost over it in the preview.
+func onionSkinFrames(frame, frameCount int) []int {
+	switch {
+	case frameCount < 2:
+		return nil
+	case frameCount == 2:
+		return []int{1 - frame}
+	}
+	return []int{(frame + frameCount - 1) % frameCount, (frame + 1) % frameCount}
+}
+
+// renderAnimationPreview plays the frames picked in the advanced editor, with play/pause and step buttons.
+// It runs on its own clock, so pausing or stepping the preview doesn't affect the animations on the grid.
+// With onion skin on, the frames before and after the current one are ghosted behind it.
+func (m *MapMaker) renderAnimationPreview(x, y int) {
+	editor := m.uiState.textureEditor
+	frameCount := len(editor.advSelectedFrames)
+	animTime, err := strconv.ParseFloat(editor.advAnimationTimeStr, 64)
+	if err != nil || animTime <= 0 {
+		animTime = 0
+	}
+	if editor.previewPlaying && animTime > 0 {
+		editor.previewElapsed += float64(rl.GetFrameTime())
+	}
+	frame := beam.AnimationFrameAt(editor.previewElapsed, animTime, frameCount)
+
+	box := rl.Rectangle{X: float32(x + 25), Y: float32(y), Width: animPreviewSize, Height: animPreviewSize}
+	rl.DrawRectangleRec(box, rl.LightGray)
+	rl.DrawRectangleLinesEx(box, 1, rl.Gray)
+	drawFrame := func(i int, tint rl.Color) {
+		if i >= frameCount || editor.advSelectedFrames[i] == "" {
+			return
+		}
+		if texInfo, err := m.resources.GetTexture("default", editor.advSelectedFrames[i]); err == nil {
+			scale := min(box.Width/texInfo.Region.Width, box.Height/texInfo.Region.Height)
+			width, height := texInfo.Region.Width*scale, texInfo.Region.Height*scale
+			rl.DrawTexturePro(
+				texInfo.Texture,
+				texInfo.Region,
+				rl.Rectangle{X: box.X + (box.Width-width)/2, Y: box.Y + (box.Height-height)/2, Width: width, Height: height},
+				rl.Vector2{}, 0, tint,
+			)
+		}
+	}
+	if editor.previewOnionSkin {
+		for _, ghost := range onionSkinFrames(frame, frameCount) {
+			drawFrame(ghost, rl.Fade(rl.White, 0.3))
+		}
+	}
+	drawFrame(frame, rl.White)
+
+	frameLabel := "No frames"
+	if frameCount > 0 {
+		frameLabel = fmt.Sprintf("Frame %d / %d", frame+1, frameCount)
+	}
+	rl.DrawText(frameLabel, int32(x+65)-rl.MeasureText(frameLabel, 14)/2, int32(y+animPreviewSize+4), 14, rl.DarkGray)
+
+	// Onion skin toggle, to the left of the preview
+	onionBtn := rl.Rectangle{X: float32(x - 75), Y: float32(y), Width: 90, Height: 24}
+	onionColor := rl.LightGray
+	if editor.previewOnionSkin {
+		onionColor = rl.SkyBlue
+	}
+	rl.DrawRectangleRec(onionBtn, onionColor)
+	rl.DrawRectangleLinesEx(onionBtn, 1, rl.Gray)
+	rl.DrawText("Onion skin", int32(onionBtn.X)+(int32(onionBtn.Width)-rl.MeasureText("Onion skin", 14))/2, int32(onionBtn.Y+5), 14, rl.Black)
+
+	// Step back, play/pause and step forward
+	buttonY := float32(y + animPreviewSize + 22)
+	prevBtn := rl.Rectangle{X: float32(x), Y: buttonY, Width: 30, Height: 24}
+	playBtn := rl.Rectangle{X: float32(x + 35), Y: buttonY, Width: 60, Height: 24}
+	nextBtn := rl.Rectangle{X: float32(x + 100), Y: buttonY, Width: 30, Height: 24}
+	playLabel := "Play"
+	if editor.previewPlaying {
+		playLabel = "Pause"
+	}
+	for _, btn := range []struct {
+		rect  rl.Rectangle
+		label string
+	}{{prevBtn, "<"}, {playBtn, playLabel}, {nextBtn, ">"}} {
+		rl.DrawRectangleRec(btn.rect, rl.LightGray)
+		rl.DrawRectangleLinesEx(btn.rect, 1, rl.Gray)
+		labelWidth := rl.MeasureText(btn.label, 14)
+		rl.DrawText(btn.label, int32(btn.rect.X)+(int32(btn.rect.Width)-labelWidth)/2, int32(btn.rect.Y+5), 14, rl.Black)
+	}
+
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return
+	}
+	mousePos := rl.GetMousePosition()
+	switch {
+	case rl.CheckCollisionPointRec(mousePos, prevBtn):
+		editor.stepPreview(-1, animTime)
+	case rl.CheckCollisionPointRec(mousePos, nextBtn):
+		editor.stepPreview(1, animTime)
+	case rl.CheckCollisionPointRec(mousePos, playBtn):
+		editor.previewPlaying = !editor.previewPlaying
+	case rl.CheckCollisionPointRec(mousePos, onionBtn):
+		editor.previewOnionSkin = !editor.previewOnionSkin
+	}
+}
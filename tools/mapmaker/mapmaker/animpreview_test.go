@@ -0,0 +1,48 @@
+package mapmaker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+func TestStepPreview(t *testing.T) {
+	editor := &TextureEditorState{
+		advSelectedFrames: []string{"a", "b", "c"},
+		previewPlaying:    true,
+	}
+
+	editor.stepPreview(1, 0.5)
+	if editor.previewPlaying {
+		t.Error("Expected stepping to pause the preview")
+	}
+	if frame := beam.AnimationFrameAt(editor.previewElapsed, 0.5, 3); frame != 1 {
+		t.Errorf("Expected frame 1 after stepping forward, got %d", frame)
+	}
+
+	editor.stepPreview(-2, 0.5)
+	if frame := beam.AnimationFrameAt(editor.previewElapsed, 0.5, 3); frame != 2 {
+		t.Errorf("Expected stepping back past the first frame to wrap to 2, got %d", frame)
+	}
+}
+
+func TestOnionSkinFrames(t *testing.T) {
+	tests := []struct {
+		frame, frameCount int
+		expected          []int
+	}{
+		{frame: 0, frameCount: 0, expected: nil},
+		{frame: 0, frameCount: 1, expected: nil},
+		{frame: 0, frameCount: 2, expected: []int{1}},
+		{frame: 1, frameCount: 2, expected: []int{0}},
+		{frame: 0, frameCount: 4, expected: []int{3, 1}},
+		{frame: 2, frameCount: 4, expected: []int{1, 3}},
+		{frame: 3, frameCount: 4, expected: []int{2, 0}},
+	}
+	for _, tt := range tests {
+		if got := onionSkinFrames(tt.frame, tt.frameCount); !slices.Equal(got, tt.expected) {
+			t.Errorf("Expected %v either side of frame %d of %d, got %v", tt.expected, tt.frame, tt.frameCount, got)
+		}
+	}
+}
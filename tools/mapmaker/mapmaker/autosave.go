@@ -0,0 +1,276 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	DefaultAutosaveSeconds = 60
+	DefaultAutosaveKeep    = 10
+
+	autosavePrefix     = ".autosave-"
+	autosaveTimeLayout = "20060102-150405.000"
+)
+
+// configureAutosave reads the autosave interval and retention from the config.
+func (m *MapMaker) configureAutosave() {
+	config, err := readConfig()
+	if err != nil {
+		config = ConfigData{}
+	}
+	m.autosaveDir = "."
+	m.autosaveInterval = time.Duration(DefaultAutosaveSeconds) * time.Second
+	if config.AutosaveSeconds != 0 {
+		m.autosaveInterval = time.Duration(config.AutosaveSeconds) * time.Second
+	}
+	m.autosaveKeep = DefaultAutosaveKeep
+	if config.AutosaveKeep > 0 {
+		m.autosaveKeep = config.AutosaveKeep
+	}
+	m.lastAutosave = time.Now()
+}
+
+// handleAutosave autosaves the edited maps once the interval has passed.
+func (m *MapMaker) handleAutosave() {
+	if m.autosaveInterval <= 0 || time.Since(m.lastAutosave) < m.autosaveInterval {
+		return
+	}
+	m.lastAutosave = time.Now()
+	if err := m.autosave(m.lastAutosave); err != nil {
+		m.showToast("Autosave failed: "+err.Error(), ToastError)
+	}
+}
+
+// markSaved records the active map as saved, so it isn't autosaved until it's edited again.
+func (m *MapMaker) markSaved() {
+	m.storeDocument()
+	doc := m.documents[m.activeDocument]
+	doc.savedChanges = m.changes
+	doc.autosavedChanges = m.changes
+}
+
+// markDirty records a change to the active map, so it's autosaved and counts as unsaved.
+// Changes recorded for undo are counted already.
+func (m *MapMaker) markDirty() {
+	m.changes++
+}
+
+// autosave writes each map edited since it was last saved to its own autosave file, then prunes old autosaves.
+func (m *MapMaker) autosave(now time.Time) error {
+	m.storeDocument()
+	for i, doc := range m.documents {
+		if doc.changes == doc.autosavedChanges {
+			continue
+		}
+		saveData := m.documentSaveData(doc)
+		saveData.SourceFile = doc.currentFile
+		jsonData, err := json.MarshalIndent(saveData, "", "    ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(m.autosaveDir, autosaveName(now, i)), jsonData, 0644); err != nil {
+			return err
+		}
+		doc.autosavedChanges = doc.changes
+	}
+	return pruneAutosaves(m.autosaveDir, m.autosaveKeep)
+}
+
+// autosaveName is the file name for a tab's autosave, named by time so they sort oldest first.
+func autosaveName(now time.Time, tab int) string {
+	return fmt.Sprintf("%s%s-%d.json", autosavePrefix, now.UTC().Format(autosaveTimeLayout), tab)
+}
+
+// autosaveStamp reads the time an autosave was written, and the tab it was written from, from its file name.
+func autosaveStamp(name string) (written time.Time, tab int, ok bool) {
+	stamp, ok := strings.CutPrefix(strings.TrimSuffix(filepath.Base(name), ".json"), autosavePrefix)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	i := strings.LastIndex(stamp, "-")
+	if i < 0 {
+		return time.Time{}, 0, false
+	}
+	written, err := time.Parse(autosaveTimeLayout, stamp[:i])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	tab, err = strconv.Atoi(stamp[i+1:])
+	return written, tab, err == nil
+}
+
+// listAutosaves returns the autosave files in dir, oldest first, then by tab.
+func listAutosaves(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var autosaves []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if _, _, ok := autosaveStamp(entry.Name()); ok {
+			autosaves = append(autosaves, entry.Name())
+		}
+	}
+	slices.SortFunc(autosaves, func(a, b string) int {
+		aTime, aTab, _ := autosaveStamp(a)
+		bTime, bTab, _ := autosaveStamp(b)
+		if c := aTime.Compare(bTime); c != 0 {
+			return c
+		}
+		return aTab - bTab
+	})
+	return autosaves, nil
+}
+
+// pruneAutosaves deletes all but the newest keep autosaves in dir, or all of them if keep is 0.
+func pruneAutosaves(dir string, keep int) error {
+	autosaves, err := listAutosaves(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range autosaves[:max(0, len(autosaves)-keep)] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discardAutosaves deletes every autosave in dir written at or before through.
+func discardAutosaves(dir string, through time.Time) error {
+	autosaves, err := listAutosaves(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range autosaves {
+		if written, _, _ := autosaveStamp(name); written.After(through) {
+			break
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recoverableAutosave is an autosave with edits that were never saved, and the map it was taken from.
+type recoverableAutosave struct {
+	path   string
+	source string
+}
+
+// recoverableAutosaves finds the newest autosave of each map, by the file it came from or the tab of an untitled map,
+// and returns those worth recovering: the map they came from was never saved, is gone,
+// or was last saved before the autosave was written. Tabs are autosaved only once edited,
+// so each map's newest autosave can be from a different time; written is the newest of them.
+func recoverableAutosaves(dir string) (recoverable []recoverableAutosave, written time.Time) {
+	autosaves, err := listAutosaves(dir)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	seenSources := make(map[string]bool)
+	seenTabs := make(map[int]bool)
+	for _, name := range slices.Backward(autosaves) {
+		stamp, tab, _ := autosaveStamp(name)
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var saveData struct {
+			SourceFile string `json:"sourceFile"`
+		}
+		if err := json.Unmarshal(data, &saveData); err != nil {
+			continue
+		}
+
+		// An untitled map is superseded by anything newer from its tab, which includes it once it's saved
+		newer := seenTabs[tab]
+		seenTabs[tab] = true
+		if saveData.SourceFile != "" {
+			newer = seenSources[saveData.SourceFile]
+			seenSources[saveData.SourceFile] = true
+		}
+		if newer {
+			continue
+		}
+		if saveData.SourceFile != "" {
+			if info, err := os.Stat(saveData.SourceFile); err == nil && !info.ModTime().Before(stamp) {
+				continue
+			}
+		}
+		recoverable = append(recoverable, recoverableAutosave{path: path, source: saveData.SourceFile})
+		if stamp.After(written) {
+			written = stamp
+		}
+	}
+	slices.Reverse(recoverable)
+	return recoverable, written
+}
+
+// RecoverAutosave offers to reopen the newest autosave of each map with edits that were never saved, on startup.
+// Recovering opens each in place of its map if that's the one open, or in its own tab.
+// Declining deletes the autosaves that were offered and every older one, so none are offered again.
+func (m *MapMaker) RecoverAutosave() {
+	recoverable, written := recoverableAutosaves(m.autosaveDir)
+	if len(recoverable) == 0 {
+		return
+	}
+	labels := make([]string, len(recoverable))
+	for i, autosave := range recoverable {
+		labels[i] = fileLabel(autosave.source)
+	}
+	message := fmt.Sprintf("Recover %s from %s?", strings.Join(labels, ", "), written.Local().Format("Jan 2 15:04"))
+	if !openConfirmationDialog("Unsaved Changes", message, "Discard", "Recover", rl.DarkGreen) {
+		if err := discardAutosaves(m.autosaveDir, written); err != nil {
+			m.showToast("Error removing autosave: "+err.Error(), ToastError)
+		}
+		return
+	}
+
+	recovered := m.openAutosaves(recoverable)
+	if recovered == 0 {
+		return
+	}
+	m.updateWindowTitle()
+	if recovered == 1 {
+		m.showToast("Map recovered from autosave", ToastSuccess)
+	} else {
+		m.showToast(fmt.Sprintf("%d maps recovered from autosave", recovered), ToastSuccess)
+	}
+}
+
+// openAutosaves opens each autosave in place of its map if that's the one open, or in its own tab,
+// and returns how many opened. Each saves back to the map it came from, and its edits count as unsaved.
+func (m *MapMaker) openAutosaves(recoverable []recoverableAutosave) int {
+	recovered := 0
+	for _, autosave := range recoverable {
+		var err error
+		if autosave.source != "" && autosave.source == m.currentFile {
+			err = m.LoadMap(autosave.path)
+		} else {
+			err = m.openMap(autosave.path)
+		}
+		if err != nil {
+			m.showToast("Error recovering map: "+err.Error(), ToastError)
+			continue
+		}
+		m.currentFile = autosave.source
+		m.markDirty()
+		m.storeDocument()
+		recovered++
+	}
+	return recovered
+}
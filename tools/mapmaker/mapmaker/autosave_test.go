@@ -0,0 +1,275 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ztkent/beam"
+)
+
+// writeTestAutosave writes an autosave of a tab taken from source at the given time, and returns its path.
+func writeTestAutosave(t *testing.T, dir string, written time.Time, tab int, source string) string {
+	t.Helper()
+	data, err := json.Marshal(SaveData{SourceFile: source})
+	if err != nil {
+		t.Fatalf("Expected no error marshaling, got %v", err)
+	}
+	path := filepath.Join(dir, autosaveName(written, tab))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Expected no error writing %s, got %v", path, err)
+	}
+	return path
+}
+
+// TestAutosaveName checks the time and tab written into an autosave's name read back.
+func TestAutosaveName(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 890_000_000, time.UTC)
+	name := autosaveName(now, 2)
+	if name != ".autosave-20260304-050607.890-2.json" {
+		t.Errorf("Expected .autosave-20260304-050607.890-2.json, got %s", name)
+	}
+	if got, tab, ok := autosaveStamp(name); !ok || !got.Equal(now) || tab != 2 {
+		t.Errorf("Expected %v from tab 2, got %v from tab %d (%v)", now, got, tab, ok)
+	}
+	if _, _, ok := autosaveStamp("level1.json"); ok {
+		t.Errorf("Expected a map file not to be read as an autosave")
+	}
+}
+
+// TestPruneAutosaves checks only the newest autosaves are kept, and other files are left alone.
+func TestPruneAutosaves(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := range 5 {
+		paths = append(paths, writeTestAutosave(t, dir, start.Add(time.Duration(i)*time.Minute), 0, ""))
+	}
+	for _, other := range []string{"level1.json", ".autosave-notes.json"} {
+		if err := os.WriteFile(filepath.Join(dir, other), []byte("{}"), 0644); err != nil {
+			t.Fatalf("Expected no error writing %s, got %v", other, err)
+		}
+	}
+
+	if err := pruneAutosaves(dir, 2); err != nil {
+		t.Fatalf("Expected no error pruning, got %v", err)
+	}
+	remaining, _ := listAutosaves(dir)
+	expected := []string{filepath.Base(paths[3]), filepath.Base(paths[4])}
+	if !slices.Equal(remaining, expected) {
+		t.Errorf("Expected %v to be kept, got %v", expected, remaining)
+	}
+	for _, other := range []string{"level1.json", ".autosave-notes.json"} {
+		if _, err := os.Stat(filepath.Join(dir, other)); err != nil {
+			t.Errorf("Expected %s to be left alone, got %v", other, err)
+		}
+	}
+
+	if err := pruneAutosaves(dir, 0); err != nil {
+		t.Fatalf("Expected no error pruning, got %v", err)
+	}
+	if remaining, _ := listAutosaves(dir); len(remaining) != 0 {
+		t.Errorf("Expected no autosaves left, got %v", remaining)
+	}
+}
+
+// TestRecoverableAutosaves checks an autosave is only offered when it's newer than the map it came from.
+func TestRecoverableAutosaves(t *testing.T) {
+	written := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if recoverable, _ := recoverableAutosaves(t.TempDir()); len(recoverable) != 0 {
+		t.Errorf("Expected nothing to recover without autosaves")
+	}
+
+	// An untitled map was never saved, so any autosave has unsaved edits
+	dir := t.TempDir()
+	writeTestAutosave(t, dir, written, 0, "")
+	if recoverable, _ := recoverableAutosaves(dir); len(recoverable) != 1 || recoverable[0].source != "" {
+		t.Errorf("Expected the untitled autosave to be offered, got %v", recoverable)
+	}
+
+	tests := []struct {
+		name    string
+		savedAt time.Duration // When the map was last saved, relative to the autosave
+		missing bool
+		offered bool
+	}{
+		{name: "saved before the autosave", savedAt: -time.Minute, offered: true},
+		{name: "saved after the autosave", savedAt: time.Minute, offered: false},
+		{name: "saved at the same time", savedAt: 0, offered: false},
+		{name: "map was deleted", missing: true, offered: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			source := filepath.Join(dir, "level1.json")
+			if !tt.missing {
+				if err := os.WriteFile(source, []byte("{}"), 0644); err != nil {
+					t.Fatalf("Expected no error writing the map, got %v", err)
+				}
+				savedAt := written.Add(tt.savedAt)
+				if err := os.Chtimes(source, savedAt, savedAt); err != nil {
+					t.Fatalf("Expected no error setting the map's time, got %v", err)
+				}
+			}
+			autosave := writeTestAutosave(t, dir, written, 0, source)
+
+			recoverable, _ := recoverableAutosaves(dir)
+			if offered := len(recoverable) == 1; offered != tt.offered {
+				t.Fatalf("Expected offered to be %v, got %v", tt.offered, recoverable)
+			}
+			if tt.offered && recoverable[0] != (recoverableAutosave{path: autosave, source: source}) {
+				t.Errorf("Expected %s from %s, got %+v", autosave, source, recoverable[0])
+			}
+		})
+	}
+
+	// Only the newest autosave from a tab is considered, which includes an untitled map once it's saved
+	dir = t.TempDir()
+	source := filepath.Join(dir, "level1.json")
+	writeTestAutosave(t, dir, written, 0, "")
+	if err := os.WriteFile(source, []byte("{}"), 0644); err != nil {
+		t.Fatalf("Expected no error writing the map, got %v", err)
+	}
+	os.Chtimes(source, written.Add(2*time.Minute), written.Add(2*time.Minute))
+	writeTestAutosave(t, dir, written.Add(time.Minute), 0, source)
+	if recoverable, _ := recoverableAutosaves(dir); len(recoverable) != 0 {
+		t.Errorf("Expected the newest autosave, which is older than its map, not to be offered, got %v", recoverable)
+	}
+}
+
+// TestRecoverableAutosavesTabs checks every tab's newest autosave is offered, oldest first and then in tab order,
+// with tab 10 sorting after tab 9 rather than by name.
+func TestRecoverableAutosavesTabs(t *testing.T) {
+	written := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	old := writeTestAutosave(t, dir, written.Add(-time.Minute), 0, "old.json")
+	nine := writeTestAutosave(t, dir, written, 9, "nine.json")
+	ten := writeTestAutosave(t, dir, written, 10, "ten.json")
+
+	autosaves, _ := listAutosaves(dir)
+	if len(autosaves) != 3 || autosaves[2] != filepath.Base(ten) {
+		t.Errorf("Expected tab 10's autosave last, got %v", autosaves)
+	}
+
+	recoverable, got := recoverableAutosaves(dir)
+	expected := []recoverableAutosave{{path: old, source: "old.json"}, {path: nine, source: "nine.json"}, {path: ten, source: "ten.json"}}
+	if !slices.Equal(recoverable, expected) || !got.Equal(written) {
+		t.Errorf("Expected every tab, the newest written at %v, got %v at %v", written, recoverable, got)
+	}
+}
+
+// TestRecoverableAutosavesPerMap checks each map's newest autosave is offered, even when another map was autosaved since.
+func TestRecoverableAutosavesPerMap(t *testing.T) {
+	written := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeTestAutosave(t, dir, written, 0, "a.json")
+	a := writeTestAutosave(t, dir, written.Add(time.Minute), 0, "a.json")
+	writeTestAutosave(t, dir, written.Add(time.Minute), 1, "")
+	untitled := writeTestAutosave(t, dir, written.Add(2*time.Minute), 1, "")
+	b := writeTestAutosave(t, dir, written.Add(3*time.Minute), 2, "b.json")
+
+	recoverable, got := recoverableAutosaves(dir)
+	expected := []recoverableAutosave{{path: a, source: "a.json"}, {path: untitled}, {path: b, source: "b.json"}}
+	if !slices.Equal(recoverable, expected) || !got.Equal(written.Add(3*time.Minute)) {
+		t.Errorf("Expected the newest autosave of each map, got %v at %v", recoverable, got)
+	}
+}
+
+// TestDiscardAutosaves checks declining recovery deletes the offered autosaves and every older one, but nothing newer.
+func TestDiscardAutosaves(t *testing.T) {
+	written := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeTestAutosave(t, dir, written.Add(-time.Minute), 0, "a.json")
+	writeTestAutosave(t, dir, written, 0, "a.json")
+	writeTestAutosave(t, dir, written, 1, "")
+	newer := writeTestAutosave(t, dir, written.Add(time.Minute), 0, "b.json")
+
+	if err := discardAutosaves(dir, written); err != nil {
+		t.Fatalf("Expected no error discarding, got %v", err)
+	}
+	autosaves, _ := listAutosaves(dir)
+	if len(autosaves) != 1 || autosaves[0] != filepath.Base(newer) {
+		t.Errorf("Expected only %s to be left, got %v", filepath.Base(newer), autosaves)
+	}
+	if recoverable, _ := recoverableAutosaves(dir); len(recoverable) != 1 || recoverable[0].path != newer {
+		t.Errorf("Expected no discarded autosave to be offered again, got %v", recoverable)
+	}
+}
+
+// TestRecoverAutosaveTabs checks recovering two edited tabs opens both, each still counting as unsaved
+// and saving back to its own map.
+func TestRecoverAutosaveTabs(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.autosaveDir = t.TempDir()
+	m.storeDocument()
+	written := time.Now()
+	for i, source := range []string{"", filepath.Join(m.autosaveDir, "level2.json")} {
+		data, err := json.Marshal(SaveData{SourceFile: source, TileGrid: m.tileGrid})
+		if err != nil {
+			t.Fatalf("Expected no error marshaling, got %v", err)
+		}
+		path := filepath.Join(m.autosaveDir, autosaveName(written, i))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Expected no error writing %s, got %v", path, err)
+		}
+	}
+
+	recoverable, _ := recoverableAutosaves(m.autosaveDir)
+	if len(recoverable) != 2 {
+		t.Fatalf("Expected both tabs to be offered, got %v", recoverable)
+	}
+	if recovered := m.openAutosaves(recoverable); recovered != 2 {
+		t.Fatalf("Expected both tabs to be recovered, got %d", recovered)
+	}
+
+	if len(m.documents) != 2 {
+		t.Fatalf("Expected the recovered untitled map to keep its tab, got %d tabs", len(m.documents))
+	}
+	for i, doc := range m.documents {
+		if doc.currentFile != recoverable[i].source || doc.changes == doc.savedChanges {
+			t.Errorf("Expected tab %d to be unsaved edits of %q, got %q", i, recoverable[i].source, doc.currentFile)
+		}
+	}
+}
+
+// TestSaveTracksEdits checks the change count the autosave compares against moves with every edit, undo and redo.
+func TestSaveTracksEdits(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.markSaved()
+	doc := m.documents[m.activeDocument]
+	if doc.savedChanges != m.changes {
+		t.Fatalf("Expected a fresh map to count as saved")
+	}
+
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+	if m.changes == doc.savedChanges {
+		t.Errorf("Expected a paint to count as an unsaved edit")
+	}
+	m.markSaved()
+	m.undo()
+	if m.changes == doc.savedChanges {
+		t.Errorf("Expected an undo after saving to count as an unsaved edit")
+	}
+}
+
+// TestSaveTracksEditsOffTheUndoStack checks edits that can't be undone still count as unsaved, so they're autosaved.
+func TestSaveTracksEditsOffTheUndoStack(t *testing.T) {
+	edits := map[string]func(m *MapMaker){
+		"duplicate NPC": func(m *MapMaker) { m.duplicateNPC(m.tileGrid.NPCs[0]) },
+		"edit NPC":      func(m *MapMaker) { m.saveEditedNPC(&beam.NPC{Data: beam.NPCData{Name: "Troll"}}) },
+		"remap":         func(m *MapMaker) { m.renameTextureReferences(map[string]string{"grass": "dirt"}) },
+	}
+	for name, edit := range edits {
+		m := newTestMapMaker(3, 3)
+		m.tileGrid.NPCs = beam.NPCs{{Data: beam.NPCData{ID: "goblin", Name: "Goblin"}}}
+		m.markSaved()
+		edit(m)
+		if m.changes == m.documents[m.activeDocument].savedChanges {
+			t.Errorf("Expected %s to count as an unsaved edit", name)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package mapmaker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ztkent/beam"
+)
+
+// Neighbor bits used to build an autotile mask.
+// A 16 tile set only uses the edges, a 47 tile blob set also uses the corners.
+const (
+	AutotileNorth = 1 << iota
+	AutotileEast
+	AutotileSouth
+	AutotileWest
+	AutotileNorthEast
+	AutotileSouthEast
+	AutotileSouthWest
+	AutotileNorthWest
+)
+
+const autotileEdges = AutotileNorth | AutotileEast | AutotileSouth | AutotileWest
+
+// AutotileSet maps a neighbor bitmask to the sprite drawn for a wall with those neighbors.
+// Sprites are found by name convention, <prefix>_<mask>, eg: wall_0 through wall_15.
+type AutotileSet struct {
+	Prefix  string
+	Blob    bool // Uses corner bits, a 47 tile set
+	Sprites map[int]string
+}
+
+// NewAutotileSet builds a set from every texture name matching <prefix>_<mask>.
+// A set is treated as a blob set if any of its masks include a corner.
+func NewAutotileSet(prefix string, names []string) (*AutotileSet, error) {
+	set := &AutotileSet{Prefix: prefix, Sprites: make(map[int]string)}
+	for _, name := range names {
+		namePrefix, mask, ok := parseAutotileName(name)
+		if !ok || namePrefix != prefix {
+			continue
+		}
+		set.Sprites[mask] = name
+		if mask&^autotileEdges != 0 {
+			set.Blob = true
+		}
+	}
+	if len(set.Sprites) == 0 {
+		return nil, fmt.Errorf("no textures named %s_<mask>", prefix)
+	}
+	return set, nil
+}
+
+// parseAutotileName splits a texture name like wall_5 into its prefix and mask.
+func parseAutotileName(name string) (string, int, bool) {
+	idx := strings.LastIndex(name, "_")
+	if idx <= 0 {
+		return "", 0, false
+	}
+	mask, err := strconv.Atoi(name[idx+1:])
+	if err != nil || mask < 0 || mask > 0xFF {
+		return "", 0, false
+	}
+	return name[:idx], mask, true
+}
+
+// SpriteFor returns the sprite for a neighbor mask.
+// Corners are dropped for 16 tile sets, missing blob sprites fall back to the edge only sprite.
+func (s *AutotileSet) SpriteFor(mask int) string {
+	mask = reduceAutotileMask(mask)
+	if !s.Blob {
+		mask &= autotileEdges
+	}
+	if name, ok := s.Sprites[mask]; ok {
+		return name
+	}
+	if name, ok := s.Sprites[mask&autotileEdges]; ok {
+		return name
+	}
+	if name, ok := s.Sprites[0]; ok {
+		return name
+	}
+	return s.Prefix + "_0"
+}
+
+// Contains reports if a texture name belongs to the set.
+func (s *AutotileSet) Contains(name string) bool {
+	prefix, _, ok := parseAutotileName(name)
+	return ok && prefix == s.Prefix
+}
+
+// reduceAutotileMask drops corners that don't have both adjacent edges set.
+// This is what brings the 256 possible masks down to the 47 tiles of a blob set.
+func reduceAutotileMask(mask int) int {
+	corners := []struct{ corner, edges int }{
+		{AutotileNorthEast, AutotileNorth | AutotileEast},
+		{AutotileSouthEast, AutotileSouth | AutotileEast},
+		{AutotileSouthWest, AutotileSouth | AutotileWest},
+		{AutotileNorthWest, AutotileNorth | AutotileWest},
+	}
+	for _, c := range corners {
+		if mask&c.edges != c.edges {
+			mask &^= c.corner
+		}
+	}
+	return mask
+}
+
+// autotileMask builds the neighbor mask for the tile at x, y.
+func autotileMask(x, y int, isWall func(x, y int) bool) int {
+	neighbors := []struct{ dx, dy, bit int }{
+		{0, -1, AutotileNorth},
+		{1, 0, AutotileEast},
+		{0, 1, AutotileSouth},
+		{-1, 0, AutotileWest},
+		{1, -1, AutotileNorthEast},
+		{1, 1, AutotileSouthEast},
+		{-1, 1, AutotileSouthWest},
+		{-1, -1, AutotileNorthWest},
+	}
+	mask := 0
+	for _, n := range neighbors {
+		if isWall(x+n.dx, y+n.dy) {
+			mask |= n.bit
+		}
+	}
+	return reduceAutotileMask(mask)
+}
+
+// toggleAutotile turns autotile mode on, using the set the active texture belongs to, or turns it off.
+func (m *MapMaker) toggleAutotile() {
+	if m.uiState.autotileSet != nil {
+		m.uiState.autotileSet = nil
+		m.showToast("Autotile disabled", ToastInfo)
+		return
+	}
+	if m.uiState.activeTexture == nil {
+		m.showToast("Select a texture from an autotile set, eg: wall_0", ToastError)
+		return
+	}
+	prefix, _, ok := parseAutotileName(m.uiState.activeTexture.Name)
+	if !ok {
+		m.showToast(m.uiState.activeTexture.Name+" isn't part of an autotile set", ToastError)
+		return
+	}
+
+	textures, err := m.resources.GetAllTextures("default", false)
+	if err != nil {
+		m.showToast("Error loading textures: "+err.Error(), ToastError)
+		return
+	}
+	names := make([]string, 0, len(textures))
+	for _, tex := range textures {
+		names = append(names, tex.Name)
+	}
+	set, err := NewAutotileSet(prefix, names)
+	if err != nil {
+		m.showToast(err.Error(), ToastError)
+		return
+	}
+	m.uiState.autotileSet = set
+	m.showToast(fmt.Sprintf("Autotile enabled: %s (%d tiles)", prefix, len(set.Sprites)), ToastSuccess)
+}
+
+// autotileAffected adds the neighbors of each position, since their sprites can change
+// when a tile is painted or erased. Returns positions unchanged if autotile is off.
+func (m *MapMaker) autotileAffected(positions beam.Positions) beam.Positions {
+	if m.uiState.autotileSet == nil {
+		return positions
+	}
+	seen := make(map[beam.Position]bool, len(positions)*9)
+	affected := make(beam.Positions, 0, len(positions)*9)
+	for _, pos := range positions {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				p := beam.Position{X: pos.X + dx, Y: pos.Y + dy}
+				if p.X < 0 || p.X >= m.tileGrid.Width || p.Y < 0 || p.Y >= m.tileGrid.Height || seen[p] {
+					continue
+				}
+				seen[p] = true
+				affected = append(affected, p)
+			}
+		}
+	}
+	return affected
+}
+
+// paintAutotile turns each position into a wall, then picks the sprites for it and its neighbors.
+func (m *MapMaker) paintAutotile(positions beam.Positions) {
+	set := m.uiState.autotileSet
+	affected := m.autotileAffected(positions)
+	m.recordTileChanges(affected, func() {
+		for _, pos := range positions {
+			tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+			tile.Type = beam.WallTile
+			if m.autotileLayer(*tile) < 0 {
+				tile.Textures = append(tile.Textures, beam.NewSimpleTileTexture(set.SpriteFor(0)))
+			}
+		}
+		m.refreshAutotiles(affected)
+	})
+}
+
+// refreshAutotiles re-evaluates the autotile sprite for each wall in positions.
+// Should be called inside recordTileChanges, so the updates are part of the same undo step.
+func (m *MapMaker) refreshAutotiles(positions beam.Positions) {
+	set := m.uiState.autotileSet
+	if set == nil {
+		return
+	}
+	isWall := func(x, y int) bool {
+		if x < 0 || x >= m.tileGrid.Width || y < 0 || y >= m.tileGrid.Height {
+			return false
+		}
+		return m.tileGrid.Tiles[y][x].Type == beam.WallTile
+	}
+	for _, pos := range positions {
+		tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+		layer := m.autotileLayer(*tile)
+		if tile.Type != beam.WallTile || layer < 0 {
+			continue
+		}
+		tile.Textures[layer].Frames[0].Name = set.SpriteFor(autotileMask(pos.X, pos.Y, isWall))
+	}
+}
+
+// autotileLayer returns the index of the tile's texture layer from the active autotile set, or -1.
+func (m *MapMaker) autotileLayer(tile beam.Tile) int {
+	for i, tex := range tile.Textures {
+		if tex != nil && !tex.IsAnimated && len(tex.Frames) > 0 && m.uiState.autotileSet.Contains(tex.Frames[0].Name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package mapmaker
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestAutotileSpriteFor checks the sprite picked for several neighbor configurations.
+func TestAutotileSpriteFor(t *testing.T) {
+	edgeNames := make([]string, 0, 16)
+	for mask := 0; mask < 16; mask++ {
+		edgeNames = append(edgeNames, fmt.Sprintf("wall_%d", mask))
+	}
+	edgeSet, err := NewAutotileSet("wall", append(edgeNames, "floor_3", "wall_top"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edgeSet.Blob || len(edgeSet.Sprites) != 16 {
+		t.Fatalf("Expected a 16 tile set, got %d sprites (blob: %v)", len(edgeSet.Sprites), edgeSet.Blob)
+	}
+
+	blobSet, err := NewAutotileSet("wall", append(edgeNames, "wall_19", "wall_255"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blobSet.Blob {
+		t.Fatal("Expected a set with corner masks to be a blob set")
+	}
+
+	// Walls are marked with #, the tile being checked is in the center
+	tests := []struct {
+		name   string
+		layout []string
+		set    *AutotileSet
+		want   string
+	}{
+		{"isolated", []string{"...", ".#.", "..."}, edgeSet, "wall_0"},
+		{"north", []string{".#.", ".#.", "..."}, edgeSet, "wall_1"},
+		{"horizontal", []string{"...", "###", "..."}, edgeSet, "wall_10"},
+		{"corner ignored by 16 tile set", []string{".##", ".##", "..."}, edgeSet, "wall_3"},
+		{"surrounded", []string{"###", "###", "###"}, edgeSet, "wall_15"},
+		{"blob corner", []string{".##", ".##", "..."}, blobSet, "wall_19"},
+		{"blob corner without edges", []string{"..#", ".#.", "..."}, blobSet, "wall_0"},
+		{"blob surrounded", []string{"###", "###", "###"}, blobSet, "wall_255"},
+		{"blob fallback to edges", []string{".##", ".##", ".#."}, blobSet, "wall_7"},
+	}
+	for _, tt := range tests {
+		isWall := func(x, y int) bool {
+			return y >= 0 && y < len(tt.layout) && x >= 0 && x < len(tt.layout[y]) && tt.layout[y][x] == '#'
+		}
+		if got := tt.set.SpriteFor(autotileMask(1, 1, isWall)); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestNewAutotileSetEmpty checks a prefix with no matching textures is rejected.
+func TestNewAutotileSetEmpty(t *testing.T) {
+	if _, err := NewAutotileSet("wall", []string{"floor_0", "wall", "wallpaper_1"}); err == nil {
+		t.Error("Expected an error for a set with no sprites")
+	}
+}
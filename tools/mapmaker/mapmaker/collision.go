@@ -0,0 +1,57 @@
+package mapmaker
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// collisionLabel describes a tile's collision, and whether it comes from its type or an override.
+func collisionLabel(tile beam.Tile) string {
+	switch {
+	case tile.Walkable == nil && tile.IsPassable():
+		return "Auto (walkable)"
+	case tile.Walkable == nil:
+		return "Auto (blocked)"
+	case *tile.Walkable:
+		return "Walkable"
+	default:
+		return "Blocked"
+	}
+}
+
+// cycleCollision steps the tiles' collision override from auto, to blocked, to walkable, and back to auto,
+// as one undo step. Every tile is set to the step after the first tile's, so mixed selections end up the same.
+func (m *MapMaker) cycleCollision(positions beam.Positions) {
+	if len(positions) == 0 {
+		return
+	}
+	first := m.tileGrid.Tiles[positions[0].Y][positions[0].X].Walkable
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+			switch {
+			case first == nil:
+				tile.SetWalkable(false)
+			case !*first:
+				tile.SetWalkable(true)
+			default:
+				tile.ClearWalkable()
+			}
+		}
+	})
+}
+
+// renderCollisionOverride marks tiles with a collision override when gridlines are shown,
+// a red cross for blocked and a green dot for walkable.
+func (m *MapMaker) renderCollisionOverride(pos rl.Rectangle, tile beam.Tile) {
+	if !m.uiState.showGridlines || tile.Walkable == nil {
+		return
+	}
+	inset := pos.Width / 4
+	if !*tile.Walkable {
+		rl.DrawLineEx(rl.Vector2{X: pos.X + inset, Y: pos.Y + inset}, rl.Vector2{X: pos.X + pos.Width - inset, Y: pos.Y + pos.Height - inset}, 2, rl.Red)
+		rl.DrawLineEx(rl.Vector2{X: pos.X + pos.Width - inset, Y: pos.Y + inset}, rl.Vector2{X: pos.X + inset, Y: pos.Y + pos.Height - inset}, 2, rl.Red)
+		return
+	}
+	rl.DrawCircleV(rl.Vector2{X: pos.X + pos.Width/2, Y: pos.Y + pos.Height/2}, inset/2, rl.Green)
+}
@@ -0,0 +1,38 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestCycleCollision steps a selection's collision override through each mode, and undoes it.
+func TestCycleCollision(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.tileGrid.Tiles[0][1].Type = beam.WallTile
+	positions := beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}}
+
+	if got := collisionLabel(m.tileGrid.Tiles[0][1]); got != "Auto (blocked)" {
+		t.Errorf("Expected a wall to be blocked by its type, got %q", got)
+	}
+	for _, want := range []string{"Blocked", "Walkable", "Auto (walkable)"} {
+		m.cycleCollision(positions)
+		if got := collisionLabel(m.tileGrid.Tiles[0][0]); got != want {
+			t.Errorf("Expected the floor to step to %q, got %q", want, got)
+		}
+	}
+	if got := collisionLabel(m.tileGrid.Tiles[0][1]); got != "Auto (blocked)" {
+		t.Errorf("Expected the wall back to its type's collision, got %q", got)
+	}
+
+	// Undo steps back through the overrides
+	if !m.history.Undo() {
+		t.Fatal("Expected the collision change to be undoable")
+	}
+	if got := collisionLabel(m.tileGrid.Tiles[0][1]); got != "Walkable" {
+		t.Errorf("Expected undo to restore the walkable override, got %q", got)
+	}
+	if !m.tileGrid.IsWalkable(1, 0) {
+		t.Error("Expected the wall with a walkable override to be walkable")
+	}
+}
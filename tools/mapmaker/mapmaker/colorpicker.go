@@ -0,0 +1,244 @@
+package mapmaker
+
+import (
+	"math"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	colorPickerWidth  = 240
+	colorPickerHeight = 230
+)
+
+// ColorPicker is a popup for picking a tint with HSV and alpha sliders.
+// It writes the color back into an editor's RGBA text fields as the sliders move.
+type ColorPicker struct {
+	visible    bool
+	x, y       int
+	hue        float64 // 0-360
+	saturation float64 // 0-1
+	value      float64 // 0-1
+	alpha      float64 // 0-255
+	dragging   string
+
+	// The editor fields the color is written back to
+	r, g, b, a *string
+}
+
+// Open shows the picker at x, y, starting from the color in the given RGBA fields.
+func (p *ColorPicker) Open(x, y int, r, g, b, a *string) {
+	p.r, p.g, p.b, p.a = r, g, b, a
+	p.hue, p.saturation, p.value = rgbToHSV(parseChannel(*r), parseChannel(*g), parseChannel(*b))
+	p.alpha = float64(parseChannel(*a))
+	p.x = max(0, min(x, rl.GetScreenWidth()-colorPickerWidth))
+	p.y = max(0, min(y, rl.GetScreenHeight()-colorPickerHeight))
+	p.dragging = ""
+	p.visible = true
+}
+
+func (p *ColorPicker) Close() {
+	p.visible = false
+	p.dragging = ""
+}
+
+// Color is the picker's current color.
+func (p *ColorPicker) Color() rl.Color {
+	r, g, b := hsvToRGB(p.hue, p.saturation, p.value)
+	return rl.Color{R: r, G: g, B: b, A: uint8(p.alpha)}
+}
+
+// Draw renders the picker, and handles its sliders and Done button.
+func (p *ColorPicker) Draw() {
+	if !p.visible {
+		return
+	}
+
+	bounds := rl.Rectangle{X: float32(p.x), Y: float32(p.y), Width: colorPickerWidth, Height: colorPickerHeight}
+	rl.DrawRectangleRec(bounds, rl.RayWhite)
+	rl.DrawRectangleLinesEx(bounds, 1, rl.Gray)
+	rl.DrawText("Tint", int32(p.x+10), int32(p.y+10), 16, rl.Black)
+
+	// Live preview, over a checkerboard so the alpha is visible
+	swatch := rl.Rectangle{X: float32(p.x + colorPickerWidth - 70), Y: float32(p.y + 8), Width: 60, Height: 40}
+	drawColorSwatch(swatch, p.Color())
+
+	changed := false
+	sliderX := float32(p.x + 40)
+	sliderWidth := float32(colorPickerWidth - 90)
+	sliders := []struct {
+		label    string
+		value    *float64
+		maxValue float64
+	}{
+		{"H", &p.hue, 360},
+		{"S", &p.saturation, 1},
+		{"V", &p.value, 1},
+		{"A", &p.alpha, 255},
+	}
+	for i, s := range sliders {
+		y := float32(p.y + 60 + i*32)
+		rl.DrawText(s.label, int32(p.x+15), int32(y), 16, rl.Black)
+		if drawSlider(s.label, rl.Rectangle{X: sliderX, Y: y, Width: sliderWidth, Height: 16}, s.value, 0, s.maxValue, &p.dragging) {
+			changed = true
+		}
+		display := *s.value
+		if s.maxValue == 1 {
+			display *= 100
+		}
+		rl.DrawText(strconv.Itoa(int(math.Round(display))), int32(sliderX+sliderWidth+8), int32(y), 14, rl.DarkGray)
+	}
+
+	if changed {
+		color := p.Color()
+		*p.r = strconv.Itoa(int(color.R))
+		*p.g = strconv.Itoa(int(color.G))
+		*p.b = strconv.Itoa(int(color.B))
+		*p.a = strconv.Itoa(int(color.A))
+	}
+
+	doneBtn := rl.Rectangle{X: float32(p.x + colorPickerWidth - 80), Y: float32(p.y + colorPickerHeight - 38), Width: 70, Height: 28}
+	rl.DrawRectangleRec(doneBtn, rl.LightGray)
+	rl.DrawText("Done", int32(doneBtn.X+16), int32(doneBtn.Y+6), 16, rl.Black)
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), doneBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		p.Close()
+	}
+}
+
+// drawSlider draws a horizontal slider for value, between minValue and maxValue.
+// dragging holds the id of the slider being dragged, so a drag keeps working after the mouse leaves the track.
+// Returns true if the value changed.
+func drawSlider(id string, bounds rl.Rectangle, value *float64, minValue, maxValue float64, dragging *string) bool {
+	mousePos := rl.GetMousePosition()
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && rl.CheckCollisionPointRec(mousePos, bounds) {
+		*dragging = id
+	}
+	if !rl.IsMouseButtonDown(rl.MouseLeftButton) && *dragging == id {
+		*dragging = ""
+	}
+
+	changed := false
+	if *dragging == id {
+		t := float64((mousePos.X - bounds.X) / bounds.Width)
+		newValue := minValue + math.Max(0, math.Min(1, t))*(maxValue-minValue)
+		if newValue != *value {
+			*value = newValue
+			changed = true
+		}
+	}
+
+	t := float32(0)
+	if maxValue > minValue {
+		t = float32((*value - minValue) / (maxValue - minValue))
+	}
+	rl.DrawRectangleRec(bounds, rl.LightGray)
+	rl.DrawRectangleRec(rl.Rectangle{X: bounds.X, Y: bounds.Y, Width: bounds.Width * t, Height: bounds.Height}, rl.SkyBlue)
+	rl.DrawRectangleLinesEx(bounds, 1, rl.Gray)
+	handleX := bounds.X + bounds.Width*t
+	rl.DrawRectangleRec(rl.Rectangle{X: handleX - 3, Y: bounds.Y - 3, Width: 6, Height: bounds.Height + 6}, rl.DarkGray)
+	return changed
+}
+
+// drawColorSwatch fills rect with a color, over a checkerboard so transparency shows.
+func drawColorSwatch(rect rl.Rectangle, color rl.Color) {
+	const cell = 8
+	for y := float32(0); y < rect.Height; y += cell {
+		for x := float32(0); x < rect.Width; x += cell {
+			checker := rl.White
+			if (int(x/cell)+int(y/cell))%2 == 1 {
+				checker = rl.LightGray
+			}
+			rl.DrawRectangleRec(rl.Rectangle{
+				X:      rect.X + x,
+				Y:      rect.Y + y,
+				Width:  min(cell, rect.Width-x),
+				Height: min(cell, rect.Height-y),
+			}, checker)
+		}
+	}
+	rl.DrawRectangleRec(rect, color)
+	rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
+}
+
+// drawTintSwatch draws a button showing the color in the RGBA fields, returns true when clicked.
+func drawTintSwatch(rect rl.Rectangle, r, g, b, a string) bool {
+	drawColorSwatch(rect, rl.Color{R: parseChannel(r), G: parseChannel(g), B: parseChannel(b), A: parseChannel(a)})
+	hovered := rl.CheckCollisionPointRec(rl.GetMousePosition(), rect)
+	if hovered {
+		rl.DrawRectangleLinesEx(rect, 2, rl.Blue)
+	}
+	return hovered && rl.IsMouseButtonPressed(rl.MouseLeftButton)
+}
+
+// parseChannel reads a color channel from a text field, clamped to 0-255.
+func parseChannel(s string) uint8 {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 255
+	}
+	return uint8(max(0, min(255, v)))
+}
+
+// hsvToRGB converts a hue (0-360), saturation and value (0-1) to RGB.
+func hsvToRGB(h, s, v float64) (uint8, uint8, uint8) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = math.Max(0, math.Min(1, s))
+	v = math.Max(0, math.Min(1, v))
+
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	toChannel := func(f float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(255, (f+m)*255))))
+	}
+	return toChannel(r), toChannel(g), toChannel(b)
+}
+
+// rgbToHSV converts RGB to a hue (0-360), saturation and value (0-1).
+func rgbToHSV(r, g, b uint8) (float64, float64, float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	maxC := math.Max(rf, math.Max(gf, bf))
+	minC := math.Min(rf, math.Min(gf, bf))
+	delta := maxC - minC
+
+	h := 0.0
+	switch {
+	case delta == 0:
+		h = 0
+	case maxC == rf:
+		h = 60 * math.Mod((gf-bf)/delta, 6)
+	case maxC == gf:
+		h = 60 * ((bf-rf)/delta + 2)
+	default:
+		h = 60 * ((rf-gf)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+
+	s := 0.0
+	if maxC > 0 {
+		s = delta / maxC
+	}
+	return h, s, maxC
+}
@@ -0,0 +1,48 @@
+package mapmaker
+
+import (
+	"math"
+	"testing"
+)
+
+// TestHSVConversion checks known colors convert both ways, and that RGB survives a round trip.
+func TestHSVConversion(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		h, s, v float64
+	}{
+		{255, 0, 0, 0, 1, 1},
+		{0, 255, 0, 120, 1, 1},
+		{0, 0, 255, 240, 1, 1},
+		{255, 255, 0, 60, 1, 1},
+		{255, 255, 255, 0, 0, 1},
+		{0, 0, 0, 0, 0, 0},
+		{128, 64, 64, 0, 0.5, 128.0 / 255},
+	}
+	for _, tt := range tests {
+		h, s, v := rgbToHSV(tt.r, tt.g, tt.b)
+		if math.Abs(h-tt.h) > 0.01 || math.Abs(s-tt.s) > 0.01 || math.Abs(v-tt.v) > 0.01 {
+			t.Errorf("rgbToHSV(%d, %d, %d) = %.2f, %.2f, %.2f, expected %.2f, %.2f, %.2f", tt.r, tt.g, tt.b, h, s, v, tt.h, tt.s, tt.v)
+		}
+		r, g, b := hsvToRGB(tt.h, tt.s, tt.v)
+		if r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("hsvToRGB(%.2f, %.2f, %.2f) = %d, %d, %d, expected %d, %d, %d", tt.h, tt.s, tt.v, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+
+	for _, c := range [][3]uint8{{12, 200, 99}, {250, 1, 130}, {77, 77, 78}, {0, 128, 255}} {
+		r, g, b := hsvToRGB(rgbToHSV(c[0], c[1], c[2]))
+		if r != c[0] || g != c[1] || b != c[2] {
+			t.Errorf("Round trip of %v gave %d, %d, %d", c, r, g, b)
+		}
+	}
+}
+
+// TestParseChannel checks tint fields are clamped to 0-255.
+func TestParseChannel(t *testing.T) {
+	for input, want := range map[string]uint8{"0": 0, "128": 128, "300": 255, "-5": 0, "abc": 255} {
+		if got := parseChannel(input); got != want {
+			t.Errorf("parseChannel(%q) = %d, expected %d", input, got, want)
+		}
+	}
+}
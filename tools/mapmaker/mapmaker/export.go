@@ -0,0 +1,243 @@
+package mapmaker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// MaxExportDimension caps the width and height of an exported map image, in pixels.
+// Larger maps are exported with smaller tiles to fit.
+const MaxExportDimension = 8192
+
+// exportChunkSize is the size of the render texture each piece of the map is drawn into,
+// small enough for any GPU. The pieces are stitched together into the final image.
+const exportChunkSize = 2048
+
+// Limits on the tile size picked in the export dialog.
+const (
+	minExportTileSize = 1
+	maxExportTileSize = 256
+)
+
+// ExportDialogState asks for the tile size to export the map image at.
+type ExportDialogState struct {
+	tileSize string
+}
+
+// openExportDialog opens the export dialog, starting from the current tile size.
+func (m *MapMaker) openExportDialog() {
+	m.uiState.exportDialog = &ExportDialogState{tileSize: strconv.Itoa(m.uiState.tileSize)}
+	m.uiState.activeInput = "export_tile_size"
+}
+
+func (m *MapMaker) closeExportDialog() {
+	m.uiState.exportDialog = nil
+	m.uiState.activeInput = ""
+}
+
+// renderExportDialog draws the tile size input, Enter or Export picks the file and writes the image.
+// Export .beam writes the map in the binary map format instead, for games to load.
+func (m *MapMaker) renderExportDialog() {
+	dialog := m.uiState.exportDialog
+	const dialogWidth, dialogHeight = 380, 170
+	dialogX := (rl.GetScreenWidth() - dialogWidth) / 2
+	dialogY := (rl.GetScreenHeight() - dialogHeight) / 2
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(rl.Black, 0.7))
+	dialogRect := rl.Rectangle{X: float32(dialogX), Y: float32(dialogY), Width: dialogWidth, Height: dialogHeight}
+	rl.DrawRectangleRec(dialogRect, rl.RayWhite)
+	rl.DrawRectangleLinesEx(dialogRect, 1, rl.Gray)
+	rl.DrawText("Export PNG", int32(dialogX+20), int32(dialogY+20), 24, rl.Black)
+
+	rl.DrawText("Tile size (px)", int32(dialogX+20), int32(dialogY+68), 16, rl.Black)
+	inputRect := rl.Rectangle{X: float32(dialogX + 150), Y: float32(dialogY + 60), Width: 80, Height: 30}
+	rl.DrawRectangleRec(inputRect, rl.White)
+	rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
+	rl.DrawText(dialog.tileSize, int32(inputRect.X+5), int32(inputRect.Y+7), 16, rl.Black)
+	for key := rl.GetCharPressed(); key > 0; key = rl.GetCharPressed() {
+		if key >= '0' && key <= '9' && len(dialog.tileSize) < 3 {
+			dialog.tileSize += string(key)
+		}
+	}
+	if rl.IsKeyPressed(rl.KeyBackspace) && len(dialog.tileSize) > 0 {
+		dialog.tileSize = dialog.tileSize[:len(dialog.tileSize)-1]
+	}
+
+	exportBtn := rl.Rectangle{X: float32(dialogX + 20), Y: float32(dialogY + 115), Width: 90, Height: 30}
+	beamBtn := rl.Rectangle{X: float32(dialogX + 125), Y: float32(dialogY + 115), Width: 130, Height: 30}
+	cancelBtn := rl.Rectangle{X: float32(dialogX + 270), Y: float32(dialogY + 115), Width: 90, Height: 30}
+	rl.DrawRectangleRec(exportBtn, rl.Green)
+	rl.DrawText("Export", int32(exportBtn.X+20), int32(exportBtn.Y+7), 16, rl.White)
+	rl.DrawRectangleRec(beamBtn, rl.DarkBlue)
+	rl.DrawText("Export .beam", int32(beamBtn.X+15), int32(beamBtn.Y+7), 16, rl.White)
+	rl.DrawRectangleRec(cancelBtn, rl.LightGray)
+	rl.DrawText("Cancel", int32(cancelBtn.X+20), int32(cancelBtn.Y+7), 16, rl.Black)
+
+	if clicked && rl.CheckCollisionPointRec(mousePos, cancelBtn) {
+		m.closeExportDialog()
+		return
+	}
+	if clicked && rl.CheckCollisionPointRec(mousePos, beamBtn) {
+		m.closeExportDialog()
+		m.exportMapBinary()
+		return
+	}
+	if rl.IsKeyPressed(rl.KeyEnter) || (clicked && rl.CheckCollisionPointRec(mousePos, exportBtn)) {
+		tileSize, err := strconv.Atoi(dialog.tileSize)
+		if err != nil || tileSize < minExportTileSize || tileSize > maxExportTileSize {
+			m.showToast(fmt.Sprintf("Tile size must be %d to %d", minExportTileSize, maxExportTileSize), ToastError)
+			return
+		}
+		m.closeExportDialog()
+		m.exportMapImage(tileSize)
+	}
+}
+
+// exportMapImage prompts for a filename and writes the whole map to a PNG, with tiles of the given size.
+func (m *MapMaker) exportMapImage(requestedTileSize int) {
+	filename := openSaveFileDialog("Export map as:", "map.png", "PNG (*.png)")
+	if filename == "" {
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(filename), ".png") {
+		filename += ".png"
+	}
+
+	tileSize := exportTileSize(requestedTileSize, m.tileGrid.Width, m.tileGrid.Height)
+	if err := m.ExportMapPNG(filename, tileSize); err != nil {
+		m.showToast("Error exporting map: "+err.Error(), ToastError)
+		return
+	}
+	if tileSize < requestedTileSize {
+		m.showToast(fmt.Sprintf("Map too large, exported with %dpx tiles", tileSize), ToastInfo)
+		return
+	}
+	m.showToast("Map exported to "+filepath.Base(filename), ToastSuccess)
+}
+
+// exportMapBinary prompts for a filename and writes the map in the binary map format.
+func (m *MapMaker) exportMapBinary() {
+	filename := openSaveFileDialog("Export map as:", "map.beam", "Beam map (*.beam)")
+	if filename == "" {
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(filename), ".beam") {
+		filename += ".beam"
+	}
+	if err := m.tileGrid.SaveMapBinary(filename); err != nil {
+		m.showToast("Error exporting map: "+err.Error(), ToastError)
+		return
+	}
+	m.showToast("Map exported to "+filepath.Base(filename), ToastSuccess)
+}
+
+// ExportMapPNG renders every tile, NPC and item on the map into an image, and writes it to filename.
+// The image is independent of the zoom and viewport, each tile is tileSize pixels.
+// The map is rendered a chunk at a time, so the image can be larger than the GPU's texture limit.
+func (m *MapMaker) ExportMapPNG(filename string, tileSize int) error {
+	if m.tileGrid.Width == 0 || m.tileGrid.Height == 0 {
+		return fmt.Errorf("map is empty")
+	}
+	width, height := m.tileGrid.Width*tileSize, m.tileGrid.Height*tileSize
+
+	chunkWidth, chunkHeight := min(width, exportChunkSize), min(height, exportChunkSize)
+	target := rl.LoadRenderTexture(int32(chunkWidth), int32(chunkHeight))
+	if !rl.IsRenderTextureValid(target) {
+		return fmt.Errorf("failed to create a %dx%d render texture", chunkWidth, chunkHeight)
+	}
+	defer rl.UnloadRenderTexture(target)
+
+	img := rl.GenImageColor(width, height, rl.Blank)
+	defer rl.UnloadImage(img)
+	for _, chunk := range exportChunks(width, height, exportChunkSize) {
+		// Shift the map so this chunk lands at the top left of the render texture
+		rl.BeginTextureMode(target)
+		rl.ClearBackground(rl.Blank)
+		rl.BeginMode2D(rl.Camera2D{Target: rl.Vector2{X: chunk.X, Y: chunk.Y}, Zoom: 1})
+		m.renderFullMap(tileSize)
+		rl.EndMode2D()
+		rl.EndTextureMode()
+
+		// Render textures are stored upside down
+		chunkImg := rl.LoadImageFromTexture(target.Texture)
+		rl.ImageFlipVertical(chunkImg)
+		rl.ImageDraw(img, chunkImg, rl.Rectangle{Width: chunk.Width, Height: chunk.Height}, chunk, rl.White)
+		rl.UnloadImage(chunkImg)
+	}
+
+	if !rl.ExportImage(*img, filename) {
+		return fmt.Errorf("failed to write %s", filename)
+	}
+	return nil
+}
+
+// renderFullMap draws the whole map from the top left, ignoring the viewport.
+// NPCs and items are drawn above the base layers, and below the foreground.
+func (m *MapMaker) renderFullMap(tileSize int) {
+	tileRect := func(pos beam.Position, scale float32) rl.Rectangle {
+		return rl.Rectangle{
+			X:      float32(pos.X * tileSize),
+			Y:      float32(pos.Y * tileSize),
+			Width:  float32(tileSize) * scale,
+			Height: float32(tileSize) * scale,
+		}
+	}
+
+	for _, layer := range beam.OrderedLayers() {
+		if layer == beam.ForegroundLayer {
+			for _, entity := range m.tileGrid.RenderOrder() {
+				switch entity := entity.(type) {
+				case *beam.Item:
+					m.resources.RenderItem(entity, tileRect(entity.Pos, .75), tileSize)
+				case *beam.NPC:
+					width, height := entity.Data.Size.GetDimensions()
+					rect := tileRect(entity.Pos, 1)
+					rect.Width, rect.Height = float32(width*tileSize), float32(height*tileSize)
+					m.resources.RenderNPC(entity, rect, tileSize)
+				}
+			}
+		}
+
+		for y := range m.tileGrid.Tiles {
+			for x, tile := range m.tileGrid.Tiles[y] {
+				pos := beam.Position{X: x, Y: y}
+				m.renderGridTile(tileRect(pos, 1), pos, tile, layer)
+			}
+		}
+		for y := range m.tileGrid.Tiles {
+			for x, tile := range m.tileGrid.Tiles[y] {
+				pos := beam.Position{X: x, Y: y}
+				m.renderGridSpans(tileRect(pos, 1), pos, tile, layer)
+			}
+		}
+	}
+}
+
+// exportChunks splits an image into pieces of at most chunkSize pixels, left to right and top to bottom.
+func exportChunks(width, height, chunkSize int) []rl.Rectangle {
+	var chunks []rl.Rectangle
+	for y := 0; y < height; y += chunkSize {
+		for x := 0; x < width; x += chunkSize {
+			chunks = append(chunks, rl.Rectangle{
+				X:      float32(x),
+				Y:      float32(y),
+				Width:  float32(min(chunkSize, width-x)),
+				Height: float32(min(chunkSize, height-y)),
+			})
+		}
+	}
+	return chunks
+}
+
+// exportTileSize shrinks the tile size if needed, so the exported image fits within MaxExportDimension.
+func exportTileSize(tileSize, width, height int) int {
+	largest := max(width, height, 1)
+	return max(1, min(tileSize, MaxExportDimension/largest))
+}
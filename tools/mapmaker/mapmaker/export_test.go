@@ -0,0 +1,46 @@
+package mapmaker
+
+import "testing"
+
+func TestExportTileSize(t *testing.T) {
+	tests := []struct {
+		tileSize, width, height, want int
+	}{
+		{32, 100, 50, 32},
+		{32, 512, 100, 16},
+		{64, 100, 20000, 1},
+	}
+	for _, tt := range tests {
+		if got := exportTileSize(tt.tileSize, tt.width, tt.height); got != tt.want {
+			t.Errorf("exportTileSize(%d, %d, %d) = %d, want %d", tt.tileSize, tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+// TestExportChunks checks the chunks cover the whole image exactly once, with smaller chunks at the edges.
+func TestExportChunks(t *testing.T) {
+	const width, height, chunkSize = 5000, 2100, 2048
+	chunks := exportChunks(width, height, chunkSize)
+	if len(chunks) != 6 {
+		t.Fatalf("Expected 3x2 chunks, got %d", len(chunks))
+	}
+
+	covered := 0
+	for _, chunk := range chunks {
+		if chunk.Width > chunkSize || chunk.Height > chunkSize {
+			t.Errorf("Chunk %+v is larger than %d", chunk, chunkSize)
+		}
+		if chunk.X+chunk.Width > width || chunk.Y+chunk.Height > height {
+			t.Errorf("Chunk %+v runs off the image", chunk)
+		}
+		covered += int(chunk.Width * chunk.Height)
+	}
+	if covered != width*height {
+		t.Errorf("Expected the chunks to cover %d pixels, got %d", width*height, covered)
+	}
+
+	last := chunks[len(chunks)-1]
+	if last.X != 4096 || last.Y != 2048 || last.Width != 904 || last.Height != 52 {
+		t.Errorf("Unexpected last chunk %+v", last)
+	}
+}
@@ -0,0 +1,192 @@
+package mapmaker
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam/resources"
+)
+
+// TextureTags are the starred textures and free-form tags, by texture name. They're saved with the map.
+type TextureTags struct {
+	Favorites []string            `json:"favorites,omitempty"`
+	Tags      map[string][]string `json:"tags,omitempty"`
+}
+
+// IsFavorite reports if the texture is starred.
+func (t TextureTags) IsFavorite(name string) bool {
+	return slices.Contains(t.Favorites, name)
+}
+
+// ToggleFavorite stars or unstars a texture.
+func (t *TextureTags) ToggleFavorite(name string) {
+	if i := slices.Index(t.Favorites, name); i >= 0 {
+		t.Favorites = slices.Delete(t.Favorites, i, i+1)
+	} else {
+		t.Favorites = append(t.Favorites, name)
+	}
+}
+
+// SetTags replaces a texture's tags with a comma separated list.
+// Tags are trimmed, lowercased, sorted and deduplicated, and an empty list removes them.
+func (t *TextureTags) SetTags(name, list string) {
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	if len(tags) == 0 {
+		delete(t.Tags, name)
+		if len(t.Tags) == 0 {
+			t.Tags = nil
+		}
+		return
+	}
+	if t.Tags == nil {
+		t.Tags = make(map[string][]string)
+	}
+	t.Tags[name] = tags
+}
+
+// Rename moves a texture's star and tags over to its new name.
+func (t *TextureTags) Rename(renames map[string]string) {
+	for i, name := range t.Favorites {
+		if newName, ok := renames[name]; ok {
+			t.Favorites[i] = newName
+		}
+	}
+	renamed := make(map[string][]string, len(t.Tags))
+	for name, tags := range t.Tags {
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		renamed[name] = tags
+	}
+	if len(renamed) > 0 {
+		t.Tags = renamed
+	}
+}
+
+// Matches reports if a texture should be shown for the resource viewer filter.
+// The filter matches the name or any of its tags, and favoritesOnly hides unstarred textures.
+func (t TextureTags) Matches(name, filter string, favoritesOnly bool) bool {
+	if favoritesOnly && !t.IsFavorite(name) {
+		return false
+	}
+	if matchesFilter(name, filter) {
+		return true
+	}
+	for _, tag := range t.Tags[name] {
+		if matchesFilter(tag, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewerTextures filters the textures shown in the resource viewer's grid.
+func (m *MapMaker) viewerTextures(textures []resources.TextureInfo) []resources.TextureInfo {
+	return filterTextures(textures, func(name string) bool {
+		return m.uiState.textureTags.Matches(name, m.uiState.resourceFilter, m.uiState.favoritesOnly)
+	})
+}
+
+// renderFavoritesToggle draws the button that shows only starred textures in the resource viewer.
+func (m *MapMaker) renderFavoritesToggle(x, y float32) {
+	btn := rl.Rectangle{X: x, Y: y, Width: 90, Height: 30}
+	if m.uiState.favoritesOnly {
+		rl.DrawRectangleRec(btn, rl.Gold)
+	} else {
+		rl.DrawRectangleRec(btn, rl.LightGray)
+	}
+	drawStar(btn.X+14, btn.Y+15, 8, rl.Orange)
+	rl.DrawText("Starred", int32(btn.X+26), int32(btn.Y+8), 16, rl.Black)
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && rl.CheckCollisionPointRec(rl.GetMousePosition(), btn) {
+		m.uiState.favoritesOnly = !m.uiState.favoritesOnly
+		m.uiState.resourceViewerScroll = 0
+	}
+}
+
+// renderFavoriteStar draws the star in the corner of a texture preview, filled if it's a favorite.
+// The outline only shows on hover, clicking it stars or unstars the texture.
+// Returns true if the click was used.
+func (m *MapMaker) renderFavoriteStar(name string, preview rl.Rectangle, canClick bool) bool {
+	const radius = 6
+	starRect := rl.Rectangle{X: preview.X + preview.Width - radius*2, Y: preview.Y, Width: radius * 2, Height: radius * 2}
+	mousePos := rl.GetMousePosition()
+	favorite := m.uiState.textureTags.IsFavorite(name)
+	if favorite {
+		drawStar(starRect.X+radius, starRect.Y+radius, radius, rl.Gold)
+	} else if rl.CheckCollisionPointRec(mousePos, preview) {
+		drawStar(starRect.X+radius, starRect.Y+radius, radius, rl.Fade(rl.DarkGray, 0.6))
+	}
+
+	if canClick && rl.IsMouseButtonPressed(rl.MouseLeftButton) && rl.CheckCollisionPointRec(mousePos, starRect) {
+		m.uiState.textureTags.ToggleFavorite(name)
+		return true
+	}
+	return false
+}
+
+// openTagEditor starts editing a texture's tags at the bottom of the resource viewer.
+func (m *MapMaker) openTagEditor(name string) {
+	m.cancelResourceRename()
+	m.uiState.taggingTexture = name
+	m.uiState.tagInput = strings.Join(m.uiState.textureTags.Tags[name], ", ")
+	m.uiState.activeInput = "texture_tags"
+}
+
+func (m *MapMaker) closeTagEditor() {
+	m.uiState.taggingTexture = ""
+	m.uiState.tagInput = ""
+	if m.uiState.activeInput == "texture_tags" {
+		m.uiState.activeInput = ""
+	}
+}
+
+// renderTagEditor draws the comma separated tag input for a texture, Enter saves and Escape cancels.
+func (m *MapMaker) renderTagEditor(x, y, width float32) {
+	if m.uiState.taggingTexture == "" {
+		return
+	}
+	rl.DrawRectangleRec(rl.Rectangle{X: x, Y: y, Width: width, Height: 36}, rl.RayWhite)
+	label := "Tags for " + truncateName(m.uiState.taggingTexture, 20) + ":"
+	labelWidth := float32(rl.MeasureText(label, 16))
+	rl.DrawText(label, int32(x), int32(y+10), 16, rl.Black)
+
+	inputRect := rl.Rectangle{X: x + labelWidth + 10, Y: y + 3, Width: width - labelWidth - 10, Height: 30}
+	rl.DrawRectangleRec(inputRect, rl.White)
+	rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
+	rl.DrawText(fitText(m.uiState.tagInput, int32(inputRect.Width)-10, 16), int32(inputRect.X+5), int32(inputRect.Y+8), 16, rl.Black)
+
+	m.textInput(&m.uiState.tagInput, true, false)
+	if rl.IsKeyPressed(rl.KeyEnter) {
+		m.uiState.textureTags.SetTags(m.uiState.taggingTexture, m.uiState.tagInput)
+		m.closeTagEditor()
+	} else if rl.IsKeyPressed(rl.KeyEscape) {
+		m.closeTagEditor()
+	}
+}
+
+// drawStar draws a filled five pointed star, pointing up.
+func drawStar(cx, cy, radius float32, color rl.Color) {
+	// raylib wants the fan counter-clockwise on screen, which is increasing angles with x = sin and y = cos
+	points := make([]rl.Vector2, 0, 12)
+	points = append(points, rl.Vector2{X: cx, Y: cy})
+	for i := 0; i <= 10; i++ {
+		r := radius * 0.45
+		if i%2 == 1 {
+			r = radius
+		}
+		angle := float64(i) * math.Pi / 5
+		points = append(points, rl.Vector2{X: cx + r*float32(math.Sin(angle)), Y: cy + r*float32(math.Cos(angle))})
+	}
+	rl.DrawTriangleFan(points, color)
+}
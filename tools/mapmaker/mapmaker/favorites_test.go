@@ -0,0 +1,72 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestTextureTags checks starring, tag cleanup, and that the viewer filter matches names and tags.
+func TestTextureTags(t *testing.T) {
+	var tags TextureTags
+	tags.ToggleFavorite("grass")
+	tags.ToggleFavorite("water")
+	tags.ToggleFavorite("grass")
+	if tags.IsFavorite("grass") || !tags.IsFavorite("water") {
+		t.Errorf("Expected only water to be starred, got %v", tags.Favorites)
+	}
+
+	tags.SetTags("dungeon_0_1", " Wall, stone,,wall ")
+	if got := tags.Tags["dungeon_0_1"]; !reflect.DeepEqual(got, []string{"stone", "wall"}) {
+		t.Errorf("Expected the tags to be cleaned up to [stone wall], got %v", got)
+	}
+
+	for _, tc := range []struct {
+		name, filter  string
+		favoritesOnly bool
+		want          bool
+	}{
+		{"dungeon_0_1", "", false, true},
+		{"dungeon_0_1", "STONE", false, true},
+		{"dungeon_0_1", "dungeon", false, true},
+		{"dungeon_0_1", "lava", false, false},
+		{"dungeon_0_1", "", true, false},
+		{"water", "", true, true},
+		{"water", "stone", true, false},
+	} {
+		if got := tags.Matches(tc.name, tc.filter, tc.favoritesOnly); got != tc.want {
+			t.Errorf("Matches(%q, %q, %v): expected %v, got %v", tc.name, tc.filter, tc.favoritesOnly, tc.want, got)
+		}
+	}
+
+	// Renaming a resource carries its star and tags over
+	tags.Rename(map[string]string{"water": "lake", "dungeon_0_1": "cave_0_1"})
+	if !tags.IsFavorite("lake") || tags.Tags["cave_0_1"] == nil || tags.Tags["dungeon_0_1"] != nil {
+		t.Errorf("Expected the star and tags to follow the rename, got %+v", tags)
+	}
+
+	// Clearing the last tag drops the map, so nothing empty is saved
+	tags.SetTags("cave_0_1", " , ")
+	if tags.Tags != nil {
+		t.Errorf("Expected no tags left, got %v", tags.Tags)
+	}
+}
+
+// TestTextureTagsSaveData checks stars and tags round trip through the save format.
+func TestTextureTagsSaveData(t *testing.T) {
+	var tags TextureTags
+	tags.ToggleFavorite("grass")
+	tags.SetTags("grass", "floor, outdoor")
+
+	data, err := json.Marshal(SaveData{TextureTags: tags})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded SaveData
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.TextureTags, tags) {
+		t.Errorf("Expected %+v after loading, got %+v", tags, loaded.TextureTags)
+	}
+}
@@ -0,0 +1,117 @@
+package mapmaker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The grid can be resized to anywhere from MinGridSize to MaxGridSize tiles in each direction.
+const (
+	MinGridSize = 10
+	MaxGridSize = 100
+)
+
+// Grid size fields, the activeInput names for typing in a new width or height.
+const (
+	gridWidthInput  = "grid_width"
+	gridHeightInput = "grid_height"
+)
+
+// gridSizeFieldRects returns where the width and height are shown in the menu bar, between their -/+ buttons.
+func gridSizeFieldRects() (width, height rl.Rectangle) {
+	return rl.Rectangle{X: 42, Y: 8, Width: 41, Height: 20}, rl.Rectangle{X: 42, Y: 33, Width: 41, Height: 20}
+}
+
+// applyGridSize sets the grid's width or height from typed text, clamped to the allowed range.
+// Tiles inside the new size are kept. Returns the size used.
+func (m *MapMaker) applyGridSize(field, text string) (int, error) {
+	size, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a whole number", text)
+	}
+	size = min(max(size, MinGridSize), MaxGridSize)
+	if field == gridWidthInput {
+		m.uiState.gridWidth = size
+	} else {
+		m.uiState.gridHeight = size
+	}
+	m.updateGridSize()
+	m.resizeGrid()
+	return size, nil
+}
+
+// handleGridSizeInput lets the width and height be clicked and typed in, applied on Enter.
+// Clicking elsewhere or pressing Escape cancels. Focus is only taken when no other input is active.
+func (m *MapMaker) handleGridSizeInput() {
+	widthRect, heightRect := gridSizeFieldRects()
+	mousePos := rl.GetMousePosition()
+	editing := m.uiState.activeInput == gridWidthInput || m.uiState.activeInput == gridHeightInput
+
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		switch {
+		case (!editing && m.uiState.activeInput != "") || m.isDialogOpen():
+			// Another input keeps focus
+		case rl.CheckCollisionPointRec(mousePos, widthRect):
+			m.uiState.activeInput = gridWidthInput
+			m.uiState.gridSizeText = strconv.Itoa(m.uiState.gridWidth)
+			return
+		case rl.CheckCollisionPointRec(mousePos, heightRect):
+			m.uiState.activeInput = gridHeightInput
+			m.uiState.gridSizeText = strconv.Itoa(m.uiState.gridHeight)
+			return
+		case editing:
+			m.uiState.activeInput = ""
+			return
+		}
+	}
+	if !editing {
+		return
+	}
+
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		m.uiState.activeInput = ""
+		return
+	}
+	m.textInput(&m.uiState.gridSizeText, true, true)
+	if rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeyKpEnter) {
+		field := m.uiState.activeInput
+		m.uiState.activeInput = ""
+		size, err := m.applyGridSize(field, m.uiState.gridSizeText)
+		if err != nil {
+			m.showToast("Grid size "+err.Error(), ToastError)
+			return
+		}
+		if typed, _ := strconv.Atoi(strings.TrimSpace(m.uiState.gridSizeText)); typed != size {
+			m.showToast(fmt.Sprintf("Grid size must be %d to %d, used %d", MinGridSize, MaxGridSize, size), ToastInfo)
+			return
+		}
+		m.showToast(fmt.Sprintf("Grid size: %dx%d", m.uiState.gridWidth, m.uiState.gridHeight), ToastInfo)
+	}
+}
+
+// renderGridSizeFields draws the width and height, as a text input while one is being typed in.
+func (m *MapMaker) renderGridSizeFields() {
+	widthRect, heightRect := gridSizeFieldRects()
+	for _, field := range []struct {
+		input string
+		rect  rl.Rectangle
+		label string
+		size  int
+	}{
+		{gridWidthInput, widthRect, "W", m.uiState.gridWidth},
+		{gridHeightInput, heightRect, "H", m.uiState.gridHeight},
+	} {
+		text := fmt.Sprintf("%s:%d", field.label, field.size)
+		if m.uiState.activeInput == field.input {
+			rl.DrawRectangleRec(field.rect, rl.White)
+			rl.DrawRectangleLinesEx(field.rect, 1, rl.Blue)
+			text = m.uiState.gridSizeText
+		} else if rl.CheckCollisionPointRec(rl.GetMousePosition(), field.rect) {
+			rl.DrawRectangleLinesEx(field.rect, 1, rl.LightGray)
+		}
+		rl.DrawText(text, int32(field.rect.X+6), int32(field.rect.Y+4), 12, rl.DarkGray)
+	}
+}
@@ -0,0 +1,45 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestApplyGridSize types sizes into the width and height, checking they're clamped and painted tiles are kept.
+func TestApplyGridSize(t *testing.T) {
+	m := newTestMapMaker(20, 15)
+	m.paintTiles(beam.Positions{{X: 3, Y: 4}}, "dirt")
+
+	tests := []struct {
+		field, text string
+		want        int
+	}{
+		{gridWidthInput, "100", 100},
+		{gridHeightInput, " 80 ", 80},
+		{gridWidthInput, "250", MaxGridSize},
+		{gridHeightInput, "2", MinGridSize},
+		{gridHeightInput, "-5", MinGridSize},
+	}
+	for _, tt := range tests {
+		got, err := m.applyGridSize(tt.field, tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("%s %q: expected %d, got %d (%v)", tt.field, tt.text, tt.want, got, err)
+		}
+	}
+	if m.tileGrid.Width != MaxGridSize || m.tileGrid.Height != MinGridSize || len(m.tileGrid.Tiles) != MinGridSize || len(m.tileGrid.Tiles[0]) != MaxGridSize {
+		t.Errorf("Expected a %dx%d grid, got %dx%d", MaxGridSize, MinGridSize, m.tileGrid.Width, m.tileGrid.Height)
+	}
+	if textures := m.tileGrid.Tiles[4][3].Textures; len(textures) != 1 || textures[0].Frames[0].Name != "dirt" {
+		t.Error("Expected the painted tile to survive resizing")
+	}
+
+	for _, text := range []string{"", "abc", "12.5"} {
+		if _, err := m.applyGridSize(gridWidthInput, text); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+	if m.uiState.gridWidth != MaxGridSize {
+		t.Errorf("Expected a bad size to leave the width alone, got %d", m.uiState.gridWidth)
+	}
+}
@@ -0,0 +1,61 @@
+package mapmaker
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestItemEditor() *ItemEditorState {
+	return &ItemEditorState{
+		id:               "potion",
+		name:             "Potion",
+		maxStack:         "10",
+		quantity:         "3",
+		attack:           "0",
+		defense:          "0",
+		attackSpeed:      "0",
+		attackRange:      "0",
+		levelReq:         "1",
+		stackable:        true,
+		frameCountStr:    "1",
+		animationTimeStr: "0.5",
+		selectedFrames:   []string{"potion_red"},
+	}
+}
+
+// TestItemEditorValidate checks the item editor rejects fields that can't make a valid item.
+func TestItemEditorValidate(t *testing.T) {
+	if err := newTestItemEditor().validate(); err != nil {
+		t.Fatalf("Expected a valid item, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(*ItemEditorState)
+		error string
+	}{
+		{"empty name", func(e *ItemEditorState) { e.name = "  " }, "Name are required"},
+		{"empty id", func(e *ItemEditorState) { e.id = "" }, "Name are required"},
+		{"stackable without max stack", func(e *ItemEditorState) { e.maxStack = "" }, "Max Stack must be"},
+		{"stackable with a max stack of 1", func(e *ItemEditorState) { e.maxStack = "1"; e.quantity = "1" }, "at least 2 for stackable"},
+		{"quantity over max stack", func(e *ItemEditorState) { e.quantity = "11" }, "more than Max Stack"},
+		{"non-numeric stat", func(e *ItemEditorState) { e.equippable = true; e.attack = "1.5" }, "Attack must be"},
+		{"missing frame", func(e *ItemEditorState) { e.frameCountStr = "2" }, "every frame"},
+	}
+	for _, tt := range tests {
+		editor := newTestItemEditor()
+		tt.edit(editor)
+		err := editor.validate()
+		if err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.error, err)
+		}
+	}
+
+	// A non-stackable item doesn't need a max stack
+	editor := newTestItemEditor()
+	editor.stackable = false
+	editor.maxStack = "0"
+	if err := editor.validate(); err != nil {
+		t.Errorf("Expected a non-stackable item without a max stack to be valid, got %v", err)
+	}
+}
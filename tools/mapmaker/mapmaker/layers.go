@@ -0,0 +1,97 @@
+package mapmaker
+
+import (
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// LayerVisibility is which layers the grid draws. Hidden layers are still painted and saved as normal.
+type LayerVisibility struct {
+	Hidden []beam.Layer `json:"hidden,omitempty"`
+	// Solo shows only this layer, ignoring Hidden
+	Solo *beam.Layer `json:"solo,omitempty"`
+}
+
+// Visible reports if the grid should draw the layer.
+func (v LayerVisibility) Visible(layer beam.Layer) bool {
+	if v.Solo != nil {
+		return *v.Solo == layer
+	}
+	return !slices.Contains(v.Hidden, layer)
+}
+
+// ToggleHidden shows or hides a layer.
+func (v *LayerVisibility) ToggleHidden(layer beam.Layer) {
+	if i := slices.Index(v.Hidden, layer); i >= 0 {
+		v.Hidden = slices.Delete(v.Hidden, i, i+1)
+	} else {
+		v.Hidden = append(v.Hidden, layer)
+	}
+}
+
+// ToggleSolo shows only the layer, or goes back to the per-layer toggles if it's already solo.
+func (v *LayerVisibility) ToggleSolo(layer beam.Layer) {
+	if v.Solo != nil && *v.Solo == layer {
+		v.Solo = nil
+		return
+	}
+	v.Solo = &layer
+}
+
+// layerLabels are the short names used for the layer toggles in the status bar.
+var layerLabels = map[beam.Layer]string{
+	beam.BackgroundLayer: "Bg",
+	beam.BaseLayer:       "Base",
+	beam.ForegroundLayer: "Fg",
+}
+
+// renderLayerToggles draws a checkbox and solo button for each layer, ending at rightEdge.
+// Returns the x the toggles start at.
+func (m *MapMaker) renderLayerToggles(rightEdge, y int32) int32 {
+	const boxSize = 14
+	visibility := &m.uiState.layerVisibility
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+
+	// Lay the toggles out right to left, so they end at rightEdge
+	x := rightEdge
+	layers := beam.OrderedLayers()
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		label := layerLabels[layer]
+
+		soloBtn := rl.Rectangle{X: float32(x - boxSize), Y: float32(y + 1), Width: boxSize, Height: boxSize}
+		soloColor := rl.LightGray
+		if visibility.Solo != nil && *visibility.Solo == layer {
+			soloColor = rl.Orange
+		}
+		rl.DrawRectangleRec(soloBtn, soloColor)
+		rl.DrawText("S", int32(soloBtn.X+4), int32(soloBtn.Y+2), 10, rl.Black)
+		x -= boxSize + 4
+
+		labelWidth := rl.MeasureText(label, 16)
+		x -= labelWidth
+		labelColor := rl.DarkGray
+		if !visibility.Visible(layer) {
+			labelColor = rl.LightGray
+		}
+		rl.DrawText(label, x, y, 16, labelColor)
+		x -= 4
+
+		checkbox := rl.Rectangle{X: float32(x - boxSize), Y: float32(y + 1), Width: boxSize, Height: boxSize}
+		rl.DrawRectangleLinesEx(checkbox, 1, rl.DarkGray)
+		if !slices.Contains(visibility.Hidden, layer) {
+			rl.DrawRectangle(int32(checkbox.X+3), int32(checkbox.Y+3), boxSize-6, boxSize-6, rl.DarkGray)
+		}
+		x -= boxSize + 12
+
+		if clicked && rl.CheckCollisionPointRec(mousePos, checkbox) {
+			visibility.ToggleHidden(layer)
+		} else if clicked && rl.CheckCollisionPointRec(mousePos, soloBtn) {
+			visibility.ToggleSolo(layer)
+		}
+	}
+	return x
+}
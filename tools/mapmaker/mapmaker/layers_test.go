@@ -0,0 +1,65 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+func TestLayerVisibility(t *testing.T) {
+	var v LayerVisibility
+	for _, layer := range beam.OrderedLayers() {
+		if !v.Visible(layer) {
+			t.Errorf("Expected %s to be visible by default", layer)
+		}
+	}
+
+	v.ToggleHidden(beam.ForegroundLayer)
+	if v.Visible(beam.ForegroundLayer) || !v.Visible(beam.BaseLayer) {
+		t.Error("Expected only the foreground to be hidden")
+	}
+
+	// Solo shows just the one layer, even if it was hidden, and toggling it again restores the rest
+	v.ToggleSolo(beam.ForegroundLayer)
+	if !v.Visible(beam.ForegroundLayer) || v.Visible(beam.BaseLayer) || v.Visible(beam.BackgroundLayer) {
+		t.Error("Expected only the soloed foreground to be visible")
+	}
+	v.ToggleSolo(beam.ForegroundLayer)
+	if v.Visible(beam.ForegroundLayer) || !v.Visible(beam.BaseLayer) {
+		t.Error("Expected the hidden toggles to apply again after leaving solo")
+	}
+
+	v.ToggleHidden(beam.ForegroundLayer)
+	if !v.Visible(beam.ForegroundLayer) {
+		t.Error("Expected the foreground to be shown again")
+	}
+}
+
+// TestLayerVisibilityJSON checks the toggles survive a save, and old saves show every layer.
+func TestLayerVisibilityJSON(t *testing.T) {
+	v := LayerVisibility{Hidden: []beam.Layer{beam.BackgroundLayer}}
+	v.ToggleSolo(beam.BaseLayer)
+	data, err := json.Marshal(SaveData{LayerVisibility: v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded SaveData
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.LayerVisibility.Solo == nil || *loaded.LayerVisibility.Solo != beam.BaseLayer ||
+		len(loaded.LayerVisibility.Hidden) != 1 || loaded.LayerVisibility.Hidden[0] != beam.BackgroundLayer {
+		t.Errorf("Expected the layer toggles after a round trip, got %+v", loaded.LayerVisibility)
+	}
+
+	var old SaveData
+	if err := json.Unmarshal([]byte(`{"tileSize": 32}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	for _, layer := range beam.OrderedLayers() {
+		if !old.LayerVisibility.Visible(layer) {
+			t.Errorf("Expected %s to be visible in an old save", layer)
+		}
+	}
+}
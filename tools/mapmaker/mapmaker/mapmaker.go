@@ -2,8 +2,10 @@ package mapmaker
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"slices"
 
@@ -25,6 +27,20 @@ type MapMaker struct {
 	showTileInfo       bool
 	showRecentTextures bool
 	clipboard          [][]beam.Tile
+	textureClipboard   *beam.AnimatedTexture // A single texture layer, copied from the tile info
+	history            *UndoStack
+	changes            int // Counts every change to the map, so a change since the last save can be spotted
+	batch              *resources.SpriteBatch
+
+	// Every open map, shown as tabs. The active one's state is held in the fields above.
+	documents      []*Document
+	activeDocument int
+
+	// Edited maps are autosaved to autosaveDir every autosaveInterval, keeping the newest autosaveKeep
+	autosaveDir      string
+	autosaveInterval time.Duration
+	autosaveKeep     int
+	lastAutosave     time.Time
 }
 
 type Window struct {
@@ -35,18 +51,51 @@ type Window struct {
 
 type UIState struct {
 	tileSize        int
+	zoomLevel       float32
 	menuBarHeight   int
 	statusBarHeight int
 	uiTextures      map[string]rl.Texture2D
 	activeTexture   *resources.TextureInfo
 	selectedTool    string
 	showGridlines   bool
+	showBrushGhost  bool
+	brushSize       int
+	// Paintbrush, how many tiles across and down the active texture is placed over, see beam.PlaceSpanTexture
+	textureSpanX, textureSpanY int
+	// Text inputs, holding backspace deletes repeatedly
+	backspaceRepeat keyRepeat
+	// Which layers the grid draws, saved with the map
+	layerVisibility LayerVisibility
+	// Autotile mode, walls painted from this set pick their sprite from their neighbors
+	autotileSet *AutotileSet
+	// Rect and line tools, the tile the shape was started from
+	shapeStart     beam.Position
+	isDrawingShape bool
+	// Select tool, the corner of the rectangle being dragged out
+	selectStart     beam.Position
+	isRectSelecting bool
+	// Ruler tool, the tile the measurement was started from
+	rulerStart  beam.Position
+	isMeasuring bool
+	// Properties tool, the key/value editor for the selected tiles
+	propertiesEditor *PropertiesEditorState
+	// Location tool in trigger mode, names a trigger over the selected tiles
+	triggerEditor *TriggerEditorState
+	// Export dialog, the tile size to export the map image at
+	exportDialog *ExportDialogState
+	// Missing resources dialog, remaps or loads textures the map references that aren't loaded
+	missingResourcesDialog *MissingResourcesDialogState
 	// Active toast notification
 	toast *Toast
 
 	// Resource Viewer
 	resourceViewerScroll   int
 	resourceViewerOpenTime float64
+	resourceFilter         string // Only resources with this in their name or tags are shown
+	favoritesOnly          bool   // Only starred textures are shown
+	textureTags            TextureTags
+	taggingTexture         string // Name of the texture whose tags are being edited, empty if none
+	tagInput               string
 
 	// Tile Info Popup
 	tileInfoPos     []beam.Position
@@ -60,6 +109,15 @@ type UIState struct {
 
 	// Resource Manage Mode
 	resourceManageMode bool
+	renamingResource   string // Name of the resource being renamed, empty if none
+	renameInput        string
+
+	// Middle mouse panning, carries over any drag smaller than a tile
+	isPanning    bool
+	panRemainder rl.Vector2
+
+	// Recentering the viewport by dragging on the minimap
+	isDraggingMinimap bool
 
 	// Track long right click for tool swap
 	rightClickStartTime float64
@@ -79,6 +137,8 @@ type UIState struct {
 	// Grid Width/Height Controls
 	gridWidth  int
 	gridHeight int
+	// The width or height being typed in, see handleGridSizeInput
+	gridSizeText string
 
 	// Tile Editor Popup
 	textureEditor          *TextureEditorState
@@ -98,10 +158,13 @@ type UIState struct {
 }
 
 type TileGrid struct {
-	offset               beam.Position    // The offset of the grid in the window
-	hasSelection         bool             // If the user has any selected tiles
-	selectedTiles        beam.Positions   // These are the tiles that are selected by the user
-	missingResourceTiles MissingResources // This is every tile that has a texture, that is missing in the resource manager
+	offset               beam.Position          // The offset of the grid in the window
+	hasSelection         bool                   // If the user has any selected tiles
+	selectedTiles        beam.Positions         // These are the tiles that are selected by the user
+	missingResourceTiles MissingResources       // This is every tile that has a texture, that is missing in the resource manager
+	validationIssues     []beam.ValidationIssue // Problems found by the last validation, highlighted on the grid
+	minimap              rl.Texture2D           // One pixel per tile, rebuilt when minimapDirty is set
+	minimapDirty         bool                   // Set whenever tiles are edited
 
 	// The section of the grid that is currently visible
 	viewportOffset beam.Position // Tracks how many tiles to offset the view
@@ -136,6 +199,11 @@ const (
 	DefaultGridHeight = 40
 	MaxDisplayWidth   = 64
 	MaxDisplayHeight  = 40
+	MinZoomLevel      = 0.25
+	MaxZoomLevel      = 4.0
+	ZoomStep          = 0.1
+	MinBrushSize      = 1
+	MaxBrushSize      = 9
 )
 
 type ResourceDialog struct {
@@ -162,9 +230,14 @@ func NewMapMaker(width, height int32) *MapMaker {
 
 			menuBarHeight:   60,
 			statusBarHeight: 25,
+			zoomLevel:       1.0,
 			uiTextures:      make(map[string]rl.Texture2D),
 			activeTexture:   nil,
 			selectedTool:    "",
+			showBrushGhost:  true,
+			brushSize:       1,
+			textureSpanX:    1,
+			textureSpanY:    1,
 			toast:           nil,
 			recentTextures:  make([]string, 0),
 
@@ -173,20 +246,28 @@ func NewMapMaker(width, height int32) *MapMaker {
 			hasSwappedLayers:   false,
 			locationMode:       0,
 		},
-		tileGrid: &TileGrid{
-			offset:         beam.Position{X: 0, Y: 0},
-			selectedTiles:  beam.Positions{{X: -1, Y: -1}},
-			hasSelection:   false,
-			viewportOffset: beam.Position{X: 0, Y: 0},
-			viewportWidth:  MaxDisplayWidth,
-			viewportHeight: MaxDisplayHeight,
-		},
+		tileGrid:    newTileGrid(),
 		currentFile: "",
+		history:     NewUndoStack(MaxUndoDepth),
+		batch:       resources.NewSpriteBatch(),
 	}
 	mm.updateGridSize()
+	mm.storeDocument()
 	return mm
 }
 
+// newTileGrid returns an empty grid, with nothing selected and the viewport at the top left.
+func newTileGrid() *TileGrid {
+	return &TileGrid{
+		offset:         beam.Position{X: 0, Y: 0},
+		selectedTiles:  beam.Positions{{X: -1, Y: -1}},
+		hasSelection:   false,
+		viewportOffset: beam.Position{X: 0, Y: 0},
+		viewportWidth:  MaxDisplayWidth,
+		viewportHeight: MaxDisplayHeight,
+	}
+}
+
 func (m *MapMaker) Init() {
 	rl.InitWindow(m.window.width, m.window.height, m.window.title)
 	rl.SetTargetFPS(60)
@@ -212,6 +293,11 @@ func (m *MapMaker) Init() {
 	m.uiState.uiTextures["gridlines"] = rl.LoadTexture("../assets/gridlines.png")
 	m.uiState.uiTextures["npc"] = rl.LoadTexture("../assets/npc.png")
 	m.uiState.uiTextures["items"] = rl.LoadTexture("../assets/sword.png")
+	m.uiState.uiTextures["rect"] = rl.LoadTexture("../assets/rect.png")
+	m.uiState.uiTextures["line"] = rl.LoadTexture("../assets/line.png")
+	m.uiState.uiTextures["ruler"] = rl.LoadTexture("../assets/ruler.png")
+	m.uiState.uiTextures["properties"] = rl.LoadTexture("../assets/properties.png")
+	m.uiState.uiTextures["stamp"] = rl.LoadTexture("../assets/stamp.png")
 
 	// Add directional arrows for viewport
 	m.uiState.uiTextures["up"] = rl.LoadTexture("../assets/up.png")
@@ -221,6 +307,7 @@ func (m *MapMaker) Init() {
 
 	m.resources = resources.NewResourceManager()
 	m.initTileGrid()
+	m.configureAutosave()
 }
 
 func (m *MapMaker) Run() {
@@ -233,6 +320,10 @@ func (m *MapMaker) Run() {
 					m.tileGrid.selectedTiles = beam.Positions{}
 					continue
 				}
+				if len(m.tileGrid.validationIssues) > 0 {
+					m.tileGrid.validationIssues = nil
+					continue
+				}
 			} else {
 				break
 			}
@@ -249,6 +340,27 @@ func (m *MapMaker) Run() {
 			}
 		}
 
+		// Undo and redo, cmd/ctrl+z and cmd/ctrl+shift+z
+		if rl.IsKeyPressed(rl.KeyZ) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyLeftSuper)) {
+			if rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift) {
+				if !m.redo() {
+					m.showToast("Nothing to redo", ToastInfo)
+				}
+			} else if !m.undo() {
+				m.showToast("Nothing to undo", ToastInfo)
+			}
+		}
+
+		// Export the whole map as a PNG, cmd/ctrl+e
+		if rl.IsKeyPressed(rl.KeyE) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyLeftSuper)) && !m.isDialogOpen() {
+			m.openExportDialog()
+		}
+
+		// Validate the map, checking every objective can be reached from the start
+		if rl.IsKeyPressed(rl.KeyR) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyLeftSuper)) {
+			m.validateMap()
+		}
+
 		// Clipboard copy
 		if rl.IsKeyPressed(rl.KeyC) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyLeftSuper)) {
 			if !m.tileGrid.hasSelection || len(m.tileGrid.selectedTiles) == 0 {
@@ -256,39 +368,7 @@ func (m *MapMaker) Run() {
 				continue
 			}
 
-			// Find bounds of selection
-			minX, minY := m.tileGrid.Width, m.tileGrid.Height
-			maxX, maxY := 0, 0
-			for _, pos := range m.tileGrid.selectedTiles {
-				if pos.X < minX {
-					minX = pos.X
-				}
-				if pos.Y < minY {
-					minY = pos.Y
-				}
-				if pos.X > maxX {
-					maxX = pos.X
-				}
-				if pos.Y > maxY {
-					maxY = pos.Y
-				}
-			}
-
-			// Create clipboard array of correct size
-			width := maxX - minX + 1
-			height := maxY - minY + 1
-			m.clipboard = make([][]beam.Tile, height)
-			for i := range m.clipboard {
-				m.clipboard[i] = make([]beam.Tile, width)
-			}
-
-			// Copy selected tiles to clipboard
-			for _, pos := range m.tileGrid.selectedTiles {
-				relX := pos.X - minX
-				relY := pos.Y - minY
-				m.clipboard[relY][relX] = m.tileGrid.Tiles[pos.Y][pos.X]
-			}
-
+			m.copySelection()
 			m.showToast("Tiles copied!", ToastSuccess)
 		}
 
@@ -302,39 +382,12 @@ func (m *MapMaker) Run() {
 
 			// Get the target position (first selected tile)
 			targetPos := m.tileGrid.selectedTiles[0]
-
-			// Calculate paste bounds
-			pasteHeight := len(m.clipboard)
-			pasteWidth := len(m.clipboard[0])
-
-			// Iterate through clipboard and paste where possible
-			for clipY := 0; clipY < pasteHeight; clipY++ {
-				for clipX := 0; clipX < pasteWidth; clipX++ {
-					// Calculate target grid position
-					gridX := targetPos.X + clipX
-					gridY := targetPos.Y + clipY
-
-					// Skip if outside grid bounds
-					if gridX >= m.tileGrid.Width || gridY >= m.tileGrid.Height {
-						continue
-					}
-
-					// Skip if clipboard tile is empty
-					if len(m.clipboard[clipY][clipX].Textures) == 0 {
-						continue
-					}
-
-					// Copy the tile data
-					m.tileGrid.Tiles[gridY][gridX] = m.clipboard[clipY][clipX]
-					// Update the position to match the new location
-					m.tileGrid.Tiles[gridY][gridX].Pos = beam.Position{X: gridX, Y: gridY}
-				}
-			}
-
+			m.stamp(targetPos)
 			m.showToast("Tiles pasted!", ToastSuccess)
 		}
 
 		m.update() // Update settings, configs, and UI state.
+		m.handleAutosave()
 		rl.BeginDrawing()
 		rl.ClearBackground(rl.RayWhite)
 		m.renderGrid()  // Render the current map
@@ -348,46 +401,248 @@ func (m *MapMaker) isUIBlocked() bool {
 	return m.showResourceViewer || (m.uiState.textureEditor != nil && m.uiState.textureEditor.visible) || m.uiState.showAdvancedEditor
 }
 
+// isDialogOpen reports if any editor, list or popup is covering the grid.
+func (m *MapMaker) isDialogOpen() bool {
+	return m.isUIBlocked() ||
+		(m.uiState.npcEditor != nil && m.uiState.npcEditor.visible) ||
+		(m.uiState.itemEditor != nil && m.uiState.itemEditor.visible) ||
+		m.uiState.showNPCList || m.uiState.showItemList || m.showRecentTextures ||
+		m.uiState.propertiesEditor != nil || m.uiState.triggerEditor != nil ||
+		m.uiState.exportDialog != nil || m.uiState.missingResourcesDialog != nil
+}
+
+// mouseGridPos returns the grid tile under the mouse, and if the mouse is over the grid.
+func (m *MapMaker) mouseGridPos() (beam.Position, bool) {
+	mousePos := rl.GetMousePosition()
+	gridX, gridY := m.screenToGrid(mousePos)
+	if mousePos.X < float32(m.tileGrid.offset.X) || mousePos.Y < float32(m.tileGrid.offset.Y) || m.isOverMinimap(mousePos) {
+		return beam.Position{}, false
+	}
+	if mousePos.Y <= float32(m.uiState.menuBarHeight) || mousePos.Y >= float32(m.tabBarY()) {
+		return beam.Position{}, false
+	}
+	if gridX < 0 || gridX >= m.tileGrid.Width || gridY < 0 || gridY >= m.tileGrid.Height {
+		return beam.Position{}, false
+	}
+	return beam.Position{X: gridX, Y: gridY}, true
+}
+
+// renderTileSize is the on-screen size of a tile, after zoom.
+func (m *MapMaker) renderTileSize() int {
+	return max(1, int(float32(m.uiState.tileSize)*m.uiState.zoomLevel))
+}
+
+// maxVisibleTiles returns how many tiles fit in the viewport at the current zoom.
+func (m *MapMaker) maxVisibleTiles() (int, int) {
+	tileSize := m.renderTileSize()
+	return MaxDisplayWidth * DefaultTileSize / tileSize, MaxDisplayHeight * DefaultTileSize / tileSize
+}
+
+// screenToGrid converts a screen position to grid coordinates, accounting for zoom and the viewport.
+// The result may be outside the grid.
+func (m *MapMaker) screenToGrid(pos rl.Vector2) (int, int) {
+	tileSize := float32(m.renderTileSize())
+	gridX := int(math.Floor(float64((pos.X-float32(m.tileGrid.offset.X))/tileSize))) + m.tileGrid.viewportOffset.X
+	gridY := int(math.Floor(float64((pos.Y-float32(m.tileGrid.offset.Y))/tileSize))) + m.tileGrid.viewportOffset.Y
+	return gridX, gridY
+}
+
+// layoutGrid centers the visible part of the grid in the workspace,
+// and keeps the viewport offset within the grid.
+func (m *MapMaker) layoutGrid() {
+	tileSize := m.renderTileSize()
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
+	displayWidth := min(m.tileGrid.Width, maxVisibleWidth)
+	displayHeight := min(m.tileGrid.Height, maxVisibleHeight)
+	totalGridWidth := displayWidth * tileSize
+	totalGridHeight := displayHeight * tileSize
+
+	// Calculate available workspace excluding UI elements
+	workspaceWidth := int(m.window.width)
+	workspaceHeight := int(m.window.height) - m.uiState.menuBarHeight - m.uiState.statusBarHeight - TabBarHeight
+
+	// Center the grid in the available workspace
+	m.tileGrid.offset = beam.Position{
+		X: (workspaceWidth - totalGridWidth) / 2,
+		Y: (workspaceHeight-totalGridHeight)/2 + m.uiState.menuBarHeight,
+	}
+
+	m.tileGrid.viewportOffset.X = max(0, min(m.tileGrid.viewportOffset.X, m.tileGrid.Width-displayWidth))
+	m.tileGrid.viewportOffset.Y = max(0, min(m.tileGrid.viewportOffset.Y, m.tileGrid.Height-displayHeight))
+}
+
+// handleZoomAndPan zooms the grid around the cursor with the mouse wheel,
+// and pans the viewport while the middle mouse button is held.
+func (m *MapMaker) handleZoomAndPan() {
+	if m.isDialogOpen() {
+		m.uiState.isPanning = false
+		return
+	}
+
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+		if _, ok := m.mouseGridPos(); ok {
+			m.zoomAt(rl.GetMousePosition(), m.uiState.zoomLevel+wheel*ZoomStep)
+		}
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseMiddleButton) {
+		if _, ok := m.mouseGridPos(); ok {
+			m.uiState.isPanning = true
+			m.uiState.panRemainder = rl.Vector2{}
+		}
+	}
+	if !rl.IsMouseButtonDown(rl.MouseMiddleButton) {
+		m.uiState.isPanning = false
+	}
+	if m.uiState.isPanning {
+		tileSize := float32(m.renderTileSize())
+		m.uiState.panRemainder = rl.Vector2Add(m.uiState.panRemainder, rl.GetMouseDelta())
+		// Dragging right moves the view left, so the grid follows the mouse
+		tilesX := int(m.uiState.panRemainder.X / tileSize)
+		tilesY := int(m.uiState.panRemainder.Y / tileSize)
+		m.tileGrid.viewportOffset.X -= tilesX
+		m.tileGrid.viewportOffset.Y -= tilesY
+		m.uiState.panRemainder.X -= float32(tilesX) * tileSize
+		m.uiState.panRemainder.Y -= float32(tilesY) * tileSize
+		m.layoutGrid()
+	}
+}
+
+// zoomAt sets the zoom level, shifting the viewport so the tile under pos stays under it.
+func (m *MapMaker) zoomAt(pos rl.Vector2, zoom float32) {
+	zoom = max(MinZoomLevel, min(MaxZoomLevel, zoom))
+	if zoom == m.uiState.zoomLevel {
+		return
+	}
+
+	oldSize := float32(m.renderTileSize())
+	anchorX := (pos.X-float32(m.tileGrid.offset.X))/oldSize + float32(m.tileGrid.viewportOffset.X)
+	anchorY := (pos.Y-float32(m.tileGrid.offset.Y))/oldSize + float32(m.tileGrid.viewportOffset.Y)
+
+	m.uiState.zoomLevel = zoom
+	m.layoutGrid()
+
+	newSize := float32(m.renderTileSize())
+	m.tileGrid.viewportOffset.X = int(math.Round(float64(anchorX - (pos.X-float32(m.tileGrid.offset.X))/newSize)))
+	m.tileGrid.viewportOffset.Y = int(math.Round(float64(anchorY - (pos.Y-float32(m.tileGrid.offset.Y))/newSize)))
+	m.layoutGrid()
+}
+
 func (m *MapMaker) update() {
-	tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, closeMapBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn := m.getUIButtons()
+	tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, closeMapBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn := m.getUIButtons()
 
 	// Only handle UI interactions if no modal is blocking
 	if !m.isUIBlocked() {
 		m.handleResizeGrid(tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn)
+		m.handleGridSizeInput()
 		m.handleSaveLoadClose(saveBtn, loadBtn, closeMapBtn)
+		m.handleTabBar()
 		m.handleResourceViewer(viewResourcesBtn, loadResourceBtn)
-		m.handleMapTools(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn)
-
-		// Center the grid in the window
-		maxVisibleWidth := MaxDisplayWidth * DefaultTileSize / m.uiState.tileSize
-		maxVisibleHeight := MaxDisplayHeight * DefaultTileSize / m.uiState.tileSize
-		displayWidth := min(m.tileGrid.Width, maxVisibleWidth)
-		displayHeight := min(m.tileGrid.Height, maxVisibleHeight)
-		totalGridWidth := displayWidth * m.uiState.tileSize
-		totalGridHeight := displayHeight * m.uiState.tileSize
-
-		// Calculate available workspace excluding UI elements
-		workspaceWidth := int(m.window.width)
-		workspaceHeight := int(m.window.height) - m.uiState.menuBarHeight - m.uiState.statusBarHeight
-
-		// Center the grid in the available workspace
-		m.tileGrid.offset = beam.Position{
-			X: (workspaceWidth - totalGridWidth) / 2,
-			Y: (workspaceHeight-totalGridHeight)/2 + m.uiState.menuBarHeight,
+		m.handleMapTools(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn)
+		if m.isButtonClicked(m.validateButton()) && !m.isDialogOpen() {
+			m.validateMap()
+		}
+
+		// Toggle the brush ghost preview, unless a capital B is being typed
+		if rl.IsKeyPressed(rl.KeyB) && (rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)) && !m.isDialogOpen() && m.uiState.activeInput == "" {
+			m.uiState.showBrushGhost = !m.uiState.showBrushGhost
+			if m.uiState.showBrushGhost {
+				m.showToast("Brush preview enabled", ToastInfo)
+			} else {
+				m.showToast("Brush preview disabled", ToastInfo)
+			}
+		}
+
+		// Adjust the brush size, or with Shift and Alt the width and height of the texture span
+		if !m.isDialogOpen() && m.uiState.activeInput == "" {
+			shiftDown := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+			altDown := rl.IsKeyDown(rl.KeyLeftAlt) || rl.IsKeyDown(rl.KeyRightAlt)
+			step := 0
+			if rl.IsKeyPressed(rl.KeyLeftBracket) {
+				step = -1
+			} else if rl.IsKeyPressed(rl.KeyRightBracket) {
+				step = 1
+			}
+			switch {
+			case step == 0:
+			case shiftDown || altDown:
+				m.adjustTextureSpan(shiftDown, altDown, step)
+			case m.uiState.brushSize+step >= MinBrushSize && m.uiState.brushSize+step <= MaxBrushSize:
+				m.uiState.brushSize += step
+				m.showToast(fmt.Sprintf("Brush size: %dx%d", m.uiState.brushSize, m.uiState.brushSize), ToastInfo)
+			}
+
+			// Toggle autotiling, using the set of the active texture
+			if rl.IsKeyPressed(rl.KeyT) {
+				m.toggleAutotile()
+			}
+
+			// Fill the whole selection with the paint bucket
+			if rl.IsKeyPressed(rl.KeyF) && m.uiState.selectedTool == "paintbucket" {
+				m.fillSelection()
+			}
+
+			// Flip the selected rectangle, Shift+H mirrors it left to right and Shift+V top to bottom,
+			// and Shift+R turns a selected square a quarter turn clockwise
+			ctrlDown := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl) || rl.IsKeyDown(rl.KeyLeftSuper) || rl.IsKeyDown(rl.KeyRightSuper)
+			if shiftDown && !ctrlDown {
+				if rl.IsKeyPressed(rl.KeyH) {
+					m.flipSelection(true)
+				} else if rl.IsKeyPressed(rl.KeyV) {
+					m.flipSelection(false)
+				} else if rl.IsKeyPressed(rl.KeyR) {
+					m.rotateSelection()
+				}
+			}
 		}
 
+		// Center the grid in the window, then apply any zoom or pan
+		m.layoutGrid()
+		m.handleZoomAndPan()
+
 		// Handle tile selection - Handle the viewport offset
 		mousePos := rl.GetMousePosition()
-		gridX := int((mousePos.X-float32(m.tileGrid.offset.X))/float32(m.uiState.tileSize)) + m.tileGrid.viewportOffset.X
-		gridY := int((mousePos.Y-float32(m.tileGrid.offset.Y))/float32(m.uiState.tileSize)) + m.tileGrid.viewportOffset.Y
-
-		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		gridX, gridY := m.screenToGrid(mousePos)
+
+		if m.handleMinimapClick() {
+			// Clicks on the minimap move the viewport, rather than selecting tiles
+		} else if isShapeTool(m.uiState.selectedTool) {
+			m.handleShapeTool()
+		} else if m.uiState.selectedTool == "ruler" {
+			m.handleRulerTool()
+		} else if m.uiState.selectedTool == "select" {
+			m.handleRectSelect()
+		} else if m.uiState.selectedTool == "stamp" {
+			m.handleStampTool()
+		} else if m.uiState.selectedTool == "pastetexture" {
+			m.handlePasteTextureTool()
+		} else if m.uiState.propertiesEditor != nil || m.uiState.triggerEditor != nil ||
+			m.uiState.exportDialog != nil || m.uiState.missingResourcesDialog != nil {
+			// Keep the grid from taking clicks meant for the dialog
+		} else if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 			// Check if click is within grid bounds and below menu bar
 			if gridX >= 0 && gridX < m.tileGrid.Width &&
 				gridY >= 0 && gridY < m.tileGrid.Height &&
 				mousePos.Y > float32(m.uiState.menuBarHeight) {
-				if m.uiState.selectedTool == "paintbucket" || m.uiState.selectedTool == "selectall" {
+				shiftDown := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+				if m.uiState.selectedTool == "paintbucket" && shiftDown {
+					// Shift replaces the region right away, adding Alt replaces every matching tile on the map
+					if m.uiState.activeTexture == nil {
+						m.showToast("Select a texture to fill with", ToastError)
+					} else {
+						wholeMap := rl.IsKeyDown(rl.KeyLeftAlt) || rl.IsKeyDown(rl.KeyRightAlt)
+						replaced := m.bucketReplace(beam.Position{X: gridX, Y: gridY}, m.uiState.activeTexture.Name, wholeMap)
+						m.showToast(fmt.Sprintf("Replaced %d tiles", len(replaced)), ToastSuccess)
+					}
+					m.tileGrid.selectedTiles = beam.Positions{{X: gridX, Y: gridY}}
+				} else if m.uiState.selectedTool == "paintbucket" && len(m.tileGrid.selectedTiles) > 1 &&
+					slices.Contains(m.tileGrid.selectedTiles, beam.Position{X: gridX, Y: gridY}) {
+					// Clicking inside a selection fills all of it, rather than flood matching
+					m.fillSelection()
+				} else if m.uiState.selectedTool == "paintbucket" || m.uiState.selectedTool == "selectall" {
 					m.tileGrid.selectedTiles = m.floodFillSelection(gridX, gridY)
+				} else if isBrushTool(m.uiState.selectedTool) {
+					m.tileGrid.selectedTiles = m.brushFootprint(beam.Position{X: gridX, Y: gridY})
 				} else {
 					m.tileGrid.selectedTiles = beam.Positions{{X: gridX, Y: gridY}}
 				}
@@ -399,14 +654,20 @@ func (m *MapMaker) update() {
 				m.uiState.selectedTool == "eraser" ||
 				m.uiState.selectedTool == "pencileraser" ||
 				m.uiState.selectedTool == "layers" ||
-				(m.uiState.selectedTool == "location" && (m.uiState.locationMode == 1 || m.uiState.locationMode == 3)) {
+				m.uiState.selectedTool == "properties" ||
+				(m.uiState.selectedTool == "location" && (m.uiState.locationMode == 1 || m.uiState.locationMode == 3 || m.uiState.locationMode == triggerLocationMode)) {
 				if gridX >= 0 && gridX < m.tileGrid.Width &&
 					gridY >= 0 && gridY < m.tileGrid.Height &&
 					mousePos.Y > float32(m.uiState.menuBarHeight) {
-					newPos := beam.Position{X: gridX, Y: gridY}
-					alreadySelected := slices.Contains(m.tileGrid.selectedTiles, newPos)
-					if !alreadySelected {
-						m.tileGrid.selectedTiles = append(m.tileGrid.selectedTiles, newPos)
+					newPositions := beam.Positions{{X: gridX, Y: gridY}}
+					if isBrushTool(m.uiState.selectedTool) {
+						newPositions = m.brushFootprint(newPositions[0])
+					}
+					for _, newPos := range newPositions {
+						alreadySelected := slices.Contains(m.tileGrid.selectedTiles, newPos)
+						if !alreadySelected {
+							m.tileGrid.selectedTiles = append(m.tileGrid.selectedTiles, newPos)
+						}
 					}
 				}
 			}
@@ -433,41 +694,30 @@ func (m *MapMaker) update() {
 			if rl.IsMouseButtonPressed(rl.MouseButtonRight) {
 				switch m.uiState.selectedTool {
 				case "paintbrush", "paintbucket":
-					if m.uiState.activeTexture != nil {
+					if m.uiState.autotileSet != nil {
+						m.paintAutotile(m.tileGrid.selectedTiles)
+					} else if m.uiState.activeTexture != nil && m.uiState.selectedTool == "paintbrush" && m.textureSpanActive() {
+						m.paintSpans(m.tileGrid.selectedTiles, m.uiState.activeTexture.Name)
+					} else if m.uiState.activeTexture != nil {
+						m.paintTiles(m.tileGrid.selectedTiles, m.uiState.activeTexture.Name)
+					}
+				case "eraser":
+					affected := m.autotileAffected(m.tileGrid.selectedTiles)
+					m.recordTileChanges(affected, func() {
 						for _, pos := range m.tileGrid.selectedTiles {
 							selectedX := int(pos.X)
 							selectedY := int(pos.Y)
 							m.tileGrid.Tiles[selectedY][selectedX].Type = beam.FloorTile
-							m.tileGrid.Tiles[selectedY][selectedX].Textures = append(
-								m.tileGrid.Tiles[selectedY][selectedX].Textures,
-								beam.NewSimpleTileTexture(m.uiState.activeTexture.Name),
-							)
+							m.tileGrid.Tiles[selectedY][selectedX].Textures = nil
 						}
-					}
-				case "eraser":
-					for _, pos := range m.tileGrid.selectedTiles {
-						selectedX := int(pos.X)
-						selectedY := int(pos.Y)
-						m.tileGrid.Tiles[selectedY][selectedX].Type = beam.FloorTile
-						m.tileGrid.Tiles[selectedY][selectedX].Textures = nil
-					}
+						m.refreshAutotiles(affected)
+					})
 				case "pencileraser":
-					for _, pos := range m.tileGrid.selectedTiles {
-						selectedX := int(pos.X)
-						selectedY := int(pos.Y)
-						tile := &m.tileGrid.Tiles[selectedY][selectedX]
-						if len(tile.Textures) > 0 {
-							lastTexture := tile.Textures[len(tile.Textures)-1]
-							if lastTexture.IsAnimated && len(lastTexture.Frames) > 0 {
-								lastTexture.Frames = lastTexture.Frames[:len(lastTexture.Frames)-1]
-								if len(lastTexture.Frames) == 0 {
-									tile.Textures = tile.Textures[:len(tile.Textures)-1]
-								}
-							} else {
-								tile.Textures = tile.Textures[:len(tile.Textures)-1]
-							}
-						}
-					}
+					affected := m.autotileAffected(m.tileGrid.selectedTiles)
+					m.recordTileChanges(affected, func() {
+						m.eraseTopLayer()
+						m.refreshAutotiles(affected)
+					})
 				case "select":
 					if !m.showTileInfo {
 						// Only show if not already open
@@ -488,37 +738,53 @@ func (m *MapMaker) update() {
 						m.uiState.tileInfoPos = pos
 					}
 				case "layers":
-					for _, pos := range m.tileGrid.selectedTiles {
-						selectedX := int(pos.X)
-						selectedY := int(pos.Y)
-						tileType := beam.FloorTile
-						if m.uiState.hasSwappedLayers {
-							tileType = beam.WallTile
+					affected := m.autotileAffected(m.tileGrid.selectedTiles)
+					m.recordTileChanges(affected, func() {
+						for _, pos := range m.tileGrid.selectedTiles {
+							selectedX := int(pos.X)
+							selectedY := int(pos.Y)
+							tileType := beam.FloorTile
+							if m.uiState.hasSwappedLayers {
+								tileType = beam.WallTile
+							}
+							m.tileGrid.Tiles[selectedY][selectedX].Type = tileType
 						}
-						m.tileGrid.Tiles[selectedY][selectedX].Type = tileType
-					}
+						m.refreshAutotiles(affected)
+					})
 					break
 				case "location":
-					// Reset the list if were about to add new positions
-					if m.uiState.locationMode == 1 {
-						m.tileGrid.DungeonEntry = beam.Positions{}
-					} else if m.uiState.locationMode == 3 {
-						m.tileGrid.Exit = beam.Positions{}
+					if m.uiState.locationMode == triggerLocationMode {
+						if m.uiState.triggerEditor == nil {
+							m.openTriggerEditor(m.tileGrid.selectedTiles)
+						}
+						break
 					}
+					m.recordLocationChange(func() {
+						// Reset the list if were about to add new positions
+						if m.uiState.locationMode == 1 {
+							m.tileGrid.DungeonEntry = beam.Positions{}
+						} else if m.uiState.locationMode == 3 {
+							m.tileGrid.Exit = beam.Positions{}
+						}
 
-					for _, tile := range m.tileGrid.selectedTiles {
-						switch m.uiState.locationMode {
-						case 0:
-							m.tileGrid.Start = tile
-						case 1:
-							m.tileGrid.DungeonEntry = append(m.tileGrid.DungeonEntry, tile)
-						case 2:
-							m.tileGrid.Respawn = tile
-						case 3:
-							m.tileGrid.Exit = append(m.tileGrid.Exit, tile)
+						for _, tile := range m.tileGrid.selectedTiles {
+							switch m.uiState.locationMode {
+							case 0:
+								m.tileGrid.Start = tile
+							case 1:
+								m.tileGrid.DungeonEntry = append(m.tileGrid.DungeonEntry, tile)
+							case 2:
+								m.tileGrid.Respawn = tile
+							case 3:
+								m.tileGrid.Exit = append(m.tileGrid.Exit, tile)
+							}
 						}
-					}
+					})
 					break
+				case "properties":
+					if m.uiState.propertiesEditor == nil {
+						m.openPropertiesEditor(m.tileGrid.selectedTiles)
+					}
 				case "npc":
 					// Initialize NPC editor
 					if m.uiState.npcEditor == nil || !m.uiState.npcEditor.visible {
@@ -545,6 +811,8 @@ func (m *MapMaker) update() {
 									IsAnimated: true,
 								},
 							},
+							idleTextures:           completeNPCTexture(nil),
+							attackTextures:         completeNPCTexture(nil),
 							health:                 "100",
 							attack:                 "10",
 							defense:                "5",
@@ -610,8 +878,9 @@ func (m *MapMaker) update() {
 }
 
 // handleMapTools handles the selecting and swapping of tools
-func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconButton, eraseBtn IconButton, selectBtn IconButton, layersBtn IconButton, locationBtn IconButton, gridlinesBtn IconButton, npcBtn IconButton, itemsBtn IconButton) {
-	if m.isIconButtonClicked(paintbrushBtn) {
+func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconButton, eraseBtn IconButton, selectBtn IconButton, layersBtn IconButton, locationBtn IconButton, gridlinesBtn IconButton, npcBtn IconButton, itemsBtn IconButton, rectBtn IconButton, lineBtn IconButton, rulerBtn IconButton, propertiesBtn IconButton, stampBtn IconButton) {
+	shortcut := m.toolShortcut()
+	if m.isIconButtonClicked(paintbrushBtn) || shortcut == "paintbrush" {
 		if m.uiState.selectedTool == "paintbrush" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -619,15 +888,15 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast("Paintbrush tool selected", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(paintbucketBtn) {
+	if m.isIconButtonClicked(paintbucketBtn) || shortcut == "paintbucket" {
 		if m.uiState.selectedTool == "paintbucket" {
 			m.uiState.selectedTool = ""
 		} else {
 			m.uiState.selectedTool = "paintbucket"
-			m.showToast("Paint bucket tool selected", ToastInfo)
+			m.showToast("Paint bucket selected, Shift+click to fill, F fills the selection", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(eraseBtn) {
+	if m.isIconButtonClicked(eraseBtn) || shortcut == "eraser" {
 		if m.uiState.selectedTool == "eraser" || m.uiState.selectedTool == "pencileraser" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -639,7 +908,7 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast(name+" tool selected", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(selectBtn) {
+	if m.isIconButtonClicked(selectBtn) || shortcut == "select" {
 		if m.uiState.selectedTool == "select" || m.uiState.selectedTool == "selectall" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -653,7 +922,7 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast(name+" tool selected", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(layersBtn) {
+	if m.isIconButtonClicked(layersBtn) || shortcut == "layers" {
 		if m.uiState.selectedTool == "layers" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -661,7 +930,7 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast("Layers tool selected", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(locationBtn) {
+	if m.isIconButtonClicked(locationBtn) || shortcut == "location" {
 		if m.uiState.selectedTool == "location" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -673,7 +942,7 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 		m.uiState.showGridlines = !m.uiState.showGridlines
 		m.showToast("Gridlines tool selected", ToastInfo)
 	}
-	if m.isIconButtonClicked(npcBtn) {
+	if m.isIconButtonClicked(npcBtn) || shortcut == "npc" {
 		if m.uiState.selectedTool == "npc" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -681,7 +950,7 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast("NPC Editor tool selected", ToastInfo)
 		}
 	}
-	if m.isIconButtonClicked(itemsBtn) {
+	if m.isIconButtonClicked(itemsBtn) || shortcut == "items" {
 		if m.uiState.selectedTool == "items" {
 			m.uiState.selectedTool = ""
 		} else {
@@ -689,6 +958,45 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 			m.showToast("Items Editor tool selected", ToastInfo)
 		}
 	}
+	if m.isIconButtonClicked(rectBtn) || shortcut == "rect" {
+		if m.uiState.selectedTool == "rect" {
+			m.uiState.selectedTool = ""
+		} else {
+			m.uiState.selectedTool = "rect"
+			m.showToast("Rectangle tool selected, hold Shift to fill", ToastInfo)
+		}
+	}
+	if m.isIconButtonClicked(lineBtn) || shortcut == "line" {
+		if m.uiState.selectedTool == "line" {
+			m.uiState.selectedTool = ""
+		} else {
+			m.uiState.selectedTool = "line"
+			m.showToast("Line tool selected", ToastInfo)
+		}
+	}
+	if m.isIconButtonClicked(rulerBtn) || shortcut == "ruler" {
+		if m.uiState.selectedTool == "ruler" {
+			m.uiState.selectedTool = ""
+		} else {
+			m.uiState.selectedTool = "ruler"
+			m.showToast("Ruler selected, drag to measure distances", ToastInfo)
+		}
+	}
+	if m.isIconButtonClicked(propertiesBtn) || shortcut == "properties" {
+		if m.uiState.selectedTool == "properties" {
+			m.uiState.selectedTool = ""
+		} else {
+			m.uiState.selectedTool = "properties"
+			m.showToast("Properties tool selected, right click to edit tile properties", ToastInfo)
+		}
+	}
+	if m.isIconButtonClicked(stampBtn) || shortcut == "stamp" {
+		if m.uiState.selectedTool == "stamp" {
+			m.uiState.selectedTool = ""
+		} else {
+			m.selectStampTool()
+		}
+	}
 
 	// Handle tool swaps
 	if rl.IsMouseButtonDown(rl.MouseButtonRight) {
@@ -730,9 +1038,8 @@ func (m *MapMaker) handleMapTools(paintbrushBtn IconButton, paintbucketBtn IconB
 
 			// Handle location swap
 			if m.uiState.selectedTool == "location" {
-				m.uiState.locationMode = (m.uiState.locationMode + 1) % 4
-				modeNames := []string{"Player Start", "Dungeon Entrance", "Respawn", "Exit"}
-				m.showToast(fmt.Sprintf("Location Mode: %s", modeNames[m.uiState.locationMode]), ToastInfo)
+				m.uiState.locationMode = (m.uiState.locationMode + 1) % len(locationModeNames)
+				m.showToast(fmt.Sprintf("Location Mode: %s", locationModeNames[m.uiState.locationMode]), ToastInfo)
 			}
 
 			// Handle NPC list view swap
@@ -784,7 +1091,8 @@ func (m *MapMaker) handleSaveLoadClose(saveBtn, loadBtn, closeMapBtn IconButton)
 		} else {
 			filename := openSaveDialog()
 			if filename != "" {
-				if !strings.HasSuffix(filename, ".json") {
+				// Keep .json.gz, to save the map gzipped
+				if !strings.HasSuffix(filename, ".json") && !strings.HasSuffix(filename, ".json.gz") {
 					filename += ".json"
 				}
 				if err := m.SaveMap(filename); err != nil {
@@ -798,7 +1106,7 @@ func (m *MapMaker) handleSaveLoadClose(saveBtn, loadBtn, closeMapBtn IconButton)
 	if m.isIconButtonClicked(loadBtn) {
 		filename := openLoadDialog()
 		if filename != "" {
-			if err := m.LoadMap(filename); err != nil {
+			if err := m.openMap(filename); err != nil {
 				m.showToast("Error loading map: "+err.Error(), ToastError)
 			} else {
 				m.showToast("Map loaded successfully!", ToastSuccess)
@@ -807,21 +1115,9 @@ func (m *MapMaker) handleSaveLoadClose(saveBtn, loadBtn, closeMapBtn IconButton)
 	}
 	if m.isIconButtonClicked(closeMapBtn) {
 		if openCloseConfirmationDialog() {
-			// Reset to default state
-			m.uiState.tileSize = DefaultTileSize
-			m.uiState.gridWidth = DefaultGridWidth
-			m.uiState.gridHeight = DefaultGridHeight
-			m.tileGrid.Map.NPCs = beam.NPCs{}
-			m.tileGrid.Map.Items = beam.Items{}
-
 			m.showResourceViewer = false
 			m.uiState.resourceViewerScroll = 0
-			m.currentFile = ""
-			rl.SetWindowTitle(m.window.title)
-
-			// Reset grid
-			m.updateGridSize()
-			m.initTileGrid()
+			m.closeActiveDocument()
 		}
 	}
 }
@@ -844,28 +1140,28 @@ func (m *MapMaker) handleResizeGrid(tileSmallerBtn Button, tileLargerBtn Button,
 	}
 
 	if m.isButtonClicked(widthSmallerBtn) {
-		if m.uiState.gridWidth > 10 {
+		if m.uiState.gridWidth > MinGridSize {
 			m.uiState.gridWidth--
 			m.updateGridSize()
 			m.resizeGrid()
 		}
 	}
 	if m.isButtonClicked(widthLargerBtn) {
-		if m.uiState.gridWidth < 100 {
+		if m.uiState.gridWidth < MaxGridSize {
 			m.uiState.gridWidth++
 			m.updateGridSize()
 			m.resizeGrid()
 		}
 	}
 	if m.isButtonClicked(heightSmallerBtn) {
-		if m.uiState.gridHeight > 10 {
+		if m.uiState.gridHeight > MinGridSize {
 			m.uiState.gridHeight--
 			m.updateGridSize()
 			m.resizeGrid()
 		}
 	}
 	if m.isButtonClicked(heightLargerBtn) {
-		if m.uiState.gridHeight < 100 {
+		if m.uiState.gridHeight < MaxGridSize {
 			m.uiState.gridHeight++
 			m.updateGridSize()
 			m.resizeGrid()
@@ -873,26 +1169,49 @@ func (m *MapMaker) handleResizeGrid(tileSmallerBtn Button, tileLargerBtn Button,
 	}
 }
 
-// resizeGrid resizes the grid its current dimensions
+// resizeGrid resizes the grid to its current dimensions, keeping the top left in place.
+// NPCs, items and locations that no longer fit are dropped, see beam.Map.Resize.
+// The resize is one undo step, which puts back everything it cut off.
 func (m *MapMaker) resizeGrid() {
-	newTiles := make([][]beam.Tile, m.tileGrid.Height)
-	for i := range newTiles {
-		newTiles[i] = make([]beam.Tile, m.tileGrid.Width)
+	oldHeight := len(m.tileGrid.Tiles)
+	oldWidth := 0
+	if oldHeight > 0 {
+		oldWidth = len(m.tileGrid.Tiles[0])
+	}
+	width, height := m.tileGrid.Width, m.tileGrid.Height
+	if width == oldWidth && height == oldHeight {
+		return
 	}
 
-	// Copy existing tiles
-	for y := 0; y < min(len(m.tileGrid.Tiles), m.tileGrid.Height); y++ {
-		for x := 0; x < min(len(m.tileGrid.Tiles[y]), m.tileGrid.Width); x++ {
-			newTiles[y][x] = m.tileGrid.Tiles[y][x]
-			newTiles[y][x].Pos = beam.Position{X: x, Y: y}
+	action := &ResizeAction{
+		grid:      m.tileGrid,
+		OldWidth:  oldWidth,
+		OldHeight: oldHeight,
+		NewWidth:  width,
+		NewHeight: height,
+		Before:    m.tileGrid.contents(),
+	}
+	for y, row := range m.tileGrid.Tiles {
+		for x, tile := range row {
+			if x >= width || y >= height {
+				action.Dropped = append(action.Dropped, TileChange{Pos: beam.Position{X: x, Y: y}, Before: copyTile(tile)})
+			}
 		}
 	}
-
-	m.tileGrid.Tiles = newTiles
+	if err := m.tileGrid.Resize(width, height, beam.AnchorTopLeft); err != nil {
+		m.showToast("Can't resize the grid: "+err.Error(), ToastError)
+		return
+	}
+	m.tileGrid.minimapDirty = true
+	action.After = m.tileGrid.contents()
+	m.history.Push(action)
+	m.markDirty()
 }
 
 // initTileGrid initializes the tile grid with default values
 func (m *MapMaker) initTileGrid() {
+	m.history.Clear()
+	m.tileGrid.minimapDirty = true
 	m.tileGrid.Tiles = make([][]beam.Tile, m.tileGrid.Height)
 	for i := range m.tileGrid.Tiles {
 		m.tileGrid.Tiles[i] = make([]beam.Tile, m.tileGrid.Width)
@@ -944,7 +1263,7 @@ func (m *MapMaker) loadResource(name string, filepath string, isSheet bool, shee
 	return nil
 }
 
-func (m *MapMaker) getUIButtons() (tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn Button, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, closeMapBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsButton IconButton) {
+func (m *MapMaker) getUIButtons() (tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn Button, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, closeMapBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsButton, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn IconButton) {
 	widthSmallerBtn = m.NewButton(10, 8, 30, 20, "-")
 	widthLargerBtn = m.NewButton(85, 8, 30, 20, "+")
 	heightSmallerBtn = m.NewButton(10, 33, 30, 20, "-")
@@ -1063,15 +1382,7 @@ func (m *MapMaker) getUIButtons() (tileSmallerBtn, tileLargerBtn, widthSmallerBt
 		layersText,
 	)
 
-	locationTooltip := "Player Start"
-	switch m.uiState.locationMode {
-	case 1:
-		locationTooltip = "Dungeon Entrance"
-	case 2:
-		locationTooltip = "Respawn"
-	case 3:
-		locationTooltip = "Exit"
-	}
+	locationTooltip := locationModeNames[m.uiState.locationMode]
 	locationBtn = m.NewIconButton(
 		420,
 		15,
@@ -1112,6 +1423,56 @@ func (m *MapMaker) getUIButtons() (tileSmallerBtn, tileLargerBtn, widthSmallerBt
 		"Item Editor",
 	)
 
+	rectBtn = m.NewIconButton(
+		620,
+		15,
+		40,
+		30,
+		m.uiState.uiTextures["rect"],
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.uiState.uiTextures["rect"].Width), Height: float32(m.uiState.uiTextures["rect"].Height)},
+		"Rectangle",
+	)
+
+	lineBtn = m.NewIconButton(
+		670,
+		15,
+		40,
+		30,
+		m.uiState.uiTextures["line"],
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.uiState.uiTextures["line"].Width), Height: float32(m.uiState.uiTextures["line"].Height)},
+		"Line",
+	)
+
+	rulerBtn = m.NewIconButton(
+		720,
+		15,
+		40,
+		30,
+		m.uiState.uiTextures["ruler"],
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.uiState.uiTextures["ruler"].Width), Height: float32(m.uiState.uiTextures["ruler"].Height)},
+		"Ruler",
+	)
+
+	propertiesBtn = m.NewIconButton(
+		770,
+		15,
+		40,
+		30,
+		m.uiState.uiTextures["properties"],
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.uiState.uiTextures["properties"].Width), Height: float32(m.uiState.uiTextures["properties"].Height)},
+		"Properties",
+	)
+
+	stampBtn = m.NewIconButton(
+		820,
+		15,
+		40,
+		30,
+		m.uiState.uiTextures["stamp"],
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.uiState.uiTextures["stamp"].Width), Height: float32(m.uiState.uiTextures["stamp"].Height)},
+		"Stamp",
+	)
+
 	return
 }
 
@@ -1136,18 +1497,8 @@ func (m *MapMaker) handleTextureSelect(texInfo *resources.TextureInfo) {
 		frameCount, _ := strconv.Atoi(editor.frameCountStr)
 		if frameCount > 0 && editor.advSelectingFrameIndex >= 0 {
 			selectedFrame := editor.advSelectingFrameIndex
-			// Get the current direction's texture
-			var currentTex *beam.AnimatedTexture
-			switch editor.editingDirection {
-			case beam.DirUp:
-				currentTex = editor.textures.Up
-			case beam.DirDown:
-				currentTex = editor.textures.Down
-			case beam.DirLeft:
-				currentTex = editor.textures.Left
-			case beam.DirRight:
-				currentTex = editor.textures.Right
-			}
+			// Get the current direction's texture, in the set being edited
+			currentTex := editor.currentTexture()
 			animationTime, _ := strconv.ParseFloat(editor.animationTimeStr, 64)
 			currentTex.AnimationTime = animationTime
 
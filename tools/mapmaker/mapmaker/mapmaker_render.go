@@ -2,10 +2,13 @@ package mapmaker
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/ztkent/beam"
+	"github.com/ztkent/beam/resources"
 )
 
 func (m *MapMaker) renderGrid() {
@@ -13,8 +16,8 @@ func (m *MapMaker) renderGrid() {
 	startY := m.tileGrid.offset.Y
 
 	// Calculate max visible tiles based on default size to maintain consistent viewport size
-	maxVisibleWidth := MaxDisplayWidth * DefaultTileSize / m.uiState.tileSize
-	maxVisibleHeight := MaxDisplayHeight * DefaultTileSize / m.uiState.tileSize
+	tileSize := m.renderTileSize()
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
 
 	// Calculate visible range based on viewport and adjusted max dimensions
 	viewStartX := m.tileGrid.viewportOffset.X
@@ -28,64 +31,121 @@ func (m *MapMaker) renderGrid() {
 
 	// Draw horizontal grid lines
 	for i := 0; i <= visibleWidth; i++ {
-		x := startX + i*m.uiState.tileSize
-		rl.DrawLine(int32(x), int32(startY), int32(x), int32(startY+visibleHeight*m.uiState.tileSize), rl.LightGray)
+		x := startX + i*tileSize
+		rl.DrawLine(int32(x), int32(startY), int32(x), int32(startY+visibleHeight*tileSize), rl.LightGray)
 	}
 
 	// Draw vertical grid lines
 	for i := 0; i <= visibleHeight; i++ {
-		y := startY + i*m.uiState.tileSize
-		rl.DrawLine(int32(startX), int32(y), int32(startX+visibleWidth*m.uiState.tileSize), int32(y), rl.LightGray)
+		y := startY + i*tileSize
+		rl.DrawLine(int32(startX), int32(y), int32(startX+visibleWidth*tileSize), int32(y), rl.LightGray)
 	}
 
-	// Draw grid tiles within viewport
+	// Draw grid tiles within viewport, a layer at a time
+	tileRect := func(x, y int) rl.Rectangle {
+		return rl.Rectangle{
+			X:      float32(startX + (x-viewStartX)*tileSize),
+			Y:      float32(startY + (y-viewStartY)*tileSize),
+			Width:  float32(tileSize),
+			Height: float32(tileSize),
+		}
+	}
 	for _, layer := range beam.OrderedLayers() {
+		if !m.uiState.layerVisibility.Visible(layer) {
+			continue
+		}
+
+		// Batched, so tiles sharing a texture are drawn together
+		m.batch.Begin()
 		for y := viewStartY; y < viewEndY; y++ {
 			for x := viewStartX; x < viewEndX; x++ {
-				// Calculate screen position for this tile
-				screenX := startX + (x-viewStartX)*m.uiState.tileSize
-				screenY := startY + (y-viewStartY)*m.uiState.tileSize
-
-				pos := rl.Rectangle{
-					X:      float32(screenX),
-					Y:      float32(screenY),
-					Width:  float32(m.uiState.tileSize),
-					Height: float32(m.uiState.tileSize),
-				}
+				m.renderTileTextures(tileRect(x, y), beam.Position{X: x, Y: y}, m.tileGrid.Tiles[y][x], layer, false)
+			}
+		}
+		m.batch.End()
 
-				// Render tile at this location
-				tile := m.tileGrid.Tiles[y][x]
-				m.renderGridTile(pos, beam.Position{X: x, Y: y}, tile, layer)
-
-				// Draw any NPC's on the map
-				for _, npc := range m.tileGrid.NPCs {
-					if npc.Pos.X == x && npc.Pos.Y == y {
-						npcX := startX + (x-viewStartX)*m.uiState.tileSize
-						npcY := startY + (y-viewStartY)*m.uiState.tileSize
-						m.resources.RenderNPC(npc, rl.Rectangle{
-							X:      float32(npcX),
-							Y:      float32(npcY),
-							Width:  float32(m.uiState.tileSize),
-							Height: float32(m.uiState.tileSize),
-						}, m.uiState.tileSize)
-					}
-				}
+		for y := viewStartY; y < viewEndY; y++ {
+			for x := viewStartX; x < viewEndX; x++ {
+				m.renderTileOutlines(tileRect(x, y), beam.Position{X: x, Y: y}, m.tileGrid.Tiles[y][x])
+			}
+		}
 
-				// Draw any items on the map
-				for _, item := range m.tileGrid.Items {
-					itemX := startX + (item.Pos.X-viewStartX)*m.uiState.tileSize
-					itemY := startY + (item.Pos.Y-viewStartY)*m.uiState.tileSize
-					m.resources.RenderItem(item, rl.Rectangle{
-						X:      float32(itemX),
-						Y:      float32(itemY),
-						Width:  float32(m.uiState.tileSize) * .75,
-						Height: float32(m.uiState.tileSize) * .75,
-					}, m.uiState.tileSize)
-				}
+		// Then the textures spanning several tiles, over the tiles they cover. Spans anchored up to
+		// MaxTextureSpan-1 tiles above or left of the view still cover it, and are clipped to the grid
+		rl.BeginScissorMode(int32(startX), int32(startY), int32(visibleWidth*tileSize), int32(visibleHeight*tileSize))
+		for y := max(0, viewStartY-(beam.MaxTextureSpan-1)); y < viewEndY; y++ {
+			for x := max(0, viewStartX-(beam.MaxTextureSpan-1)); x < viewEndX; x++ {
+				m.renderGridSpans(tileRect(x, y), beam.Position{X: x, Y: y}, m.tileGrid.Tiles[y][x], layer)
+			}
+		}
+		rl.EndScissorMode()
+	}
+
+	// Draw the NPCs and items over the tiles, back to front so taller sprites overlap correctly.
+	// NPCs larger than 1x1 cover several tiles
+	for _, entity := range m.tileGrid.RenderOrder() {
+		switch entity := entity.(type) {
+		case *beam.NPC:
+			if entity.Pos.X < viewStartX || entity.Pos.X >= viewEndX || entity.Pos.Y < viewStartY || entity.Pos.Y >= viewEndY {
+				continue
+			}
+			width, height := entity.Data.Size.GetDimensions()
+			footprint := rl.Rectangle{
+				X:      float32(startX + (entity.Pos.X-viewStartX)*tileSize),
+				Y:      float32(startY + (entity.Pos.Y-viewStartY)*tileSize),
+				Width:  float32(width * tileSize),
+				Height: float32(height * tileSize),
+			}
+			m.resources.RenderNPC(entity, footprint, tileSize)
+			if width > 1 || height > 1 {
+				rl.DrawRectangleLinesEx(footprint, 1, rl.Fade(rl.Purple, 0.6))
+			}
+		case *beam.Item:
+			if entity.Pos.X < viewStartX || entity.Pos.X >= viewEndX || entity.Pos.Y < viewStartY || entity.Pos.Y >= viewEndY {
+				continue
+			}
+			m.resources.RenderItem(entity, rl.Rectangle{
+				X:      float32(startX + (entity.Pos.X-viewStartX)*tileSize),
+				Y:      float32(startY + (entity.Pos.Y-viewStartY)*tileSize),
+				Width:  float32(tileSize) * .75,
+				Height: float32(tileSize) * .75,
+			}, tileSize)
+		}
+	}
+
+	// Highlight validation issues, and the area any unreachable objectives are cut off in
+	for _, issue := range m.tileGrid.validationIssues {
+		outline := rl.Red
+		if issue.Severity == beam.SeverityWarning {
+			outline = rl.Orange
+		}
+		for _, pos := range issue.Region {
+			if pos.X >= viewStartX && pos.X < viewEndX && pos.Y >= viewStartY && pos.Y < viewEndY {
+				rl.DrawRectangle(
+					int32(startX+(pos.X-viewStartX)*tileSize),
+					int32(startY+(pos.Y-viewStartY)*tileSize),
+					int32(tileSize), int32(tileSize),
+					rl.Fade(rl.Red, 0.2))
 			}
 		}
+		if issue.Pos.X >= viewStartX && issue.Pos.X < viewEndX && issue.Pos.Y >= viewStartY && issue.Pos.Y < viewEndY {
+			rl.DrawRectangleLinesEx(rl.Rectangle{
+				X:      float32(startX + (issue.Pos.X-viewStartX)*tileSize),
+				Y:      float32(startY + (issue.Pos.Y-viewStartY)*tileSize),
+				Width:  float32(tileSize),
+				Height: float32(tileSize),
+			}, 3, outline)
+		}
 	}
 
+	// Preview the active texture under the cursor
+	m.renderBrushGhost(viewStartX, viewStartY, viewEndX, viewEndY)
+	m.renderStampGhost(viewStartX, viewStartY, viewEndX, viewEndY)
+	m.renderPasteTextureGhost(viewStartX, viewStartY, viewEndX, viewEndY)
+
+	// Measure distances with the ruler
+	m.renderRuler(viewStartX, viewStartY)
+
 	// Draw viewport controls if any part of the grid is not visible
 	if m.tileGrid.Width > maxVisibleWidth || m.tileGrid.Height > maxVisibleHeight {
 		m.renderViewportControls()
@@ -96,8 +156,8 @@ func (m *MapMaker) renderGrid() {
 		for _, tile := range m.tileGrid.selectedTiles {
 			// Only draw highlight if tile is in viewport
 			if tile.X >= viewStartX && tile.X < viewEndX && tile.Y >= viewStartY && tile.Y < viewEndY {
-				highlightX := startX + (tile.X-viewStartX)*m.uiState.tileSize
-				highlightY := startY + (tile.Y-viewStartY)*m.uiState.tileSize
+				highlightX := startX + (tile.X-viewStartX)*tileSize
+				highlightY := startY + (tile.Y-viewStartY)*tileSize
 
 				// Highlight red if its an eraser
 				color := rl.Black
@@ -108,21 +168,140 @@ func (m *MapMaker) renderGrid() {
 				rl.DrawRectangleLinesEx(rl.Rectangle{
 					X:      float32(highlightX),
 					Y:      float32(highlightY),
-					Width:  float32(m.uiState.tileSize),
-					Height: float32(m.uiState.tileSize),
+					Width:  float32(tileSize),
+					Height: float32(tileSize),
 				}, 2, color)
 			}
 		}
 	}
 
+	// Preview the rect or line being dragged out
+	if m.uiState.isDrawingShape {
+		for _, tile := range m.shapePositions(m.uiState.shapeStart, m.shapeEnd()) {
+			if tile.X >= viewStartX && tile.X < viewEndX && tile.Y >= viewStartY && tile.Y < viewEndY {
+				rl.DrawRectangleLinesEx(rl.Rectangle{
+					X:      float32(startX + (tile.X-viewStartX)*tileSize),
+					Y:      float32(startY + (tile.Y-viewStartY)*tileSize),
+					Width:  float32(tileSize),
+					Height: float32(tileSize),
+				}, 2, rl.Blue)
+			}
+		}
+	}
+
+	// Outline the rectangle being dragged out with the select tool
+	if m.uiState.isRectSelecting {
+		topLeft, bottomRight, ok := visibleRect(m.uiState.selectStart, m.shapeEnd(),
+			beam.Position{X: viewStartX, Y: viewStartY}, beam.Position{X: viewEndX, Y: viewEndY})
+		if ok {
+			rect := rl.Rectangle{
+				X:      float32(startX + (topLeft.X-viewStartX)*tileSize),
+				Y:      float32(startY + (topLeft.Y-viewStartY)*tileSize),
+				Width:  float32((bottomRight.X - topLeft.X + 1) * tileSize),
+				Height: float32((bottomRight.Y - topLeft.Y + 1) * tileSize),
+			}
+			rl.DrawRectangleRec(rect, rl.Fade(rl.Blue, 0.15))
+			rl.DrawRectangleLinesEx(rect, 2, rl.Blue)
+		}
+	}
+
+	// Preview the brush footprint under the cursor
+	if isBrushTool(m.uiState.selectedTool) && !m.isDialogOpen() {
+		if hovered, ok := m.mouseGridPos(); ok {
+			color := rl.Fade(rl.Black, 0.4)
+			if m.uiState.selectedTool == "eraser" || m.uiState.selectedTool == "pencileraser" {
+				color = rl.Fade(rl.Red, 0.4)
+			}
+			for _, tile := range m.brushFootprint(hovered) {
+				if tile.X >= viewStartX && tile.X < viewEndX && tile.Y >= viewStartY && tile.Y < viewEndY {
+					rl.DrawRectangleLinesEx(rl.Rectangle{
+						X:      float32(startX + (tile.X-viewStartX)*tileSize),
+						Y:      float32(startY + (tile.Y-viewStartY)*tileSize),
+						Width:  float32(tileSize),
+						Height: float32(tileSize),
+					}, 1, color)
+				}
+			}
+		}
+	}
+
 	// Draw grid dimensions in bottom right
 	dimensions := fmt.Sprintf("%dx%d", m.tileGrid.Width, m.tileGrid.Height)
 	textWidth := int(rl.MeasureText(dimensions, 20))
-	textX := startX + visibleWidth*m.uiState.tileSize - textWidth
-	textY := startY + visibleHeight*m.uiState.tileSize + 5
+	textX := startX + visibleWidth*tileSize - textWidth
+	textY := startY + visibleHeight*tileSize + 5
 	rl.DrawText(dimensions, int32(textX), int32(textY), 20, rl.DarkGray)
 }
 
+// renderBrushGhost draws a translucent copy of the active texture on the hovered tiles,
+// so placement can be previewed before painting.
+func (m *MapMaker) renderBrushGhost(viewStartX, viewStartY, viewEndX, viewEndY int) {
+	if !m.uiState.showBrushGhost || m.uiState.activeTexture == nil || m.isDialogOpen() {
+		return
+	} else if m.uiState.selectedTool != "paintbrush" && m.uiState.selectedTool != "paintbucket" {
+		return
+	}
+
+	hovered, ok := m.mouseGridPos()
+	if !ok {
+		return
+	}
+
+	// Use the same defaults a painted tile will get
+	frame := beam.NewSimpleTileTexture(m.uiState.activeTexture.Name).Frames[0]
+	tileSize := float32(m.renderTileSize())
+
+	// A spanning texture is previewed over its whole span, red if it can't be placed there
+	if m.uiState.selectedTool == "paintbrush" && m.textureSpanActive() {
+		w, h := max(1, m.uiState.textureSpanX), max(1, m.uiState.textureSpanY)
+		span := rl.Rectangle{
+			X:      float32(m.tileGrid.offset.X) + float32(hovered.X-viewStartX)*tileSize,
+			Y:      float32(m.tileGrid.offset.Y) + float32(hovered.Y-viewStartY)*tileSize,
+			Width:  float32(w) * tileSize,
+			Height: float32(h) * tileSize,
+		}
+		if info, err := m.resources.GetTexture("default", frame.Name); err == nil {
+			rl.DrawTexturePro(info.Texture, info.Region, span, rl.Vector2{}, 0, rl.Fade(frame.Tint, 0.5))
+		}
+		outline := rl.Green
+		if m.tileGrid.CanPlaceSpan(hovered, w, h) != nil {
+			outline = rl.Red
+		}
+		rl.DrawRectangleLinesEx(span, 2, rl.Fade(outline, 0.8))
+		return
+	}
+
+	footprint := beam.Positions{hovered}
+	if isBrushTool(m.uiState.selectedTool) {
+		footprint = m.brushFootprint(hovered)
+	}
+	for _, pos := range footprint {
+		if pos.X < viewStartX || pos.X >= viewEndX || pos.Y < viewStartY || pos.Y >= viewEndY {
+			continue
+		}
+		screenX := float32(m.tileGrid.offset.X) + float32(pos.X-viewStartX)*tileSize
+		screenY := float32(m.tileGrid.offset.Y) + float32(pos.Y-viewStartY)*tileSize
+		m.drawGhostFrame(frame, screenX, screenY, tileSize)
+		rl.DrawRectangleLinesEx(rl.Rectangle{X: screenX, Y: screenY, Width: tileSize, Height: tileSize}, 1, rl.Fade(rl.DarkGray, 0.5))
+	}
+}
+
+// drawGhostFrame draws a translucent copy of a texture frame on the tile at screenX, screenY.
+func (m *MapMaker) drawGhostFrame(frame beam.Texture, screenX, screenY, tileSize float32) {
+	info, err := m.resources.GetTexture("default", frame.Name)
+	if err != nil {
+		return
+	}
+	origin := rl.Vector2{X: tileSize / 2, Y: tileSize / 2}
+	destRect := rl.Rectangle{
+		X:      screenX + tileSize/2 + float32(frame.OffsetX)*tileSize,
+		Y:      screenY + tileSize/2 + float32(frame.OffsetY)*tileSize,
+		Width:  tileSize * float32(frame.ScaleX),
+		Height: tileSize * float32(frame.ScaleY),
+	}
+	rl.DrawTexturePro(info.Texture, info.Region, destRect, origin, float32(frame.Rotation), rl.Fade(frame.Tint, 0.5))
+}
+
 func (m *MapMaker) renderViewportControls() {
 	btnSize := int32(24)
 	gutterPadding := int32(15)
@@ -130,10 +309,9 @@ func (m *MapMaker) renderViewportControls() {
 	verticalOffset := int(35)
 
 	baseX := int32(gutterPadding)
-	baseY := int32(m.tileGrid.offset.Y + (m.tileGrid.viewportHeight*m.uiState.tileSize)/2 + verticalOffset)
+	baseY := int32(m.tileGrid.offset.Y + (m.tileGrid.viewportHeight*m.renderTileSize())/2 + verticalOffset)
 
-	maxVisibleWidth := MaxDisplayWidth * DefaultTileSize / m.uiState.tileSize
-	maxVisibleHeight := MaxDisplayHeight * DefaultTileSize / m.uiState.tileSize
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
 
 	remainingUp := m.tileGrid.viewportOffset.Y
 	remainingDown := m.tileGrid.Height - (m.tileGrid.viewportOffset.Y + maxVisibleHeight)
@@ -225,18 +403,30 @@ func (m *MapMaker) renderViewportControls() {
 	}
 }
 
-func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile beam.Tile, layer beam.Layer) {
-	if len(tile.Textures) == 0 {
-		return
-	}
+// renderGridSpans draws the tile's spanning textures on the layer. They're drawn after every
+// other tile on the layer, so the tiles they cover don't draw over them.
+func (m *MapMaker) renderGridSpans(pos rl.Rectangle, pos2d beam.Position, tile beam.Tile, layer beam.Layer) {
+	m.renderTileTextures(pos, pos2d, tile, layer, true)
+}
 
+// renderTileTextures draws the tile's textures on the layer, either the spanning ones or the rest.
+// Between m.batch.Begin and End the frames are batched, each frame stacked at a greater depth.
+func (m *MapMaker) renderTileTextures(pos rl.Rectangle, pos2d beam.Position, tile beam.Tile, layer beam.Layer, spanning bool) {
+	depth := 0
 	for _, tex := range tile.Textures {
 		if len(tex.Frames) == 0 {
 			continue
-		} else if tex.Layer != layer {
+		} else if tex.Layer != layer || tex.IsSpanning() != spanning {
 			continue
 		}
 
+		// Spanning textures are drawn over every tile they cover, from the anchor
+		dest := pos
+		if w, h := tex.Span(); w > 1 || h > 1 {
+			dest.Width *= float32(w)
+			dest.Height *= float32(h)
+		}
+
 		// If the texture isn't complex, we can just draw the frames on top of each other.
 		if !tex.IsAnimated {
 			for _, frame := range tex.Frames {
@@ -244,14 +434,15 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 					continue
 				} else if m.tileGrid.missingResourceTiles.Contains(pos2d, frame.Name) {
 					// Draw yellow outline for missing resource
-					rl.DrawRectangleLinesEx(pos, 2, rl.Yellow)
+					rl.DrawRectangleLinesEx(dest, 2, rl.Yellow)
 					continue
 				}
 
 				// Center the texture in the tile
+				box := spanFrameBox(dest, tex, frame)
 				origin := rl.Vector2{
-					X: float32(m.uiState.tileSize) / 2,
-					Y: float32(m.uiState.tileSize) / 2,
+					X: box.Width / 2,
+					Y: box.Height / 2,
 				}
 
 				info, err := m.resources.GetTexture("default", frame.Name)
@@ -262,10 +453,10 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 
 				// Adjust destination rectangle to use center-based rotation with scale and offset
 				destRect := rl.Rectangle{
-					X:      pos.X + pos.Width/2 + float32(frame.OffsetX*float64(m.uiState.tileSize)),
-					Y:      pos.Y + pos.Height/2 + float32(frame.OffsetY*float64(m.uiState.tileSize)),
-					Width:  pos.Width * float32(frame.ScaleX),
-					Height: pos.Height * float32(frame.ScaleY),
+					X:      dest.X + dest.Width/2 + float32(frame.OffsetX*float64(pos.Width)),
+					Y:      dest.Y + dest.Height/2 + float32(frame.OffsetY*float64(pos.Width)),
+					Width:  box.Width * float32(frame.ScaleX),
+					Height: box.Height * float32(frame.ScaleY),
 				}
 
 				if frame.MirrorX {
@@ -280,21 +471,24 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 					frame.Tint = rl.White
 				}
 
-				rl.DrawTexturePro(
-					info.Texture,
-					info.Region,
-					destRect,
-					origin,
-					float32(frame.Rotation),
-					frame.Tint,
-				)
+				m.batch.Draw(resources.Sprite{
+					Texture:  info.Texture,
+					Source:   info.Region,
+					Dest:     destRect,
+					Origin:   origin,
+					Rotation: float32(frame.Rotation),
+					Tint:     frame.Tint,
+					Depth:    depth,
+				})
+				depth++
 			}
 		} else {
 			// If the texture is complex, we need draw the current frame for the animation time.
 			frame := tex.GetCurrentFrame(rl.GetTime())
+			box := spanFrameBox(dest, tex, frame)
 			origin := rl.Vector2{
-				X: float32(m.uiState.tileSize) / 2,
-				Y: float32(m.uiState.tileSize) / 2,
+				X: box.Width / 2,
+				Y: box.Height / 2,
 			}
 			info, err := m.resources.GetTexture("default", frame.Name)
 			if err != nil {
@@ -302,10 +496,10 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 				continue
 			}
 			destRect := rl.Rectangle{
-				X:      pos.X + pos.Width/2 + float32(frame.OffsetX*float64(m.uiState.tileSize)),
-				Y:      pos.Y + pos.Height/2 + float32(frame.OffsetY*float64(m.uiState.tileSize)),
-				Width:  pos.Width * float32(frame.ScaleX),
-				Height: pos.Height * float32(frame.ScaleY),
+				X:      dest.X + dest.Width/2 + float32(frame.OffsetX*float64(pos.Width)),
+				Y:      dest.Y + dest.Height/2 + float32(frame.OffsetY*float64(pos.Width)),
+				Width:  box.Width * float32(frame.ScaleX),
+				Height: box.Height * float32(frame.ScaleY),
 			}
 
 			if frame.MirrorY {
@@ -315,21 +509,41 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 				destRect.Height = -destRect.Height
 			}
 
-			rl.DrawTexturePro(
-				info.Texture,
-				info.Region,
-				destRect,
-				origin,
-				float32(frame.Rotation),
-				frame.Tint,
-			)
+			m.batch.Draw(resources.Sprite{
+				Texture:  info.Texture,
+				Source:   info.Region,
+				Dest:     destRect,
+				Origin:   origin,
+				Rotation: float32(frame.Rotation),
+				Tint:     frame.Tint,
+				Depth:    depth,
+			})
+			depth++
 		}
 	}
+}
+
+// spanFrameBox is the size to draw a frame at before it's rotated. A spanning frame turned on its side
+// is drawn with its width and height swapped, so once rotated it fills the tiles the span covers.
+func spanFrameBox(dest rl.Rectangle, tex *beam.AnimatedTexture, frame beam.Texture) rl.Rectangle {
+	if tex.IsSpanning() && quarterTurned(frame.Rotation) {
+		dest.Width, dest.Height = dest.Height, dest.Width
+	}
+	return dest
+}
 
-	// Draw special tile outlines
+// renderGridTile draws the tile's textures on the layer, other than spanning ones, then its outlines.
+func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile beam.Tile, layer beam.Layer) {
+	m.renderTileTextures(pos, pos2d, tile, layer, false)
+	m.renderTileOutlines(pos, pos2d, tile)
+}
+
+// renderTileOutlines marks walls, collision overrides, and the start, respawn, exits, dungeon entries and triggers when gridlines are shown.
+func (m *MapMaker) renderTileOutlines(pos rl.Rectangle, pos2d beam.Position, tile beam.Tile) {
 	if m.uiState.showGridlines && tile.Type == beam.WallTile {
 		rl.DrawRectangleLinesEx(pos, 2, rl.Brown)
 	}
+	m.renderCollisionOverride(pos, tile)
 
 	if m.uiState.showGridlines && pos2d.X != 0 && pos2d.Y != 0 {
 		switch {
@@ -350,6 +564,10 @@ func (m *MapMaker) renderGridTile(pos rl.Rectangle, pos2d beam.Position, tile be
 				rl.DrawRectangleLinesEx(pos, 2, rl.Purple)
 			}
 		}
+
+		if len(m.tileGrid.TriggersAt(pos2d)) > 0 {
+			rl.DrawRectangleLinesEx(rl.Rectangle{X: pos.X + 3, Y: pos.Y + 3, Width: pos.Width - 6, Height: pos.Height - 6}, 2, rl.Orange)
+		}
 	}
 }
 
@@ -363,23 +581,22 @@ func (m *MapMaker) renderUI() {
 	rl.DrawLine(m.window.width-180, 5, m.window.width-180, int32(m.uiState.menuBarHeight-5), rl.LightGray)
 
 	// Get all buttons
-	tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, resetBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn := m.getUIButtons()
+	tileSmallerBtn, tileLargerBtn, widthSmallerBtn, widthLargerBtn, heightSmallerBtn, heightLargerBtn, loadBtn, saveBtn, loadResourceBtn, viewResourcesBtn, resetBtn, paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn := m.getUIButtons()
 
 	// Draw size control buttons
 	m.drawButton(widthSmallerBtn, rl.White)
 	m.drawButton(widthLargerBtn, rl.White)
-	rl.DrawText(fmt.Sprintf("W:%d", m.uiState.gridWidth), 48, 12, 12, rl.DarkGray)
-
 	m.drawButton(heightSmallerBtn, rl.White)
 	m.drawButton(heightLargerBtn, rl.White)
-	rl.DrawText(fmt.Sprintf("H:%d", m.uiState.gridHeight), 48, 37, 12, rl.DarkGray)
+	m.renderGridSizeFields()
 
 	m.drawButton(tileSmallerBtn, rl.White)
 	m.drawButton(tileLargerBtn, rl.White)
 	rl.DrawText(fmt.Sprintf("%dpx", m.uiState.tileSize), 48, 62, 12, rl.DarkGray)
 
 	// Draw new grid control buttons
-	m.drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn)
+	m.drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn)
+	m.drawButton(m.validateButton(), rl.White)
 
 	// Draw other icon buttons
 	m.drawIconButton(saveBtn, rl.LightGray)
@@ -412,19 +629,125 @@ func (m *MapMaker) renderUI() {
 		m.renderItemEditor()
 	}
 
+	if m.uiState.propertiesEditor != nil {
+		m.renderPropertiesEditor()
+	}
+
+	if m.uiState.triggerEditor != nil {
+		m.renderTriggerEditor()
+	}
+
+	if m.uiState.exportDialog != nil {
+		m.renderExportDialog()
+	}
+
+	if m.uiState.missingResourcesDialog != nil {
+		m.renderMissingResourcesDialog()
+	}
+
+	m.renderMinimap()
+
 	if m.showResourceViewer {
 		m.renderResourceViewer()
+	} else {
+		if m.uiState.activeInput == "resource_filter" {
+			m.uiState.activeInput = ""
+		}
+		if m.uiState.taggingTexture != "" {
+			m.closeTagEditor()
+		}
+	}
+
+	m.renderTabBar()
+	m.renderStatusBar()
+}
+
+// renderStatusBar draws the bar along the bottom of the window, with a readout of
+// the file, hovered tile, tool and texture on the left, and the layer toggles and zoom on the right.
+func (m *MapMaker) renderStatusBar() {
+	barY := m.window.height - int32(m.uiState.statusBarHeight)
+	rl.DrawRectangle(0, barY, m.window.width, int32(m.uiState.statusBarHeight), rl.RayWhite)
+	rl.DrawLine(0, barY, m.window.width, barY, rl.LightGray)
+
+	// Draw the zoom level on the right of the status bar
+	zoomText := fmt.Sprintf("Zoom: %d%%", int(math.Round(float64(m.uiState.zoomLevel*100))))
+	zoomWidth := rl.MeasureText(zoomText, 16)
+	rl.DrawText(zoomText, m.window.width-zoomWidth-10, barY+5, 16, rl.DarkGray)
+	rightEdge := m.window.width - zoomWidth - 30
+	rightEdge = m.renderLayerToggles(rightEdge, barY+5) - 10
+
+	if m.uiState.autotileSet != nil {
+		autotileText := "Autotile: " + m.uiState.autotileSet.Prefix
+		autotileWidth := rl.MeasureText(autotileText, 16)
+		rl.DrawText(autotileText, rightEdge-autotileWidth, barY+5, 16, rl.DarkGray)
+		rightEdge -= autotileWidth + 20
+	}
+
+	// Missing textures, click to open the repair dialog
+	if missing := m.tileGrid.missingResourceTiles.missingTextures(); len(missing) > 0 {
+		missingText := fmt.Sprintf("Missing: %d", len(missing))
+		missingWidth := rl.MeasureText(missingText, 16)
+		missingRect := rl.Rectangle{X: float32(rightEdge - missingWidth), Y: float32(barY), Width: float32(missingWidth), Height: float32(m.uiState.statusBarHeight)}
+		rl.DrawText(missingText, rightEdge-missingWidth, barY+5, 16, rl.Orange)
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) && rl.CheckCollisionPointRec(rl.GetMousePosition(), missingRect) && !m.isDialogOpen() {
+			m.openMissingResourcesDialog()
+		}
+		rightEdge -= missingWidth + 20
 	}
 
-	// Draw status bar
-	rl.DrawRectangle(0, m.window.height-int32(m.uiState.statusBarHeight),
-		m.window.width, int32(m.uiState.statusBarHeight), rl.RayWhite)
-	rl.DrawLine(0, m.window.height-int32(m.uiState.statusBarHeight),
-		m.window.width, m.window.height-int32(m.uiState.statusBarHeight), rl.LightGray)
+	readout := fitText(m.statusReadout(), rightEdge-10, 16)
+	rl.DrawText(readout, 10, barY+5, 16, rl.DarkGray)
+}
+
+// statusReadout describes the current file and map size, the tile under the cursor, the selected tool and the active texture.
+func (m *MapMaker) statusReadout() string {
+	file := fileLabel(m.currentFile)
+
+	tile := "off-grid"
+	if pos, ok := m.mouseGridPos(); ok {
+		t := m.tileGrid.Tiles[pos.Y][pos.X]
+		tile = fmt.Sprintf("(%d, %d) %s, %d textures", pos.X, pos.Y, t.Type, len(t.Textures))
+	}
+
+	tool := m.uiState.selectedTool
+	if tool == "" {
+		tool = "none"
+	}
+	texture := "none"
+	if m.uiState.activeTexture != nil {
+		texture = truncateName(m.uiState.activeTexture.Name, 24)
+	}
+	return fmt.Sprintf("%s %dx%d | %s | Tool: %s | Texture: %s", file, m.tileGrid.Width, m.tileGrid.Height, tile, tool, texture)
+}
+
+// truncateName shortens a name to maxLen characters, ending in "..." if it was cut.
+func truncateName(name string, maxLen int) string {
+	runes := []rune(name)
+	if len(runes) <= maxLen {
+		return name
+	}
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
 
+// fitText truncates text until it fits in maxWidth pixels at the given font size.
+func fitText(text string, maxWidth, fontSize int32) string {
+	if rl.MeasureText(text, fontSize) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		cut := string(runes[:n]) + "..."
+		if rl.MeasureText(cut, fontSize) <= maxWidth {
+			return cut
+		}
+	}
+	return ""
 }
 
-func (m *MapMaker) drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn IconButton) {
+func (m *MapMaker) drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, selectBtn, layersBtn, locationBtn, gridlinesBtn, npcBtn, itemsBtn, rectBtn, lineBtn, rulerBtn, propertiesBtn, stampBtn IconButton) {
 	m.drawIconButton(paintbrushBtn, rl.LightGray)
 	m.drawIconButton(paintbucketBtn, rl.LightGray)
 	m.drawIconButton(eraseBtn, rl.LightGray)
@@ -434,6 +757,11 @@ func (m *MapMaker) drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, select
 	m.drawIconButton(gridlinesBtn, rl.LightGray)
 	m.drawIconButton(npcBtn, rl.LightGray)
 	m.drawIconButton(itemsBtn, rl.LightGray)
+	m.drawIconButton(rectBtn, rl.LightGray)
+	m.drawIconButton(lineBtn, rl.LightGray)
+	m.drawIconButton(rulerBtn, rl.LightGray)
+	m.drawIconButton(propertiesBtn, rl.LightGray)
+	m.drawIconButton(stampBtn, rl.LightGray)
 
 	// Draw tools with selection highlight
 	toolButtons := map[string]IconButton{
@@ -448,6 +776,11 @@ func (m *MapMaker) drawToolIcons(paintbrushBtn, paintbucketBtn, eraseBtn, select
 		"gridlines":    gridlinesBtn,
 		"npc":          npcBtn,
 		"items":        itemsBtn,
+		"rect":         rectBtn,
+		"line":         lineBtn,
+		"ruler":        rulerBtn,
+		"properties":   propertiesBtn,
+		"stamp":        stampBtn,
 	}
 	for toolName, btn := range toolButtons {
 		if m.uiState.selectedTool == toolName || (toolName == "gridlines" && m.uiState.showGridlines) {
@@ -651,6 +984,8 @@ func (m *MapMaker) renderResourceViewer() {
 	// Title section and heading buttons
 	titleHeight := 50
 	rl.DrawText("Loaded Resources", int32(dialogX+20), int32(dialogY+20), 20, rl.Black)
+	m.renderResourceFilter(float32(dialogX+210), float32(dialogY+10), 170)
+	m.renderFavoritesToggle(float32(dialogX+390), float32(dialogY+10))
 
 	// Add manage button
 	manageBtn := rl.Rectangle{
@@ -674,6 +1009,7 @@ func (m *MapMaker) renderResourceViewer() {
 
 	if rl.CheckCollisionPointRec(rl.GetMousePosition(), closeBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 		m.showResourceViewer = false
+		m.cancelResourceRename()
 		if m.uiState.textureEditor != nil && m.uiState.textureEditor.advSelectingFrameIndex != -1 {
 			m.uiState.textureEditor.advSelectingFrameIndex = -1
 		}
@@ -682,6 +1018,8 @@ func (m *MapMaker) renderResourceViewer() {
 	// Toggle manage mode when manage button is clicked
 	if rl.CheckCollisionPointRec(rl.GetMousePosition(), manageBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 		m.uiState.resourceManageMode = !m.uiState.resourceManageMode
+		m.cancelResourceRename()
+		m.closeTagEditor()
 	}
 
 	// Setup scrollable content area
@@ -711,6 +1049,8 @@ func (m *MapMaker) renderResourceViewer() {
 	// Calculate content bounds
 	ss, _ := m.resources.GetAllSpritesheets("default")
 	textures, _ := m.resources.GetAllTextures("default", false)
+	ss = filterSpritesheets(ss, m.uiState.resourceFilter)
+	textures = m.viewerTextures(textures)
 
 	totalRows := (len(textures) + len(ss) + itemsPerRow - 1) / itemsPerRow
 	contentHeight := totalRows*int(itemTotalWidth) + int(bottomMargin)
@@ -754,14 +1094,54 @@ func (m *MapMaker) renderResourceViewer() {
 			// Draw item background
 			rl.DrawRectangleRec(itemRect, rl.LightGray)
 
-			// Draw texture name
-			rl.DrawText(texInfo.Name, int32(itemRect.X+10), int32(itemRect.Y+8), 16, rl.Black)
+			// Draw texture name, or the rename field if it's being renamed
+			renaming := m.uiState.renamingResource == texInfo.Name
+			if renaming {
+				nameRect := rl.Rectangle{X: itemRect.X + 5, Y: itemRect.Y + 3, Width: 250, Height: 24}
+				rl.DrawRectangleRec(nameRect, rl.RayWhite)
+				rl.DrawRectangleLinesEx(nameRect, 2, rl.Blue)
+				rl.DrawText(m.uiState.renameInput, int32(nameRect.X+5), int32(nameRect.Y+4), 16, rl.Black)
+
+				m.textInput(&m.uiState.renameInput, true, false)
+				if rl.IsKeyPressed(rl.KeyEnter) {
+					m.renameResource(texInfo.Name, m.uiState.renameInput)
+					m.uiState.renamingResource = ""
+					m.uiState.activeInput = ""
+				}
+			} else {
+				rl.DrawText(texInfo.Name, int32(itemRect.X+10), int32(itemRect.Y+8), 16, rl.Black)
+			}
 
 			// Draw grid size and margin info
 			gridInfo := fmt.Sprintf("Grid: %dx%d  Margin: %d",
 				texInfo.GridSizeX, texInfo.GridSizeY, texInfo.Margin)
 			rl.DrawText(gridInfo, int32(itemRect.X+10), int32(itemRect.Y+28), 14, rl.DarkGray)
 
+			// Rename button, saves the new name while renaming
+			renameBtn := rl.Rectangle{
+				X:      itemRect.X + itemRect.Width - 130,
+				Y:      itemRect.Y + 10,
+				Width:  60,
+				Height: 26,
+			}
+			rl.DrawRectangleRec(renameBtn, rl.Gray)
+			if renaming {
+				rl.DrawText("Save", int32(renameBtn.X+14), int32(renameBtn.Y+5), 14, rl.White)
+			} else {
+				rl.DrawText("Rename", int32(renameBtn.X+5), int32(renameBtn.Y+5), 14, rl.White)
+			}
+			if rl.CheckCollisionPointRec(rl.GetMousePosition(), renameBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+				if renaming {
+					m.renameResource(texInfo.Name, m.uiState.renameInput)
+					m.uiState.renamingResource = ""
+					m.uiState.activeInput = ""
+				} else {
+					m.uiState.renamingResource = texInfo.Name
+					m.uiState.renameInput = texInfo.Name
+					m.uiState.activeInput = "rename_resource"
+				}
+			}
+
 			// Delete button
 			deleteBtn := rl.Rectangle{
 				X:      itemRect.X + itemRect.Width - 60,
@@ -855,7 +1235,14 @@ func (m *MapMaker) renderResourceViewer() {
 				rl.DrawRectangleLinesEx(clickArea, 2, rl.Blue)
 			}
 
+			// Star in the corner, right click to edit tags
+			starClicked := m.renderFavoriteStar(texInfo.Name, clickArea, canAcceptClicks)
 			if canAcceptClicks && rl.CheckCollisionPointRec(rl.GetMousePosition(), clickArea) &&
+				rl.IsMouseButtonPressed(rl.MouseRightButton) {
+				m.openTagEditor(texInfo.Name)
+			}
+
+			if canAcceptClicks && !starClicked && rl.CheckCollisionPointRec(rl.GetMousePosition(), clickArea) &&
 				rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 				tex, err := m.resources.GetTexture("default", texInfo.Name)
 				if err != nil {
@@ -868,6 +1255,21 @@ func (m *MapMaker) renderResourceViewer() {
 	}
 
 	rl.EndScissorMode()
+
+	empty := len(textures) == 0
+	if m.uiState.resourceManageMode {
+		empty = len(ss) == 0
+	}
+	if !m.uiState.resourceManageMode {
+		m.renderTagEditor(float32(dialogX+20), float32(dialogY+dialogHeight-40), float32(dialogWidth-40))
+	}
+	if empty && (m.uiState.resourceFilter != "" || m.uiState.favoritesOnly) {
+		message := "No resources match \"" + truncateName(m.uiState.resourceFilter, 24) + "\""
+		if m.uiState.resourceFilter == "" {
+			message = "No starred textures"
+		}
+		rl.DrawText(message, int32(dialogX+leftMargin), int32(dialogY+titleHeight+10), 16, rl.Gray)
+	}
 }
 
 func (m *MapMaker) renderTileInfoPopup() {
@@ -912,7 +1314,7 @@ func (m *MapMaker) renderTileInfoPopup() {
 	}
 
 	// Calculate total content height first
-	var totalHeight int32 = 60
+	var totalHeight int32 = 85
 	tempTile := m.tileGrid.Tiles[m.uiState.tileInfoPos[0].Y][m.uiState.tileInfoPos[0].X]
 	for _, tex := range tempTile.Textures {
 		totalHeight += 35
@@ -984,11 +1386,43 @@ func (m *MapMaker) renderTileInfoPopup() {
 	rl.DrawText(posText, m.uiState.tileInfoPopupX+padding, textY, 16, rl.Black)
 	textY += 25
 
+	// Collision, clicking steps the override for every tile shown
+	collisionText := "Collision: " + collisionLabel(tile)
+	rl.DrawText(collisionText, m.uiState.tileInfoPopupX+padding, textY, 16, rl.Black)
+	collisionBtn := rl.Rectangle{
+		X:      float32(m.uiState.tileInfoPopupX + padding + rl.MeasureText(collisionText, 16) + 10),
+		Y:      float32(textY),
+		Width:  50,
+		Height: 16,
+	}
+	rl.DrawRectangleRec(collisionBtn, rl.LightGray)
+	rl.DrawText("Change", int32(collisionBtn.X+5), int32(collisionBtn.Y+3), 10, rl.Black)
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), collisionBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) &&
+		textY >= m.uiState.tileInfoPopupY+30 {
+		m.cycleCollision(m.uiState.tileInfoPos)
+	}
+	textY += 25
+
 	// Draw textures
 	rl.DrawText("Textures:", m.uiState.tileInfoPopupX+padding, textY, 16, rl.Black)
 	textY += 20
 
 	for texIndex, tex := range tile.Textures {
+		// Right clicking a texture's entry copies just that texture, to paste onto other tiles
+		entryHeight := int32(20 + 55*len(tex.Frames))
+		entryRect := rl.Rectangle{
+			X:      float32(m.uiState.tileInfoPopupX),
+			Y:      float32(textY),
+			Width:  float32(dialogWidth),
+			Height: float32(entryHeight),
+		}
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), entryRect) && textY >= m.uiState.tileInfoPopupY+30 {
+			rl.DrawRectangleRec(entryRect, rl.Fade(rl.SkyBlue, 0.2))
+			if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+				m.copyTextureLayer(tex)
+			}
+		}
+
 		// Draw complex text and edit button side by side
 		complexText := fmt.Sprintf("- Complex: %t", tex.IsAnimated)
 		rl.DrawText(complexText, m.uiState.tileInfoPopupX+padding+10, textY, 14, rl.DarkGray)
@@ -1073,8 +1507,8 @@ func (m *MapMaker) renderTileInfoPopup() {
 				textColor = rl.Yellow
 			}
 
-			rl.DrawText(fmt.Sprintf("  - %s (%.1f°) Scale: %.2f Offset: (%.2f, %.2f)",
-				frame.Name, frame.Rotation, frame.ScaleX, frame.OffsetX, frame.OffsetY),
+			rl.DrawText(fmt.Sprintf("  - %s (%.1f°) Scale: (%.2f, %.2f) Offset: (%.2f, %.2f)",
+				frame.Name, frame.Rotation, frame.ScaleX, frame.ScaleY, frame.OffsetX, frame.OffsetY),
 				m.uiState.tileInfoPopupX+padding+5, textY, 12, textColor)
 			textY += 15
 
@@ -1133,7 +1567,10 @@ func (m *MapMaker) renderTileInfoPopup() {
 
 type NPCEditorState struct {
 	visible     bool
+	data        beam.NPCData // The NPC as it was opened, keeping the fields the editor doesn't show
+	id          string       // ID of the NPC being edited, empty for a new NPC
 	spawnPos    beam.Position
+	size        beam.NPCSize
 	name        string
 	health      string
 	attack      string
@@ -1147,7 +1584,10 @@ type NPCEditorState struct {
 
 	// Texture editing state
 	editingDirection       beam.Direction
+	editingSet             npcTextureSet
 	textures               *beam.NPCTexture
+	idleTextures           *beam.NPCTexture
+	attackTextures         *beam.NPCTexture
 	frameCountStr          string
 	animationTimeStr       string
 	selectedFrames         []string
@@ -1173,6 +1613,148 @@ type NPCEditorState struct {
 	frameTintG         string
 	frameTintB         string
 	frameTintA         string
+	frameTintPicker    ColorPicker
+
+	// Template dropdown
+	showTemplates bool
+	templates     []NamedTemplate
+
+	// nameSuggestion is a free name to offer, set when saving with a name another NPC has
+	nameSuggestion string
+}
+
+// npcTextureSet is which of an NPC's textures the editor's frame grid is editing.
+type npcTextureSet int
+
+const (
+	npcTextureBase npcTextureSet = iota
+	npcTextureIdle
+	npcTextureAttack
+)
+
+func (set npcTextureSet) String() string {
+	switch set {
+	case npcTextureIdle:
+		return "Idle"
+	case npcTextureAttack:
+		return "Attack"
+	default:
+		return "Base"
+	}
+}
+
+// currentTextures returns the texture set being edited.
+func (editor *NPCEditorState) currentTextures() *beam.NPCTexture {
+	switch editor.editingSet {
+	case npcTextureIdle:
+		return editor.idleTextures
+	case npcTextureAttack:
+		return editor.attackTextures
+	default:
+		return editor.textures
+	}
+}
+
+// currentTexture returns the texture being edited, for the selected set and direction.
+func (editor *NPCEditorState) currentTexture() *beam.AnimatedTexture {
+	textures := editor.currentTextures()
+	switch editor.editingDirection {
+	case beam.DirUp:
+		return textures.Up
+	case beam.DirDown:
+		return textures.Down
+	case beam.DirLeft:
+		return textures.Left
+	case beam.DirRight:
+		return textures.Right
+	}
+	return nil
+}
+
+// loadCurrentFrames fills the frame grid from the texture being edited.
+func (editor *NPCEditorState) loadCurrentFrames() {
+	editor.selectedFrameIndex = -1
+	tex := editor.currentTexture()
+	if tex == nil || len(tex.Frames) == 0 {
+		editor.frameCountStr = "1"
+		editor.animationTimeStr = "0.5"
+		editor.selectedFrames = make([]string, 1)
+		return
+	}
+	editor.frameCountStr = fmt.Sprintf("%d", len(tex.Frames))
+	editor.animationTimeStr = fmt.Sprintf("%.1f", tex.AnimationTime)
+	editor.selectedFrames = make([]string, len(tex.Frames))
+	for i, frame := range tex.Frames {
+		editor.selectedFrames[i] = frame.Name
+	}
+}
+
+// editableNPCTexture returns a copy of an optional texture set with every direction filled in,
+// so the editor can add frames to it without changing the NPC it came from.
+func editableNPCTexture(tex *beam.NPCTexture) *beam.NPCTexture {
+	if tex == nil {
+		return completeNPCTexture(nil)
+	}
+	copied := *tex
+	return completeNPCTexture(&copied)
+}
+
+// usedNPCTexture returns an optional texture set without the directions that have no frames,
+// or nil if none of them do. GetCurrentTexture falls back to the base texture for those directions.
+func usedNPCTexture(tex *beam.NPCTexture) *beam.NPCTexture {
+	if tex == nil {
+		return nil
+	}
+	used := &beam.NPCTexture{}
+	empty := true
+	for _, dir := range []struct{ from, to **beam.AnimatedTexture }{
+		{&tex.Up, &used.Up}, {&tex.Down, &used.Down}, {&tex.Left, &used.Left}, {&tex.Right, &used.Right},
+	} {
+		if *dir.from != nil && len((*dir.from).Frames) > 0 {
+			*dir.to = *dir.from
+			empty = false
+		}
+	}
+	if empty {
+		return nil
+	}
+	return used
+}
+
+// npcData builds NPC data from the editor fields, without validating them.
+// Fields the editor doesn't show, like loot and faction, are kept from the NPC it was opened with.
+func (editor *NPCEditorState) npcData() beam.NPCData {
+	health, _ := strconv.Atoi(editor.health)
+	attack, _ := strconv.Atoi(editor.attack)
+	defense, _ := strconv.Atoi(editor.defense)
+	attackSpeed, _ := strconv.ParseFloat(editor.attackSpeed, 64)
+	attackRange, _ := strconv.ParseFloat(editor.attackRange, 64)
+	moveSpeed, _ := strconv.ParseFloat(editor.moveSpeed, 64)
+	aggroRange, _ := strconv.Atoi(editor.aggroRange)
+	spawnX, _ := strconv.Atoi(editor.spawnXStr)
+	spawnY, _ := strconv.Atoi(editor.spawnYStr)
+	wanderRange, _ := strconv.Atoi(editor.wanderRange)
+
+	data := editor.data
+	data.ID = editor.id
+	data.Name = editor.name
+	data.Texture = editor.textures
+	data.IdleTexture = usedNPCTexture(editor.idleTextures)
+	data.AttackTexture = usedNPCTexture(editor.attackTextures)
+	data.MaxHealth = health
+	data.BaseAttack = attack
+	data.BaseDefense = defense
+	data.BaseAttackSpeed = attackSpeed
+	data.BaseAttackRange = attackRange
+	data.MoveSpeed = moveSpeed
+	data.Hostile = editor.isHostile
+	data.AggroRange = aggroRange
+	data.Attackable = editor.attackable
+	data.Impassable = editor.impassable
+	data.WanderRange = wanderRange
+	data.SpawnPos = beam.Position{X: spawnX, Y: spawnY}
+	data.Size = editor.size
+	return data
 }
 
 func (m *MapMaker) renderNPCEditor() {
@@ -1232,36 +1814,17 @@ func (m *MapMaker) renderNPCEditor() {
 
 		if m.uiState.activeNPCInput == label {
 			rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
-
-			key := rl.GetCharPressed()
-			for key > 0 {
-				if numeric {
-					if (key >= '0' && key <= '9') || key == '.' {
-						*value += string(key)
-					}
-				} else {
-					if key >= 32 && key <= 126 {
-						*value += string(key)
-					}
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
+		}
+		m.textInput(value, m.uiState.activeNPCInput == label, numeric)
+		if msg, ok := invalidNumbers(editor.numberFields())[label]; ok {
+			drawNumberError(inputRect, msg)
 		}
 
 		if label == "Animation Time" {
 			animTime, err := strconv.ParseFloat(*value, 64)
 			if err == nil && animTime >= 0 {
-				if editor.editingDirection == beam.DirUp {
-					editor.textures.Up.AnimationTime = animTime
-				} else if editor.editingDirection == beam.DirDown {
-					editor.textures.Down.AnimationTime = animTime
-				} else if editor.editingDirection == beam.DirLeft {
-					editor.textures.Left.AnimationTime = animTime
-				} else if editor.editingDirection == beam.DirRight {
-					editor.textures.Right.AnimationTime = animTime
+				if tex := editor.currentTexture(); tex != nil {
+					tex.AnimationTime = animTime
 				}
 			}
 		}
@@ -1368,9 +1931,45 @@ func (m *MapMaker) renderNPCEditor() {
 		editor.isHostile = !editor.isHostile
 	}
 
+	// Size dropdown, beside the hostile checkbox. The list is drawn last so it covers the textures below
+	rl.DrawText("Size", int32(rightX+170), int32(y+8), 16, rl.Black)
+	sizeRect := rl.Rectangle{
+		X:      float32(rightX + 215),
+		Y:      float32(y),
+		Width:  100,
+		Height: float32(inputHeight),
+	}
+	rl.DrawRectangleRec(sizeRect, rl.LightGray)
+	rl.DrawText(editor.size.String(), int32(sizeRect.X+5), int32(sizeRect.Y+8), 16, rl.Black)
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), sizeRect) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		m.uiState.activeNPCInput = "npcSize"
+	}
+
 	// Direction selector
 	y += inputHeight + padding*2
 	rl.DrawText("Direction Textures", int32(rightX), int32(y), 16, rl.Black)
+
+	// Base, idle and attack textures share the direction buttons and frame grid
+	for i, set := range []npcTextureSet{npcTextureBase, npcTextureIdle, npcTextureAttack} {
+		tabBtn := rl.Rectangle{
+			X:      float32(rightX + 165 + i*62),
+			Y:      float32(y - 4),
+			Width:  58,
+			Height: 22,
+		}
+		tabColor := rl.LightGray
+		if editor.editingSet == set {
+			tabColor = rl.Blue
+		}
+		rl.DrawRectangleRec(tabBtn, tabColor)
+		label := set.String()
+		rl.DrawText(label, int32(tabBtn.X+(tabBtn.Width-float32(rl.MeasureText(label, 14)))/2), int32(tabBtn.Y+4), 14, rl.Black)
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), tabBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) &&
+			editor.editingSet != set && m.uiState.activeNPCInput != "npcSize" {
+			editor.editingSet = set
+			editor.loadCurrentFrames()
+		}
+	}
 	y += 25
 
 	dirBtnSize := int32(60)
@@ -1420,15 +2019,16 @@ func (m *MapMaker) renderNPCEditor() {
 		rl.DrawRectangleRec(btn, btnColor)
 
 		var tex *beam.AnimatedTexture
+		textures := editor.currentTextures()
 		switch dir {
 		case beam.DirUp:
-			tex = editor.textures.Up
+			tex = textures.Up
 		case beam.DirDown:
-			tex = editor.textures.Down
+			tex = textures.Down
 		case beam.DirLeft:
-			tex = editor.textures.Left
+			tex = textures.Left
 		case beam.DirRight:
-			tex = editor.textures.Right
+			tex = textures.Right
 		}
 
 		if len(tex.Frames) > 0 && tex.Frames[0].Name != "" {
@@ -1456,20 +2056,9 @@ func (m *MapMaker) renderNPCEditor() {
 				int32(btn.Y+btn.Height/2-8), 16, rl.Black)
 		}
 
-		if rl.CheckCollisionPointRec(rl.GetMousePosition(), btn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), btn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) && m.uiState.activeNPCInput != "npcSize" {
 			editor.editingDirection = dir
-			editor.selectedFrames = make([]string, 1)
-			if len(tex.Frames) > 0 {
-				editor.frameCountStr = fmt.Sprintf("%d", len(tex.Frames))
-				editor.animationTimeStr = fmt.Sprintf("%.1f", tex.AnimationTime)
-				editor.selectedFrames = make([]string, len(tex.Frames))
-				for i, frame := range tex.Frames {
-					editor.selectedFrames[i] = frame.Name
-				}
-			} else {
-				editor.frameCountStr = "1"
-				editor.animationTimeStr = "0.5"
-			}
+			editor.loadCurrentFrames()
 		}
 	}
 
@@ -1551,17 +2140,7 @@ func (m *MapMaker) renderNPCEditor() {
 						editor.selectedFrameIndex = i
 
 						// Initialize frame settings with current values
-						var currentTex *beam.AnimatedTexture
-						switch editor.editingDirection {
-						case beam.DirUp:
-							currentTex = editor.textures.Up
-						case beam.DirDown:
-							currentTex = editor.textures.Down
-						case beam.DirLeft:
-							currentTex = editor.textures.Left
-						case beam.DirRight:
-							currentTex = editor.textures.Right
-						}
+						currentTex := editor.currentTexture()
 
 						if currentTex != nil && i < len(currentTex.Frames) {
 							frame := currentTex.Frames[i]
@@ -1606,10 +2185,74 @@ func (m *MapMaker) renderNPCEditor() {
 		}
 	}
 
+	if m.uiState.activeNPCInput == "npcSize" {
+		sizes := beam.AllNPCSizes()
+		dropdownRect := rl.Rectangle{
+			X:      sizeRect.X,
+			Y:      sizeRect.Y + sizeRect.Height,
+			Width:  sizeRect.Width,
+			Height: float32(len(sizes) * inputHeight),
+		}
+		rl.DrawRectangleRec(dropdownRect, rl.White)
+		rl.DrawRectangleLinesEx(dropdownRect, 1, rl.Gray)
+
+		for i, size := range sizes {
+			sizeOption := rl.Rectangle{
+				X:      dropdownRect.X,
+				Y:      dropdownRect.Y + float32(i*inputHeight),
+				Width:  dropdownRect.Width,
+				Height: float32(inputHeight),
+			}
+			if rl.CheckCollisionPointRec(rl.GetMousePosition(), sizeOption) {
+				rl.DrawRectangleRec(sizeOption, rl.LightGray)
+				if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+					editor.size = size
+					m.uiState.activeNPCInput = ""
+				}
+			}
+			rl.DrawText(size.String(), int32(sizeOption.X+5), int32(sizeOption.Y+8), 16, rl.Black)
+		}
+
+		// Close the dropdown when clicking outside
+		mousePos := rl.GetMousePosition()
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !rl.CheckCollisionPointRec(mousePos, sizeRect) && !rl.CheckCollisionPointRec(mousePos, dropdownRect) {
+			m.uiState.activeNPCInput = ""
+		}
+	}
+
 	if editor.selectedFrameIndex >= 0 {
 		m.renderNPCFrameSettings(editor, dialogX, dialogY, dialogWidth, dialogHeight)
 	}
 
+	m.renderNPCTemplateButtons(editor, dialogX, dialogY, dialogHeight)
+	if m.uiState.npcEditor != editor {
+		return
+	}
+
+	// A taken name blocks saving, until it's changed or the suggestion is used
+	if editor.nameSuggestion != "" {
+		if m.npcNameTaken(editor.name, editor.id) {
+			errX, errY := int32(dialogX+20), int32(dialogY+dialogHeight-80)
+			msg := fmt.Sprintf("Another NPC is named %q.", strings.TrimSpace(editor.name))
+			rl.DrawText(msg, errX, errY, 16, rl.Red)
+			label := fmt.Sprintf("Use %q", editor.nameSuggestion)
+			useBtn := rl.Rectangle{
+				X:      float32(errX + rl.MeasureText(msg, 16) + 10),
+				Y:      float32(errY - 5),
+				Width:  float32(rl.MeasureText(label, 16) + 16),
+				Height: 26,
+			}
+			rl.DrawRectangleRec(useBtn, rl.SkyBlue)
+			rl.DrawText(label, int32(useBtn.X+8), int32(useBtn.Y+5), 16, rl.Black)
+			if rl.CheckCollisionPointRec(rl.GetMousePosition(), useBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+				editor.name = editor.nameSuggestion
+				editor.nameSuggestion = ""
+			}
+		} else {
+			editor.nameSuggestion = ""
+		}
+	}
+
 	// Save/Cancel buttons
 	saveBtn := rl.Rectangle{
 		X:      float32(dialogX + dialogWidth - 200),
@@ -1625,7 +2268,11 @@ func (m *MapMaker) renderNPCEditor() {
 		Height: 30,
 	}
 
-	rl.DrawRectangleRec(saveBtn, rl.Green)
+	saveColor := rl.Green
+	if len(invalidNumbers(editor.numberFields())) > 0 {
+		saveColor = rl.Fade(rl.Green, 0.4)
+	}
+	rl.DrawRectangleRec(saveBtn, saveColor)
 	rl.DrawRectangleRec(cancelBtn, rl.Red)
 	rl.DrawText("Save", int32(saveBtn.X+25), int32(saveBtn.Y+8), 16, rl.White)
 	rl.DrawText("Cancel", int32(cancelBtn.X+20), int32(cancelBtn.Y+8), 16, rl.White)
@@ -1637,40 +2284,11 @@ func (m *MapMaker) renderNPCEditor() {
 
 	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 		// Validate and save NPC data
-		health, _ := strconv.Atoi(editor.health)
-		attack, _ := strconv.Atoi(editor.attack)
-		defense, _ := strconv.Atoi(editor.defense)
-		attackSpeed, _ := strconv.ParseFloat(editor.attackSpeed, 64)
-		attackRange, _ := strconv.ParseFloat(editor.attackRange, 64)
-		moveSpeed, _ := strconv.ParseFloat(editor.moveSpeed, 64)
-		aggroRange, _ := strconv.Atoi(editor.aggroRange)
-		spawnX, _ := strconv.Atoi(editor.spawnXStr)
-		spawnY, _ := strconv.Atoi(editor.spawnYStr)
-		wanderRange, _ := strconv.Atoi(editor.wanderRange)
-
-		// Create NPC data
-		npcData := beam.NPCData{
-			Name:            editor.name,
-			Texture:         editor.textures,
-			Health:          health,
-			MaxHealth:       health,
-			Attack:          attack,
-			BaseAttack:      attack,
-			Defense:         defense,
-			BaseDefense:     defense,
-			AttackSpeed:     attackSpeed,
-			BaseAttackSpeed: attackSpeed,
-			AttackRange:     attackRange,
-			BaseAttackRange: attackRange,
-			MoveSpeed:       moveSpeed,
-			Direction:       beam.DirDown,
-			Hostile:         editor.isHostile,
-			AggroRange:      aggroRange,
-			Attackable:      editor.attackable,
-			Impassable:      editor.impassable,
-			WanderRange:     wanderRange,
-			SpawnPos:        beam.Position{X: spawnX, Y: spawnY}, // Set SpawnPos
+		if len(invalidNumbers(editor.numberFields())) > 0 {
+			m.showToast(invalidNumbersMessage, ToastError)
+			return
 		}
+		npcData := editor.npcData()
 
 		// Validate all inputs
 		if editor.name == "" || editor.health == "" || editor.attack == "" ||
@@ -1679,8 +2297,8 @@ func (m *MapMaker) renderNPCEditor() {
 			rl.DrawText("Please fill in all fields.", int32(dialogX+20), int32(dialogY+dialogHeight-80), 16, rl.Red)
 			return
 		}
-		if health <= 0 || attack <= 0 || defense < 0 || attackSpeed <= 0 ||
-			attackRange <= 0 || moveSpeed <= 0 || aggroRange < 0 || spawnX < 0 || spawnY < 0 { // Added spawn pos validation
+		if npcData.MaxHealth <= 0 || npcData.BaseAttack <= 0 || npcData.BaseDefense < 0 || npcData.BaseAttackSpeed <= 0 ||
+			npcData.BaseAttackRange <= 0 || npcData.MoveSpeed <= 0 || npcData.AggroRange < 0 || npcData.SpawnPos.X < 0 || npcData.SpawnPos.Y < 0 { // Added spawn pos validation
 			rl.DrawText("Values must be positive (except Defense/Aggro/Spawn).", int32(dialogX+20), int32(dialogY+dialogHeight-80), 16, rl.Red)
 			return
 		}
@@ -1715,26 +2333,34 @@ func (m *MapMaker) renderNPCEditor() {
 			return
 		}
 
-		// Save NPC data to the tile
-		found := false
-		for i, npc := range m.tileGrid.Map.NPCs {
-			if npc.Data.Name == editor.name {
-				m.tileGrid.Map.NPCs[i] = &beam.NPC{
-					Data: npcData,
-					Pos:  npcData.SpawnPos,
-				}
-				found = true
-				break
-			}
+		// The spawn has to be on the map, and the NPC can't start inside a wall or chest
+		spawn := npcData.SpawnPos
+		if spawn.X >= m.tileGrid.Width || spawn.Y >= m.tileGrid.Height {
+			m.showToast(fmt.Sprintf("Spawn must be within the %dx%d map", m.tileGrid.Width, m.tileGrid.Height), ToastError)
+			return
+		}
+		npcData.SpawnPos = m.tileGrid.ClampSpawnPos(spawn, npcData.Size)
+		if !m.tileGrid.IsSpawnable(npcData.SpawnPos, npcData.Size) {
+			m.showToast("Spawn is blocked by a wall or chest", ToastError)
+			return
+		}
+		if npcData.SpawnPos != spawn {
+			m.showToast(fmt.Sprintf("Moved spawn to (%d, %d) to fit the NPC on the map", npcData.SpawnPos.X, npcData.SpawnPos.Y), ToastInfo)
 		}
 
-		if !found {
-			// Add new NPC to the map
-			m.tileGrid.Map.NPCs = append(m.tileGrid.Map.NPCs, &beam.NPC{
-				Data: npcData,
-				Pos:  npcData.SpawnPos,
-			})
+		// Another NPC with the same name would be impossible to tell apart in the NPC list
+		if m.npcNameTaken(editor.name, editor.id) {
+			editor.nameSuggestion = m.uniqueNPCName(strings.TrimSpace(editor.name), editor.id)
+			return
+		}
+
+		// Save the NPC over the one with the same ID, or add it if it's new
+		newNPC := &beam.NPC{
+			Data: npcData,
+			Pos:  npcData.SpawnPos,
 		}
+		newNPC.ResetRuntime()
+		m.saveEditedNPC(newNPC)
 		m.closeNPCEditor()
 	}
 }
@@ -1783,7 +2409,7 @@ func (m *MapMaker) renderNPCList() {
 	// Draw headers
 	rl.DrawText("Name", int32(dialogX+20), int32(contentY), 20, rl.DarkGray)
 	rl.DrawText("Position", int32(dialogX+200), int32(contentY), 20, rl.DarkGray)
-	rl.DrawText("Actions", int32(dialogX+400), int32(contentY), 20, rl.DarkGray)
+	rl.DrawText("Actions", int32(dialogX+330), int32(contentY), 20, rl.DarkGray)
 	contentY += 30
 
 	// Draw NPC rows
@@ -1809,7 +2435,7 @@ func (m *MapMaker) renderNPCList() {
 
 		// Edit button
 		editBtn := rl.Rectangle{
-			X:      float32(dialogX + 400),
+			X:      float32(dialogX + 330),
 			Y:      float32(y + padding/2),
 			Width:  60,
 			Height: float32(rowHeight - padding),
@@ -1817,9 +2443,19 @@ func (m *MapMaker) renderNPCList() {
 		rl.DrawRectangleRec(editBtn, rl.Blue)
 		rl.DrawText("Edit", int32(editBtn.X+15), int32(editBtn.Y+5), 16, rl.White)
 
+		// Duplicate button
+		duplicateBtn := rl.Rectangle{
+			X:      float32(dialogX + 400),
+			Y:      float32(y + padding/2),
+			Width:  90,
+			Height: float32(rowHeight - padding),
+		}
+		rl.DrawRectangleRec(duplicateBtn, rl.DarkGreen)
+		rl.DrawText("Duplicate", int32(duplicateBtn.X+8), int32(duplicateBtn.Y+5), 16, rl.White)
+
 		// Delete button
 		deleteBtn := rl.Rectangle{
-			X:      float32(dialogX + 470),
+			X:      float32(dialogX + 500),
 			Y:      float32(y + padding/2),
 			Width:  60,
 			Height: float32(rowHeight - padding),
@@ -1829,36 +2465,21 @@ func (m *MapMaker) renderNPCList() {
 
 		// Handle button clicks
 		if rl.CheckCollisionPointRec(rl.GetMousePosition(), editBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			m.uiState.npcEditor = &NPCEditorState{
-				visible:          true,
-				spawnPos:         npc.Data.SpawnPos,
-				name:             npc.Data.Name,
-				health:           strconv.Itoa(npc.Data.Health),
-				attack:           strconv.Itoa(npc.Data.Attack),
-				defense:          strconv.Itoa(npc.Data.Defense),
-				attackSpeed:      fmt.Sprintf("%.1f", npc.Data.AttackSpeed),
-				attackRange:      fmt.Sprintf("%.1f", npc.Data.AttackRange),
-				moveSpeed:        fmt.Sprintf("%.1f", npc.Data.MoveSpeed),
-				aggroRange:       strconv.Itoa(npc.Data.AggroRange),
-				isHostile:        npc.Data.Hostile,
-				textures:         npc.Data.Texture,
-				editingDirection: beam.DirDown,
-				frameCountStr:    "1",
-				animationTimeStr: "0.5",
-				selectedFrames:   make([]string, 1),
-				spawnXStr:        strconv.Itoa(npc.Data.SpawnPos.X), // Initialize spawnXStr
-				spawnYStr:        strconv.Itoa(npc.Data.SpawnPos.Y), // Initialize spawnYStr
-				attackable:       npc.Data.Attackable,
-				impassable:       npc.Data.Impassable,
-				wanderRange:      strconv.Itoa(npc.Data.WanderRange),
-			}
+			m.openNPCEditor(npc.Data)
 			m.uiState.showNPCList = false
-			m.uiState.npcEditor.selectedFrameIndex = -1
+		}
+
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), duplicateBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+			duplicate := m.duplicateNPC(npc)
+			m.openNPCEditor(duplicate.Data)
+			m.uiState.showNPCList = false
+			break
 		}
 
 		if rl.CheckCollisionPointRec(rl.GetMousePosition(), deleteBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 			// Remove the NPC
 			m.tileGrid.NPCs = append(m.tileGrid.NPCs[:i], m.tileGrid.NPCs[i+1:]...)
+			m.markDirty()
 		}
 	}
 
@@ -1873,17 +2494,7 @@ func (m *MapMaker) renderNPCFrameSettings(editor *NPCEditorState, dialogX, dialo
 	}
 
 	// Get the current frame's texture
-	var currentTex *beam.AnimatedTexture
-	switch editor.editingDirection {
-	case beam.DirUp:
-		currentTex = editor.textures.Up
-	case beam.DirDown:
-		currentTex = editor.textures.Down
-	case beam.DirLeft:
-		currentTex = editor.textures.Left
-	case beam.DirRight:
-		currentTex = editor.textures.Right
-	}
+	currentTex := editor.currentTexture()
 
 	if currentTex == nil || editor.selectedFrameIndex >= len(currentTex.Frames) {
 		return
@@ -1928,22 +2539,10 @@ func (m *MapMaker) renderNPCFrameSettings(editor *NPCEditorState, dialogX, dialo
 
 		if m.uiState.activeNPCInput == "frame_"+label {
 			rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
-			key := rl.GetCharPressed()
-			for key > 0 {
-				if numeric {
-					if (key >= '0' && key <= '9') || key == '.' || key == '-' {
-						*value += string(key)
-					}
-				} else {
-					if key >= 32 && key <= 126 {
-						*value += string(key)
-					}
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
+		}
+		m.textInput(value, m.uiState.activeNPCInput == "frame_"+label, numeric)
+		if msg, ok := invalidNumbers(editor.frameNumberFields())[label]; ok {
+			drawNumberError(inputRect, msg)
 		}
 	}
 
@@ -2002,12 +2601,17 @@ func (m *MapMaker) renderNPCFrameSettings(editor *NPCEditorState, dialogX, dialo
 
 	y += spacing + 10
 
-	// Tint inputs
+	// Tint inputs, with a swatch that opens the color picker
 	rl.DrawText("Tint:", int32(settingsX+10), int32(y+2), 14, rl.Black)
 	createFrameInput("R", &editor.frameTintR, y, true)
 	createFrameInput("G", &editor.frameTintG, y+spacing, true)
 	createFrameInput("B", &editor.frameTintB, y+spacing*2, true)
 	createFrameInput("A", &editor.frameTintA, y+spacing*3, true)
+	tintSwatch := rl.Rectangle{X: float32(settingsX + 210), Y: float32(y), Width: 30, Height: 25}
+	if drawTintSwatch(tintSwatch, editor.frameTintR, editor.frameTintG, editor.frameTintB, editor.frameTintA) {
+		editor.frameTintPicker.Open(settingsX-colorPickerWidth-10, y, &editor.frameTintR, &editor.frameTintG, &editor.frameTintB, &editor.frameTintA)
+	}
+	editor.frameTintPicker.Draw()
 
 	// Apply button
 	applyBtn := rl.Rectangle{
@@ -2016,11 +2620,20 @@ func (m *MapMaker) renderNPCFrameSettings(editor *NPCEditorState, dialogX, dialo
 		Width:  60,
 		Height: 25,
 	}
-	rl.DrawRectangleRec(applyBtn, rl.Green)
+	frameValid := len(invalidNumbers(editor.frameNumberFields())) == 0
+	applyColor := rl.Green
+	if !frameValid {
+		applyColor = rl.Fade(rl.Green, 0.4)
+	}
+	rl.DrawRectangleRec(applyBtn, applyColor)
 	rl.DrawText("Apply", int32(applyBtn.X+10), int32(applyBtn.Y+5), 14, rl.White)
 
 	if rl.CheckCollisionPointRec(rl.GetMousePosition(), applyBtn) &&
 		rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if !frameValid {
+			m.showToast(invalidNumbersMessage, ToastError)
+			return
+		}
 		// Apply the changes to the current frame
 		rotation, _ := strconv.ParseFloat(editor.frameRotation, 64)
 		scaleX, _ := strconv.ParseFloat(editor.frameScaleX, 64)
@@ -2047,6 +2660,7 @@ func (m *MapMaker) renderNPCFrameSettings(editor *NPCEditorState, dialogX, dialo
 			A: uint8(tintA),
 		}
 
+		editor.frameTintPicker.Close()
 		editor.selectedFrameIndex = -1
 	}
 }
@@ -2099,6 +2713,113 @@ type ItemEditorState struct {
 	frameTintG    string
 	frameTintB    string
 	frameTintA    string
+
+	// Template dropdown
+	showTemplates bool
+	templates     []NamedItemTemplate
+}
+
+// validate checks the editor fields can be saved as an item, returning the first problem found.
+func (editor *ItemEditorState) validate() error {
+	if strings.TrimSpace(editor.id) == "" || strings.TrimSpace(editor.name) == "" {
+		return fmt.Errorf("ID and Name are required")
+	}
+
+	fields := editor.numberFields()
+	invalid := invalidNumbers(fields)
+	for _, field := range fields {
+		if msg, ok := invalid[field.label]; ok {
+			return fmt.Errorf("%s %s", field.label, msg)
+		}
+		if field.kind == wholeNumber && field.label != "Frame Count" {
+			if v, _ := strconv.Atoi(field.value); v < 0 {
+				return fmt.Errorf("%s must be 0 or more", field.label)
+			}
+		}
+	}
+
+	maxStack, _ := strconv.Atoi(editor.maxStack)
+	quantity, _ := strconv.Atoi(editor.quantity)
+	if editor.stackable && maxStack < 2 {
+		return fmt.Errorf("Max Stack must be at least 2 for stackable items")
+	}
+	if editor.stackable && quantity > maxStack {
+		return fmt.Errorf("Quantity can't be more than Max Stack")
+	}
+
+	frameCount, err := strconv.Atoi(editor.frameCountStr)
+	if err != nil || frameCount < 1 {
+		return fmt.Errorf("Frame Count must be at least 1")
+	}
+	for i := 0; i < frameCount; i++ {
+		if i >= len(editor.selectedFrames) || editor.selectedFrames[i] == "" {
+			return fmt.Errorf("every frame needs a texture assigned")
+		}
+	}
+	return nil
+}
+
+// buildItem builds an item from the editor fields, without validating them.
+// The editor's texture is rebuilt from the selected frames.
+func (editor *ItemEditorState) buildItem() beam.Item {
+	maxStack, _ := strconv.Atoi(editor.maxStack)
+	quantity, _ := strconv.Atoi(editor.quantity)
+	attack, _ := strconv.Atoi(editor.attack)
+	defense, _ := strconv.Atoi(editor.defense)
+	attackSpeed, _ := strconv.Atoi(editor.attackSpeed)
+	attackRange, _ := strconv.Atoi(editor.attackRange)
+	levelReq, _ := strconv.Atoi(editor.levelReq)
+	spawnX, _ := strconv.Atoi(editor.spawnXStr)
+	spawnY, _ := strconv.Atoi(editor.spawnYStr)
+
+	// Apply animation settings
+	frameCount, _ := strconv.Atoi(editor.frameCountStr)
+	animTime, _ := strconv.ParseFloat(editor.animationTimeStr, 64)
+
+	if editor.texture == nil {
+		editor.texture = &beam.AnimatedTexture{
+			Frames:     make([]beam.Texture, 0),
+			IsAnimated: frameCount > 1,
+		}
+	}
+
+	editor.texture.AnimationTime = animTime
+	editor.texture.Frames = make([]beam.Texture, max(frameCount, 0))
+	for i := 0; i < frameCount; i++ {
+		if i < len(editor.selectedFrames) {
+			editor.texture.Frames[i] = beam.Texture{
+				Name:   editor.selectedFrames[i],
+				ScaleX: 1.0,
+				ScaleY: 1.0,
+				Tint:   rl.White,
+			}
+		}
+	}
+
+	return beam.Item{
+		ID:            editor.id,
+		Name:          editor.name,
+		Description:   editor.description,
+		Type:          editor.itemType,
+		EquipmentType: editor.equipmentType,
+		Pos:           beam.Position{X: spawnX, Y: spawnY},
+		Texture:       editor.texture,
+		Blocking:      editor.blocking,
+		Equippable:    editor.equippable,
+		Consumable:    editor.consumable,
+		Stackable:     editor.stackable,
+		MaxStack:      maxStack,
+		Quantity:      quantity,
+		Stats: beam.ItemStats{
+			Attack:      attack,
+			Defense:     defense,
+			AttackSpeed: attackSpeed,
+			AttackRange: attackRange,
+		},
+		Requirements: beam.ItemRequirements{
+			Level: levelReq,
+		},
+	}
 }
 
 func (m *MapMaker) renderItemEditor() {
@@ -2159,21 +2880,10 @@ func (m *MapMaker) renderItemEditor() {
 
 		if m.uiState.activeItemInput == label {
 			rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
-
-			key := rl.GetCharPressed()
-			for key > 0 {
-				if numeric {
-					if (key >= '0' && key <= '9') || key == '.' {
-						*value += string(key)
-					}
-				} else {
-					*value += string(key)
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
+		}
+		m.textInput(value, m.uiState.activeItemInput == label, numeric)
+		if msg, ok := invalidNumbers(editor.numberFields())[label]; ok {
+			drawNumberError(inputRect, msg)
 		}
 	}
 
@@ -2276,6 +2986,29 @@ func (m *MapMaker) renderItemEditor() {
 		}
 	}
 
+	y += inputHeight + padding
+	checkboxRect = rl.Rectangle{
+		X:      float32(leftX + labelWidth),
+		Y:      float32(y),
+		Width:  float32(checkboxSize),
+		Height: float32(checkboxSize),
+	}
+	rl.DrawRectangleRec(checkboxRect, rl.LightGray)
+	if editor.stackable {
+		rl.DrawRectangle(
+			int32(checkboxRect.X+5),
+			int32(checkboxRect.Y+5),
+			int32(checkboxRect.Width-10),
+			int32(checkboxRect.Height-10),
+			rl.Black,
+		)
+	}
+	rl.DrawText("Stackable", int32(leftX), int32(y+8), 16, rl.Black)
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), checkboxRect) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		editor.stackable = !editor.stackable
+	}
+
 	// Right column - Item type and stats
 	y = startY
 
@@ -2499,6 +3232,11 @@ func (m *MapMaker) renderItemEditor() {
 		}
 	}
 
+	m.renderItemTemplateButtons(editor, dialogX, dialogY, dialogHeight)
+	if m.uiState.itemEditor != editor {
+		return
+	}
+
 	// Save/Cancel buttons
 	saveBtn := rl.Rectangle{
 		X:      float32(dialogX + dialogWidth - 200),
@@ -2514,7 +3252,11 @@ func (m *MapMaker) renderItemEditor() {
 		Height: 30,
 	}
 
-	rl.DrawRectangleRec(saveBtn, rl.Green)
+	saveColor := rl.Green
+	if len(invalidNumbers(editor.numberFields())) > 0 {
+		saveColor = rl.Fade(rl.Green, 0.4)
+	}
+	rl.DrawRectangleRec(saveBtn, saveColor)
 	rl.DrawRectangleRec(cancelBtn, rl.Red)
 	rl.DrawText("Save", int32(saveBtn.X+25), int32(saveBtn.Y+8), 16, rl.White)
 	rl.DrawText("Cancel", int32(cancelBtn.X+20), int32(cancelBtn.Y+8), 16, rl.White)
@@ -2526,79 +3268,13 @@ func (m *MapMaker) renderItemEditor() {
 
 	// Inside the save button click handler in renderItemEditor():
 	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-		// Validate and save item data
-		maxStack, _ := strconv.Atoi(editor.maxStack)
-		quantity, _ := strconv.Atoi(editor.quantity)
-		attack, _ := strconv.Atoi(editor.attack)
-		defense, _ := strconv.Atoi(editor.defense)
-		attackSpeed, _ := strconv.Atoi(editor.attackSpeed)
-		attackRange, _ := strconv.Atoi(editor.attackRange)
-		levelReq, _ := strconv.Atoi(editor.levelReq)
-		spawnX, _ := strconv.Atoi(editor.spawnXStr)
-		spawnY, _ := strconv.Atoi(editor.spawnYStr)
-
-		// Validate required fields
-		if editor.id == "" || editor.name == "" {
-			rl.DrawText("ID and Name are required", int32(dialogX+20), int32(dialogY+dialogHeight-80), 16, rl.Red)
+		if err := editor.validate(); err != nil {
+			m.showToast(err.Error(), ToastError)
 			return
 		}
 
-		for i := 0; i < frameCount; i++ {
-			if i >= len(editor.selectedFrames) || editor.selectedFrames[i] == "" {
-				rl.DrawText("All frames must have a texture assigned", int32(dialogX+20), int32(dialogY+dialogHeight-80), 16, rl.Red)
-				return
-			}
-		}
-
-		// Apply animation settings
-		frameCount, _ := strconv.Atoi(editor.frameCountStr)
-		animTime, _ := strconv.ParseFloat(editor.animationTimeStr, 64)
-
-		if editor.texture == nil {
-			editor.texture = &beam.AnimatedTexture{
-				Frames:     make([]beam.Texture, 0),
-				IsAnimated: frameCount > 1,
-			}
-		}
-
-		editor.texture.AnimationTime = animTime
-		editor.texture.Frames = make([]beam.Texture, frameCount)
-		for i := 0; i < frameCount; i++ {
-			if i < len(editor.selectedFrames) {
-				editor.texture.Frames[i] = beam.Texture{
-					Name:   editor.selectedFrames[i],
-					ScaleX: 1.0,
-					ScaleY: 1.0,
-					Tint:   rl.White,
-				}
-			}
-		}
-
-		// Create item data
-		item := beam.Item{
-			ID:            editor.id,
-			Name:          editor.name,
-			Description:   editor.description,
-			Type:          editor.itemType,
-			EquipmentType: editor.equipmentType,
-			Pos:           beam.Position{X: spawnX, Y: spawnY},
-			Texture:       editor.texture,
-			Blocking:      editor.blocking,
-			Equippable:    editor.equippable,
-			Consumable:    editor.consumable,
-			Stackable:     editor.stackable,
-			MaxStack:      maxStack,
-			Quantity:      quantity,
-			Stats: beam.ItemStats{
-				Attack:      attack,
-				Defense:     defense,
-				AttackSpeed: attackSpeed,
-				AttackRange: attackRange,
-			},
-			Requirements: beam.ItemRequirements{
-				Level: levelReq,
-			},
-		}
+		item := editor.buildItem()
+		spawnX, spawnY := item.Pos.X, item.Pos.Y
 
 		// Check if an item already exists at this position
 		found := false
@@ -2615,6 +3291,7 @@ func (m *MapMaker) renderItemEditor() {
 			// Add new item to the map
 			m.tileGrid.Items = append(m.tileGrid.Items, &item)
 		}
+		m.markDirty()
 
 		m.showToast("Item saved successfully!", ToastSuccess)
 		m.closeItemEditor()
@@ -2690,6 +3367,7 @@ func (m *MapMaker) renderItemList() {
 		// Draw Item info
 		rl.DrawText(item.Name, int32(dialogX+20), int32(y+10), 16, rl.Black)
 		rl.DrawText(fmt.Sprintf("(%d, %d)", item.Pos.X, item.Pos.Y), int32(dialogX+200), int32(y+10), 16, rl.Black)
+		rl.DrawText(item.Type.String(), int32(dialogX+300), int32(y+10), 16, rl.Black)
 
 		// Edit button
 		editBtn := rl.Rectangle{
@@ -2713,49 +3391,15 @@ func (m *MapMaker) renderItemList() {
 
 		// Handle button clicks
 		if rl.CheckCollisionPointRec(rl.GetMousePosition(), editBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			m.uiState.itemEditor = &ItemEditorState{
-				visible:          true,
-				spawnPos:         item.Pos,
-				id:               item.ID,
-				name:             item.Name,
-				description:      item.Description,
-				itemType:         item.Type,
-				equipmentType:    item.EquipmentType,
-				texture:          item.Texture,
-				blocking:         item.Blocking,
-				equippable:       item.Equippable,
-				consumable:       item.Consumable,
-				stackable:        item.Stackable,
-				maxStack:         strconv.Itoa(item.MaxStack),
-				quantity:         strconv.Itoa(item.Quantity),
-				attack:           strconv.Itoa(item.Stats.Attack),
-				defense:          strconv.Itoa(item.Stats.Defense),
-				attackSpeed:      strconv.Itoa(item.Stats.AttackSpeed),
-				attackRange:      strconv.Itoa(item.Stats.AttackRange),
-				levelReq:         strconv.Itoa(item.Requirements.Level),
-				spawnXStr:        strconv.Itoa(item.Pos.X),
-				spawnYStr:        strconv.Itoa(item.Pos.Y),
-				frameCountStr:    "1",
-				animationTimeStr: "0.5",
-				selectedFrames:   make([]string, 1),
-			}
-
-			// Initialize texture frames if they exist
-			if item.Texture != nil && len(item.Texture.Frames) > 0 {
-				m.uiState.itemEditor.frameCountStr = strconv.Itoa(len(item.Texture.Frames))
-				m.uiState.itemEditor.animationTimeStr = fmt.Sprintf("%.1f", item.Texture.AnimationTime)
-				m.uiState.itemEditor.selectedFrames = make([]string, len(item.Texture.Frames))
-				for i, frame := range item.Texture.Frames {
-					m.uiState.itemEditor.selectedFrames[i] = frame.Name
-				}
-			}
-
+			m.openItemEditor(*item)
 			m.uiState.showItemList = false
 		}
 
 		if rl.CheckCollisionPointRec(rl.GetMousePosition(), deleteBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-			// Remove the Item
+			// Remove the Item, the rows shift so stop drawing them this frame
 			m.tileGrid.Items = append(m.tileGrid.Items[:i], m.tileGrid.Items[i+1:]...)
+			m.markDirty()
+			break
 		}
 	}
 
@@ -2782,6 +3426,7 @@ type TextureEditorState struct {
 	mirrorY       bool
 	clearedInputs map[string]bool
 	layer         beam.Layer
+	tintPicker    ColorPicker
 
 	// Advanced Editor State
 	advAnimationTimeStr    string
@@ -2789,6 +3434,12 @@ type TextureEditorState struct {
 	advSelectedFrames      []string // Stores texture names for each frame
 	advSelectingFrameIndex int      // Index of the frame being selected via resource viewer, -1 if none
 	selectedFrameIndex     int
+
+	// Animation preview, played on its own clock rather than rl.GetTime()
+	previewPlaying bool
+	previewElapsed float64
+	// Ghost the frames either side of the previewed one over it
+	previewOnionSkin bool
 }
 
 func (m *MapMaker) renderTextureEditor() {
@@ -2844,23 +3495,10 @@ func (m *MapMaker) renderTextureEditor() {
 		}
 		if m.uiState.activeInput == label {
 			rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
-
-			// Clear value on first keypress if not already cleared
-			key := rl.GetCharPressed()
-			if !editor.clearedInputs[label] && key > 0 {
-				*value = ""
-				editor.clearedInputs[label] = true
-			}
-
-			for key > 0 {
-				if key >= 32 && key <= 126 {
-					*value += string(key)
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
+			m.clearingTextInput(editor, label, value)
+		}
+		if msg, ok := invalidNumbers(editor.numberFields())[label]; ok {
+			drawNumberError(inputRect, msg)
 		}
 	}
 
@@ -2912,9 +3550,13 @@ func (m *MapMaker) renderTextureEditor() {
 	// Draw layer dropdown
 	m.renderLayerDropdown(dialogX, padding, layerY, labelWidth, inputWidth+55, inputHeight, editor)
 
-	// Continue with tint inputs
-	tintLabel := "Tint RGBA:"
+	// Continue with tint inputs, with a swatch that opens the color picker
+	tintLabel := "Tint:"
 	rl.DrawText(tintLabel, int32(dialogX+padding)-5, int32(y+8), 16, rl.Black)
+	tintSwatch := rl.Rectangle{X: float32(dialogX + padding + 40), Y: float32(y), Width: 30, Height: float32(inputHeight)}
+	if drawTintSwatch(tintSwatch, editor.tintR, editor.tintG, editor.tintB, editor.tintA) {
+		editor.tintPicker.Open(dialogX+dialogWidth+10, y-colorPickerHeight/2, &editor.tintR, &editor.tintG, &editor.tintB, &editor.tintA)
+	}
 
 	tintWidth := 45
 	tintSpacing := 5
@@ -2948,25 +3590,13 @@ func (m *MapMaker) renderTextureEditor() {
 		}
 		if m.uiState.activeInput == label {
 			rl.DrawRectangleLinesEx(rect, 2, rl.Blue)
-
-			// Clear value on first keypress if not already cleared
-			key := rl.GetCharPressed()
-			if !editor.clearedInputs[label] && key > 0 {
-				*value = ""
-				editor.clearedInputs[label] = true
-			}
-
-			for key > 0 {
-				if key >= 32 && key <= 126 {
-					*value += string(key)
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
+			m.clearingTextInput(editor, label, value)
+		}
+		if msg, ok := invalidNumbers(editor.numberFields())[label]; ok {
+			drawNumberError(rect, msg)
 		}
 	}
+	editor.tintPicker.Draw()
 
 	// Save/Cancel buttons
 	btnWidth := 80
@@ -2990,10 +3620,16 @@ func (m *MapMaker) renderTextureEditor() {
 		Height: float32(btnHeight),
 	}
 
+	// Saving is blocked while any field is invalid
+	valid := len(invalidNumbers(editor.numberFields())) == 0
+	saveTextColor := rl.Black
+	if !valid {
+		saveTextColor = rl.Gray
+	}
 	rl.DrawRectangleRec(saveBtn, rl.LightGray)
 	rl.DrawRectangleRec(cancelBtn, rl.LightGray)
 	rl.DrawRectangleRec(advancedBtn, rl.LightGray)
-	rl.DrawText("Save", int32(saveBtn.X+20), int32(saveBtn.Y+8), 16, rl.Black)
+	rl.DrawText("Save", int32(saveBtn.X+20), int32(saveBtn.Y+8), 16, saveTextColor)
 	rl.DrawText("Cancel", int32(cancelBtn.X+15), int32(cancelBtn.Y+8), 16, rl.Black)
 	rl.DrawText("Advanced", int32(advancedBtn.X+4), int32(advancedBtn.Y+8), 16, rl.Black)
 
@@ -3002,7 +3638,9 @@ func (m *MapMaker) renderTextureEditor() {
 		m.closeTextureEditor()
 	}
 
-	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) && !valid {
+		m.showToast(invalidNumbersMessage, ToastError)
+	} else if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 		// Update all selected tiles with new values
 		for _, pos := range m.uiState.tileInfoPos {
 			tile := &m.tileGrid.Tiles[pos.Y][pos.X]
@@ -3025,6 +3663,7 @@ func (m *MapMaker) renderTextureEditor() {
 				frame.Tint = rl.Color{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}
 			}
 		}
+		m.markDirty()
 		m.closeTextureEditor()
 	}
 
@@ -3045,6 +3684,8 @@ func (m *MapMaker) renderTextureEditor() {
 			editor.selectedFrameIndex = -1               // Initialize to no selection
 		}
 		editor.advSelectingFrameIndex = -1
+		editor.previewPlaying = true
+		editor.previewElapsed = 0
 		m.uiState.showAdvancedEditor = true
 		m.uiState.advancedEditorOpenTime = rl.GetTime()
 		m.uiState.activeInput = ""
@@ -3173,6 +3814,8 @@ func (m *MapMaker) renderAdvancedEditor() {
 	inputWidth := 80
 	inputHeight := 30
 
+	m.renderAnimationPreview(dialogX+dialogWidth-padding-130, contentY)
+
 	// Helper function for input fields in this context
 	createAdvInput := func(label string, value *string, yPos int, inputID string) {
 		rl.DrawText(label, int32(dialogX+padding), int32(yPos+8), 16, rl.Black)
@@ -3191,18 +3834,8 @@ func (m *MapMaker) renderAdvancedEditor() {
 		}
 		if m.uiState.activeInput == inputID {
 			rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
-
-			key := rl.GetCharPressed()
-			for key > 0 {
-				if (key >= '0' && key <= '9') || (inputID == "advAnimTime" && key == '.') {
-					*value += string(key)
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(*value) > 0 {
-				*value = (*value)[:len(*value)-1]
-			}
 		}
+		m.textInput(value, m.uiState.activeInput == inputID, true)
 	}
 
 	// Animation Time Input - Disable if frameCount is 1
@@ -3238,18 +3871,8 @@ func (m *MapMaker) renderAdvancedEditor() {
 		}
 		if m.uiState.activeInput == "advAnimTime" {
 			rl.DrawRectangleLinesEx(animTimeInputRect, 2, rl.Blue)
-
-			key := rl.GetCharPressed()
-			for key > 0 {
-				if (key >= '0' && key <= '9') || key == '.' {
-					editor.advAnimationTimeStr += string(key)
-				}
-				key = rl.GetCharPressed()
-			}
-			if rl.IsKeyPressed(rl.KeyBackspace) && len(editor.advAnimationTimeStr) > 0 {
-				editor.advAnimationTimeStr = editor.advAnimationTimeStr[:len(editor.advAnimationTimeStr)-1]
-			}
 		}
+		m.textInput(&editor.advAnimationTimeStr, m.uiState.activeInput == "advAnimTime", true)
 	}
 	contentY += inputHeight + padding
 
@@ -3530,6 +4153,7 @@ func (m *MapMaker) renderAdvancedEditor() {
 				}
 			}
 
+			m.markDirty()
 			m.showToast("Texture properties saved!", ToastSuccess)
 			m.uiState.showAdvancedEditor = false
 			m.uiState.textureEditor = nil
@@ -3565,11 +4189,84 @@ func (m *MapMaker) closeAllEditors() {
 	m.uiState.activeInput = ""
 }
 
+// openNPCEditor opens the NPC editor, pre-filled from an NPC's data.
+func (m *MapMaker) openNPCEditor(data beam.NPCData) {
+	m.uiState.npcEditor = &NPCEditorState{
+		visible:          true,
+		data:             data,
+		id:               data.ID,
+		spawnPos:         data.SpawnPos,
+		size:             data.Size,
+		name:             data.Name,
+		health:           strconv.Itoa(data.MaxHealth),
+		attack:           strconv.Itoa(data.BaseAttack),
+		defense:          strconv.Itoa(data.BaseDefense),
+		attackSpeed:      fmt.Sprintf("%.1f", data.BaseAttackSpeed),
+		attackRange:      fmt.Sprintf("%.1f", data.BaseAttackRange),
+		moveSpeed:        fmt.Sprintf("%.1f", data.MoveSpeed),
+		aggroRange:       strconv.Itoa(data.AggroRange),
+		isHostile:        data.Hostile,
+		textures:         data.Texture,
+		idleTextures:     editableNPCTexture(data.IdleTexture),
+		attackTextures:   editableNPCTexture(data.AttackTexture),
+		editingDirection: beam.DirDown,
+		frameCountStr:    "1",
+		animationTimeStr: "0.5",
+		selectedFrames:   make([]string, 1),
+		spawnXStr:        strconv.Itoa(data.SpawnPos.X), // Initialize spawnXStr
+		spawnYStr:        strconv.Itoa(data.SpawnPos.Y), // Initialize spawnYStr
+		attackable:       data.Attackable,
+		impassable:       data.Impassable,
+		wanderRange:      strconv.Itoa(data.WanderRange),
+	}
+	m.uiState.npcEditor.selectedFrameIndex = -1
+}
+
 func (m *MapMaker) closeNPCEditor() {
 	m.uiState.npcEditor = nil
 	m.showResourceViewer = false // Close the resource viewer if it was open
 }
 
+// openItemEditor opens the item editor, pre-filled from an item.
+func (m *MapMaker) openItemEditor(item beam.Item) {
+	m.uiState.itemEditor = &ItemEditorState{
+		visible:          true,
+		spawnPos:         item.Pos,
+		id:               item.ID,
+		name:             item.Name,
+		description:      item.Description,
+		itemType:         item.Type,
+		equipmentType:    item.EquipmentType,
+		texture:          item.Texture,
+		blocking:         item.Blocking,
+		equippable:       item.Equippable,
+		consumable:       item.Consumable,
+		stackable:        item.Stackable,
+		maxStack:         strconv.Itoa(item.MaxStack),
+		quantity:         strconv.Itoa(item.Quantity),
+		attack:           strconv.Itoa(item.Stats.Attack),
+		defense:          strconv.Itoa(item.Stats.Defense),
+		attackSpeed:      strconv.Itoa(item.Stats.AttackSpeed),
+		attackRange:      strconv.Itoa(item.Stats.AttackRange),
+		levelReq:         strconv.Itoa(item.Requirements.Level),
+		spawnXStr:        strconv.Itoa(item.Pos.X),
+		spawnYStr:        strconv.Itoa(item.Pos.Y),
+		frameCountStr:    "1",
+		animationTimeStr: "0.5",
+		selectedFrames:   make([]string, 1),
+	}
+
+	// Initialize texture frames if they exist
+	if item.Texture != nil && len(item.Texture.Frames) > 0 {
+		m.uiState.itemEditor.frameCountStr = strconv.Itoa(len(item.Texture.Frames))
+		m.uiState.itemEditor.animationTimeStr = fmt.Sprintf("%.1f", item.Texture.AnimationTime)
+		m.uiState.itemEditor.selectedFrames = make([]string, len(item.Texture.Frames))
+		for i, frame := range item.Texture.Frames {
+			m.uiState.itemEditor.selectedFrames[i] = frame.Name
+		}
+	}
+}
+
 func (m *MapMaker) closeItemEditor() {
 	m.uiState.itemEditor = nil
 	m.showResourceViewer = false // Close the resource viewer if it was open
@@ -0,0 +1,148 @@
+package mapmaker
+
+import (
+	"image/color"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+const (
+	MinimapMaxWidth  = 160
+	MinimapMaxHeight = 120
+	MinimapMargin    = 10
+)
+
+// Minimap colors, by tile type and whether the tile has any textures
+var (
+	minimapEmptyFloor    = color.RGBA{R: 235, G: 235, B: 235, A: 255}
+	minimapTexturedFloor = color.RGBA{R: 150, G: 190, B: 120, A: 255}
+	minimapEmptyWall     = color.RGBA{R: 90, G: 90, B: 90, A: 255}
+	minimapTexturedWall  = color.RGBA{R: 120, G: 95, B: 75, A: 255}
+	minimapChest         = color.RGBA{R: 230, G: 180, B: 40, A: 255}
+)
+
+// minimapColor returns the pixel color used for a tile on the minimap.
+func minimapColor(tile beam.Tile) color.RGBA {
+	textured := len(tile.Textures) > 0
+	switch {
+	case tile.Type == beam.ChestTile:
+		return minimapChest
+	case tile.Type == beam.WallTile && textured:
+		return minimapTexturedWall
+	case tile.Type == beam.WallTile:
+		return minimapEmptyWall
+	case textured:
+		return minimapTexturedFloor
+	default:
+		return minimapEmptyFloor
+	}
+}
+
+// minimapVisible reports if the map is larger than the viewport, and needs a minimap.
+func (m *MapMaker) minimapVisible() bool {
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
+	return m.tileGrid.Width > maxVisibleWidth || m.tileGrid.Height > maxVisibleHeight
+}
+
+// minimapRect is where the minimap is drawn, in the bottom right above the tab bar.
+func (m *MapMaker) minimapRect() rl.Rectangle {
+	scale := min(float32(MinimapMaxWidth)/float32(m.tileGrid.Width), float32(MinimapMaxHeight)/float32(m.tileGrid.Height))
+	width := float32(m.tileGrid.Width) * scale
+	height := float32(m.tileGrid.Height) * scale
+	return rl.Rectangle{
+		X:      float32(m.window.width) - width - MinimapMargin,
+		Y:      float32(m.tabBarY()) - height - MinimapMargin,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// isOverMinimap reports if a screen position is on the minimap.
+func (m *MapMaker) isOverMinimap(pos rl.Vector2) bool {
+	return m.minimapVisible() && rl.CheckCollisionPointRec(pos, m.minimapRect())
+}
+
+// updateMinimapTexture rebuilds the minimap texture, only if the tiles have changed since the last rebuild.
+func (m *MapMaker) updateMinimapTexture() {
+	width, height := m.tileGrid.Width, m.tileGrid.Height
+	if !m.tileGrid.minimapDirty && m.tileGrid.minimap.Width == int32(width) && m.tileGrid.minimap.Height == int32(height) {
+		return
+	}
+
+	pixels := make([]color.RGBA, 0, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels = append(pixels, minimapColor(m.tileGrid.Tiles[y][x]))
+		}
+	}
+
+	// Reuse the texture unless the grid was resized
+	if m.tileGrid.minimap.Width != int32(width) || m.tileGrid.minimap.Height != int32(height) {
+		if m.tileGrid.minimap.ID != 0 {
+			rl.UnloadTexture(m.tileGrid.minimap)
+		}
+		img := rl.GenImageColor(width, height, rl.Blank)
+		m.tileGrid.minimap = rl.LoadTextureFromImage(img)
+		rl.UnloadImage(img)
+	}
+	rl.UpdateTexture(m.tileGrid.minimap, pixels)
+	m.tileGrid.minimapDirty = false
+}
+
+// renderMinimap draws the whole map scaled down, with an outline of the current viewport.
+func (m *MapMaker) renderMinimap() {
+	if !m.minimapVisible() {
+		return
+	}
+	m.updateMinimapTexture()
+
+	rect := m.minimapRect()
+	scale := rect.Width / float32(m.tileGrid.Width)
+	rl.DrawRectangleRec(rl.Rectangle{X: rect.X - 2, Y: rect.Y - 2, Width: rect.Width + 4, Height: rect.Height + 4}, rl.RayWhite)
+	rl.DrawTexturePro(
+		m.tileGrid.minimap,
+		rl.Rectangle{X: 0, Y: 0, Width: float32(m.tileGrid.minimap.Width), Height: float32(m.tileGrid.minimap.Height)},
+		rect,
+		rl.Vector2{X: 0, Y: 0},
+		0,
+		rl.White,
+	)
+	rl.DrawRectangleLinesEx(rect, 1, rl.DarkGray)
+
+	// Outline the part of the map that's in the viewport
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
+	visibleWidth := min(maxVisibleWidth, m.tileGrid.Width-m.tileGrid.viewportOffset.X)
+	visibleHeight := min(maxVisibleHeight, m.tileGrid.Height-m.tileGrid.viewportOffset.Y)
+	rl.DrawRectangleLinesEx(rl.Rectangle{
+		X:      rect.X + float32(m.tileGrid.viewportOffset.X)*scale,
+		Y:      rect.Y + float32(m.tileGrid.viewportOffset.Y)*scale,
+		Width:  float32(visibleWidth) * scale,
+		Height: float32(visibleHeight) * scale,
+	}, 2, rl.Red)
+}
+
+// handleMinimapClick recenters the viewport on the clicked part of the minimap.
+// Dragging on the minimap keeps recentering. Returns true if the minimap used the mouse.
+func (m *MapMaker) handleMinimapClick() bool {
+	mousePos := rl.GetMousePosition()
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		m.uiState.isDraggingMinimap = m.isOverMinimap(mousePos) && !m.isDialogOpen()
+	}
+	if !rl.IsMouseButtonDown(rl.MouseLeftButton) || !m.minimapVisible() {
+		m.uiState.isDraggingMinimap = false
+	}
+	if !m.uiState.isDraggingMinimap {
+		return false
+	}
+
+	rect := m.minimapRect()
+	scale := rect.Width / float32(m.tileGrid.Width)
+	maxVisibleWidth, maxVisibleHeight := m.maxVisibleTiles()
+	m.tileGrid.viewportOffset = beam.Position{
+		X: int((mousePos.X-rect.X)/scale) - maxVisibleWidth/2,
+		Y: int((mousePos.Y-rect.Y)/scale) - maxVisibleHeight/2,
+	}
+	m.layoutGrid()
+	return true
+}
@@ -0,0 +1,192 @@
+package mapmaker
+
+import (
+	"fmt"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// missingRowHeight is the height of each row in the missing resources dialog lists.
+const missingRowHeight = 24
+
+// MissingResourcesDialogState lists the textures the map references that aren't loaded,
+// so they can be remapped to a loaded texture or loaded from a new file.
+type MissingResourcesDialogState struct {
+	selected      string // The missing texture name being repaired
+	textureScroll int    // First visible row of the loaded textures list
+	missingScroll int    // First visible row of the missing textures list
+}
+
+// MissingTexture is a texture name that isn't loaded, and how many tiles reference it.
+type MissingTexture struct {
+	Name  string
+	Tiles int
+}
+
+// missingTextures groups the missing resource tiles by texture name, sorted by name.
+// A tile is counted once per name, even if several of its frames use it.
+func (p MissingResources) missingTextures() []MissingTexture {
+	seen := make(map[MissingResource]bool)
+	counts := make(map[string]int)
+	for _, missing := range p {
+		if seen[missing] {
+			continue
+		}
+		seen[missing] = true
+		counts[missing.textureName]++
+	}
+
+	textures := make([]MissingTexture, 0, len(counts))
+	for name, tiles := range counts {
+		textures = append(textures, MissingTexture{Name: name, Tiles: tiles})
+	}
+	sort.Slice(textures, func(i, j int) bool {
+		return textures[i].Name < textures[j].Name
+	})
+	return textures
+}
+
+// openMissingResourcesDialog opens the repair dialog, with the first missing texture selected.
+func (m *MapMaker) openMissingResourcesDialog() {
+	dialog := &MissingResourcesDialogState{}
+	if textures := m.tileGrid.missingResourceTiles.missingTextures(); len(textures) > 0 {
+		dialog.selected = textures[0].Name
+	}
+	m.uiState.missingResourcesDialog = dialog
+}
+
+func (m *MapMaker) closeMissingResourcesDialog() {
+	m.uiState.missingResourcesDialog = nil
+}
+
+// remapMissingTexture points every reference to a missing texture at a loaded one, then revalidates the map.
+func (m *MapMaker) remapMissingTexture(missing, replacement string) {
+	m.renameTextureReferences(map[string]string{missing: replacement})
+	m.ValidateTileGrid()
+}
+
+// loadMissingTexture loads an image file under the missing texture's name, so the references resolve as they are.
+func (m *MapMaker) loadMissingTexture(missing string) {
+	path := openFileDialog()
+	if path == "" {
+		return
+	}
+	if err := m.loadResource(missing, path, false, 0, 0, 0); err != nil {
+		m.showToast("Error loading texture: "+err.Error(), ToastError)
+		return
+	}
+	m.showToast("Loaded "+missing, ToastSuccess)
+	m.afterMissingRepair()
+}
+
+// afterMissingRepair moves the selection on to the next missing texture, closing the dialog once none are left.
+func (m *MapMaker) afterMissingRepair() {
+	dialog := m.uiState.missingResourcesDialog
+	if dialog == nil {
+		return
+	}
+	textures := m.tileGrid.missingResourceTiles.missingTextures()
+	if len(textures) == 0 {
+		m.closeMissingResourcesDialog()
+		m.showToast("All textures resolved", ToastSuccess)
+		return
+	}
+	dialog.selected = textures[0].Name
+	dialog.missingScroll = 0
+}
+
+// renderMissingResourcesDialog draws the missing textures on the left and the loaded textures on the right.
+// Picking a loaded texture remaps the selected missing texture to it.
+func (m *MapMaker) renderMissingResourcesDialog() {
+	dialog := m.uiState.missingResourcesDialog
+	const dialogWidth, dialogHeight = 560, 420
+	const listHeight = 280
+	dialogX := int32((rl.GetScreenWidth() - dialogWidth) / 2)
+	dialogY := int32((rl.GetScreenHeight() - dialogHeight) / 2)
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+	wheel := rl.GetMouseWheelMove()
+
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(rl.Black, 0.7))
+	dialogRect := rl.Rectangle{X: float32(dialogX), Y: float32(dialogY), Width: dialogWidth, Height: dialogHeight}
+	rl.DrawRectangleRec(dialogRect, rl.RayWhite)
+	rl.DrawRectangleLinesEx(dialogRect, 1, rl.Gray)
+	rl.DrawText("Missing Resources", dialogX+20, dialogY+20, 24, rl.Black)
+
+	// Missing textures, with the number of tiles using each
+	missing := m.tileGrid.missingResourceTiles.missingTextures()
+	missingRect := rl.Rectangle{X: float32(dialogX + 20), Y: float32(dialogY + 80), Width: 250, Height: listHeight}
+	rl.DrawText("Missing", dialogX+20, dialogY+58, 16, rl.DarkGray)
+	rl.DrawRectangleRec(missingRect, rl.White)
+	rl.DrawRectangleLinesEx(missingRect, 1, rl.LightGray)
+	visibleRows := listHeight / missingRowHeight
+	if rl.CheckCollisionPointRec(mousePos, missingRect) {
+		dialog.missingScroll = clampScroll(dialog.missingScroll-int(wheel), len(missing), visibleRows)
+	}
+	for i := dialog.missingScroll; i < len(missing) && i < dialog.missingScroll+visibleRows; i++ {
+		rowRect := rl.Rectangle{X: missingRect.X, Y: missingRect.Y + float32((i-dialog.missingScroll)*missingRowHeight), Width: missingRect.Width, Height: missingRowHeight}
+		if missing[i].Name == dialog.selected {
+			rl.DrawRectangleRec(rowRect, rl.Fade(rl.Blue, 0.2))
+		} else if rl.CheckCollisionPointRec(mousePos, rowRect) {
+			rl.DrawRectangleRec(rowRect, rl.Fade(rl.LightGray, 0.5))
+		}
+		count := fmt.Sprintf("%d", missing[i].Tiles)
+		countWidth := rl.MeasureText(count, 16)
+		rl.DrawText(fitText(missing[i].Name, int32(rowRect.Width)-countWidth-20, 16), int32(rowRect.X+5), int32(rowRect.Y+4), 16, rl.Black)
+		rl.DrawText(count, int32(rowRect.X+rowRect.Width)-countWidth-5, int32(rowRect.Y+4), 16, rl.Gray)
+		if clicked && rl.CheckCollisionPointRec(mousePos, rowRect) {
+			dialog.selected = missing[i].Name
+		}
+	}
+
+	// Loaded textures, clicking one remaps the selected missing texture to it
+	textures, _ := m.resources.GetAllTextures("default", false)
+	texturesRect := rl.Rectangle{X: float32(dialogX + 290), Y: float32(dialogY + 80), Width: 250, Height: listHeight}
+	rl.DrawText("Remap to", dialogX+290, dialogY+58, 16, rl.DarkGray)
+	rl.DrawRectangleRec(texturesRect, rl.White)
+	rl.DrawRectangleLinesEx(texturesRect, 1, rl.LightGray)
+	if rl.CheckCollisionPointRec(mousePos, texturesRect) {
+		dialog.textureScroll = clampScroll(dialog.textureScroll-int(wheel), len(textures), visibleRows)
+	}
+	for i := dialog.textureScroll; i < len(textures) && i < dialog.textureScroll+visibleRows; i++ {
+		rowRect := rl.Rectangle{X: texturesRect.X, Y: texturesRect.Y + float32((i-dialog.textureScroll)*missingRowHeight), Width: texturesRect.Width, Height: missingRowHeight}
+		hovered := rl.CheckCollisionPointRec(mousePos, rowRect)
+		if hovered {
+			rl.DrawRectangleRec(rowRect, rl.Fade(rl.LightGray, 0.5))
+		}
+		preview := textures[i]
+		rl.DrawTexturePro(preview.Texture, preview.Region, rl.Rectangle{X: rowRect.X + 4, Y: rowRect.Y + 2, Width: 20, Height: 20}, rl.Vector2{}, 0, rl.White)
+		rl.DrawText(fitText(preview.Name, int32(rowRect.Width)-35, 16), int32(rowRect.X+30), int32(rowRect.Y+4), 16, rl.Black)
+		if clicked && hovered && dialog.selected != "" {
+			selected := dialog.selected
+			m.remapMissingTexture(selected, preview.Name)
+			m.showToast(fmt.Sprintf("Remapped %s to %s", selected, preview.Name), ToastSuccess)
+			m.afterMissingRepair()
+			return
+		}
+	}
+	if len(textures) == 0 {
+		rl.DrawText("No textures loaded", int32(texturesRect.X+10), int32(texturesRect.Y+10), 16, rl.Gray)
+	}
+
+	loadBtn := rl.Rectangle{X: float32(dialogX + 20), Y: float32(dialogY + 375), Width: 120, Height: 30}
+	closeBtn := rl.Rectangle{X: float32(dialogX + dialogWidth - 110), Y: float32(dialogY + 375), Width: 90, Height: 30}
+	rl.DrawRectangleRec(loadBtn, rl.Green)
+	rl.DrawText("Load File...", int32(loadBtn.X+12), int32(loadBtn.Y+7), 16, rl.White)
+	rl.DrawRectangleRec(closeBtn, rl.LightGray)
+	rl.DrawText("Close", int32(closeBtn.X+25), int32(closeBtn.Y+7), 16, rl.Black)
+
+	if rl.IsKeyPressed(rl.KeyEscape) || (clicked && rl.CheckCollisionPointRec(mousePos, closeBtn)) {
+		m.closeMissingResourcesDialog()
+		return
+	}
+	if clicked && rl.CheckCollisionPointRec(mousePos, loadBtn) && dialog.selected != "" {
+		m.loadMissingTexture(dialog.selected)
+	}
+}
+
+// clampScroll keeps a list's first visible row within the rows that can be scrolled to.
+func clampScroll(scroll, rows, visibleRows int) int {
+	return max(0, min(scroll, rows-visibleRows))
+}
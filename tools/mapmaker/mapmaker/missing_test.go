@@ -0,0 +1,52 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+	"github.com/ztkent/beam/resources"
+)
+
+// TestRemapMissingTexture remaps a missing texture to a loaded one, and checks every reference
+// on tiles and NPCs follows, while other missing textures are left alone.
+func TestRemapMissingTexture(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.resources = &resources.ResourceManager{Scenes: []resources.Scene{{
+		Name:         "default",
+		Loaded:       true,
+		SpriteSheets: []*resources.SpriteSheet{{Name: "tiles", Sprites: map[string]resources.Rectangle{"tiles_0_0": {}}, Loaded: true}},
+	}}}
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}}, "old_grass")
+	m.paintTiles(beam.Positions{{X: 2, Y: 2}}, "old_stone")
+	water := beam.NewSimpleTileTexture("old_grass", "old_stone", "old_grass")
+	water.IsAnimated = true
+	m.tileGrid.Tiles[1][1].Textures = append(m.tileGrid.Tiles[1][1].Textures, water)
+	npc := &beam.NPC{Data: beam.NPCData{Texture: beam.NewSimpleNPCTexture("old_grass")}}
+	m.tileGrid.NPCs = beam.NPCs{npc}
+	m.ValidateTileGrid()
+
+	want := []MissingTexture{{Name: "old_grass", Tiles: 3}, {Name: "old_stone", Tiles: 2}}
+	if got := m.tileGrid.missingResourceTiles.missingTextures(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Expected missing textures %v, got %v", want, got)
+	}
+
+	m.remapMissingTexture("old_grass", "tiles_0_0")
+
+	for _, pos := range []beam.Position{{X: 0, Y: 0}, {X: 1, Y: 0}} {
+		if got := m.tileGrid.Tiles[pos.Y][pos.X].Textures[0].Frames[0].Name; got != "tiles_0_0" {
+			t.Errorf("Expected %v to be remapped to tiles_0_0, got %s", pos, got)
+		}
+	}
+	frames := m.tileGrid.Tiles[1][1].Textures[0].Frames
+	if frames[0].Name != "tiles_0_0" || frames[1].Name != "old_stone" || frames[2].Name != "tiles_0_0" {
+		t.Errorf("Expected only the old_grass frames to be remapped, got %v", frames)
+	}
+	if got := npc.Data.Texture.Down.Frames[0].Name; got != "tiles_0_0" {
+		t.Errorf("Expected the NPC to be remapped to tiles_0_0, got %s", got)
+	}
+
+	want = []MissingTexture{{Name: "old_stone", Tiles: 2}}
+	if got := m.tileGrid.missingResourceTiles.missingTextures(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Expected only %v to be missing after the remap, got %v", want, got)
+	}
+}
@@ -0,0 +1,214 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestDuplicateNPC checks the copy is offset and renamed, and editing its frames leaves the original alone.
+func TestDuplicateNPC(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	original := &beam.NPC{Data: beam.NPCData{
+		Name:          "Guard",
+		SpawnPos:      beam.Position{X: 1, Y: 2},
+		Texture:       beam.NewSimpleNPCTexture("guard"),
+		IdleTexture:   beam.NewSimpleNPCTexture("guard_idle"),
+		AttackTexture: beam.NewSimpleNPCTexture("guard_attack"),
+	}}
+	m.tileGrid.NPCs = beam.NPCs{original}
+
+	duplicate := m.duplicateNPC(original)
+	if duplicate.Data.Name != "Guard (copy)" {
+		t.Errorf("Expected the copy to be named 'Guard (copy)', got %q", duplicate.Data.Name)
+	}
+	if duplicate.Data.SpawnPos != (beam.Position{X: 2, Y: 2}) || duplicate.Pos != duplicate.Data.SpawnPos {
+		t.Errorf("Expected the copy to spawn at 2,2, got %v", duplicate.Data.SpawnPos)
+	}
+	if len(m.tileGrid.NPCs) != 2 {
+		t.Fatalf("Expected 2 NPCs on the map, got %d", len(m.tileGrid.NPCs))
+	}
+
+	duplicate.Data.Texture.Down.Frames[0].Name = "changed"
+	duplicate.Data.Texture.Up.Frames = append(duplicate.Data.Texture.Up.Frames, beam.Texture{Name: "extra"})
+	duplicate.Data.IdleTexture.Left.Frames[0].Name = "changed"
+	if original.Data.Texture.Down.Frames[0].Name != "guard" || len(original.Data.Texture.Up.Frames) != 1 {
+		t.Errorf("Expected the original's frames to be unchanged")
+	}
+	duplicate.Data.AttackTexture.Right.Frames[0].Name = "changed"
+	if original.Data.IdleTexture.Left.Frames[0].Name != "guard_idle" {
+		t.Errorf("Expected the original's idle frames to be unchanged")
+	}
+	if original.Data.AttackTexture.Right.Frames[0].Name != "guard_attack" {
+		t.Errorf("Expected the original's attack frames to be unchanged")
+	}
+
+	// The next copy skips the tile the first one is on
+	again := m.duplicateNPC(original)
+	if again.Data.Name != "Guard (copy) 2" {
+		t.Errorf("Expected a second copy to get a unique name, got %q", again.Data.Name)
+	}
+	if again.Data.SpawnPos != (beam.Position{X: 0, Y: 2}) {
+		t.Errorf("Expected the second copy to spawn at 0,2, got %v", again.Data.SpawnPos)
+	}
+}
+
+// TestDuplicateNPCSkipsWalls checks a copy isn't placed on a wall next to the original.
+func TestDuplicateNPCSkipsWalls(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	m.tileGrid.Tiles[2][2].Type = beam.WallTile
+	m.tileGrid.Tiles[2][0].Type = beam.WallTile
+	original := &beam.NPC{Data: beam.NPCData{Name: "Guard", SpawnPos: beam.Position{X: 1, Y: 2}, Texture: beam.NewSimpleNPCTexture("guard")}}
+	m.tileGrid.NPCs = beam.NPCs{original}
+
+	if duplicate := m.duplicateNPC(original); duplicate.Data.SpawnPos != (beam.Position{X: 1, Y: 3}) {
+		t.Errorf("Expected the copy to go below the original at 1,3, got %v", duplicate.Data.SpawnPos)
+	}
+}
+
+// TestDuplicateNPCLarge checks a large NPC's copy goes clear of its footprint, and of other NPCs' footprints,
+// not just their top left tiles.
+func TestDuplicateNPCLarge(t *testing.T) {
+	m := newTestMapMaker(8, 8)
+	original := &beam.NPC{Data: beam.NPCData{Name: "Ogre", Size: beam.NPCSize2x2, SpawnPos: beam.Position{X: 2, Y: 2}, Texture: beam.NewSimpleNPCTexture("ogre")}}
+	// A 3x3 NPC whose top left tile is clear of the spot to the right, but whose footprint covers it
+	troll := &beam.NPC{Data: beam.NPCData{Name: "Troll", Size: beam.NPCSize3x3, SpawnPos: beam.Position{X: 4, Y: 3}, Texture: beam.NewSimpleNPCTexture("troll")}}
+	m.tileGrid.NPCs = beam.NPCs{original, troll}
+
+	// Right of the ogre overlaps the troll, and left of it is free
+	duplicate := m.duplicateNPC(original)
+	if duplicate.Data.SpawnPos != (beam.Position{X: 0, Y: 2}) {
+		t.Errorf("Expected the copy at 0,2 clear of both footprints, got %v", duplicate.Data.SpawnPos)
+	}
+	for _, other := range []*beam.NPC{original, troll} {
+		if npcFootprintsOverlap(duplicate.Data.SpawnPos, duplicate.Data.Size, other.Data.SpawnPos, other.Data.Size) {
+			t.Errorf("Expected the copy not to overlap %s", other.Data.Name)
+		}
+	}
+}
+
+// TestNPCNameTaken checks names clash with other NPCs ignoring case and spaces, but not with the NPC being edited.
+func TestNPCNameTaken(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	m.tileGrid.NPCs = beam.NPCs{
+		{Data: beam.NPCData{ID: "a", Name: "Guard"}},
+		{Data: beam.NPCData{ID: "b", Name: "Guard 2"}},
+	}
+
+	if !m.npcNameTaken("Guard", "") || !m.npcNameTaken(" guard ", "b") {
+		t.Error("Expected Guard to be taken")
+	}
+	if m.npcNameTaken("Guard", "a") {
+		t.Error("Expected an NPC to keep its own name")
+	}
+	if m.npcNameTaken("Merchant", "") {
+		t.Error("Expected Merchant to be free")
+	}
+	if got := m.uniqueNPCName("Guard", ""); got != "Guard 3" {
+		t.Errorf("Expected the suggestion 'Guard 3', got %q", got)
+	}
+	if got := m.uniqueNPCName("Guard 2", "b"); got != "Guard 2" {
+		t.Errorf("Expected the edited NPC's own name to be free, got %q", got)
+	}
+}
+
+// TestSaveEditedNPC checks saving matches NPCs by ID, so a rename replaces the NPC instead of adding or merging.
+func TestSaveEditedNPC(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	m.tileGrid.NPCs = beam.NPCs{
+		{Data: beam.NPCData{Name: "Guard"}},
+		{Data: beam.NPCData{Name: "Merchant"}},
+	}
+	m.ensureNPCIDs()
+	guardID := m.tileGrid.NPCs[0].Data.ID
+	if guardID == "" || guardID == m.tileGrid.NPCs[1].Data.ID {
+		t.Fatalf("Expected every NPC to get a different ID, got %q and %q", guardID, m.tileGrid.NPCs[1].Data.ID)
+	}
+
+	m.saveEditedNPC(&beam.NPC{Data: beam.NPCData{ID: guardID, Name: "Captain"}})
+	if len(m.tileGrid.NPCs) != 2 || m.tileGrid.NPCs[0].Data.Name != "Captain" || m.tileGrid.NPCs[1].Data.Name != "Merchant" {
+		t.Errorf("Expected the guard to be renamed in place, got %d NPCs", len(m.tileGrid.NPCs))
+	}
+
+	added := &beam.NPC{Data: beam.NPCData{Name: "Merchant"}}
+	m.saveEditedNPC(added)
+	if len(m.tileGrid.NPCs) != 3 || added.Data.ID == "" {
+		t.Errorf("Expected a new NPC to be added with an ID, got %d NPCs", len(m.tileGrid.NPCs))
+	}
+
+	duplicate := m.duplicateNPC(m.tileGrid.NPCs[0])
+	if duplicate.Data.ID == guardID {
+		t.Error("Expected the duplicate to get its own ID")
+	}
+}
+
+// TestNPCEditorKeepsHiddenFields checks saving from the NPC editor keeps the fields it doesn't show,
+// like loot, faction and experience, while taking the ones it does from the editor.
+func TestNPCEditorKeepsHiddenFields(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	loot := &beam.LootTable{Entries: []beam.LootEntry{{Weight: 1}}}
+	m.openNPCEditor(beam.NPCData{
+		ID:               "goblin",
+		Name:             "Goblin",
+		MaxHealth:        20,
+		Faction:          "goblins",
+		Level:            3,
+		ExperienceReward: 40,
+		HitEffects:       []beam.StatusEffect{{Kind: beam.StatusPoison, Magnitude: 2}},
+		Loot:             loot,
+	})
+	editor := m.uiState.npcEditor
+	editor.name = "Goblin Chief"
+	editor.health = "35"
+
+	data := editor.npcData()
+	if data.Name != "Goblin Chief" || data.MaxHealth != 35 || data.ID != "goblin" {
+		t.Errorf("Expected the edited name and health, got %q with %d health", data.Name, data.MaxHealth)
+	}
+	if data.Loot != loot || data.Faction != "goblins" || data.Level != 3 || data.ExperienceReward != 40 || len(data.HitEffects) != 1 {
+		t.Errorf("Expected the loot, faction, level, reward and hit effects to be kept, got %+v", data)
+	}
+}
+
+// TestNPCEditorIdleAttackTextures checks idle and attack frames are edited separately from the base texture,
+// and only the directions with frames are saved.
+func TestNPCEditorIdleAttackTextures(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	idle := &beam.NPCTexture{Down: &beam.AnimatedTexture{Frames: []beam.Texture{{Name: "guard_idle"}}}}
+	m.openNPCEditor(beam.NPCData{Name: "Guard", Texture: beam.NewSimpleNPCTexture("guard"), IdleTexture: idle})
+	editor := m.uiState.npcEditor
+	if idle.Up != nil {
+		t.Error("Expected opening the editor to leave the NPC's idle texture alone")
+	}
+
+	editor.editingSet = npcTextureAttack
+	editor.editingDirection = beam.DirLeft
+	editor.loadCurrentFrames()
+	if editor.frameCountStr != "1" || editor.selectedFrames[0] != "" {
+		t.Errorf("Expected an empty attack frame grid, got %v", editor.selectedFrames)
+	}
+	editor.currentTexture().Frames = []beam.Texture{{Name: "guard_attack"}}
+
+	editor.editingSet = npcTextureIdle
+	editor.editingDirection = beam.DirDown
+	editor.loadCurrentFrames()
+	if len(editor.selectedFrames) != 1 || editor.selectedFrames[0] != "guard_idle" {
+		t.Errorf("Expected the idle frames to load, got %v", editor.selectedFrames)
+	}
+
+	data := editor.npcData()
+	if data.Texture.Left.Frames[0].Name != "guard" {
+		t.Errorf("Expected the base texture to be unchanged, got %q", data.Texture.Left.Frames[0].Name)
+	}
+	if data.AttackTexture == nil || data.AttackTexture.Left.Frames[0].Name != "guard_attack" || data.AttackTexture.Down != nil {
+		t.Errorf("Expected only the left attack texture to be saved, got %+v", data.AttackTexture)
+	}
+	if data.IdleTexture == nil || data.IdleTexture.Down.Frames[0].Name != "guard_idle" || data.IdleTexture.Up != nil {
+		t.Errorf("Expected only the down idle texture to be saved, got %+v", data.IdleTexture)
+	}
+
+	editor.idleTextures = completeNPCTexture(nil)
+	if data := editor.npcData(); data.IdleTexture != nil {
+		t.Errorf("Expected no idle texture without frames, got %+v", data.IdleTexture)
+	}
+}
@@ -0,0 +1,153 @@
+package mapmaker
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// numberKind is the kind of number a numeric field takes.
+type numberKind int
+
+const (
+	decimalNumber numberKind = iota
+	wholeNumber
+	colorChannel // A whole number from 0 to 255
+)
+
+// numberField is a numeric editor field, checked before the editor saves.
+// Optional fields can be left empty.
+type numberField struct {
+	label    string
+	value    string
+	kind     numberKind
+	optional bool
+}
+
+// numberError returns why the text isn't a number of the kind, or "" if it is.
+func numberError(text string, kind numberKind, optional bool) string {
+	if text == "" && optional {
+		return ""
+	}
+	switch kind {
+	case wholeNumber:
+		if _, err := strconv.Atoi(text); err != nil {
+			return "must be a whole number"
+		}
+	case colorChannel:
+		if v, err := strconv.Atoi(text); err != nil || v < 0 || v > 255 {
+			return "must be 0 to 255"
+		}
+	default:
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return "must be a number"
+		}
+	}
+	return ""
+}
+
+// invalidNumbers returns why each field that doesn't hold a valid number is invalid, keyed by label.
+func invalidNumbers(fields []numberField) map[string]string {
+	invalid := make(map[string]string)
+	for _, field := range fields {
+		if msg := numberError(field.value, field.kind, field.optional); msg != "" {
+			invalid[field.label] = msg
+		}
+	}
+	return invalid
+}
+
+// invalidNumbersMessage is shown when saving is blocked by invalid number fields.
+const invalidNumbersMessage = "Fix the highlighted fields before saving"
+
+// drawNumberError outlines an invalid field in red, and shows why above it while it's hovered.
+// Fields are drawn top to bottom, so the tooltip isn't covered by the fields after it.
+func drawNumberError(rect rl.Rectangle, msg string) {
+	rl.DrawRectangleLinesEx(rect, 2, rl.Red)
+	if !rl.CheckCollisionPointRec(rl.GetMousePosition(), rect) {
+		return
+	}
+	const fontSize, padding = 14, 4
+	msg = strings.ToUpper(msg[:1]) + msg[1:]
+	tip := rl.Rectangle{
+		X:      rect.X,
+		Y:      rect.Y - fontSize - padding*2 - 2,
+		Width:  float32(rl.MeasureText(msg, fontSize) + padding*2),
+		Height: fontSize + padding*2,
+	}
+	rl.DrawRectangleRec(tip, rl.White)
+	rl.DrawRectangleLinesEx(tip, 1, rl.Red)
+	rl.DrawText(msg, int32(tip.X+padding), int32(tip.Y+padding), fontSize, rl.Red)
+}
+
+// numberFields returns the texture editor's number fields.
+func (editor *TextureEditorState) numberFields() []numberField {
+	return []numberField{
+		{label: "Rotation", value: editor.rotation},
+		{label: "Scale X", value: editor.scalex},
+		{label: "Scale Y", value: editor.scaley},
+		{label: "Offset X", value: editor.offsetX},
+		{label: "Offset Y", value: editor.offsetY},
+		{label: "TintR", value: editor.tintR, kind: colorChannel},
+		{label: "TintG", value: editor.tintG, kind: colorChannel},
+		{label: "TintB", value: editor.tintB, kind: colorChannel},
+		{label: "TintA", value: editor.tintA, kind: colorChannel},
+	}
+}
+
+// numberFields returns the NPC editor's stat and animation number fields.
+// Wander Range can be left empty for an NPC that doesn't wander.
+func (editor *NPCEditorState) numberFields() []numberField {
+	return []numberField{
+		{label: "Health", value: editor.health, kind: wholeNumber},
+		{label: "Attack", value: editor.attack, kind: wholeNumber},
+		{label: "Defense", value: editor.defense, kind: wholeNumber},
+		{label: "Attack Speed", value: editor.attackSpeed},
+		{label: "Attack Range", value: editor.attackRange},
+		{label: "Wander Range", value: editor.wanderRange, kind: wholeNumber, optional: true},
+		{label: "Move Speed", value: editor.moveSpeed},
+		{label: "Aggro Range", value: editor.aggroRange, kind: wholeNumber},
+		{label: "Frame Count", value: editor.frameCountStr, kind: wholeNumber},
+		{label: "Animation Time", value: editor.animationTimeStr},
+	}
+}
+
+// frameNumberFields returns the number fields of the NPC editor's frame settings.
+func (editor *NPCEditorState) frameNumberFields() []numberField {
+	return []numberField{
+		{label: "Rotation", value: editor.frameRotation},
+		{label: "Scale X", value: editor.frameScaleX},
+		{label: "Scale Y", value: editor.frameScaleY},
+		{label: "Offset X", value: editor.frameOffsetX},
+		{label: "Offset Y", value: editor.frameOffsetY},
+		{label: "R", value: editor.frameTintR, kind: colorChannel},
+		{label: "G", value: editor.frameTintG, kind: colorChannel},
+		{label: "B", value: editor.frameTintB, kind: colorChannel},
+		{label: "A", value: editor.frameTintA, kind: colorChannel},
+	}
+}
+
+// numberFields returns the item editor's number fields. The stats are only
+// checked for equippable items, since they're hidden otherwise.
+func (editor *ItemEditorState) numberFields() []numberField {
+	fields := []numberField{
+		{label: "Max Stack", value: editor.maxStack, kind: wholeNumber},
+		{label: "Quantity", value: editor.quantity, kind: wholeNumber},
+	}
+	if editor.equippable {
+		fields = append(fields,
+			numberField{label: "Attack", value: editor.attack, kind: wholeNumber},
+			numberField{label: "Defense", value: editor.defense, kind: wholeNumber},
+			numberField{label: "Attack Speed", value: editor.attackSpeed, kind: wholeNumber},
+			numberField{label: "Attack Range", value: editor.attackRange, kind: wholeNumber},
+			numberField{label: "Level Req", value: editor.levelReq, kind: wholeNumber},
+		)
+	}
+	return append(fields,
+		numberField{label: "Frame Count", value: editor.frameCountStr, kind: wholeNumber},
+		numberField{label: "Animation Time", value: editor.animationTimeStr},
+	)
+}
@@ -0,0 +1,92 @@
+package mapmaker
+
+import "testing"
+
+// TestNumberError checks which text each kind of number field accepts.
+func TestNumberError(t *testing.T) {
+	tests := []struct {
+		text     string
+		kind     numberKind
+		optional bool
+		valid    bool
+	}{
+		{"1.5", decimalNumber, false, true},
+		{"-2", decimalNumber, false, true},
+		{"1.2.3", decimalNumber, false, false},
+		{"", decimalNumber, false, false},
+		{"", wholeNumber, true, true},
+		{"-", decimalNumber, false, false},
+		{"abc", decimalNumber, false, false},
+		{"NaN", decimalNumber, false, false},
+		{"Inf", decimalNumber, false, false},
+		{"12", wholeNumber, false, true},
+		{"1.5", wholeNumber, false, false},
+		{"0", colorChannel, false, true},
+		{"255", colorChannel, false, true},
+		{"256", colorChannel, false, false},
+		{"-1", colorChannel, false, false},
+		{"12.5", colorChannel, false, false},
+	}
+	for _, tt := range tests {
+		msg := numberError(tt.text, tt.kind, tt.optional)
+		if (msg == "") != tt.valid {
+			t.Errorf("%q (kind %d): expected valid=%v, got error %q", tt.text, tt.kind, tt.valid, msg)
+		}
+	}
+}
+
+// TestTextureEditorNumberFields checks the texture editor flags only its invalid fields.
+func TestTextureEditorNumberFields(t *testing.T) {
+	editor := &TextureEditorState{
+		rotation: "0", scalex: "1.2.3", scaley: "1", offsetX: "-", offsetY: "0",
+		tintR: "255", tintG: "300", tintB: "0", tintA: "abc",
+	}
+	invalid := invalidNumbers(editor.numberFields())
+	for _, label := range []string{"Scale X", "Offset X", "TintG", "TintA"} {
+		if _, ok := invalid[label]; !ok {
+			t.Errorf("Expected %s to be flagged, got %v", label, invalid)
+		}
+	}
+	if len(invalid) != 4 {
+		t.Errorf("Expected 4 invalid fields, got %d: %v", len(invalid), invalid)
+	}
+}
+
+// TestNPCEditorNumberFields checks NPC stats must be numbers, but Wander Range can be left empty.
+func TestNPCEditorNumberFields(t *testing.T) {
+	editor := &NPCEditorState{
+		health: "10", attack: "2", defense: "1", attackSpeed: "1.5", attackRange: "1",
+		moveSpeed: "2", aggroRange: "5", frameCountStr: "1", animationTimeStr: "0.5",
+	}
+	if invalid := invalidNumbers(editor.numberFields()); len(invalid) != 0 {
+		t.Fatalf("Expected no invalid fields, got %v", invalid)
+	}
+
+	editor.health = "1.5"
+	editor.moveSpeed = ""
+	editor.frameTintR = "256"
+	invalid := invalidNumbers(editor.numberFields())
+	if len(invalid) != 2 || invalid["Health"] == "" || invalid["Move Speed"] == "" {
+		t.Errorf("Expected Health and Move Speed to be flagged, got %v", invalid)
+	}
+	if msg := invalidNumbers(editor.frameNumberFields())["R"]; msg == "" {
+		t.Errorf("Expected frame tint R to be flagged")
+	}
+}
+
+// TestItemEditorNumberFields checks item stats are only flagged when they're shown.
+func TestItemEditorNumberFields(t *testing.T) {
+	editor := newTestItemEditor()
+	editor.attack = "x"
+	if invalid := invalidNumbers(editor.numberFields()); len(invalid) != 0 {
+		t.Errorf("Expected hidden stats to be ignored, got %v", invalid)
+	}
+	editor.equippable = true
+	if invalid := invalidNumbers(editor.numberFields()); invalid["Attack"] == "" {
+		t.Errorf("Expected Attack to be flagged, got %v", invalid)
+	}
+	editor.animationTimeStr = "0.5.1"
+	if err := editor.validate(); err == nil {
+		t.Errorf("Expected an invalid item to fail validation")
+	}
+}
@@ -0,0 +1,222 @@
+package mapmaker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// mixedValue is shown for a property that differs between the selected tiles.
+const mixedValue = "(mixed)"
+
+// PropertiesEditorState is the key/value editor for the custom properties of the selected tiles.
+type PropertiesEditorState struct {
+	positions   beam.Positions
+	key         string
+	value       string
+	activeField string // "key", "value" or ""
+}
+
+// openPropertiesEditor opens the properties editor for the selected tiles.
+func (m *MapMaker) openPropertiesEditor(positions beam.Positions) {
+	m.uiState.propertiesEditor = &PropertiesEditorState{
+		positions:   append(beam.Positions(nil), positions...),
+		activeField: "key",
+	}
+	m.uiState.activeInput = "tile_properties"
+}
+
+func (m *MapMaker) closePropertiesEditor() {
+	m.uiState.propertiesEditor = nil
+	m.uiState.activeInput = ""
+}
+
+// setTileProperty sets a property on every tile at positions, as one undo step.
+func (m *MapMaker) setTileProperty(positions beam.Positions, key, value string) {
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			setProperty(&m.tileGrid.Tiles[pos.Y][pos.X], key, value)
+		}
+	})
+}
+
+// deleteTileProperty removes a property from every tile at positions, as one undo step.
+func (m *MapMaker) deleteTileProperty(positions beam.Positions, key string) {
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			deleteProperty(&m.tileGrid.Tiles[pos.Y][pos.X], key)
+		}
+	})
+}
+
+func setProperty(tile *beam.Tile, key, value string) {
+	if tile.Properties == nil {
+		tile.Properties = make(map[string]string)
+	}
+	tile.Properties[key] = value
+}
+
+// deleteProperty removes a property, dropping the map once it's empty so it isn't saved.
+func deleteProperty(tile *beam.Tile, key string) {
+	delete(tile.Properties, key)
+	if len(tile.Properties) == 0 {
+		tile.Properties = nil
+	}
+}
+
+// commonProperties returns every property key set on any of the tiles, sorted, with its value.
+// Keys that are missing from some tiles, or set to different values, have the value mixedValue.
+func commonProperties(tiles []beam.Tile) ([]string, map[string]string) {
+	values := make(map[string]string)
+	for _, tile := range tiles {
+		for key, value := range tile.Properties {
+			if existing, ok := values[key]; ok && existing != value {
+				values[key] = mixedValue
+			} else {
+				values[key] = value
+			}
+		}
+	}
+	for key := range values {
+		for _, tile := range tiles {
+			if _, ok := tile.Properties[key]; !ok {
+				values[key] = mixedValue
+				break
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, values
+}
+
+// renderPropertiesEditor draws the properties of the selected tiles, with a row to add or change one.
+// Click a property to edit it, Tab switches between the key and value, and Enter sets the property.
+func (m *MapMaker) renderPropertiesEditor() {
+	editor := m.uiState.propertiesEditor
+	tiles := make([]beam.Tile, 0, len(editor.positions))
+	for _, pos := range editor.positions {
+		if pos.X < m.tileGrid.Width && pos.Y < m.tileGrid.Height {
+			tiles = append(tiles, m.tileGrid.Tiles[pos.Y][pos.X])
+		}
+	}
+	keys, values := commonProperties(tiles)
+
+	const rowHeight = 28
+	dialogWidth := 460
+	dialogHeight := 170 + max(1, len(keys))*rowHeight
+	dialogX := (rl.GetScreenWidth() - dialogWidth) / 2
+	dialogY := (rl.GetScreenHeight() - dialogHeight) / 2
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(rl.Black, 0.7))
+	dialogRect := rl.Rectangle{X: float32(dialogX), Y: float32(dialogY), Width: float32(dialogWidth), Height: float32(dialogHeight)}
+	rl.DrawRectangleRec(dialogRect, rl.RayWhite)
+	rl.DrawRectangleLinesEx(dialogRect, 1, rl.Gray)
+
+	title := "Tile Properties"
+	if len(tiles) == 1 {
+		title += fmt.Sprintf(" (%d, %d)", tiles[0].Pos.X, tiles[0].Pos.Y)
+	} else {
+		title += fmt.Sprintf(" (%d tiles)", len(tiles))
+	}
+	rl.DrawText(title, int32(dialogX+20), int32(dialogY+20), 24, rl.Black)
+
+	closeBtn := rl.Rectangle{X: float32(dialogX + dialogWidth - 40), Y: float32(dialogY + 10), Width: 30, Height: 30}
+	rl.DrawRectangleRec(closeBtn, rl.LightGray)
+	rl.DrawText("X", int32(closeBtn.X+10), int32(closeBtn.Y+5), 20, rl.Black)
+	if clicked && rl.CheckCollisionPointRec(mousePos, closeBtn) {
+		m.closePropertiesEditor()
+		return
+	}
+
+	// Existing properties, click one to load it into the inputs
+	y := dialogY + 60
+	if len(keys) == 0 {
+		rl.DrawText("No properties set", int32(dialogX+20), int32(y+6), 16, rl.DarkGray)
+	}
+	for _, key := range keys {
+		row := rl.Rectangle{X: float32(dialogX + 20), Y: float32(y), Width: float32(dialogWidth - 110), Height: rowHeight - 2}
+		deleteBtn := rl.Rectangle{X: float32(dialogX + dialogWidth - 80), Y: float32(y), Width: 60, Height: rowHeight - 2}
+		if rl.CheckCollisionPointRec(mousePos, row) {
+			rl.DrawRectangleRec(row, rl.LightGray)
+			if clicked {
+				editor.key = key
+				if values[key] != mixedValue {
+					editor.value = values[key]
+				}
+				editor.activeField = "value"
+			}
+		}
+		rl.DrawText(truncateName(key, 20), int32(row.X+6), int32(row.Y+6), 16, rl.Black)
+		rl.DrawText(truncateName(values[key], 20), int32(row.X+180), int32(row.Y+6), 16, rl.DarkGray)
+
+		rl.DrawRectangleRec(deleteBtn, rl.Red)
+		rl.DrawText("Delete", int32(deleteBtn.X+8), int32(deleteBtn.Y+6), 14, rl.White)
+		if clicked && rl.CheckCollisionPointRec(mousePos, deleteBtn) {
+			m.deleteTileProperty(editor.positions, key)
+		}
+		y += rowHeight
+	}
+
+	// Key and value inputs, with a button to set the property on every selected tile
+	inputY := float32(dialogY + dialogHeight - 90)
+	keyRect := rl.Rectangle{X: float32(dialogX + 20), Y: inputY, Width: 160, Height: 30}
+	valueRect := rl.Rectangle{X: float32(dialogX + 190), Y: inputY, Width: 160, Height: 30}
+	setBtn := rl.Rectangle{X: float32(dialogX + 360), Y: inputY, Width: 80, Height: 30}
+	rl.DrawText("Key", int32(keyRect.X), int32(inputY-18), 14, rl.DarkGray)
+	rl.DrawText("Value", int32(valueRect.X), int32(inputY-18), 14, rl.DarkGray)
+	for _, field := range []struct {
+		name string
+		rect rl.Rectangle
+		text string
+	}{{"key", keyRect, editor.key}, {"value", valueRect, editor.value}} {
+		if clicked && rl.CheckCollisionPointRec(mousePos, field.rect) {
+			editor.activeField = field.name
+		}
+		rl.DrawRectangleRec(field.rect, rl.White)
+		borderColor := rl.Gray
+		if editor.activeField == field.name {
+			borderColor = rl.Blue
+		}
+		rl.DrawRectangleLinesEx(field.rect, 2, borderColor)
+		rl.DrawText(fitText(field.text, int32(field.rect.Width-10), 16), int32(field.rect.X+5), int32(field.rect.Y+7), 16, rl.Black)
+	}
+	rl.DrawRectangleRec(setBtn, rl.Gray)
+	rl.DrawText("Set", int32(setBtn.X+27), int32(setBtn.Y+7), 16, rl.White)
+	rl.DrawText("Tab switches fields, Enter sets the property", int32(dialogX+20), int32(dialogY+dialogHeight-40), 14, rl.DarkGray)
+
+	// Type into the active field
+	field := &editor.key
+	if editor.activeField == "value" {
+		field = &editor.value
+	}
+	m.textInput(field, true, false)
+	if rl.IsKeyPressed(rl.KeyTab) {
+		if editor.activeField == "key" {
+			editor.activeField = "value"
+		} else {
+			editor.activeField = "key"
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeyEnter) || (clicked && rl.CheckCollisionPointRec(mousePos, setBtn)) {
+		key := strings.TrimSpace(editor.key)
+		if key == "" {
+			m.showToast("Property key is required", ToastError)
+			return
+		}
+		m.setTileProperty(editor.positions, key, editor.value)
+		m.showToast("Set property "+key, ToastSuccess)
+		editor.key, editor.value = "", ""
+		editor.activeField = "key"
+	}
+}
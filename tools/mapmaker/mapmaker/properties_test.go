@@ -0,0 +1,69 @@
+package mapmaker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestCommonProperties checks keys shared with the same value keep it, and anything else is mixed.
+func TestCommonProperties(t *testing.T) {
+	tiles := []beam.Tile{
+		{Properties: map[string]string{"water": "true", "damage": "5", "trigger": "a"}},
+		{Properties: map[string]string{"water": "true", "damage": "3"}},
+	}
+	keys, values := commonProperties(tiles)
+	if want := []string{"damage", "trigger", "water"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected keys %v, got %v", want, keys)
+	}
+	want := map[string]string{"water": "true", "damage": mixedValue, "trigger": mixedValue}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Expected values %v, got %v", want, values)
+	}
+
+	if keys, _ := commonProperties([]beam.Tile{{}}); len(keys) != 0 {
+		t.Errorf("Expected no keys for a tile without properties, got %v", keys)
+	}
+}
+
+// TestTilePropertyUndo sets and deletes a property on several tiles, and checks each is one undo step.
+func TestTilePropertyUndo(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	positions := beam.Positions{{X: 0, Y: 0}, {X: 2, Y: 1}}
+
+	m.setTileProperty(positions, "water", "true")
+	for _, pos := range positions {
+		if !m.tileGrid.Tiles[pos.Y][pos.X].PropBool("water") {
+			t.Fatalf("Expected water to be set at %v", pos)
+		}
+	}
+	if m.tileGrid.Tiles[1][1].Properties != nil {
+		t.Error("Expected unselected tiles to have no properties")
+	}
+
+	m.deleteTileProperty(positions, "water")
+	for _, pos := range positions {
+		if m.tileGrid.Tiles[pos.Y][pos.X].Properties != nil {
+			t.Errorf("Expected properties to be cleared at %v, got %v", pos, m.tileGrid.Tiles[pos.Y][pos.X].Properties)
+		}
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the delete to be undoable")
+	}
+	for _, pos := range positions {
+		if !m.tileGrid.Tiles[pos.Y][pos.X].PropBool("water") {
+			t.Errorf("Expected water to be restored at %v", pos)
+		}
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the set to be undoable")
+	}
+	for _, pos := range positions {
+		if m.tileGrid.Tiles[pos.Y][pos.X].Properties != nil {
+			t.Errorf("Expected no properties at %v after undoing the set", pos)
+		}
+	}
+}
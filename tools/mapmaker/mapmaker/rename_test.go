@@ -0,0 +1,47 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+	"github.com/ztkent/beam/resources"
+)
+
+// TestRenameResourceReferences renames a sprite sheet, and checks tiles and NPCs using its sprites follow the rename.
+func TestRenameResourceReferences(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.resources = &resources.ResourceManager{Scenes: []resources.Scene{{
+		Name:   "default",
+		Loaded: true,
+		SpriteSheets: []*resources.SpriteSheet{{
+			Name:    "dungeon",
+			Sprites: map[string]resources.Rectangle{"dungeon_0_0": {}, "dungeon_0_1": {}},
+			Loaded:  true,
+		}},
+	}}}
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "dungeon_0_0")
+	m.paintTiles(beam.Positions{{X: 1, Y: 1}}, "dungeon_0_1")
+	m.paintTiles(beam.Positions{{X: 2, Y: 2}}, "other")
+	npc := &beam.NPC{Data: beam.NPCData{Texture: beam.NewSimpleNPCTexture("dungeon_0_1")}}
+	m.tileGrid.NPCs = beam.NPCs{npc}
+
+	m.renameResource("dungeon", "cave")
+
+	if got := m.tileGrid.Tiles[0][0].Textures[0].Frames[0].Name; got != "cave_0_0" {
+		t.Errorf("Expected the tile to reference cave_0_0, got %s", got)
+	}
+	if got := m.tileGrid.Tiles[1][1].Textures[0].Frames[0].Name; got != "cave_0_1" {
+		t.Errorf("Expected the tile to reference cave_0_1, got %s", got)
+	}
+	if got := m.tileGrid.Tiles[2][2].Textures[0].Frames[0].Name; got != "other" {
+		t.Errorf("Expected unrelated textures to be kept, got %s", got)
+	}
+	if got := npc.Data.Texture.Left.Frames[0].Name; got != "cave_0_1" {
+		t.Errorf("Expected the NPC to reference cave_0_1, got %s", got)
+	}
+
+	// Only the unrelated texture is missing, the renamed sprites still resolve
+	if len(m.tileGrid.missingResourceTiles) != 1 || m.tileGrid.missingResourceTiles[0].textureName != "other" {
+		t.Errorf("Expected only 'other' to be missing, got %v", m.tileGrid.missingResourceTiles)
+	}
+}
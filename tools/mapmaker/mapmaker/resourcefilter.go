@@ -0,0 +1,72 @@
+package mapmaker
+
+import (
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam/resources"
+)
+
+// renderResourceFilter draws the search box in the resource viewer's title bar.
+// Typing filters the viewer as you go, and starts it back at the top.
+func (m *MapMaker) renderResourceFilter(x, y, width float32) {
+	inputRect := rl.Rectangle{X: x, Y: y, Width: width, Height: 30}
+	rl.DrawRectangleRec(inputRect, rl.White)
+	rl.DrawRectangleLinesEx(inputRect, 1, rl.Gray)
+	if m.uiState.resourceFilter == "" && m.uiState.activeInput != "resource_filter" {
+		rl.DrawText("Search...", int32(inputRect.X+8), int32(inputRect.Y+8), 16, rl.Gray)
+	} else {
+		rl.DrawText(fitText(m.uiState.resourceFilter, int32(width)-16, 16), int32(inputRect.X+8), int32(inputRect.Y+8), 16, rl.Black)
+	}
+
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), inputRect) {
+			m.cancelResourceRename()
+			m.closeTagEditor()
+			m.uiState.activeInput = "resource_filter"
+		} else if m.uiState.activeInput == "resource_filter" {
+			m.uiState.activeInput = ""
+		}
+	}
+	if m.uiState.activeInput != "resource_filter" {
+		return
+	}
+	rl.DrawRectangleLinesEx(inputRect, 2, rl.Blue)
+
+	filter := m.uiState.resourceFilter
+	m.textInput(&filter, true, false)
+	if rl.IsKeyPressed(rl.KeyEnter) {
+		m.uiState.activeInput = ""
+	}
+	if filter != m.uiState.resourceFilter {
+		m.uiState.resourceFilter = filter
+		m.uiState.resourceViewerScroll = 0
+	}
+}
+
+// matchesFilter reports if name contains filter, ignoring case. An empty filter matches everything.
+func matchesFilter(name, filter string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(strings.TrimSpace(filter)))
+}
+
+// filterTextures returns the textures with names that match, in their original order.
+func filterTextures(textures []resources.TextureInfo, match func(name string) bool) []resources.TextureInfo {
+	filtered := make([]resources.TextureInfo, 0, len(textures))
+	for _, tex := range textures {
+		if match(tex.Name) {
+			filtered = append(filtered, tex)
+		}
+	}
+	return filtered
+}
+
+// filterSpritesheets returns the sprite sheets with names matching filter, in their original order.
+func filterSpritesheets(sheets []resources.SpriteSheet, filter string) []resources.SpriteSheet {
+	filtered := make([]resources.SpriteSheet, 0, len(sheets))
+	for _, sheet := range sheets {
+		if matchesFilter(sheet.Name, filter) {
+			filtered = append(filtered, sheet)
+		}
+	}
+	return filtered
+}
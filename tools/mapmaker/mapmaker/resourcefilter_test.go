@@ -0,0 +1,38 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam/resources"
+)
+
+// TestFilterTextures checks the resource viewer filter is a case insensitive substring match that keeps the order.
+func TestFilterTextures(t *testing.T) {
+	textures := []resources.TextureInfo{{Name: "grass"}, {Name: "Dungeon_0_1"}, {Name: "dungeon_wall"}, {Name: "water"}}
+	for _, tc := range []struct {
+		filter string
+		want   []string
+	}{
+		{"", []string{"grass", "Dungeon_0_1", "dungeon_wall", "water"}},
+		{"dungeon", []string{"Dungeon_0_1", "dungeon_wall"}},
+		{" WALL ", []string{"dungeon_wall"}},
+		{"a", []string{"grass", "dungeon_wall", "water"}},
+		{"lava", []string{}},
+	} {
+		got := filterTextures(textures, func(name string) bool { return matchesFilter(name, tc.filter) })
+		names := make([]string, len(got))
+		for i, tex := range got {
+			names[i] = tex.Name
+		}
+		if len(names) != len(tc.want) {
+			t.Errorf("Filter %q: expected %v, got %v", tc.filter, tc.want, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.want[i] {
+				t.Errorf("Filter %q: expected %v, got %v", tc.filter, tc.want, names)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mapmaker
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+	beam_math "github.com/ztkent/beam/math"
+)
+
+// handleRulerTool starts measuring on left mouse down, and stops on release.
+// The ruler only draws an overlay, it never changes the map.
+func (m *MapMaker) handleRulerTool() {
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !m.isDialogOpen() {
+		if pos, ok := m.mouseGridPos(); ok {
+			m.uiState.rulerStart = pos
+			m.uiState.isMeasuring = true
+		}
+	}
+	if m.uiState.isMeasuring && rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		m.uiState.isMeasuring = false
+	}
+}
+
+// rulerDistances returns the Manhattan and Chebyshev distances between two tiles.
+// Manhattan matches how NPC aggro and wander ranges are measured, Chebyshev counts diagonal steps as one.
+func rulerDistances(a, b beam.Position) (manhattan, chebyshev int) {
+	manhattan = beam_math.ManhattanDistance(a.X, a.Y, b.X, b.Y)
+	chebyshev = max(beam_math.Abs(b.X-a.X), beam_math.Abs(b.Y-a.Y))
+	return manhattan, chebyshev
+}
+
+// renderRuler draws a line between the centers of the start and current tiles,
+// with the distances next to the cursor.
+func (m *MapMaker) renderRuler(viewStartX, viewStartY int) {
+	if !m.uiState.isMeasuring {
+		return
+	}
+
+	start, end := m.uiState.rulerStart, m.shapeEnd()
+	tileSize := float32(m.renderTileSize())
+	tileCenter := func(pos beam.Position) rl.Vector2 {
+		return rl.Vector2{
+			X: float32(m.tileGrid.offset.X) + (float32(pos.X-viewStartX)+0.5)*tileSize,
+			Y: float32(m.tileGrid.offset.Y) + (float32(pos.Y-viewStartY)+0.5)*tileSize,
+		}
+	}
+	from, to := tileCenter(start), tileCenter(end)
+
+	for _, pos := range []beam.Position{start, end} {
+		center := tileCenter(pos)
+		rl.DrawRectangleLinesEx(rl.Rectangle{
+			X:      center.X - tileSize/2,
+			Y:      center.Y - tileSize/2,
+			Width:  tileSize,
+			Height: tileSize,
+		}, 2, rl.Orange)
+	}
+	rl.DrawLineEx(from, to, 3, rl.Orange)
+	rl.DrawCircleV(from, 4, rl.Orange)
+	rl.DrawCircleV(to, 4, rl.Orange)
+
+	manhattan, chebyshev := rulerDistances(start, end)
+	label := fmt.Sprintf("Manhattan: %d  Chebyshev: %d", manhattan, chebyshev)
+	labelWidth := rl.MeasureText(label, 16)
+	mousePos := rl.GetMousePosition()
+	labelX := min(int32(mousePos.X)+16, m.window.width-labelWidth-16)
+	labelY := int32(mousePos.Y) + 16
+	rl.DrawRectangle(labelX-6, labelY-4, labelWidth+12, 24, rl.Fade(rl.Black, 0.75))
+	rl.DrawText(label, labelX, labelY, 16, rl.White)
+}
@@ -1,8 +1,11 @@
 package mapmaker
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -26,6 +29,19 @@ func (t *TileGrid) SaveMapToFile(filename string) error {
 	return nil
 }
 
+// SaveMapBinary writes the map in the compact binary format, see beam.Map.MarshalBinary.
+func (t *TileGrid) SaveMapBinary(filename string) error {
+	data, err := t.Map.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal map data: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write map file: %w", err)
+	}
+	return nil
+}
+
 // SaveData represents the structure of our mapmaker save files
 type SaveData struct {
 	TileGrid        *TileGrid               `json:"tileGrid"`
@@ -33,16 +49,29 @@ type SaveData struct {
 	CurrentResIndex int                     `json:"currentResIndex"`
 	ResourceState   resources.ResourceState `json:"resourceState"`
 	RecentTextures  []string                `json:"recentTextures"`
+	LayerVisibility LayerVisibility         `json:"layerVisibility"`
+	TextureTags     TextureTags             `json:"textureTags"`
+
+	// SourceFile is the map an autosave was taken from, empty for a map that was never saved
+	SourceFile string `json:"sourceFile,omitempty"`
 }
 
 type ConfigData struct {
 	LastOpenedFile string `json:"lastOpenedFile"`
+
+	// AutosaveSeconds is how often edited maps are autosaved, 0 uses DefaultAutosaveSeconds and less than 0 turns autosave off
+	AutosaveSeconds int `json:"autosaveSeconds,omitempty"`
+	// AutosaveKeep is how many autosaves are kept, 0 uses DefaultAutosaveKeep
+	AutosaveKeep int `json:"autosaveKeep,omitempty"`
 }
 
+// SaveConfig records the last opened file, keeping the rest of the config.
 func SaveConfig(filename string) error {
-	config := ConfigData{
-		LastOpenedFile: filename,
+	config, err := readConfig()
+	if err != nil {
+		return err
 	}
+	config.LastOpenedFile = filename
 	jsonData, err := json.MarshalIndent(config, "", "    ")
 	if err != nil {
 		return err
@@ -51,31 +80,45 @@ func SaveConfig(filename string) error {
 }
 
 func LoadConfig() (string, error) {
+	config, err := readConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.LastOpenedFile, nil
+}
+
+// readConfig reads the mapmaker config, a missing config is empty.
+func readConfig() (ConfigData, error) {
+	var config ConfigData
 	data, err := os.ReadFile(".mapmaker-config")
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil
+			return config, nil
 		}
-		return "", err
+		return config, err
 	}
 
-	var config ConfigData
 	if err := json.Unmarshal(data, &config); err != nil {
-		return "", err
+		return config, err
 	}
-
-	return config.LastOpenedFile, nil
+	return config, nil
 }
 
-func (m *MapMaker) SaveMap(filename string) error {
-	saveData := SaveData{
-		TileSize:       m.uiState.tileSize,
-		ResourceState:  m.resources.SaveState(),
-		TileGrid:       m.tileGrid,
-		RecentTextures: m.uiState.recentTextures,
+// documentSaveData is what gets written for a document, the resources, recent textures and tags are shared by every tab.
+func (m *MapMaker) documentSaveData(doc *Document) SaveData {
+	return SaveData{
+		TileSize:        doc.tileSize,
+		ResourceState:   m.resources.SaveState(),
+		TileGrid:        doc.tileGrid,
+		RecentTextures:  m.uiState.recentTextures,
+		LayerVisibility: doc.layerVisibility,
+		TextureTags:     m.uiState.textureTags,
 	}
+}
 
-	jsonData, err := json.MarshalIndent(saveData, "", "    ")
+func (m *MapMaker) SaveMap(filename string) error {
+	m.storeDocument()
+	jsonData, err := json.MarshalIndent(m.documentSaveData(m.documents[m.activeDocument]), "", "    ")
 	if err != nil {
 		return err
 	}
@@ -84,16 +127,16 @@ func (m *MapMaker) SaveMap(filename string) error {
 		return err
 	}
 	m.currentFile = filename
-	if m.currentFile != "" {
-		rl.SetWindowTitle(fmt.Sprintf("%s - (%s)", m.window.title, m.currentFile))
-	} else {
-		rl.SetWindowTitle(m.window.title)
+	m.updateWindowTitle()
+	if err := writeSaveFile(filename, jsonData); err != nil {
+		return err
 	}
-	return os.WriteFile(filename, jsonData, 0644)
+	m.markSaved()
+	return nil
 }
 
 func (m *MapMaker) LoadMap(filename string) error {
-	data, err := os.ReadFile(filename)
+	data, err := readSaveFile(filename)
 	if err != nil {
 		return err
 	}
@@ -112,6 +155,8 @@ func (m *MapMaker) LoadMap(filename string) error {
 	// Update UI state with loaded map dimensions
 	m.uiState.tileSize = saveData.TileSize
 	m.uiState.recentTextures = saveData.RecentTextures
+	m.uiState.layerVisibility = saveData.LayerVisibility
+	m.uiState.textureTags = saveData.TextureTags
 	m.uiState.gridWidth = saveData.TileGrid.Width
 	m.uiState.gridHeight = saveData.TileGrid.Height
 
@@ -125,20 +170,63 @@ func (m *MapMaker) LoadMap(filename string) error {
 	m.updateGridSize()
 	m.currentFile = filename
 
-	// Update grid data directly
+	// Update grid data directly, the new grid builds its own minimap
+	if m.tileGrid.minimap.ID != 0 {
+		rl.UnloadTexture(m.tileGrid.minimap)
+	}
 	m.tileGrid = saveData.TileGrid
+	m.history.Clear()
+	// Map files only store NPC definitions, start them fresh
+	m.tileGrid.NPCs.ResetRuntime()
+	m.ensureNPCIDs()
+	m.markSaved()
 
-	if m.currentFile != "" {
-		rl.SetWindowTitle(fmt.Sprintf("%s - (%s)", m.window.title, m.currentFile))
-	} else {
-		rl.SetWindowTitle(m.window.title)
-	}
+	m.updateWindowTitle()
 
 	// Validate the tile grid to ensure all textures are loaded
 	m.ValidateTileGrid()
 	return nil
 }
 
+// writeSaveFile writes a save file, gzipped if the file name ends in .gz.
+func writeSaveFile(filename string, data []byte) error {
+	if !strings.HasSuffix(filename, ".gz") {
+		return os.WriteFile(filename, data, 0644)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// readSaveFile reads a save file, decompressing it if it's gzipped whatever the file name.
+func readSaveFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress map: %w", err)
+	}
+	return data, nil
+}
+
 func (m *MapMaker) ValidateTileGrid() error {
 	// Clear the missing resource tiles list
 	newGrid := make(MissingResources, 0)
@@ -159,6 +247,93 @@ func (m *MapMaker) ValidateTileGrid() error {
 	return nil
 }
 
+// renameResource renames a texture or sprite sheet, and rewrites every reference to it on the map.
+func (m *MapMaker) renameResource(oldName, newName string) {
+	newName = strings.TrimSpace(newName)
+
+	// Sprites named after the sheet are renamed along with it
+	renames := map[string]string{oldName: newName}
+	sheets, _ := m.resources.GetAllSpritesheets("default")
+	for _, sheet := range sheets {
+		if sheet.Name != oldName {
+			continue
+		}
+		for spriteName := range sheet.Sprites {
+			if renamed, ok := resources.RenamedSpriteName(oldName, newName, spriteName); ok {
+				renames[spriteName] = renamed
+			}
+		}
+	}
+
+	if err := m.resources.RenameResource("default", oldName, newName); err != nil {
+		m.showToast("Error renaming resource: "+err.Error(), ToastError)
+		return
+	}
+	m.renameTextureReferences(renames)
+	m.ValidateTileGrid()
+	m.showToast(fmt.Sprintf("Renamed %s to %s", oldName, newName), ToastSuccess)
+}
+
+// cancelResourceRename closes the rename field in the resource manage view, if it's open.
+func (m *MapMaker) cancelResourceRename() {
+	if m.uiState.renamingResource != "" {
+		m.uiState.renamingResource = ""
+		m.uiState.activeInput = ""
+	}
+}
+
+// renameTextureReferences rewrites texture names on tiles, NPCs and items, using renames from old to new name.
+func (m *MapMaker) renameTextureReferences(renames map[string]string) {
+	renameFrames := func(tex *beam.AnimatedTexture) {
+		if tex == nil {
+			return
+		}
+		for i := range tex.Frames {
+			if newName, ok := renames[tex.Frames[i].Name]; ok {
+				tex.Frames[i].Name = newName
+			}
+		}
+	}
+	renameNPCTexture := func(tex *beam.NPCTexture) {
+		if tex == nil {
+			return
+		}
+		for _, dir := range []*beam.AnimatedTexture{tex.Up, tex.Down, tex.Left, tex.Right} {
+			renameFrames(dir)
+		}
+	}
+
+	for y := range m.tileGrid.Tiles {
+		for x := range m.tileGrid.Tiles[y] {
+			for _, tex := range m.tileGrid.Tiles[y][x].Textures {
+				renameFrames(tex)
+			}
+		}
+	}
+	for _, npc := range m.tileGrid.NPCs {
+		renameNPCTexture(npc.Data.Texture)
+		renameNPCTexture(npc.Data.IdleTexture)
+		renameNPCTexture(npc.Data.AttackTexture)
+	}
+	for _, item := range m.tileGrid.Items {
+		renameFrames(item.Texture)
+	}
+
+	m.uiState.textureTags.Rename(renames)
+	for i, name := range m.uiState.recentTextures {
+		if newName, ok := renames[name]; ok {
+			m.uiState.recentTextures[i] = newName
+		}
+	}
+	if m.uiState.activeTexture != nil {
+		if newName, ok := renames[m.uiState.activeTexture.Name]; ok {
+			m.uiState.activeTexture.Name = newName
+		}
+	}
+	m.tileGrid.minimapDirty = true
+	m.markDirty()
+}
+
 func openLoadDialog() string {
 	var cmd *exec.Cmd
 
@@ -166,7 +341,7 @@ func openLoadDialog() string {
 	case "darwin":
 		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file with prompt "Choose a map file")`)
 	case "linux":
-		cmd = exec.Command("zenity", "--file-selection", "--file-filter=JSON (*.json)")
+		cmd = exec.Command("zenity", "--file-selection", "--file-filter=Maps (*.json *.json.gz)")
 	default:
 		return ""
 	}
@@ -179,13 +354,18 @@ func openLoadDialog() string {
 }
 
 func openSaveDialog() string {
+	return openSaveFileDialog("Save map as:", "untitled.json", "Maps (*.json *.json.gz)")
+}
+
+// openSaveFileDialog prompts for a file to save to, filtered on linux by a zenity filter like "PNG (*.png)".
+func openSaveFileDialog(prompt, defaultName, filter string) string {
 	var cmd *exec.Cmd
 
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file name with prompt "Save map as:" default name "untitled.json")`)
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf(`POSIX path of (choose file name with prompt %q default name %q)`, prompt, defaultName))
 	case "linux":
-		cmd = exec.Command("zenity", "--file-selection", "--save", "--file-filter=JSON (*.json)", "--confirm-overwrite")
+		cmd = exec.Command("zenity", "--file-selection", "--save", "--file-filter="+filter, "--confirm-overwrite")
 	default:
 		return ""
 	}
@@ -0,0 +1,138 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// TestSaveDataTileTextures paints tiles, round trips them through the save format,
+// and checks every texture comes back with its frames, transforms, layer and animation.
+func TestSaveDataTileTextures(t *testing.T) {
+	m := newTestMapMaker(3, 2)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}, {X: 2, Y: 1}}, "grass")
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "flower")
+
+	// A transformed, animated texture on the foreground
+	water := beam.NewSimpleTileTexture("water_0", "water_1")
+	water.IsAnimated = true
+	water.AnimationTime = 0.25
+	water.Layer = beam.ForegroundLayer
+	water.Frames[1].ScaleX, water.Frames[1].ScaleY = 1.5, 0.5
+	water.Frames[1].Rotation = 90
+	water.Frames[1].MirrorX = true
+	water.Frames[1].Tint = rl.Color{R: 10, G: 20, B: 30, A: 200}
+	m.tileGrid.Tiles[1][0].Textures = append(m.tileGrid.Tiles[1][0].Textures, water)
+
+	data, err := json.Marshal(SaveData{TileGrid: m.tileGrid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded SaveData
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.TileGrid.Tiles, m.tileGrid.Tiles) {
+		t.Errorf("Expected the tiles to round trip\nsaved:  %+v\nloaded: %+v", m.tileGrid.Tiles, loaded.TileGrid.Tiles)
+	}
+	if got := loaded.TileGrid.Tiles[0][0].Textures; len(got) != 2 || got[1].Frames[0].Name != "flower" {
+		t.Errorf("Expected grass then flower on (0, 0), got %d textures", len(got))
+	}
+	if got := loaded.TileGrid.Tiles[1][0].Textures[0]; !got.IsAnimated || got.Layer != beam.ForegroundLayer || got.Frames[1].ScaleY != 0.5 {
+		t.Errorf("Expected the animated foreground water, got %+v", got)
+	}
+}
+
+// TestSaveMapBinary writes the painted map as a .beam file and checks it loads back with the same tiles.
+func TestSaveMapBinary(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 3, Y: 2}}, "grass")
+
+	filename := filepath.Join(t.TempDir(), "map.beam")
+	if err := m.tileGrid.SaveMapBinary(filename); err != nil {
+		t.Fatalf("SaveMapBinary failed: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded beam.Map
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if loaded.Width != 4 || loaded.Height != 3 {
+		t.Errorf("Expected a 4x3 map, got %dx%d", loaded.Width, loaded.Height)
+	}
+	if got := loaded.Tiles[2][3].Textures; len(got) != 1 || got[0].Frames[0].Name != "grass" {
+		t.Errorf("Expected grass on (3, 2), got %d textures", len(got))
+	}
+}
+
+// TestSaveFileGzip saves a map to .json.gz, and checks it's compressed and loads back as an identical map.
+func TestSaveFileGzip(t *testing.T) {
+	m := newTestMapMaker(16, 12)
+	for y := range 12 {
+		for x := range 16 {
+			water := beam.NewSimpleTileTexture("water_0", "water_1", "water_2")
+			water.IsAnimated = true
+			water.AnimationTime = 0.25
+			m.tileGrid.Tiles[y][x].Textures = append(m.tileGrid.Tiles[y][x].Textures, water)
+		}
+	}
+	m.tileGrid.Start = beam.Position{X: 2, Y: 3}
+	m.tileGrid.Exit = beam.Positions{{X: 15, Y: 11}}
+
+	data, err := json.MarshalIndent(SaveData{TileGrid: m.tileGrid}, "", "    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "map.json.gz")
+	if err := writeSaveFile(filename, data); err != nil {
+		t.Fatalf("writeSaveFile failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		t.Errorf("Expected the file to be gzipped")
+	}
+	if len(raw)*10 > len(data) {
+		t.Errorf("Expected the gzipped map to be a tenth of the JSON, got %d bytes and %d bytes", len(raw), len(data))
+	}
+
+	loadedData, err := readSaveFile(filename)
+	if err != nil {
+		t.Fatalf("readSaveFile failed: %v", err)
+	}
+	var loaded SaveData
+	if err := json.Unmarshal(loadedData, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.TileGrid.Map, m.tileGrid.Map) {
+		t.Errorf("Expected the map to round trip through gzip unchanged")
+	}
+
+	// Gzip is spotted from the data, not the name, and plain JSON is read as is
+	renamed := filepath.Join(t.TempDir(), "map.json")
+	if err := os.WriteFile(renamed, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readSaveFile(renamed); err != nil || string(got) != string(data) {
+		t.Errorf("Expected a gzipped .json file to be decompressed, got error %v", err)
+	}
+	plain := filepath.Join(t.TempDir(), "plain.json")
+	if err := writeSaveFile(plain, data); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readSaveFile(plain); err != nil || string(got) != string(data) {
+		t.Errorf("Expected a plain .json file to be read unchanged, got error %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package mapmaker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestLinePositions checks Bresenham lines are continuous in any direction.
+func TestLinePositions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     beam.Position
+		expected beam.Positions
+	}{
+		{name: "single", a: beam.Position{X: 2, Y: 2}, b: beam.Position{X: 2, Y: 2}, expected: beam.Positions{{X: 2, Y: 2}}},
+		{name: "horizontal", a: beam.Position{X: 3, Y: 1}, b: beam.Position{X: 0, Y: 1}, expected: beam.Positions{{X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}},
+		{name: "diagonal", a: beam.Position{X: 0, Y: 0}, b: beam.Position{X: 2, Y: 2}, expected: beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}},
+		{name: "shallow", a: beam.Position{X: 0, Y: 0}, b: beam.Position{X: 4, Y: 1}, expected: beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := linePositions(tc.a, tc.b); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestRectPositions checks the outline and filled tile counts for a rectangle dragged in reverse.
+func TestRectPositions(t *testing.T) {
+	a, b := beam.Position{X: 4, Y: 3}, beam.Position{X: 1, Y: 0}
+	if got := len(rectPositions(a, b, false)); got != 12 {
+		t.Errorf("Expected 12 outline tiles, got %d", got)
+	}
+	if got := len(rectPositions(a, b, true)); got != 16 {
+		t.Errorf("Expected 16 filled tiles, got %d", got)
+	}
+}
+
+// TestRulerDistances checks the ruler's Manhattan and Chebyshev distances, in either drag direction.
+func TestRulerDistances(t *testing.T) {
+	a, b := beam.Position{X: 2, Y: 7}, beam.Position{X: 5, Y: 3}
+	for _, pair := range [][2]beam.Position{{a, b}, {b, a}} {
+		manhattan, chebyshev := rulerDistances(pair[0], pair[1])
+		if manhattan != 7 || chebyshev != 4 {
+			t.Errorf("Expected Manhattan 7 and Chebyshev 4, got %d and %d", manhattan, chebyshev)
+		}
+	}
+	if manhattan, chebyshev := rulerDistances(a, a); manhattan != 0 || chebyshev != 0 {
+		t.Errorf("Expected no distance to the same tile, got %d and %d", manhattan, chebyshev)
+	}
+}
+
+// TestVisibleRect checks a selection rectangle is clipped to the viewport, in either drag direction.
+func TestVisibleRect(t *testing.T) {
+	viewStart, viewEnd := beam.Position{X: 10, Y: 5}, beam.Position{X: 20, Y: 15}
+	testCases := []struct {
+		name                 string
+		a, b                 beam.Position
+		topLeft, bottomRight beam.Position
+		ok                   bool
+	}{
+		{name: "inside", a: beam.Position{X: 12, Y: 6}, b: beam.Position{X: 14, Y: 8}, topLeft: beam.Position{X: 12, Y: 6}, bottomRight: beam.Position{X: 14, Y: 8}, ok: true},
+		{name: "reversed", a: beam.Position{X: 14, Y: 8}, b: beam.Position{X: 12, Y: 6}, topLeft: beam.Position{X: 12, Y: 6}, bottomRight: beam.Position{X: 14, Y: 8}, ok: true},
+		{name: "clipped", a: beam.Position{X: 2, Y: 2}, b: beam.Position{X: 30, Y: 9}, topLeft: beam.Position{X: 10, Y: 5}, bottomRight: beam.Position{X: 19, Y: 9}, ok: true},
+		{name: "offscreen", a: beam.Position{X: 0, Y: 0}, b: beam.Position{X: 9, Y: 4}, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topLeft, bottomRight, ok := visibleRect(tc.a, tc.b, viewStart, viewEnd)
+			if ok != tc.ok {
+				t.Fatalf("Expected visible %v, got %v", tc.ok, ok)
+			}
+			if ok && (topLeft != tc.topLeft || bottomRight != tc.bottomRight) {
+				t.Errorf("Expected %v to %v, got %v to %v", tc.topLeft, tc.bottomRight, topLeft, bottomRight)
+			}
+		})
+	}
+}
+
+// TestRectSelection checks a drag box selects every tile inside it, top left first in any drag direction,
+// and that copying and pasting the selection moves the whole rectangle.
+func TestRectSelection(t *testing.T) {
+	expected := beam.Positions{
+		{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1},
+		{X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2},
+	}
+	corners := [][2]beam.Position{
+		{{X: 1, Y: 1}, {X: 3, Y: 2}},
+		{{X: 3, Y: 2}, {X: 1, Y: 1}},
+		{{X: 3, Y: 1}, {X: 1, Y: 2}},
+		{{X: 1, Y: 2}, {X: 3, Y: 1}},
+	}
+	for _, c := range corners {
+		if got := rectPositions(c[0], c[1], true); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected dragging %v to %v to select %v, got %v", c[0], c[1], expected, got)
+		}
+	}
+
+	m := newTestMapMaker(6, 6)
+	m.paintTiles(expected, "grass")
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 3, Y: 2}, beam.Position{X: 1, Y: 1}, true)
+	m.tileGrid.hasSelection = true
+	m.copySelection()
+	if len(m.clipboard) != 2 || len(m.clipboard[0]) != 3 {
+		t.Fatalf("Expected a 3x2 clipboard, got %d rows", len(m.clipboard))
+	}
+	m.stamp(beam.Position{X: 2, Y: 4})
+	for y := 4; y <= 5; y++ {
+		for x := 2; x <= 4; x++ {
+			if textures := m.tileGrid.Tiles[y][x].Textures; len(textures) != 1 || textures[0].Frames[0].Name != "grass" {
+				t.Errorf("Expected grass pasted at (%d, %d), got %d textures", x, y, len(textures))
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package mapmaker
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// toolShortcuts are the single keys that select each tool, as if its toolbar button was clicked.
+var toolShortcuts = []struct {
+	key  int32
+	tool string
+}{
+	{rl.KeyB, "paintbrush"},
+	{rl.KeyG, "paintbucket"},
+	{rl.KeyE, "eraser"},
+	{rl.KeyS, "select"},
+	{rl.KeyL, "layers"},
+	{rl.KeyP, "location"},
+	{rl.KeyN, "npc"},
+	{rl.KeyI, "items"},
+	{rl.KeyR, "rect"},
+	{rl.KeyK, "line"},
+	{rl.KeyM, "ruler"},
+	{rl.KeyO, "properties"},
+	{rl.KeyA, "stamp"},
+}
+
+// toolShortcut returns the tool whose shortcut was pressed this frame, or "" if there wasn't one.
+// Shortcuts are ignored while typing in an input or with a dialog open, and when a modifier is held,
+// so they don't clash with Ctrl/Cmd shortcuts like save.
+func (m *MapMaker) toolShortcut() string {
+	if m.uiState.activeInput != "" || m.isDialogOpen() {
+		return ""
+	}
+	for _, modifier := range []int32{rl.KeyLeftControl, rl.KeyRightControl, rl.KeyLeftSuper, rl.KeyRightSuper, rl.KeyLeftShift, rl.KeyRightShift, rl.KeyLeftAlt, rl.KeyRightAlt} {
+		if rl.IsKeyDown(modifier) {
+			return ""
+		}
+	}
+	for _, shortcut := range toolShortcuts {
+		if rl.IsKeyPressed(shortcut.key) {
+			return shortcut.tool
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package mapmaker
+
+import "testing"
+
+func TestToolShortcutsUnique(t *testing.T) {
+	keys := make(map[int32]string)
+	tools := make(map[string]bool)
+	for _, shortcut := range toolShortcuts {
+		if tool, ok := keys[shortcut.key]; ok {
+			t.Errorf("key %d is bound to both %s and %s", shortcut.key, tool, shortcut.tool)
+		}
+		if tools[shortcut.tool] {
+			t.Errorf("tool %s has more than one shortcut", shortcut.tool)
+		}
+		keys[shortcut.key] = shortcut.tool
+		tools[shortcut.tool] = true
+	}
+}
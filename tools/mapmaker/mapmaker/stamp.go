@@ -0,0 +1,66 @@
+package mapmaker
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// selectStampTool switches to the stamp tool. A multi-tile selection becomes the new stamp,
+// otherwise the stamp is whatever was last copied with Ctrl+C.
+func (m *MapMaker) selectStampTool() {
+	m.uiState.selectedTool = "stamp"
+	if m.tileGrid.hasSelection && len(m.tileGrid.selectedTiles) > 1 {
+		m.copySelection()
+	}
+	if len(m.clipboard) == 0 {
+		m.showToast("Stamp tool selected, copy some tiles to use as a stamp", ToastInfo)
+		return
+	}
+	m.showToast("Stamp tool selected, click to place the copied tiles", ToastInfo)
+}
+
+// handleStampTool places the stamp with its top left corner on the clicked tile.
+func (m *MapMaker) handleStampTool() {
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) || m.isDialogOpen() {
+		return
+	}
+	pos, ok := m.mouseGridPos()
+	if !ok {
+		return
+	}
+	if len(m.clipboard) == 0 {
+		m.showToast("Copy some tiles to use as a stamp first", ToastError)
+		return
+	}
+	m.stamp(pos)
+}
+
+// renderStampGhost previews the stamp under the cursor, clipped to the grid and viewport.
+func (m *MapMaker) renderStampGhost(viewStartX, viewStartY, viewEndX, viewEndY int) {
+	if m.uiState.selectedTool != "stamp" || len(m.clipboard) == 0 || m.isDialogOpen() {
+		return
+	}
+	anchor, ok := m.mouseGridPos()
+	if !ok {
+		return
+	}
+
+	tileSize := float32(m.renderTileSize())
+	for clipY, row := range m.clipboard {
+		for clipX, tile := range row {
+			pos := beam.Position{X: anchor.X + clipX, Y: anchor.Y + clipY}
+			if pos.X >= m.tileGrid.Width || pos.Y >= m.tileGrid.Height ||
+				pos.X < viewStartX || pos.X >= viewEndX || pos.Y < viewStartY || pos.Y >= viewEndY {
+				continue
+			}
+			screenX := float32(m.tileGrid.offset.X) + float32(pos.X-viewStartX)*tileSize
+			screenY := float32(m.tileGrid.offset.Y) + float32(pos.Y-viewStartY)*tileSize
+			for _, tex := range tile.Textures {
+				if len(tex.Frames) > 0 {
+					m.drawGhostFrame(tex.Frames[0], screenX, screenY, tileSize)
+				}
+			}
+			rl.DrawRectangleLinesEx(rl.Rectangle{X: screenX, Y: screenY, Width: tileSize, Height: tileSize}, 1, rl.Fade(rl.DarkGray, 0.5))
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package mapmaker
+
+import "testing"
+
+func TestTruncateName(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxLen int
+		want   string
+	}{
+		{"grass", 10, "grass"},
+		{"grass_tile", 10, "grass_tile"},
+		{"grass_tile_01", 10, "grass_t..."},
+		{"grass", 3, "gra"},
+		{"", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateName(tt.name, tt.maxLen); got != tt.want {
+			t.Errorf("truncateName(%q, %d) = %q, want %q", tt.name, tt.maxLen, got, tt.want)
+		}
+	}
+}
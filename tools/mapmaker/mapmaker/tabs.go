@@ -0,0 +1,276 @@
+package mapmaker
+
+import (
+	"fmt"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	TabBarHeight   = 22
+	MaxTabWidth    = 160
+	TabFontSize    = 14
+	TabTextPadding = 10
+)
+
+// Document is a single open map, shown as a tab.
+// The active document's state lives on the MapMaker, and is stored back here when switching away.
+type Document struct {
+	tileGrid         *TileGrid
+	currentFile      string
+	history          *UndoStack
+	changes          int
+	tileSize         int
+	zoomLevel        float32
+	gridWidth        int
+	gridHeight       int
+	layerVisibility  LayerVisibility
+	savedChanges     int // The change count at the last save
+	autosavedChanges int // The change count at the last save or autosave
+}
+
+// newDocument returns an empty map with the default size.
+func newDocument() *Document {
+	doc := &Document{
+		tileGrid:   newTileGrid(),
+		history:    NewUndoStack(MaxUndoDepth),
+		tileSize:   DefaultTileSize,
+		zoomLevel:  1.0,
+		gridWidth:  DefaultGridWidth,
+		gridHeight: DefaultGridHeight,
+	}
+	doc.tileGrid.Width = doc.gridWidth
+	doc.tileGrid.Height = doc.gridHeight
+	return doc
+}
+
+// storeDocument copies the active map's state back into its document.
+func (m *MapMaker) storeDocument() {
+	if len(m.documents) == 0 {
+		m.documents = []*Document{{}}
+		m.activeDocument = 0
+	}
+	doc := m.documents[m.activeDocument]
+	doc.tileGrid = m.tileGrid
+	doc.currentFile = m.currentFile
+	doc.history = m.history
+	doc.changes = m.changes
+	doc.tileSize = m.uiState.tileSize
+	doc.zoomLevel = m.uiState.zoomLevel
+	doc.gridWidth = m.uiState.gridWidth
+	doc.gridHeight = m.uiState.gridHeight
+	doc.layerVisibility = m.uiState.layerVisibility
+}
+
+// restoreDocument makes the document at index i the active map.
+func (m *MapMaker) restoreDocument(i int) {
+	doc := m.documents[i]
+	m.activeDocument = i
+	m.tileGrid = doc.tileGrid
+	m.currentFile = doc.currentFile
+	m.history = doc.history
+	m.changes = doc.changes
+	m.uiState.tileSize = doc.tileSize
+	m.uiState.zoomLevel = doc.zoomLevel
+	m.uiState.gridWidth = doc.gridWidth
+	m.uiState.gridHeight = doc.gridHeight
+	m.uiState.layerVisibility = doc.layerVisibility
+
+	// Anything being typed or dragged belonged to the last map
+	m.uiState.activeInput = ""
+	m.uiState.isPanning = false
+	m.tileGrid.minimapDirty = true
+	if m.resources != nil {
+		m.ValidateTileGrid()
+	}
+	m.updateWindowTitle()
+}
+
+// switchDocument stores the active map and switches to the document at index i.
+func (m *MapMaker) switchDocument(i int) {
+	if i < 0 || i >= len(m.documents) || i == m.activeDocument {
+		return
+	}
+	m.storeDocument()
+	m.restoreDocument(i)
+}
+
+// cycleDocument switches to the next tab, or the previous one when step is negative, wrapping around.
+func (m *MapMaker) cycleDocument(step int) {
+	if len(m.documents) < 2 {
+		return
+	}
+	m.switchDocument(((m.activeDocument+step)%len(m.documents) + len(m.documents)) % len(m.documents))
+}
+
+// openNewDocument adds an empty map in a new tab, and switches to it.
+func (m *MapMaker) openNewDocument() {
+	m.storeDocument()
+	m.documents = append(m.documents, newDocument())
+	m.restoreDocument(len(m.documents) - 1)
+	m.initTileGrid()
+}
+
+// openInNewTab loads a map file into a new tab.
+// If the load fails, the new tab is closed and the previous map is shown again.
+func (m *MapMaker) openInNewTab(filename string) error {
+	previous := m.activeDocument
+	m.openNewDocument()
+	if err := m.LoadMap(filename); err != nil {
+		m.documents = m.documents[:len(m.documents)-1]
+		m.restoreDocument(previous)
+		return err
+	}
+	m.storeDocument()
+	return nil
+}
+
+// openMap loads a map file, reusing the active tab if it's an untitled map that hasn't been changed.
+// Any change counts, including ones that can't be undone or were autosaved, and a map recovered from an autosave,
+// so unsaved work is never replaced.
+func (m *MapMaker) openMap(filename string) error {
+	m.storeDocument()
+	if m.currentFile == "" && m.changes == m.documents[m.activeDocument].savedChanges {
+		if err := m.LoadMap(filename); err != nil {
+			return err
+		}
+		m.storeDocument()
+		return nil
+	}
+	return m.openInNewTab(filename)
+}
+
+// closeActiveDocument closes the active tab, and switches to its neighbour.
+// Closing the last tab leaves a single empty map.
+func (m *MapMaker) closeActiveDocument() {
+	m.storeDocument()
+	closed := m.documents[m.activeDocument]
+	if closed.tileGrid.minimap.ID != 0 {
+		rl.UnloadTexture(closed.tileGrid.minimap)
+	}
+
+	m.documents = append(m.documents[:m.activeDocument], m.documents[m.activeDocument+1:]...)
+	if len(m.documents) == 0 {
+		m.documents = []*Document{newDocument()}
+		m.restoreDocument(0)
+		m.initTileGrid()
+		return
+	}
+	m.restoreDocument(min(m.activeDocument, len(m.documents)-1))
+}
+
+// updateWindowTitle shows the active map's file in the window title.
+func (m *MapMaker) updateWindowTitle() {
+	if !rl.IsWindowReady() {
+		return
+	}
+	if m.currentFile != "" {
+		rl.SetWindowTitle(fmt.Sprintf("%s - (%s)", m.window.title, m.currentFile))
+	} else {
+		rl.SetWindowTitle(m.window.title)
+	}
+}
+
+// fileLabel is the name shown for a map file, or "Untitled" if it hasn't been saved.
+func fileLabel(path string) string {
+	if path == "" {
+		return "Untitled"
+	}
+	return filepath.Base(path)
+}
+
+// tabBarY is the top of the tab bar, which sits just above the status bar.
+func (m *MapMaker) tabBarY() int32 {
+	return m.window.height - int32(m.uiState.statusBarHeight) - TabBarHeight
+}
+
+// tabRects returns the rectangle of each tab, and of the new tab button after them.
+func (m *MapMaker) tabRects() ([]rl.Rectangle, rl.Rectangle) {
+	y := float32(m.tabBarY())
+	x := float32(0)
+	rects := make([]rl.Rectangle, len(m.documents))
+	for i, doc := range m.documents {
+		file := doc.currentFile
+		if i == m.activeDocument {
+			file = m.currentFile
+		}
+		width := min(float32(rl.MeasureText(fileLabel(file), TabFontSize)+TabTextPadding*2), MaxTabWidth)
+		rects[i] = rl.Rectangle{X: x, Y: y, Width: width, Height: TabBarHeight}
+		x += width
+	}
+	return rects, rl.Rectangle{X: x + 4, Y: y + 2, Width: TabBarHeight - 4, Height: TabBarHeight - 4}
+}
+
+// handleTabBar switches tabs when one is clicked, opens a new tab from the + button,
+// and cycles tabs with Ctrl+Tab and Ctrl+Shift+Tab.
+func (m *MapMaker) handleTabBar() {
+	if m.isDialogOpen() || m.uiState.activeInput != "" {
+		return
+	}
+	if rl.IsKeyPressed(rl.KeyTab) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)) {
+		if rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift) {
+			m.cycleDocument(-1)
+		} else {
+			m.cycleDocument(1)
+		}
+		return
+	}
+	if rl.IsKeyPressed(rl.KeyN) && (rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyLeftSuper)) {
+		m.openNewDocument()
+		return
+	}
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return
+	}
+
+	mousePos := rl.GetMousePosition()
+	tabs, newTab := m.tabRects()
+	if rl.CheckCollisionPointRec(mousePos, newTab) {
+		m.openNewDocument()
+		return
+	}
+	for i, rect := range tabs {
+		if rl.CheckCollisionPointRec(mousePos, rect) {
+			m.switchDocument(i)
+			return
+		}
+	}
+}
+
+// renderTabBar draws a tab for each open map, with the active one highlighted.
+func (m *MapMaker) renderTabBar() {
+	y := m.tabBarY()
+	rl.DrawRectangle(0, y, m.window.width, TabBarHeight, rl.RayWhite)
+	rl.DrawLine(0, y, m.window.width, y, rl.LightGray)
+
+	mousePos := rl.GetMousePosition()
+	tabs, newTab := m.tabRects()
+	for i, rect := range tabs {
+		file := m.documents[i].currentFile
+		if i == m.activeDocument {
+			file = m.currentFile
+		}
+
+		if i == m.activeDocument {
+			rl.DrawRectangleRec(rect, rl.White)
+		} else if rl.CheckCollisionPointRec(mousePos, rect) {
+			rl.DrawRectangleRec(rect, rl.Fade(rl.LightGray, 0.5))
+		}
+		rl.DrawRectangleLinesEx(rect, 1, rl.LightGray)
+		if i == m.activeDocument {
+			rl.DrawRectangle(int32(rect.X), int32(rect.Y), int32(rect.Width), 2, rl.Blue)
+		}
+
+		label := fitText(fileLabel(file), int32(rect.Width)-TabTextPadding*2, TabFontSize)
+		rl.DrawText(label, int32(rect.X)+TabTextPadding, int32(rect.Y)+4, TabFontSize, rl.DarkGray)
+	}
+
+	newTabColor := rl.White
+	if rl.CheckCollisionPointRec(mousePos, newTab) {
+		newTabColor = rl.LightGray
+	}
+	rl.DrawRectangleRec(newTab, newTabColor)
+	rl.DrawRectangleLinesEx(newTab, 1, rl.Gray)
+	rl.DrawText("+", int32(newTab.X)+5, int32(newTab.Y)+1, 16, rl.DarkGray)
+}
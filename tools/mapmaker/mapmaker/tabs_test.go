@@ -0,0 +1,179 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// topTexture returns the name of the top texture on a tile, or "" if it has none.
+func topTexture(m *MapMaker, pos beam.Position) string {
+	textures := m.tileGrid.Tiles[pos.Y][pos.X].Textures
+	if len(textures) == 0 {
+		return ""
+	}
+	return textures[len(textures)-1].Frames[0].Name
+}
+
+// TestSwitchDocumentPreservesState edits two tabs, and checks each keeps its own tiles, file, size and undo history.
+func TestSwitchDocumentPreservesState(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.currentFile = "level1.json"
+	m.uiState.zoomLevel = 2.0
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+
+	m.openNewDocument()
+	if len(m.documents) != 2 || m.activeDocument != 1 {
+		t.Fatalf("Expected 2 tabs with the second active, got %d tabs with %d active", len(m.documents), m.activeDocument)
+	}
+	if m.currentFile != "" || m.tileGrid.Width != DefaultGridWidth || m.tileGrid.Height != DefaultGridHeight {
+		t.Errorf("Expected an untitled %dx%d map, got %q at %dx%d", DefaultGridWidth, DefaultGridHeight, m.currentFile, m.tileGrid.Width, m.tileGrid.Height)
+	}
+	if m.uiState.zoomLevel != 1.0 {
+		t.Errorf("Expected the new tab at zoom 1, got %v", m.uiState.zoomLevel)
+	}
+	if m.history.Undo() {
+		t.Errorf("Expected the new tab to have no undo history")
+	}
+	m.currentFile = "level2.json"
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "stone")
+
+	m.switchDocument(0)
+	if m.currentFile != "level1.json" {
+		t.Errorf("Expected file level1.json, got %q", m.currentFile)
+	}
+	if m.tileGrid.Width != 4 || m.tileGrid.Height != 3 || m.uiState.gridWidth != 4 || m.uiState.gridHeight != 3 {
+		t.Errorf("Expected a 4x3 map, got %dx%d", m.tileGrid.Width, m.tileGrid.Height)
+	}
+	if m.uiState.zoomLevel != 2.0 {
+		t.Errorf("Expected zoom 2, got %v", m.uiState.zoomLevel)
+	}
+	if got := topTexture(m, beam.Position{X: 0, Y: 0}); got != "grass" {
+		t.Errorf("Expected grass on the first tab, got %q", got)
+	}
+
+	// Undo only reverts this tab's edit
+	if !m.history.Undo() {
+		t.Fatalf("Expected the first tab's paint to be undone")
+	}
+	if got := topTexture(m, beam.Position{X: 0, Y: 0}); got != "" {
+		t.Errorf("Expected no texture after undo, got %q", got)
+	}
+
+	m.switchDocument(1)
+	if m.currentFile != "level2.json" {
+		t.Errorf("Expected file level2.json, got %q", m.currentFile)
+	}
+	if got := topTexture(m, beam.Position{X: 0, Y: 0}); got != "stone" {
+		t.Errorf("Expected stone on the second tab, got %q", got)
+	}
+	if !m.history.Undo() || m.history.Undo() {
+		t.Errorf("Expected exactly one undo step on the second tab")
+	}
+}
+
+// TestCycleDocument checks cycling wraps around in both directions.
+func TestCycleDocument(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.openNewDocument()
+	m.openNewDocument()
+
+	m.cycleDocument(1)
+	if m.activeDocument != 0 {
+		t.Errorf("Expected cycling forward from the last tab to wrap to 0, got %d", m.activeDocument)
+	}
+	m.cycleDocument(-1)
+	if m.activeDocument != 2 {
+		t.Errorf("Expected cycling back from the first tab to wrap to 2, got %d", m.activeDocument)
+	}
+	m.cycleDocument(-1)
+	if m.activeDocument != 1 {
+		t.Errorf("Expected tab 1, got %d", m.activeDocument)
+	}
+}
+
+// TestCloseActiveDocument closes tabs, and checks the neighbour is shown and the last close leaves an empty map.
+func TestCloseActiveDocument(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.currentFile = "a.json"
+	m.openNewDocument()
+	m.currentFile = "b.json"
+	m.openNewDocument()
+	m.currentFile = "c.json"
+
+	m.switchDocument(1)
+	m.closeActiveDocument()
+	if len(m.documents) != 2 || m.currentFile != "c.json" {
+		t.Errorf("Expected c.json to take the closed tab's place, got %q with %d tabs", m.currentFile, len(m.documents))
+	}
+
+	m.closeActiveDocument()
+	if len(m.documents) != 1 || m.currentFile != "a.json" || m.tileGrid.Width != 4 {
+		t.Errorf("Expected only a.json to be left, got %q with %d tabs", m.currentFile, len(m.documents))
+	}
+
+	m.paintTiles(beam.Positions{{X: 1, Y: 1}}, "grass")
+	m.closeActiveDocument()
+	if len(m.documents) != 1 || m.currentFile != "" {
+		t.Errorf("Expected a single untitled tab, got %q with %d tabs", m.currentFile, len(m.documents))
+	}
+	if m.tileGrid.Width != DefaultGridWidth || len(m.tileGrid.Tiles) != DefaultGridHeight {
+		t.Errorf("Expected an empty %dx%d map, got %dx%d", DefaultGridWidth, DefaultGridHeight, m.tileGrid.Width, len(m.tileGrid.Tiles))
+	}
+	if m.history.Undo() {
+		t.Errorf("Expected no undo history after closing the last tab")
+	}
+}
+
+// TestOpenMapKeepsChangedTab checks opening a map reuses an untouched untitled tab, but not one holding
+// an NPC, even after it's autosaved and with nothing to undo.
+func TestOpenMapKeepsChangedTab(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.autosaveDir = t.TempDir()
+	m.storeDocument()
+	data, err := json.Marshal(SaveData{TileGrid: m.tileGrid})
+	if err != nil {
+		t.Fatalf("Expected no error marshaling, got %v", err)
+	}
+	path := filepath.Join(m.autosaveDir, "level1.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Expected no error writing %s, got %v", path, err)
+	}
+
+	if err := m.openMap(path); err != nil || len(m.documents) != 1 {
+		t.Fatalf("Expected the untouched tab to be reused, got %d tabs (%v)", len(m.documents), err)
+	}
+
+	m.openNewDocument()
+	m.saveEditedNPC(&beam.NPC{Data: beam.NPCData{Name: "Goblin"}})
+	if err := m.autosave(time.Now()); err != nil {
+		t.Fatalf("Expected no error autosaving, got %v", err)
+	}
+	if err := m.openMap(path); err != nil || len(m.documents) != 3 {
+		t.Fatalf("Expected the map to open in a new tab, got %d tabs (%v)", len(m.documents), err)
+	}
+	if npcs := m.documents[1].tileGrid.NPCs; m.documents[1].currentFile != "" || len(npcs) != 1 {
+		t.Errorf("Expected the untitled tab to keep its NPC, got %q with %d NPCs", m.documents[1].currentFile, len(npcs))
+	}
+}
+
+// TestMinimapAboveTabBar checks the minimap sits above the tab bar, so clicks on the tabs don't land on it.
+func TestMinimapAboveTabBar(t *testing.T) {
+	m := newTestMapMaker(80, 80)
+	m.window = &Window{width: 1280, height: 720}
+	m.uiState.statusBarHeight = 24
+
+	rect := m.minimapRect()
+	if bottom := rect.Y + rect.Height; bottom > float32(m.tabBarY()) {
+		t.Errorf("Expected the minimap to end above the tab bar at %d, got %v", m.tabBarY(), bottom)
+	}
+	tab := rl.Vector2{X: rect.X + rect.Width/2, Y: float32(m.tabBarY() + TabBarHeight/2)}
+	if rl.CheckCollisionPointRec(tab, rect) {
+		t.Errorf("Expected a click on the tab bar not to hit the minimap")
+	}
+}
@@ -0,0 +1,272 @@
+package mapmaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// templateDir is where NPC and item templates are saved, swapped in tests.
+var templateDir = ".mapmaker-templates"
+
+// NamedTemplate is a saved NPC definition that can be reused across maps.
+// Textures are saved by sprite name, so a template works on any map with the same resources loaded.
+type NamedTemplate struct {
+	Name string       `json:"name"`
+	Data beam.NPCData `json:"data"`
+}
+
+// NamedItemTemplate is a saved item definition that can be reused across maps.
+type NamedItemTemplate struct {
+	Name string    `json:"name"`
+	Item beam.Item `json:"item"`
+}
+
+// SaveNPCTemplate writes an NPC template, replacing any template with the same name.
+// The spawn position and ID aren't saved, they're set wherever the template is used.
+func SaveNPCTemplate(name string, data beam.NPCData) error {
+	data.SpawnPos = beam.Position{}
+	data.ID = ""
+	return saveTemplate("npcs", name, NamedTemplate{Name: name, Data: data})
+}
+
+// LoadNPCTemplates reads every saved NPC template, sorted by name.
+func LoadNPCTemplates() ([]NamedTemplate, error) {
+	var templates []NamedTemplate
+	err := loadTemplates("npcs", func(data []byte) error {
+		var template NamedTemplate
+		if err := json.Unmarshal(data, &template); err != nil {
+			return err
+		}
+		templates = append(templates, template)
+		return nil
+	})
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, err
+}
+
+// SaveItemTemplate writes an item template, replacing any template with the same name.
+// The position and picked up state aren't saved.
+func SaveItemTemplate(name string, item beam.Item) error {
+	item.Pos = beam.Position{}
+	item.Removed = false
+	return saveTemplate("items", name, NamedItemTemplate{Name: name, Item: item})
+}
+
+// LoadItemTemplates reads every saved item template, sorted by name.
+func LoadItemTemplates() ([]NamedItemTemplate, error) {
+	var templates []NamedItemTemplate
+	err := loadTemplates("items", func(data []byte) error {
+		var template NamedItemTemplate
+		if err := json.Unmarshal(data, &template); err != nil {
+			return err
+		}
+		templates = append(templates, template)
+		return nil
+	})
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, err
+}
+
+func saveTemplate(kind, name string, template any) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	dir := filepath.Join(templateDir, kind)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create template directory: %w", err)
+	}
+	jsonData, err := json.MarshalIndent(template, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, templateFileName(name)), jsonData, 0644)
+}
+
+// loadTemplates calls parse with each template file of a kind. A missing directory just means no templates.
+func loadTemplates(kind string, parse func([]byte) error) error {
+	dir := filepath.Join(templateDir, kind)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read templates: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+		}
+		if err := parse(data); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// templateFileName turns a template name into a safe file name.
+func templateFileName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	return safe + ".json"
+}
+
+// drawTemplateDropdown lists template names above the anchor button.
+// Returns the index of the clicked template, or -1.
+func drawTemplateDropdown(anchor rl.Rectangle, names []string) int {
+	const rowHeight = 26
+	if len(names) == 0 {
+		rl.DrawText("No templates saved", int32(anchor.X), int32(anchor.Y-20), 14, rl.DarkGray)
+		return -1
+	}
+
+	width := anchor.Width * 1.5
+	listRect := rl.Rectangle{
+		X:      anchor.X,
+		Y:      anchor.Y - float32(len(names)*rowHeight) - 4,
+		Width:  width,
+		Height: float32(len(names) * rowHeight),
+	}
+	rl.DrawRectangleRec(listRect, rl.RayWhite)
+	rl.DrawRectangleLinesEx(listRect, 1, rl.Gray)
+
+	selected := -1
+	for i, name := range names {
+		row := rl.Rectangle{X: listRect.X, Y: listRect.Y + float32(i*rowHeight), Width: width, Height: rowHeight}
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), row) {
+			rl.DrawRectangleRec(row, rl.LightGray)
+			if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+				selected = i
+			}
+		}
+		rl.DrawText(name, int32(row.X+8), int32(row.Y+6), 14, rl.Black)
+	}
+	return selected
+}
+
+// renderNPCTemplateButtons draws the Save Template and Load Template buttons at the bottom left of the NPC editor.
+// Loading a template reopens the editor with the template's fields, keeping the current spawn position.
+func (m *MapMaker) renderNPCTemplateButtons(editor *NPCEditorState, dialogX, dialogY, dialogHeight int) {
+	saveBtn, loadBtn := drawTemplateButtons(dialogX, dialogY, dialogHeight)
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if err := SaveNPCTemplate(editor.name, editor.npcData()); err != nil {
+			m.showToast("Failed to save template: "+err.Error(), ToastError)
+		} else {
+			m.showToast("Saved template "+editor.name, ToastSuccess)
+		}
+	}
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), loadBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		editor.showTemplates = !editor.showTemplates
+		if editor.showTemplates {
+			templates, err := LoadNPCTemplates()
+			if err != nil {
+				m.showToast("Failed to load templates: "+err.Error(), ToastError)
+			}
+			editor.templates = templates
+		}
+		return
+	}
+
+	if !editor.showTemplates {
+		return
+	}
+	names := make([]string, len(editor.templates))
+	for i, template := range editor.templates {
+		names[i] = template.Name
+	}
+	if i := drawTemplateDropdown(loadBtn, names); i >= 0 {
+		data := editor.templates[i].Data
+		data.ID = editor.id
+		data.Name = m.uniqueNPCName(data.Name, editor.id)
+		data.SpawnPos = editor.spawnPos
+		data.Texture = completeNPCTexture(data.Texture)
+		m.openNPCEditor(data)
+	}
+}
+
+// renderItemTemplateButtons draws the Save Template and Load Template buttons at the bottom left of the item editor.
+func (m *MapMaker) renderItemTemplateButtons(editor *ItemEditorState, dialogX, dialogY, dialogHeight int) {
+	saveBtn, loadBtn := drawTemplateButtons(dialogX, dialogY, dialogHeight)
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), saveBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		if err := SaveItemTemplate(editor.name, editor.buildItem()); err != nil {
+			m.showToast("Failed to save template: "+err.Error(), ToastError)
+		} else {
+			m.showToast("Saved template "+editor.name, ToastSuccess)
+		}
+	}
+
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), loadBtn) && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		editor.showTemplates = !editor.showTemplates
+		if editor.showTemplates {
+			templates, err := LoadItemTemplates()
+			if err != nil {
+				m.showToast("Failed to load templates: "+err.Error(), ToastError)
+			}
+			editor.templates = templates
+		}
+		return
+	}
+
+	if !editor.showTemplates {
+		return
+	}
+	names := make([]string, len(editor.templates))
+	for i, template := range editor.templates {
+		names[i] = template.Name
+	}
+	if i := drawTemplateDropdown(loadBtn, names); i >= 0 {
+		item := editor.templates[i].Item
+		item.Pos = editor.spawnPos
+		m.openItemEditor(item)
+	}
+}
+
+func drawTemplateButtons(dialogX, dialogY, dialogHeight int) (saveBtn, loadBtn rl.Rectangle) {
+	saveBtn = rl.Rectangle{
+		X:      float32(dialogX + 20),
+		Y:      float32(dialogY + dialogHeight - 40),
+		Width:  130,
+		Height: 30,
+	}
+	loadBtn = rl.Rectangle{
+		X:      float32(dialogX + 160),
+		Y:      float32(dialogY + dialogHeight - 40),
+		Width:  130,
+		Height: 30,
+	}
+	rl.DrawRectangleRec(saveBtn, rl.LightGray)
+	rl.DrawRectangleRec(loadBtn, rl.LightGray)
+	rl.DrawText("Save Template", int32(saveBtn.X+10), int32(saveBtn.Y+8), 16, rl.Black)
+	rl.DrawText("Load Template", int32(loadBtn.X+10), int32(loadBtn.Y+8), 16, rl.Black)
+	return saveBtn, loadBtn
+}
+
+// completeNPCTexture fills in any missing direction, since the NPC editor expects all four.
+func completeNPCTexture(tex *beam.NPCTexture) *beam.NPCTexture {
+	if tex == nil {
+		tex = &beam.NPCTexture{}
+	}
+	for _, dir := range []**beam.AnimatedTexture{&tex.Up, &tex.Down, &tex.Left, &tex.Right} {
+		if *dir == nil {
+			*dir = &beam.AnimatedTexture{Frames: []beam.Texture{}}
+		}
+	}
+	return tex
+}
@@ -0,0 +1,111 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+func frameNames(tex *beam.AnimatedTexture) []string {
+	names := make([]string, len(tex.Frames))
+	for i, frame := range tex.Frames {
+		names[i] = frame.Name
+	}
+	return names
+}
+
+// TestNPCTemplateRoundTrip checks a saved NPC template reloads with the same stats and frame names.
+func TestNPCTemplateRoundTrip(t *testing.T) {
+	defer func(dir string) { templateDir = dir }(templateDir)
+	templateDir = t.TempDir()
+	walk := func(prefix string) *beam.AnimatedTexture {
+		return &beam.AnimatedTexture{
+			Frames:        []beam.Texture{{Name: prefix + "_1", ScaleX: 1, ScaleY: 1}, {Name: prefix + "_2", ScaleX: 1, ScaleY: 1}},
+			IsAnimated:    true,
+			AnimationTime: 0.25,
+		}
+	}
+	data := beam.NPCData{
+		Name:            "Goblin Archer",
+		Texture:         &beam.NPCTexture{Up: walk("goblin_up"), Down: walk("goblin_down"), Left: walk("goblin_left"), Right: walk("goblin_right")},
+		SpawnPos:        beam.Position{X: 4, Y: 7},
+		MaxHealth:       40,
+		BaseAttack:      6,
+		BaseDefense:     2,
+		BaseAttackSpeed: 1.5,
+		BaseAttackRange: 4,
+		MoveSpeed:       2,
+		Hostile:         true,
+		AggroRange:      5,
+		WanderRange:     3,
+	}
+	if err := SaveNPCTemplate(data.Name, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveNPCTemplate("  ", data); err == nil {
+		t.Errorf("Expected an error saving a template without a name")
+	}
+
+	templates, err := LoadNPCTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Name != "Goblin Archer" {
+		t.Fatalf("Expected the Goblin Archer template, got %+v", templates)
+	}
+	got := templates[0].Data
+	if got.MaxHealth != 40 || got.BaseAttack != 6 || got.BaseDefense != 2 || got.BaseAttackSpeed != 1.5 ||
+		got.BaseAttackRange != 4 || got.MoveSpeed != 2 || !got.Hostile || got.AggroRange != 5 || got.WanderRange != 3 {
+		t.Errorf("Expected stats to round trip, got %+v", got)
+	}
+	if got.SpawnPos != (beam.Position{}) {
+		t.Errorf("Expected the spawn position not to be saved, got %v", got.SpawnPos)
+	}
+	for dir, tex := range map[string]*beam.AnimatedTexture{"up": got.Texture.Up, "down": got.Texture.Down, "left": got.Texture.Left, "right": got.Texture.Right} {
+		names := frameNames(tex)
+		if len(names) != 2 || names[0] != "goblin_"+dir+"_1" || names[1] != "goblin_"+dir+"_2" {
+			t.Errorf("Expected %s frames to round trip, got %v", dir, names)
+		}
+		if tex.AnimationTime != 0.25 {
+			t.Errorf("Expected %s animation time 0.25, got %v", dir, tex.AnimationTime)
+		}
+	}
+}
+
+// TestItemTemplateRoundTrip checks a saved item template reloads with the same stats and frame names.
+func TestItemTemplateRoundTrip(t *testing.T) {
+	defer func(dir string) { templateDir = dir }(templateDir)
+	templateDir = t.TempDir()
+	sword := beam.NewItem("iron_sword", "Iron Sword", beam.ItemTypeEquipment).
+		WithStats(beam.ItemStats{Attack: 7, Defense: 1, AttackSpeed: 2, AttackRange: 1}).
+		WithRequirements(beam.ItemRequirements{Level: 3})
+	sword.EquipmentType = beam.EquipmentTypeWeapon
+	sword.Pos = beam.Position{X: 2, Y: 9}
+	sword.Texture = &beam.AnimatedTexture{Frames: []beam.Texture{{Name: "sword_1"}, {Name: "sword_2"}}}
+
+	if err := SaveItemTemplate("Iron Sword", *sword); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveItemTemplate("Another/Sword", *sword); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := LoadItemTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "Another/Sword" || templates[1].Name != "Iron Sword" {
+		t.Fatalf("Expected both templates sorted by name, got %+v", templates)
+	}
+	got := templates[1].Item
+	if got.ID != "iron_sword" || got.EquipmentType != beam.EquipmentTypeWeapon || got.Stats.Attack != 7 ||
+		got.Stats.Defense != 1 || got.Stats.AttackSpeed != 2 || got.Stats.AttackRange != 1 || got.Requirements.Level != 3 {
+		t.Errorf("Expected stats to round trip, got %+v", got)
+	}
+	if got.Pos != (beam.Position{}) {
+		t.Errorf("Expected the position not to be saved, got %v", got.Pos)
+	}
+	if names := frameNames(got.Texture); len(names) != 2 || names[0] != "sword_1" || names[1] != "sword_2" {
+		t.Errorf("Expected frame names to round trip, got %v", names)
+	}
+}
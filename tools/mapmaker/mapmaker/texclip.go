@@ -0,0 +1,98 @@
+package mapmaker
+
+import (
+	"fmt"
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// copyTextureLayer copies a single texture layer, and switches to the paste texture tool to apply it to other tiles.
+// Unlike Ctrl+C, which copies whole tiles, only this texture is pasted, on top of what's already there.
+func (m *MapMaker) copyTextureLayer(tex *beam.AnimatedTexture) {
+	if tex == nil || len(tex.Frames) == 0 {
+		return
+	}
+	m.textureClipboard = copyAnimatedTexture(tex)
+	m.uiState.selectedTool = "pastetexture"
+	m.showTileInfo = false
+	m.showToast(fmt.Sprintf("Copied %s, click tiles to paste it", tex.Frames[0].Name), ToastInfo)
+}
+
+// handlePasteTextureTool pastes the copied texture onto the clicked tile,
+// or onto every selected tile when clicking inside a selection.
+func (m *MapMaker) handlePasteTextureTool() {
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) || m.isDialogOpen() {
+		return
+	}
+	pos, ok := m.mouseGridPos()
+	if !ok {
+		return
+	}
+	if m.textureClipboard == nil {
+		m.showToast("Right click a texture in the tile info to copy it first", ToastError)
+		return
+	}
+
+	targets := beam.Positions{pos}
+	if m.tileGrid.hasSelection && len(m.tileGrid.selectedTiles) > 1 && slices.Contains(m.tileGrid.selectedTiles, pos) {
+		targets = m.tileGrid.selectedTiles
+	} else {
+		m.tileGrid.selectedTiles = targets
+		m.tileGrid.hasSelection = true
+	}
+	m.pasteTextureLayer(targets)
+}
+
+// pasteTextureLayer appends a copy of the copied texture to each tile, as one undo step.
+// The texture keeps the layer it was copied from, unless a layer is solo, in which case it goes on that layer.
+// A spanning texture is skipped where its span doesn't fit, like the paintbrush.
+func (m *MapMaker) pasteTextureLayer(positions beam.Positions) {
+	if m.textureClipboard == nil {
+		return
+	}
+	placed := 0
+	var firstErr error
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			// Every tile gets its own copy, so editing one pasted texture doesn't change the others
+			tex := copyAnimatedTexture(m.textureClipboard)
+			if solo := m.uiState.layerVisibility.Solo; solo != nil {
+				tex.Layer = *solo
+			}
+			if tex.IsSpanning() {
+				if err := m.tileGrid.PlaceSpanTexture(pos, tex); err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+			} else {
+				tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+				tile.Textures = append(tile.Textures, tex)
+			}
+			m.tileGrid.Tiles[pos.Y][pos.X].Type = beam.FloorTile
+			placed++
+		}
+	})
+	if placed == 0 && firstErr != nil {
+		m.showToast("Can't paste texture: "+firstErr.Error(), ToastError)
+	}
+}
+
+// renderPasteTextureGhost previews the copied texture on the tile under the cursor.
+func (m *MapMaker) renderPasteTextureGhost(viewStartX, viewStartY, viewEndX, viewEndY int) {
+	if m.uiState.selectedTool != "pastetexture" || m.textureClipboard == nil || m.isDialogOpen() {
+		return
+	}
+	pos, ok := m.mouseGridPos()
+	if !ok || pos.X < viewStartX || pos.X >= viewEndX || pos.Y < viewStartY || pos.Y >= viewEndY {
+		return
+	}
+	tileSize := float32(m.renderTileSize())
+	screenX := float32(m.tileGrid.offset.X) + float32(pos.X-viewStartX)*tileSize
+	screenY := float32(m.tileGrid.offset.Y) + float32(pos.Y-viewStartY)*tileSize
+	m.drawGhostFrame(m.textureClipboard.Frames[0], screenX, screenY, tileSize)
+	rl.DrawRectangleLinesEx(rl.Rectangle{X: screenX, Y: screenY, Width: tileSize, Height: tileSize}, 1, rl.Fade(rl.DarkGray, 0.5))
+}
@@ -0,0 +1,77 @@
+package mapmaker
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// TestPasteTextureLayer copies one texture from a tile and pastes it onto others, checking the copies don't alias the source.
+func TestPasteTextureLayer(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "dirt")
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "flower")
+	m.paintTiles(beam.Positions{{X: 2, Y: 1}}, "grass")
+	source := m.tileGrid.Tiles[0][0].Textures[1]
+	source.Layer = beam.ForegroundLayer
+
+	m.copyTextureLayer(source)
+	if m.uiState.selectedTool != "pastetexture" {
+		t.Errorf("Expected copying a texture to select the paste texture tool, got %q", m.uiState.selectedTool)
+	}
+	targets := beam.Positions{{X: 2, Y: 1}, {X: 3, Y: 2}}
+	m.pasteTextureLayer(targets)
+
+	for _, pos := range targets {
+		textures := m.tileGrid.Tiles[pos.Y][pos.X].Textures
+		top := textures[len(textures)-1]
+		if top.Frames[0].Name != "flower" || top.Layer != beam.ForegroundLayer {
+			t.Errorf("Expected a foreground flower pasted on %v, got %s on %s", pos, top.Frames[0].Name, top.Layer)
+		}
+	}
+	if got := len(m.tileGrid.Tiles[1][2].Textures); got != 2 {
+		t.Errorf("Expected the flower appended on top of the grass, got %d textures", got)
+	}
+
+	// Editing a pasted texture's frame changes neither the source, the clipboard, nor the other pastes
+	pasted := m.tileGrid.Tiles[1][2].Textures[1]
+	pasted.Frames[0].Rotation = 90
+	pasted.Frames[0].Tint = rl.Red
+	for name, tex := range map[string]*beam.AnimatedTexture{
+		"source":    source,
+		"clipboard": m.textureClipboard,
+		"other":     m.tileGrid.Tiles[2][3].Textures[0],
+	} {
+		if tex.Frames[0].Rotation != 0 || tex.Frames[0].Tint != rl.White {
+			t.Errorf("Expected editing a pasted texture to leave the %s alone, got rotation %v", name, tex.Frames[0].Rotation)
+		}
+	}
+	// And editing the source doesn't change what's pasted next
+	source.Frames[0].Name = "weed"
+	if m.textureClipboard.Frames[0].Name != "flower" {
+		t.Errorf("Expected the clipboard to keep the flower, got %s", m.textureClipboard.Frames[0].Name)
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the paste to be undoable")
+	}
+	if got := len(m.tileGrid.Tiles[2][3].Textures); got != 0 {
+		t.Errorf("Expected undo to remove the pasted texture, got %d textures", got)
+	}
+	if got := len(m.tileGrid.Tiles[1][2].Textures); got != 1 {
+		t.Errorf("Expected undo to leave only the grass, got %d textures", got)
+	}
+}
+
+// TestPasteTextureLayerSolo checks a texture pasted while a layer is solo goes on that layer.
+func TestPasteTextureLayerSolo(t *testing.T) {
+	m := newTestMapMaker(2, 2)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "flower")
+	m.copyTextureLayer(m.tileGrid.Tiles[0][0].Textures[0])
+	m.uiState.layerVisibility.ToggleSolo(beam.BackgroundLayer)
+	m.pasteTextureLayer(beam.Positions{{X: 1, Y: 1}})
+	if layer := m.tileGrid.Tiles[1][1].Textures[0].Layer; layer != beam.BackgroundLayer {
+		t.Errorf("Expected the paste on the solo background layer, got %s", layer)
+	}
+}
@@ -0,0 +1,74 @@
+package mapmaker
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	KeyRepeatDelay    = 0.5  // Seconds a key is held before it starts repeating
+	KeyRepeatInterval = 0.05 // Seconds between repeats once it has started
+)
+
+// keyRepeat tracks a held key, so it acts once when pressed, and then repeatedly after KeyRepeatDelay.
+type keyRepeat struct {
+	down bool
+	held float64
+}
+
+// update advances the key by dt seconds, and returns how many times it fires this frame.
+// Repeats are counted from the time held, so a slow frame fires all the repeats it covered.
+func (k *keyRepeat) update(down bool, dt float64) int {
+	if !down {
+		*k = keyRepeat{}
+		return 0
+	}
+	if !k.down {
+		*k = keyRepeat{down: true}
+		return 1
+	}
+	before := k.held
+	k.held += dt
+	return repeatsBy(k.held) - repeatsBy(before)
+}
+
+// repeatsBy returns how many repeats have fired after a key was held for the given time.
+func repeatsBy(held float64) int {
+	if held < KeyRepeatDelay {
+		return 0
+	}
+	return int(math.Floor((held-KeyRepeatDelay)/KeyRepeatInterval)) + 1
+}
+
+// textInput types the characters pressed this frame into value, if the field is focused.
+// Numeric fields only take digits, '.' and '-'. Holding backspace deletes repeatedly.
+func (m *MapMaker) textInput(value *string, focused bool, numeric bool) {
+	if !focused {
+		return
+	}
+	for key := rl.GetCharPressed(); key > 0; key = rl.GetCharPressed() {
+		if numeric && !((key >= '0' && key <= '9') || key == '.' || key == '-') {
+			continue
+		} else if key < 32 || key > 126 {
+			continue
+		}
+		*value += string(key)
+	}
+	for range m.uiState.backspaceRepeat.update(rl.IsKeyDown(rl.KeyBackspace), float64(rl.GetFrameTime())) {
+		if len(*value) > 0 {
+			*value = (*value)[:len(*value)-1]
+		}
+	}
+}
+
+// clearingTextInput is a textInput for the texture editor, where the first character typed
+// into a field replaces the value it was opened with, rather than adding to it.
+func (m *MapMaker) clearingTextInput(editor *TextureEditorState, label string, value *string) {
+	before := *value
+	m.textInput(value, true, false)
+	if !editor.clearedInputs[label] && len(*value) > len(before) && (*value)[:len(before)] == before {
+		*value = (*value)[len(before):]
+		editor.clearedInputs[label] = true
+	}
+}
@@ -0,0 +1,66 @@
+package mapmaker
+
+import "testing"
+
+// TestKeyRepeat checks a held key fires once when pressed, waits out the delay, then repeats at the interval.
+func TestKeyRepeat(t *testing.T) {
+	var k keyRepeat
+	if got := k.update(false, 0.1); got != 0 {
+		t.Errorf("Expected no fires for an idle key, got %d", got)
+	}
+	if got := k.update(true, 0.1); got != 1 {
+		t.Errorf("Expected 1 fire when pressed, got %d", got)
+	}
+
+	// Held through most of the delay
+	total := 0
+	for range 4 {
+		total += k.update(true, 0.1)
+	}
+	if total != 0 {
+		t.Errorf("Expected no repeats before %vs, got %d", KeyRepeatDelay, total)
+	}
+
+	// Crossing the delay fires the first repeat, then one per interval
+	if got := k.update(true, 0.1); got != 1 {
+		t.Errorf("Expected the first repeat at %vs, got %d", KeyRepeatDelay, got)
+	}
+	if got := k.update(true, KeyRepeatInterval); got != 1 {
+		t.Errorf("Expected 1 repeat after an interval, got %d", got)
+	}
+
+	// A slow frame fires every repeat it covered
+	if got := k.update(true, 4*KeyRepeatInterval); got != 4 {
+		t.Errorf("Expected 4 repeats over 4 intervals, got %d", got)
+	}
+
+	// Releasing starts over
+	if got := k.update(false, 0.1); got != 0 {
+		t.Errorf("Expected no fires on release, got %d", got)
+	}
+	if got := k.update(true, 0.1); got != 1 {
+		t.Errorf("Expected 1 fire when pressed again, got %d", got)
+	}
+	if got := k.update(true, 0.1); got != 0 {
+		t.Errorf("Expected the delay to start over, got %d", got)
+	}
+}
+
+// TestKeyRepeatFrameRate checks the same hold fires the same number of times at different frame rates.
+func TestKeyRepeatFrameRate(t *testing.T) {
+	fires := func(dt float64) int {
+		var k keyRepeat
+		total := k.update(true, 0)
+		for range int(1.5 / dt) {
+			total += k.update(true, dt)
+		}
+		return total
+	}
+	// 1 press, then repeats from 0.5s to 1.5s
+	want := 1 + repeatsBy(1.5)
+	for _, dt := range []float64{1.0 / 30, 1.0 / 60, 1.0 / 144} {
+		if got := fires(dt); got < want-1 || got > want {
+			t.Errorf("Expected about %d fires at %.0ffps, got %d", want, 1/dt, got)
+		}
+	}
+}
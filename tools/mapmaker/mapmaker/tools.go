@@ -1,6 +1,7 @@
 package mapmaker
 
 import (
+	"crypto/rand"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -8,6 +9,7 @@ import (
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/ztkent/beam"
+	beam_math "github.com/ztkent/beam/math"
 )
 
 // Option Buttons
@@ -97,81 +99,298 @@ func (m *MapMaker) isIconButtonClicked(btn IconButton) bool {
 	return rl.CheckCollisionPointRec(rl.GetMousePosition(), btn.rect) && rl.IsMouseButtonPressed(rl.MouseLeftButton)
 }
 
-func (m *MapMaker) floodFillSelection(startX, startY int) beam.Positions {
-	result := make(beam.Positions, 0)
-	if startX < 0 || startX >= m.tileGrid.Width || startY < 0 || startY >= m.tileGrid.Height {
-		return result
-	}
+// paintTiles adds a texture layer to each tile, recording the change for undo.
+func (m *MapMaker) paintTiles(positions beam.Positions, textureName string) {
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+			tile.Type = beam.FloorTile
+			tile.Textures = append(tile.Textures, beam.NewSimpleTileTexture(textureName))
+		}
+	})
+}
 
-	// Get the source tile's texture pattern
-	sourceTile := m.tileGrid.Tiles[startY][startX]
+// textureSpanActive reports if the paintbrush places the active texture over more than one tile.
+func (m *MapMaker) textureSpanActive() bool {
+	return m.uiState.textureSpanX > 1 || m.uiState.textureSpanY > 1
+}
 
-	// Create a visited map
-	visited := make(map[string]bool)
+// adjustTextureSpan steps the width of the texture span with shift, and the height with alt.
+func (m *MapMaker) adjustTextureSpan(width, height bool, step int) {
+	clamp := func(span int) int { return min(max(1, span+step), beam.MaxTextureSpan) }
+	if width {
+		m.uiState.textureSpanX = clamp(m.uiState.textureSpanX)
+	}
+	if height {
+		m.uiState.textureSpanY = clamp(m.uiState.textureSpanY)
+	}
+	m.showToast(fmt.Sprintf("Texture span: %dx%d", max(1, m.uiState.textureSpanX), max(1, m.uiState.textureSpanY)), ToastInfo)
+}
 
-	// Stack for flood fill
-	stack := beam.Positions{{X: startX, Y: startY}}
+// paintSpans places the active texture over the texture span at each anchor, as one undo step.
+// Anchors where the whole span doesn't fit, or would overlap another span, are skipped.
+func (m *MapMaker) paintSpans(anchors beam.Positions, textureName string) {
+	placed := 0
+	var firstErr error
+	m.recordTileChanges(anchors, func() {
+		for _, anchor := range anchors {
+			tex := beam.NewSimpleTileTexture(textureName)
+			tex.Frames[0].SpanX, tex.Frames[0].SpanY = m.uiState.textureSpanX, m.uiState.textureSpanY
+			if err := m.tileGrid.PlaceSpanTexture(anchor, tex); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			m.tileGrid.Tiles[anchor.Y][anchor.X].Type = beam.FloorTile
+			placed++
+		}
+	})
+	if placed == 0 && firstErr != nil {
+		m.showToast("Can't place texture: "+firstErr.Error(), ToastError)
+	}
+}
 
-	// Check if two tiles have the same texture pattern
-	matchesPattern := func(x, y int) bool {
-		if x < 0 || x >= m.tileGrid.Width || y < 0 || y >= m.tileGrid.Height {
-			return false
+// eraseTopLayer removes the last frame or layer from each selected tile.
+func (m *MapMaker) eraseTopLayer() {
+	for _, pos := range m.tileGrid.selectedTiles {
+		tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+		if len(tile.Textures) > 0 {
+			lastTexture := tile.Textures[len(tile.Textures)-1]
+			if lastTexture.IsAnimated && len(lastTexture.Frames) > 0 {
+				lastTexture.Frames = lastTexture.Frames[:len(lastTexture.Frames)-1]
+				if len(lastTexture.Frames) == 0 {
+					tile.Textures = tile.Textures[:len(tile.Textures)-1]
+				}
+			} else {
+				tile.Textures = tile.Textures[:len(tile.Textures)-1]
+			}
 		}
+	}
+}
 
-		targetTile := m.tileGrid.Tiles[y][x]
-		if len(targetTile.Textures) != len(sourceTile.Textures) {
-			return false
-		} else if targetTile.Type != sourceTile.Type {
-			return false
+// copySelection copies the selected tiles into the clipboard, keeping their arrangement.
+// Gaps in the selection are left as empty tiles, which aren't pasted.
+func (m *MapMaker) copySelection() {
+	// Find bounds of selection
+	minX, minY := m.tileGrid.Width, m.tileGrid.Height
+	maxX, maxY := 0, 0
+	for _, pos := range m.tileGrid.selectedTiles {
+		minX, minY = min(minX, pos.X), min(minY, pos.Y)
+		maxX, maxY = max(maxX, pos.X), max(maxY, pos.Y)
+	}
+
+	// Create clipboard array of correct size
+	width := maxX - minX + 1
+	height := maxY - minY + 1
+	m.clipboard = make([][]beam.Tile, height)
+	for i := range m.clipboard {
+		m.clipboard[i] = make([]beam.Tile, width)
+	}
+
+	// Copy selected tiles to clipboard
+	for _, pos := range m.tileGrid.selectedTiles {
+		// Deep copied, so editing the source tiles doesn't change what gets pasted
+		m.clipboard[pos.Y-minY][pos.X-minX] = copyTile(m.tileGrid.Tiles[pos.Y][pos.X])
+	}
+}
+
+// stamp pastes the clipboard with its top left corner at anchor, as one undo step.
+// Anything past the edge of the grid is clipped.
+func (m *MapMaker) stamp(anchor beam.Position) {
+	positions := beam.Positions{}
+	for clipY := range m.clipboard {
+		for clipX := range m.clipboard[clipY] {
+			positions = append(positions, beam.Position{X: anchor.X + clipX, Y: anchor.Y + clipY})
 		}
+	}
+	m.recordTileChanges(positions, func() {
+		m.pasteClipboard(anchor)
+	})
+}
 
-		// Compare each texture in the pattern
-		for i, tex := range targetTile.Textures {
-			sourceTex := sourceTile.Textures[i]
+// pasteClipboard copies the clipboard onto the grid, with its top left corner at targetPos.
+// Empty clipboard tiles are skipped.
+func (m *MapMaker) pasteClipboard(targetPos beam.Position) {
+	for clipY := range m.clipboard {
+		for clipX := range m.clipboard[clipY] {
+			// Calculate target grid position
+			gridX := targetPos.X + clipX
+			gridY := targetPos.Y + clipY
+
+			// Skip if outside grid bounds
+			if gridX >= m.tileGrid.Width || gridY >= m.tileGrid.Height {
+				continue
+			}
 
-			// Check if both textures are complex or simple
-			if tex.IsAnimated != sourceTex.IsAnimated {
-				return false
+			// Skip if clipboard tile is empty
+			if len(m.clipboard[clipY][clipX].Textures) == 0 {
+				continue
 			}
 
-			// Compare frames if complex
-			if tex.IsAnimated {
-				if len(tex.Frames) != len(sourceTex.Frames) {
-					return false
-				}
-				for j, frame := range tex.Frames {
-					sourceFrame := sourceTex.Frames[j]
-					if frame.Name != sourceFrame.Name ||
-						frame.Rotation != sourceFrame.Rotation ||
-						frame.ScaleX != sourceFrame.ScaleX ||
-						frame.ScaleY != sourceFrame.ScaleY ||
-						frame.OffsetX != sourceFrame.OffsetX ||
-						frame.OffsetY != sourceFrame.OffsetY ||
-						frame.Tint != sourceFrame.Tint {
-						return false
-					}
-				}
-			} else {
-				// Compare the first frame for simple textures
-				if len(tex.Frames) == 0 || len(sourceTex.Frames) == 0 {
-					return false
-				}
-				frame := tex.Frames[0]
-				sourceFrame := sourceTex.Frames[0]
-				if frame.Name != sourceFrame.Name ||
-					frame.Rotation != sourceFrame.Rotation ||
-					frame.ScaleX != sourceFrame.ScaleX ||
-					frame.ScaleY != sourceFrame.ScaleY ||
-					frame.OffsetX != sourceFrame.OffsetX ||
-					frame.OffsetY != sourceFrame.OffsetY ||
-					frame.Tint != sourceFrame.Tint {
-					return false
-				}
+			// Copy the tile data, so pasted tiles don't share textures
+			m.tileGrid.Tiles[gridY][gridX] = copyTile(m.clipboard[clipY][clipX])
+			// Update the position to match the new location
+			m.tileGrid.Tiles[gridY][gridX].Pos = beam.Position{X: gridX, Y: gridY}
+		}
+	}
+}
+
+// brushFootprint returns the NxN block of tiles a brush centered on pos would paint,
+// clamped to the grid. Even sizes extend further down and to the right.
+// A paintbrush placing a spanning texture only anchors on the tile under the cursor.
+func (m *MapMaker) brushFootprint(pos beam.Position) beam.Positions {
+	size := max(1, m.uiState.brushSize)
+	if m.uiState.selectedTool == "paintbrush" && m.textureSpanActive() {
+		size = 1
+	}
+	start := beam.Position{X: pos.X - (size-1)/2, Y: pos.Y - (size-1)/2}
+	footprint := make(beam.Positions, 0, size*size)
+	for y := start.Y; y < start.Y+size; y++ {
+		for x := start.X; x < start.X+size; x++ {
+			if x < 0 || x >= m.tileGrid.Width || y < 0 || y >= m.tileGrid.Height {
+				continue
 			}
+			footprint = append(footprint, beam.Position{X: x, Y: y})
 		}
-		return true
+	}
+	return footprint
+}
+
+// isBrushTool reports if the tool applies to the brush footprint rather than a single tile.
+func isBrushTool(tool string) bool {
+	return tool == "paintbrush" || tool == "eraser" || tool == "pencileraser"
+}
+
+// isShapeTool reports if the tool drags out a shape between two tiles.
+func isShapeTool(tool string) bool {
+	return tool == "rect" || tool == "line"
+}
+
+// handleShapeTool starts a shape on left mouse down, and paints it on release.
+func (m *MapMaker) handleShapeTool() {
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !m.isDialogOpen() {
+		if pos, ok := m.mouseGridPos(); ok {
+			m.uiState.shapeStart = pos
+			m.uiState.isDrawingShape = true
+		}
+	}
+	if !m.uiState.isDrawingShape || !rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		return
+	}
+
+	m.uiState.isDrawingShape = false
+	if m.uiState.activeTexture == nil {
+		m.showToast("Select a texture to draw with", ToastError)
+		return
+	}
+	// Painted as one action, so the whole shape is a single undo
+	m.paintTiles(m.shapePositions(m.uiState.shapeStart, m.shapeEnd()), m.uiState.activeTexture.Name)
+}
+
+// handleRectSelect starts a rectangle on left mouse down, and selects every tile inside it on release.
+// Clicking without dragging selects a single tile.
+func (m *MapMaker) handleRectSelect() {
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !m.isDialogOpen() {
+		if pos, ok := m.mouseGridPos(); ok {
+			m.uiState.selectStart = pos
+			m.uiState.isRectSelecting = true
+		}
+	}
+	if !m.uiState.isRectSelecting || !rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+		return
 	}
 
+	m.uiState.isRectSelecting = false
+	m.tileGrid.selectedTiles = rectPositions(m.uiState.selectStart, m.shapeEnd(), true)
+	m.tileGrid.hasSelection = true
+}
+
+// visibleRect returns the corners of the rectangle between a and b, clipped to the visible tiles
+// from viewStart up to, but not including, viewEnd. ok is false if none of it is visible.
+func visibleRect(a, b, viewStart, viewEnd beam.Position) (topLeft, bottomRight beam.Position, ok bool) {
+	topLeft = beam.Position{X: max(min(a.X, b.X), viewStart.X), Y: max(min(a.Y, b.Y), viewStart.Y)}
+	bottomRight = beam.Position{X: min(max(a.X, b.X), viewEnd.X-1), Y: min(max(a.Y, b.Y), viewEnd.Y-1)}
+	return topLeft, bottomRight, topLeft.X <= bottomRight.X && topLeft.Y <= bottomRight.Y
+}
+
+// shapeEnd returns the tile under the mouse, clamped to the grid.
+func (m *MapMaker) shapeEnd() beam.Position {
+	x, y := m.screenToGrid(rl.GetMousePosition())
+	return beam.Position{
+		X: max(0, min(x, m.tileGrid.Width-1)),
+		Y: max(0, min(y, m.tileGrid.Height-1)),
+	}
+}
+
+// shapePositions returns the tiles the active shape tool covers from start to end.
+// Holding Shift fills the rectangle instead of drawing its outline.
+func (m *MapMaker) shapePositions(start, end beam.Position) beam.Positions {
+	if m.uiState.selectedTool == "line" {
+		return linePositions(start, end)
+	}
+	filled := rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+	return rectPositions(start, end, filled)
+}
+
+// rectPositions returns the outline of the rectangle with corners a and b,
+// or every tile inside it when filled.
+func rectPositions(a, b beam.Position, filled bool) beam.Positions {
+	minX, maxX := min(a.X, b.X), max(a.X, b.X)
+	minY, maxY := min(a.Y, b.Y), max(a.Y, b.Y)
+	positions := make(beam.Positions, 0)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			if filled || x == minX || x == maxX || y == minY || y == maxY {
+				positions = append(positions, beam.Position{X: x, Y: y})
+			}
+		}
+	}
+	return positions
+}
+
+// linePositions returns the tiles on a straight line from a to b, using Bresenham's algorithm.
+func linePositions(a, b beam.Position) beam.Positions {
+	dx := beam_math.Abs(b.X - a.X)
+	dy := -beam_math.Abs(b.Y - a.Y)
+	stepX := beam_math.Sign(b.X - a.X)
+	stepY := beam_math.Sign(b.Y - a.Y)
+
+	positions := make(beam.Positions, 0, max(dx, -dy)+1)
+	x, y, err := a.X, a.Y, dx+dy
+	for {
+		positions = append(positions, beam.Position{X: x, Y: y})
+		if x == b.X && y == b.Y {
+			return positions
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += stepX
+		}
+		if e2 <= dx {
+			err += dx
+			y += stepY
+		}
+	}
+}
+
+func (m *MapMaker) floodFillSelection(startX, startY int) beam.Positions {
+	result := make(beam.Positions, 0)
+	source, ok := m.tileGrid.TileAt(beam.Position{X: startX, Y: startY})
+	if !ok {
+		return result
+	}
+
+	// Get the source tile's texture pattern
+	sourceTile := *source
+
+	// Create a visited map
+	visited := make(map[string]bool)
+
+	// Stack for flood fill
+	stack := beam.Positions{{X: startX, Y: startY}}
+
 	// Process the stack
 	for len(stack) > 0 {
 		current := stack[len(stack)-1]
@@ -185,30 +404,132 @@ func (m *MapMaker) floodFillSelection(startX, startY int) beam.Positions {
 		visited[key] = true
 		result = append(result, current)
 
-		// Check all 4 directions
-		directions := beam.Positions{
-			{X: current.X + 1, Y: current.Y}, // right
-			{X: current.X - 1, Y: current.Y}, // left
-			{X: current.X, Y: current.Y + 1}, // down
-			{X: current.X, Y: current.Y - 1}, // up
+		// Check all 4 directions for a neighbor with the same texture pattern
+		for _, next := range m.tileGrid.Neighbors(current, false) {
+			key := fmt.Sprintf("%d,%d", next.X, next.Y)
+			if !visited[key] && tilesMatch(m.tileGrid.Tiles[next.Y][next.X], sourceTile) {
+				stack = append(stack, next)
+			}
 		}
+	}
 
-		for _, dir := range directions {
-			if dir.X >= 0 && dir.X < m.tileGrid.Width &&
-				dir.Y >= 0 && dir.Y < m.tileGrid.Height {
-				key := fmt.Sprintf("%d,%d", dir.X, dir.Y)
-				if !visited[key] && matchesPattern(dir.X, dir.Y) {
-					stack = append(stack, dir)
-				}
+	return result
+}
+
+// matchingTiles returns every tile on the map with the same pattern as the tile at (x, y).
+func (m *MapMaker) matchingTiles(x, y int) beam.Positions {
+	result := make(beam.Positions, 0)
+	if x < 0 || x >= m.tileGrid.Width || y < 0 || y >= m.tileGrid.Height {
+		return result
+	}
+	sourceTile := m.tileGrid.Tiles[y][x]
+	for tileY := 0; tileY < m.tileGrid.Height; tileY++ {
+		for tileX := 0; tileX < m.tileGrid.Width; tileX++ {
+			if tilesMatch(m.tileGrid.Tiles[tileY][tileX], sourceTile) {
+				result = append(result, beam.Position{X: tileX, Y: tileY})
 			}
 		}
 	}
-
 	return result
 }
 
+// bucketReplace replaces the tile at pos, and every connected tile with the same pattern, with a single texture.
+// With wholeMap set, every matching tile on the map is replaced, connected or not.
+// The replacement is one undo step. Returns the tiles that were replaced.
+func (m *MapMaker) bucketReplace(pos beam.Position, textureName string, wholeMap bool) beam.Positions {
+	positions := m.floodFillSelection(pos.X, pos.Y)
+	if wholeMap {
+		positions = m.matchingTiles(pos.X, pos.Y)
+	}
+	m.recordTileChanges(positions, func() {
+		for _, pos := range positions {
+			tile := &m.tileGrid.Tiles[pos.Y][pos.X]
+			tile.Type = beam.FloorTile
+			tile.Textures = []*beam.AnimatedTexture{beam.NewSimpleTileTexture(textureName)}
+		}
+	})
+	return positions
+}
+
+// fillSelection paints every selected tile with the active texture, or autotile set, as one undo step.
+func (m *MapMaker) fillSelection() {
+	if !m.tileGrid.hasSelection || len(m.tileGrid.selectedTiles) == 0 {
+		m.showToast("Select tiles to fill first", ToastError)
+		return
+	}
+	if m.uiState.autotileSet != nil {
+		m.paintAutotile(m.tileGrid.selectedTiles)
+	} else if m.uiState.activeTexture != nil {
+		m.paintTiles(m.tileGrid.selectedTiles, m.uiState.activeTexture.Name)
+	} else {
+		m.showToast("Select a texture to fill with", ToastError)
+		return
+	}
+	m.showToast(fmt.Sprintf("Filled %d tiles", len(m.tileGrid.selectedTiles)), ToastSuccess)
+}
+
+// tilesMatch reports if two tiles have the same type and texture pattern, layer by layer.
+func tilesMatch(targetTile, sourceTile beam.Tile) bool {
+	if len(targetTile.Textures) != len(sourceTile.Textures) {
+		return false
+	} else if targetTile.Type != sourceTile.Type {
+		return false
+	}
+
+	// Compare each texture in the pattern
+	for i, tex := range targetTile.Textures {
+		sourceTex := sourceTile.Textures[i]
+
+		// Check if both textures are complex or simple
+		if tex.IsAnimated != sourceTex.IsAnimated {
+			return false
+		}
+
+		// Compare frames if complex
+		if tex.IsAnimated {
+			if len(tex.Frames) != len(sourceTex.Frames) {
+				return false
+			}
+			for j, frame := range tex.Frames {
+				sourceFrame := sourceTex.Frames[j]
+				if frame.Name != sourceFrame.Name ||
+					frame.Rotation != sourceFrame.Rotation ||
+					frame.ScaleX != sourceFrame.ScaleX ||
+					frame.ScaleY != sourceFrame.ScaleY ||
+					frame.OffsetX != sourceFrame.OffsetX ||
+					frame.OffsetY != sourceFrame.OffsetY ||
+					frame.Tint != sourceFrame.Tint {
+					return false
+				}
+			}
+		} else {
+			// Compare the first frame for simple textures
+			if len(tex.Frames) == 0 || len(sourceTex.Frames) == 0 {
+				return false
+			}
+			frame := tex.Frames[0]
+			sourceFrame := sourceTex.Frames[0]
+			if frame.Name != sourceFrame.Name ||
+				frame.Rotation != sourceFrame.Rotation ||
+				frame.ScaleX != sourceFrame.ScaleX ||
+				frame.ScaleY != sourceFrame.ScaleY ||
+				frame.OffsetX != sourceFrame.OffsetX ||
+				frame.OffsetY != sourceFrame.OffsetY ||
+				frame.Tint != sourceFrame.Tint {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func openCloseConfirmationDialog() bool {
-	dialogWidth := int32(300)
+	return openConfirmationDialog("Close Map", "Are you sure you want to close?", "Cancel", "Close", rl.Red)
+}
+
+// openConfirmationDialog blocks until the user picks cancel or confirm, returns true if they confirmed.
+func openConfirmationDialog(title, message, cancelText, confirmText string, confirmColor rl.Color) bool {
+	dialogWidth := max(int32(300), rl.MeasureText(message, 16)+40)
 	dialogHeight := int32(150)
 
 	for {
@@ -234,8 +555,8 @@ func openCloseConfirmationDialog() bool {
 		}, 2, rl.Gray)
 
 		// Draw title and message
-		rl.DrawText("Close Map", int32(dialogX+20), int32(dialogY+20), 20, rl.Black)
-		rl.DrawText("Are you sure you want to close?", int32(dialogX+20), int32(dialogY+50), 16, rl.DarkGray)
+		rl.DrawText(title, int32(dialogX+20), int32(dialogY+20), 20, rl.Black)
+		rl.DrawText(message, int32(dialogX+20), int32(dialogY+50), 16, rl.DarkGray)
 
 		// Draw buttons
 		cancelBtn := rl.Rectangle{
@@ -252,11 +573,9 @@ func openCloseConfirmationDialog() bool {
 		}
 
 		rl.DrawRectangleRec(cancelBtn, rl.LightGray)
-		rl.DrawRectangleRec(confirmBtn, rl.Red)
+		rl.DrawRectangleRec(confirmBtn, confirmColor)
 
 		// Center text in buttons
-		cancelText := "Cancel"
-		confirmText := "Close"
 		cancelTextWidth := rl.MeasureText(cancelText, 16)
 		confirmTextWidth := rl.MeasureText(confirmText, 16)
 
@@ -491,9 +810,9 @@ func openFileDialog() string {
 
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file with prompt "Choose a sprite sheet or texture:" of type {"png","jpg","jpeg"})`)
+		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file with prompt "Choose a sprite sheet or texture:" of type {"png","jpg","jpeg","bmp","tga","qoi","gif"})`)
 	case "linux":
-		cmd = exec.Command("zenity", "--file-selection", "--file-filter=Images (*.png *.jpg *.jpeg)")
+		cmd = exec.Command("zenity", "--file-selection", "--file-filter=Images (*.png *.jpg *.jpeg *.bmp *.tga *.qoi *.gif)")
 	default:
 		return ""
 	}
@@ -504,3 +823,131 @@ func openFileDialog() string {
 	}
 	return strings.TrimSpace(string(output))
 }
+
+// duplicateNPC adds a copy of an NPC to the map, one tile over from the original's spawn.
+// The copy gets its own ID, its textures don't share frames with the original,
+// and its name gets a " (copy)" suffix so the two can be told apart.
+func (m *MapMaker) duplicateNPC(npc *beam.NPC) *beam.NPC {
+	data := copyNPCData(npc.Data)
+	data.ID = newNPCID()
+	data.Name = m.uniqueNPCName(npc.Data.Name+" (copy)", "")
+	data.SpawnPos = m.duplicateSpawnPos(data.SpawnPos, data.Size)
+
+	duplicate := &beam.NPC{Data: data, Pos: data.SpawnPos}
+	duplicate.ResetRuntime()
+	m.tileGrid.NPCs = append(m.tileGrid.NPCs, duplicate)
+	m.markDirty()
+	return duplicate
+}
+
+// duplicateSpawnPos picks the first free spot to the right, left, below or above pos for a copied NPC,
+// just clear of the original's footprint. Spots where the copy would cover a wall, a chest or part of
+// another NPC are skipped. If none are free, the copy goes to the right, or the left at the edge of the map.
+func (m *MapMaker) duplicateSpawnPos(pos beam.Position, size beam.NPCSize) beam.Position {
+	width, height := size.GetDimensions()
+	for _, offset := range []beam.Position{{X: width}, {X: -width}, {Y: height}, {Y: -height}} {
+		next := beam.Position{X: pos.X + offset.X, Y: pos.Y + offset.Y}
+		if !m.tileGrid.IsSpawnable(next, size) {
+			continue
+		}
+		occupied := false
+		for _, other := range m.tileGrid.NPCs {
+			if npcFootprintsOverlap(next, size, other.Data.SpawnPos, other.Data.Size) {
+				occupied = true
+				break
+			}
+		}
+		if !occupied {
+			return next
+		}
+	}
+
+	if pos.X+1 < m.tileGrid.Width {
+		pos.X++
+	} else if pos.X > 0 {
+		pos.X--
+	}
+	return pos
+}
+
+// npcFootprintsOverlap reports if NPCs of the given sizes at a and b would cover any of the same tiles.
+func npcFootprintsOverlap(a beam.Position, aSize beam.NPCSize, b beam.Position, bSize beam.NPCSize) bool {
+	aWidth, aHeight := aSize.GetDimensions()
+	bWidth, bHeight := bSize.GetDimensions()
+	return a.X < b.X+bWidth && b.X < a.X+aWidth && a.Y < b.Y+bHeight && b.Y < a.Y+aHeight
+}
+
+// uniqueNPCName returns name, or name with a number after it if another NPC already has that name.
+// The NPC with exceptID doesn't count, so an NPC being edited can keep its own name.
+func (m *MapMaker) uniqueNPCName(name, exceptID string) string {
+	unique := name
+	for i := 2; m.npcNameTaken(unique, exceptID); i++ {
+		unique = fmt.Sprintf("%s %d", name, i)
+	}
+	return unique
+}
+
+// npcNameTaken reports if an NPC other than the one with exceptID is called name.
+// Names are compared ignoring case and surrounding spaces.
+func (m *MapMaker) npcNameTaken(name, exceptID string) bool {
+	name = strings.TrimSpace(name)
+	for _, npc := range m.tileGrid.NPCs {
+		if exceptID != "" && npc.Data.ID == exceptID {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(npc.Data.Name), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newNPCID generates an ID for a new NPC.
+func newNPCID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return fmt.Sprintf("npc_%x", b)
+}
+
+// ensureNPCIDs gives an ID to any NPC without one, like those in maps saved before NPCs had IDs.
+func (m *MapMaker) ensureNPCIDs() {
+	for _, npc := range m.tileGrid.NPCs {
+		if npc.Data.ID == "" {
+			npc.Data.ID = newNPCID()
+		}
+	}
+}
+
+// saveEditedNPC replaces the NPC with the same ID, or adds it to the map if it's new.
+func (m *MapMaker) saveEditedNPC(npc *beam.NPC) {
+	m.markDirty()
+	if npc.Data.ID == "" {
+		npc.Data.ID = newNPCID()
+	}
+	for i, existing := range m.tileGrid.NPCs {
+		if existing.Data.ID == npc.Data.ID {
+			m.tileGrid.NPCs[i] = npc
+			return
+		}
+	}
+	m.tileGrid.NPCs = append(m.tileGrid.NPCs, npc)
+}
+
+// copyNPCData deep copies NPC data, including every direction of its textures.
+func copyNPCData(data beam.NPCData) beam.NPCData {
+	copyNPCTexture := func(tex *beam.NPCTexture) *beam.NPCTexture {
+		if tex == nil {
+			return nil
+		}
+		return &beam.NPCTexture{
+			Up:    copyAnimatedTexture(tex.Up),
+			Down:  copyAnimatedTexture(tex.Down),
+			Left:  copyAnimatedTexture(tex.Left),
+			Right: copyAnimatedTexture(tex.Right),
+		}
+	}
+	data.Texture = copyNPCTexture(data.Texture)
+	data.IdleTexture = copyNPCTexture(data.IdleTexture)
+	data.AttackTexture = copyNPCTexture(data.AttackTexture)
+	return data
+}
@@ -0,0 +1,229 @@
+package mapmaker
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam"
+	"github.com/ztkent/beam/resources"
+)
+
+// newTestBucketMap returns a 5x3 grid of grass, split by a column of stone at x=2,
+// with another stone tile at 4,0 that isn't connected to the column.
+//
+//	g g s g s
+//	g g s g g
+//	g g s g g
+func newTestBucketMap() *MapMaker {
+	m := newTestMapMaker(5, 3)
+	var grass, stone beam.Positions
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			if x == 2 || (x == 4 && y == 0) {
+				stone = append(stone, beam.Position{X: x, Y: y})
+			} else {
+				grass = append(grass, beam.Position{X: x, Y: y})
+			}
+		}
+	}
+	m.paintTiles(grass, "grass")
+	m.paintTiles(stone, "stone")
+	m.history.Clear()
+	return m
+}
+
+func tileTexture(m *MapMaker, x, y int) string {
+	return m.tileGrid.Tiles[y][x].Textures[0].Frames[0].Name
+}
+
+// TestBucketReplaceRegion checks only the connected region is replaced, and the fill is one undo step.
+func TestBucketReplaceRegion(t *testing.T) {
+	m := newTestBucketMap()
+	replaced := m.bucketReplace(beam.Position{X: 0, Y: 0}, "water", false)
+	if len(replaced) != 6 {
+		t.Fatalf("Expected the 6 grass tiles left of the stone to be replaced, got %d", len(replaced))
+	}
+	for _, pos := range replaced {
+		if len(m.tileGrid.Tiles[pos.Y][pos.X].Textures) != 1 || tileTexture(m, pos.X, pos.Y) != "water" {
+			t.Errorf("Expected only water at %v", pos)
+		}
+	}
+	if got := tileTexture(m, 3, 1); got != "grass" {
+		t.Errorf("Expected grass right of the stone to be untouched, got %s", got)
+	}
+	if got := tileTexture(m, 2, 1); got != "stone" {
+		t.Errorf("Expected the stone column to be untouched, got %s", got)
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the fill to be undoable")
+	}
+	if got := tileTexture(m, 0, 0); got != "grass" {
+		t.Errorf("Expected grass after undo, got %s", got)
+	}
+	if m.history.Undo() {
+		t.Error("Expected the fill to be a single undo step")
+	}
+}
+
+// TestBucketReplaceWholeMap checks every matching tile is replaced, even ones that aren't connected.
+func TestBucketReplaceWholeMap(t *testing.T) {
+	m := newTestBucketMap()
+	replaced := m.bucketReplace(beam.Position{X: 2, Y: 2}, "lava", true)
+	if len(replaced) != 4 {
+		t.Fatalf("Expected all 4 stone tiles to be replaced, got %d", len(replaced))
+	}
+	if got := tileTexture(m, 4, 0); got != "lava" {
+		t.Errorf("Expected the unconnected stone to be replaced, got %s", got)
+	}
+	if got := tileTexture(m, 0, 0); got != "grass" {
+		t.Errorf("Expected grass to be untouched, got %s", got)
+	}
+
+	// Without wholeMap, only the column is replaced
+	m.history.Undo()
+	if replaced := m.bucketReplace(beam.Position{X: 2, Y: 2}, "lava", false); len(replaced) != 3 {
+		t.Errorf("Expected the 3 connected stone tiles to be replaced, got %d", len(replaced))
+	}
+}
+
+// TestFillSelection checks every selected tile is painted as one undo step, and an empty selection is left alone.
+func TestFillSelection(t *testing.T) {
+	m := newTestBucketMap()
+	m.uiState.activeTexture = &resources.TextureInfo{Name: "sand"}
+
+	m.fillSelection()
+	if m.uiState.toast == nil || m.uiState.toast.toastType != ToastError {
+		t.Error("Expected an error toast with nothing selected")
+	}
+	if m.history.Undo() {
+		t.Error("Expected no change with nothing selected")
+	}
+
+	m.tileGrid.selectedTiles = beam.Positions{{X: 0, Y: 0}, {X: 2, Y: 1}, {X: 4, Y: 2}}
+	m.tileGrid.hasSelection = true
+	m.fillSelection()
+	for _, pos := range m.tileGrid.selectedTiles {
+		textures := m.tileGrid.Tiles[pos.Y][pos.X].Textures
+		if got := textures[len(textures)-1].Frames[0].Name; got != "sand" {
+			t.Errorf("Expected sand on top at %v, got %s", pos, got)
+		}
+	}
+	if got := tileTexture(m, 1, 0); got != "grass" || len(m.tileGrid.Tiles[0][1].Textures) != 1 {
+		t.Errorf("Expected unselected tiles to be untouched, got %s", got)
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the fill to be undoable")
+	}
+	if m.history.Undo() {
+		t.Error("Expected the fill to be a single undo step")
+	}
+}
+
+// TestFloodFillPaintedTiles checks tiles painted with the same texture, separately, flood fill together,
+// and that pasted tiles still match the tiles they were copied from.
+func TestFloodFillPaintedTiles(t *testing.T) {
+	m := newTestMapMaker(4, 1)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+	m.paintTiles(beam.Positions{{X: 1, Y: 0}}, "grass")
+	m.paintTiles(beam.Positions{{X: 2, Y: 0}}, "stone")
+	if got := m.floodFillSelection(0, 0); len(got) != 2 {
+		t.Errorf("Expected both grass tiles to flood fill together, got %v", got)
+	}
+
+	m.clipboard = [][]beam.Tile{{copyTile(m.tileGrid.Tiles[0][0])}}
+	m.pasteClipboard(beam.Position{X: 3, Y: 0})
+	if !tilesMatch(m.tileGrid.Tiles[0][3], m.tileGrid.Tiles[0][0]) {
+		t.Error("Expected the pasted tile to match the tile it was copied from")
+	}
+	m.pasteClipboard(beam.Position{X: 2, Y: 0})
+	if got := m.floodFillSelection(0, 0); len(got) != 4 {
+		t.Errorf("Expected every grass tile to flood fill together after pasting, got %v", got)
+	}
+}
+
+// TestStamp copies a selection into a stamp, places it over the edge of the grid, and checks it's clipped and undoable.
+func TestStamp(t *testing.T) {
+	m := newTestBucketMap()
+	m.tileGrid.selectedTiles = beam.Positions{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}}
+	m.tileGrid.hasSelection = true
+	m.copySelection()
+	if len(m.clipboard) != 2 || len(m.clipboard[0]) != 2 {
+		t.Fatalf("Expected a 2x2 stamp, got %dx%d", len(m.clipboard[0]), len(m.clipboard))
+	}
+	if len(m.clipboard[1][0].Textures) != 0 {
+		t.Error("Expected the gap in the selection to be an empty stamp tile")
+	}
+
+	// Only the left column of the stamp fits on the grid
+	m.stamp(beam.Position{X: 4, Y: 1})
+	if got := tileTexture(m, 4, 1); got != "grass" || len(m.tileGrid.Tiles[1][4].Textures) != 1 {
+		t.Errorf("Expected grass stamped at 4,1, got %s", got)
+	}
+	if got := tileTexture(m, 4, 2); got != "grass" {
+		t.Errorf("Expected the empty stamp tile to leave 4,2 alone, got %s", got)
+	}
+
+	m.stamp(beam.Position{X: 3, Y: 1})
+	if got := tileTexture(m, 4, 1); got != "stone" {
+		t.Errorf("Expected stone stamped at 4,1, got %s", got)
+	}
+	if got := tileTexture(m, 4, 2); got != "stone" {
+		t.Errorf("Expected stone stamped at 4,2, got %s", got)
+	}
+	if m.tileGrid.Tiles[2][4].Pos != (beam.Position{X: 4, Y: 2}) {
+		t.Errorf("Expected stamped tiles to keep their own position, got %v", m.tileGrid.Tiles[2][4].Pos)
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected the stamp to be undoable")
+	}
+	if got := tileTexture(m, 4, 2); got != "grass" {
+		t.Errorf("Expected grass at 4,2 after undo, got %s", got)
+	}
+}
+
+// TestPaintSpans checks a spanning texture is placed on its anchor only, that overlapping anchors are skipped,
+// and the whole paint is one undo step.
+func TestPaintSpans(t *testing.T) {
+	m := newTestBucketMap()
+	m.uiState.selectedTool = "paintbrush"
+	m.uiState.brushSize = 3
+	m.uiState.textureSpanX, m.uiState.textureSpanY = 2, 2
+	if footprint := m.brushFootprint(beam.Position{X: 1, Y: 1}); len(footprint) != 1 {
+		t.Errorf("Expected a spanning brush to anchor on one tile, got %d", len(footprint))
+	}
+
+	// The second anchor overlaps the first, and the third doesn't fit
+	m.paintSpans(beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 4, Y: 2}, {X: 2, Y: 1}}, "tree")
+	for _, pos := range []beam.Position{{X: 0, Y: 0}, {X: 2, Y: 1}} {
+		textures := m.tileGrid.Tiles[pos.Y][pos.X].Textures
+		if top := textures[len(textures)-1]; top.Frames[0].Name != "tree" || !top.IsSpanning() {
+			t.Errorf("Expected a spanning tree anchored at %v", pos)
+		}
+	}
+	for _, pos := range []beam.Position{{X: 1, Y: 1}, {X: 4, Y: 2}, {X: 1, Y: 0}} {
+		if got := len(m.tileGrid.Tiles[pos.Y][pos.X].Textures); got != 1 {
+			t.Errorf("Expected no tree anchored at %v, got %d textures", pos, got)
+		}
+	}
+	if m.tileGrid.IsWalkable(1, 1) || m.tileGrid.IsWalkable(3, 2) || !m.tileGrid.IsWalkable(4, 0) {
+		t.Error("Expected the tiles under the trees, and only those, to block")
+	}
+
+	if !m.history.Undo() || m.history.Undo() {
+		t.Error("Expected the paint to be a single undo step")
+	}
+	if _, _, covered := m.tileGrid.SpanAt(beam.Position{X: 1, Y: 1}); covered {
+		t.Error("Expected undo to remove the trees")
+	}
+
+	// Nothing fits, so nothing is recorded
+	m.paintSpans(beam.Positions{{X: 4, Y: 2}}, "tree")
+	if m.uiState.toast == nil || m.uiState.toast.toastType != ToastError {
+		t.Error("Expected an error toast when no span fits")
+	}
+	if m.history.Undo() {
+		t.Error("Expected nothing to undo when no span fits")
+	}
+}
@@ -0,0 +1,189 @@
+package mapmaker
+
+import (
+	"math"
+
+	"github.com/ztkent/beam"
+)
+
+// selectionRect returns the corners of the selection, if the selected tiles fill a whole rectangle.
+func selectionRect(positions beam.Positions) (topLeft, bottomRight beam.Position, ok bool) {
+	if len(positions) == 0 {
+		return beam.Position{}, beam.Position{}, false
+	}
+	topLeft, bottomRight = positions[0], positions[0]
+	seen := make(map[beam.Position]bool, len(positions))
+	for _, pos := range positions {
+		topLeft = beam.Position{X: min(topLeft.X, pos.X), Y: min(topLeft.Y, pos.Y)}
+		bottomRight = beam.Position{X: max(bottomRight.X, pos.X), Y: max(bottomRight.Y, pos.Y)}
+		seen[pos] = true
+	}
+	area := (bottomRight.X - topLeft.X + 1) * (bottomRight.Y - topLeft.Y + 1)
+	return topLeft, bottomRight, len(seen) == area
+}
+
+// flipSelection mirrors the selected rectangle left to right, or top to bottom, as one undo step.
+// Tiles swap places across the middle of the rectangle, and every texture frame is mirrored too,
+// so the art faces the other way. Spanning textures move their anchor to the span's new top left corner.
+func (m *MapMaker) flipSelection(horizontal bool) {
+	topLeft, bottomRight, ok := selectionRect(m.tileGrid.selectedTiles)
+	if !m.tileGrid.hasSelection || !ok {
+		m.showToast("Select a rectangle of tiles to flip", ToastError)
+		return
+	}
+
+	mirror := func(pos beam.Position) beam.Position {
+		if horizontal {
+			return beam.Position{X: topLeft.X + bottomRight.X - pos.X, Y: pos.Y}
+		}
+		return beam.Position{X: pos.X, Y: topLeft.Y + bottomRight.Y - pos.Y}
+	}
+	positions := rectPositions(topLeft, bottomRight, true)
+	m.recordTileChanges(positions, func() {
+		source := make(map[beam.Position]beam.Tile, len(positions))
+		for _, pos := range positions {
+			source[pos] = copyTile(m.tileGrid.Tiles[pos.Y][pos.X])
+		}
+
+		// Place the tiles mirrored, holding back spanning textures until every tile is in place
+		type span struct {
+			anchor beam.Position
+			tex    *beam.AnimatedTexture
+		}
+		var spans []span
+		for _, pos := range positions {
+			tile := source[mirror(pos)]
+			tile.Pos = pos
+			textures := tile.Textures[:0:0]
+			for _, tex := range tile.Textures {
+				mirrorTexture(tex, horizontal)
+				if tex != nil && tex.IsSpanning() {
+					// The span's far edge is its new anchor, when the span fits inside the selection
+					w, h := tex.Span()
+					farEdge := beam.Position{X: pos.X - (w - 1), Y: pos.Y}
+					if !horizontal {
+						farEdge = beam.Position{X: pos.X, Y: pos.Y - (h - 1)}
+					}
+					anchor := pos
+					if farEdge.X >= topLeft.X && farEdge.Y >= topLeft.Y {
+						anchor = farEdge
+					}
+					spans = append(spans, span{anchor: anchor, tex: tex})
+					continue
+				}
+				textures = append(textures, tex)
+			}
+			if tile.Textures != nil {
+				tile.Textures = textures
+			}
+			m.tileGrid.Tiles[pos.Y][pos.X] = tile
+		}
+		for _, s := range spans {
+			tile := &m.tileGrid.Tiles[s.anchor.Y][s.anchor.X]
+			tile.Textures = append(tile.Textures, s.tex)
+		}
+	})
+}
+
+// rotateSelection turns the selected square a quarter turn clockwise, as one undo step.
+// Tiles move around the square and every texture frame turns 90 degrees with them, along with its offset.
+// Spanning textures swap their width and height, and move their anchor to the top left corner of where the span now lies.
+func (m *MapMaker) rotateSelection() {
+	topLeft, bottomRight, ok := selectionRect(m.tileGrid.selectedTiles)
+	size := bottomRight.X - topLeft.X + 1
+	if !m.tileGrid.hasSelection || !ok || size != bottomRight.Y-topLeft.Y+1 {
+		m.showToast("Select a square of tiles to rotate", ToastError)
+		return
+	}
+
+	// rotate is where a tile moves to, source is where a tile came from
+	rotate := func(pos beam.Position) beam.Position {
+		return beam.Position{X: topLeft.X + size - 1 - (pos.Y - topLeft.Y), Y: topLeft.Y + pos.X - topLeft.X}
+	}
+	source := func(pos beam.Position) beam.Position {
+		return beam.Position{X: topLeft.X + pos.Y - topLeft.Y, Y: topLeft.Y + size - 1 - (pos.X - topLeft.X)}
+	}
+	positions := rectPositions(topLeft, bottomRight, true)
+	m.recordTileChanges(positions, func() {
+		tiles := make(map[beam.Position]beam.Tile, len(positions))
+		for _, pos := range positions {
+			tiles[pos] = copyTile(m.tileGrid.Tiles[pos.Y][pos.X])
+		}
+
+		// Place the tiles rotated, holding back spanning textures until every tile is in place
+		type span struct {
+			anchor beam.Position
+			tex    *beam.AnimatedTexture
+		}
+		var spans []span
+		for _, pos := range positions {
+			from := source(pos)
+			tile := tiles[from]
+			tile.Pos = pos
+			textures := tile.Textures[:0:0]
+			for _, tex := range tile.Textures {
+				rotateTexture(tex)
+				if tex != nil && tex.IsSpanning() {
+					// The span's bottom left corner turns into its top left, when the span fits inside the selection
+					w, h := tex.Span()
+					anchor := pos
+					if from.X+w-1 <= bottomRight.X && from.Y+h-1 <= bottomRight.Y {
+						anchor = rotate(beam.Position{X: from.X, Y: from.Y + h - 1})
+					}
+					for i := range tex.Frames {
+						tex.Frames[i].SpanX, tex.Frames[i].SpanY = tex.Frames[i].SpanY, tex.Frames[i].SpanX
+					}
+					spans = append(spans, span{anchor: anchor, tex: tex})
+					continue
+				}
+				textures = append(textures, tex)
+			}
+			if tile.Textures != nil {
+				tile.Textures = textures
+			}
+			m.tileGrid.Tiles[pos.Y][pos.X] = tile
+		}
+		for _, s := range spans {
+			tile := &m.tileGrid.Tiles[s.anchor.Y][s.anchor.X]
+			tile.Textures = append(tile.Textures, s.tex)
+		}
+	})
+}
+
+// rotateTexture turns every frame of a texture a quarter turn clockwise, offset included.
+func rotateTexture(tex *beam.AnimatedTexture) {
+	if tex == nil {
+		return
+	}
+	for i := range tex.Frames {
+		frame := &tex.Frames[i]
+		frame.Rotation = math.Mod(frame.Rotation+90, 360)
+		frame.OffsetX, frame.OffsetY = -frame.OffsetY, frame.OffsetX
+	}
+}
+
+// quarterTurned reports if a frame's rotation turns it on its side, so its width and height trade places.
+func quarterTurned(rotation float64) bool {
+	return math.Mod(math.Abs(rotation), 180) == 90
+}
+
+// mirrorTexture flips every frame of a texture, along with its rotation and offset, so the frame is drawn mirrored.
+func mirrorTexture(tex *beam.AnimatedTexture, horizontal bool) {
+	if tex == nil {
+		return
+	}
+	for i := range tex.Frames {
+		frame := &tex.Frames[i]
+		if horizontal {
+			frame.MirrorX = !frame.MirrorX
+			frame.OffsetX = -frame.OffsetX
+		} else {
+			frame.MirrorY = !frame.MirrorY
+			frame.OffsetY = -frame.OffsetY
+		}
+		// A mirrored frame turns the other way
+		if frame.Rotation != 0 {
+			frame.Rotation = math.Mod(360-frame.Rotation, 360)
+		}
+	}
+}
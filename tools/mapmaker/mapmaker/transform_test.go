@@ -0,0 +1,242 @@
+package mapmaker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestSelectionRect checks only selections that fill their bounding box count as rectangles.
+func TestSelectionRect(t *testing.T) {
+	rect := rectPositions(beam.Position{X: 3, Y: 2}, beam.Position{X: 1, Y: 1}, true)
+	if topLeft, bottomRight, ok := selectionRect(rect); !ok || topLeft != (beam.Position{X: 1, Y: 1}) || bottomRight != (beam.Position{X: 3, Y: 2}) {
+		t.Errorf("Expected a rectangle from (1, 1) to (3, 2), got %v to %v (%v)", topLeft, bottomRight, ok)
+	}
+	if _, _, ok := selectionRect(beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}); ok {
+		t.Errorf("Expected an L shape not to be a rectangle")
+	}
+	if _, _, ok := selectionRect(beam.Positions{{X: 0, Y: 0}, {X: 0, Y: 0}}); !ok {
+		t.Errorf("Expected a repeated single tile to be a rectangle")
+	}
+	if _, _, ok := selectionRect(nil); ok {
+		t.Errorf("Expected an empty selection not to be a rectangle")
+	}
+}
+
+// TestFlipSelectionHorizontal flips a 3x2 selection, and checks the tiles swap columns with their art mirrored.
+func TestFlipSelectionHorizontal(t *testing.T) {
+	m := newTestMapMaker(5, 3)
+	m.paintTiles(beam.Positions{{X: 1, Y: 0}}, "left")
+	m.paintTiles(beam.Positions{{X: 2, Y: 0}}, "middle")
+	m.paintTiles(beam.Positions{{X: 3, Y: 1}}, "turned")
+	m.paintTiles(beam.Positions{{X: 4, Y: 0}}, "outside")
+	m.tileGrid.Tiles[0][1].Properties = map[string]string{"water": "true"}
+	m.tileGrid.Tiles[0][1].Textures[0].Frames[0].OffsetX = 0.25
+	m.tileGrid.Tiles[1][3].Textures[0].Frames[0].Rotation = 90
+	before := snapshotTiles(m.tileGrid.Tiles)
+
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 1, Y: 0}, beam.Position{X: 3, Y: 1}, true)
+	m.tileGrid.hasSelection = true
+	m.flipSelection(true)
+
+	left := m.tileGrid.Tiles[0][3]
+	if len(left.Textures) != 1 || left.Textures[0].Frames[0].Name != "left" || left.Pos != (beam.Position{X: 3, Y: 0}) {
+		t.Fatalf("Expected the left tile at (3, 0), got %+v", left)
+	}
+	if frame := left.Textures[0].Frames[0]; !frame.MirrorX || frame.MirrorY || frame.OffsetX != -0.25 {
+		t.Errorf("Expected the left tile mirrored across X with its offset flipped, got %+v", frame)
+	}
+	if left.Properties["water"] != "true" {
+		t.Errorf("Expected the properties to move with the tile, got %v", left.Properties)
+	}
+	if frame := m.tileGrid.Tiles[0][2].Textures[0].Frames[0]; frame.Name != "middle" || !frame.MirrorX {
+		t.Errorf("Expected the middle tile to stay put but be mirrored, got %+v", frame)
+	}
+	if frame := m.tileGrid.Tiles[1][1].Textures[0].Frames[0]; frame.Name != "turned" || frame.Rotation != 270 {
+		t.Errorf("Expected the turned tile at (1, 1) rotated the other way, got %+v", frame)
+	}
+	if len(m.tileGrid.Tiles[0][1].Textures) != 0 || len(m.tileGrid.Tiles[1][3].Textures) != 0 {
+		t.Errorf("Expected the tiles flipped from empty tiles to be empty")
+	}
+	if frame := m.tileGrid.Tiles[0][4].Textures[0].Frames[0]; frame.MirrorX {
+		t.Errorf("Expected the tile outside the selection to be left alone")
+	}
+
+	// The whole flip is one undo step
+	if !m.history.Undo() {
+		t.Fatalf("Expected the flip to be undoable")
+	}
+	if !reflect.DeepEqual(m.tileGrid.Tiles, before) {
+		t.Errorf("Expected undo to restore the tiles from before the flip")
+	}
+}
+
+// TestFlipSelectionVertical flips a column, and checks the tiles swap rows and mirror across Y.
+func TestFlipSelectionVertical(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.paintTiles(beam.Positions{{X: 1, Y: 0}}, "top")
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 1, Y: 0}, beam.Position{X: 1, Y: 2}, true)
+	m.tileGrid.hasSelection = true
+	m.flipSelection(false)
+
+	bottom := m.tileGrid.Tiles[2][1]
+	if len(bottom.Textures) != 1 || bottom.Textures[0].Frames[0].Name != "top" {
+		t.Fatalf("Expected the top tile at (1, 2), got %+v", bottom)
+	}
+	if frame := bottom.Textures[0].Frames[0]; !frame.MirrorY || frame.MirrorX {
+		t.Errorf("Expected the tile mirrored across Y only, got %+v", frame)
+	}
+}
+
+// TestFlipSelectionSpan checks a spanning texture is re-anchored on its new left edge.
+func TestFlipSelectionSpan(t *testing.T) {
+	m := newTestMapMaker(6, 2)
+	tree := beam.NewSimpleTileTexture("tree")
+	tree.Frames[0].SpanX = 2
+	if err := m.tileGrid.PlaceSpanTexture(beam.Position{X: 1, Y: 0}, tree); err != nil {
+		t.Fatal(err)
+	}
+
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 1, Y: 0}, beam.Position{X: 4, Y: 1}, true)
+	m.tileGrid.hasSelection = true
+	m.flipSelection(true)
+
+	anchor, tex, ok := m.tileGrid.SpanAt(beam.Position{X: 4, Y: 0})
+	if !ok || anchor != (beam.Position{X: 3, Y: 0}) || tex.Frames[0].Name != "tree" {
+		t.Errorf("Expected the tree anchored at (3, 0) covering (4, 0), got %v (%v)", anchor, ok)
+	}
+	if len(m.tileGrid.Tiles[0][4].Textures) != 0 {
+		t.Errorf("Expected nothing left on the tile the span was mirrored to")
+	}
+}
+
+// TestFlipSelectionNotRect checks a selection that isn't a rectangle is left alone.
+func TestFlipSelectionNotRect(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+	m.tileGrid.selectedTiles = beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}
+	m.tileGrid.hasSelection = true
+	m.flipSelection(true)
+
+	if frame := m.tileGrid.Tiles[0][0].Textures[0].Frames[0]; frame.MirrorX {
+		t.Errorf("Expected the tiles to be left alone")
+	}
+	if m.history.Undo() && m.history.Undo() {
+		t.Errorf("Expected no undo step for the refused flip")
+	}
+}
+
+// TestRotateSelection turns a 3x3 selection, and checks where every tile lands and that its art is turned with it.
+func TestRotateSelection(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	names := [3][3]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h", "i"}}
+	for y, row := range names {
+		for x, name := range row {
+			m.paintTiles(beam.Positions{{X: x + 1, Y: y + 1}}, name)
+		}
+	}
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "outside")
+	m.tileGrid.Tiles[1][1].Textures[0].Frames[0].Rotation = 270
+	m.tileGrid.Tiles[1][1].Textures[0].Frames[0].OffsetX = 0.25
+	before := snapshotTiles(m.tileGrid.Tiles)
+
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 1, Y: 1}, beam.Position{X: 3, Y: 3}, true)
+	m.tileGrid.hasSelection = true
+	m.rotateSelection()
+
+	// Turned clockwise, the left column reads bottom to top along the top row
+	expected := [3][3]string{{"g", "d", "a"}, {"h", "e", "b"}, {"i", "f", "c"}}
+	for y, row := range expected {
+		for x, name := range row {
+			tile := m.tileGrid.Tiles[y+1][x+1]
+			if len(tile.Textures) != 1 || tile.Textures[0].Frames[0].Name != name || tile.Pos != (beam.Position{X: x + 1, Y: y + 1}) {
+				t.Fatalf("Expected %s at (%d, %d), got %+v", name, x+1, y+1, tile)
+			}
+		}
+	}
+	if frame := m.tileGrid.Tiles[1][2].Textures[0].Frames[0]; frame.Rotation != 90 {
+		t.Errorf("Expected the art turned 90 degrees, got %v", frame.Rotation)
+	}
+	if frame := m.tileGrid.Tiles[1][3].Textures[0].Frames[0]; frame.Rotation != 0 || frame.OffsetX != 0 || frame.OffsetY != 0.25 {
+		t.Errorf("Expected the turned tile back at 0 degrees with its offset turned down, got %+v", frame)
+	}
+	if frame := m.tileGrid.Tiles[0][0].Textures[0].Frames[0]; frame.Rotation != 0 {
+		t.Errorf("Expected the tile outside the selection to be left alone")
+	}
+
+	// The whole rotation is one undo step
+	if !m.history.Undo() {
+		t.Fatalf("Expected the rotation to be undoable")
+	}
+	if !reflect.DeepEqual(m.tileGrid.Tiles, before) {
+		t.Errorf("Expected undo to restore the tiles from before the rotation")
+	}
+}
+
+// TestRotateSelectionSpan checks a spanning texture is re-anchored on the corner it's turned onto.
+func TestRotateSelectionSpan(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	rock := beam.NewSimpleTileTexture("rock")
+	rock.Frames[0].SpanX, rock.Frames[0].SpanY = 2, 2
+	if err := m.tileGrid.PlaceSpanTexture(beam.Position{X: 0, Y: 0}, rock); err != nil {
+		t.Fatal(err)
+	}
+
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 0, Y: 0}, beam.Position{X: 2, Y: 2}, true)
+	m.tileGrid.hasSelection = true
+	m.rotateSelection()
+
+	anchor, tex, ok := m.tileGrid.SpanAt(beam.Position{X: 2, Y: 1})
+	if !ok || anchor != (beam.Position{X: 1, Y: 0}) || tex.Frames[0].Rotation != 90 {
+		t.Errorf("Expected the rock anchored at (1, 0) turned 90 degrees, got %v (%v)", anchor, ok)
+	}
+}
+
+// TestRotateSelectionWideSpan checks a 2x1 span turns into a 1x2 span, anchored on the top of where it now lies,
+// so collision follows the way it's drawn.
+func TestRotateSelectionWideSpan(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	log := beam.NewSimpleTileTexture("log")
+	log.Frames[0].SpanX, log.Frames[0].SpanY = 2, 1
+	if err := m.tileGrid.PlaceSpanTexture(beam.Position{X: 0, Y: 0}, log); err != nil {
+		t.Fatal(err)
+	}
+
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 0, Y: 0}, beam.Position{X: 2, Y: 2}, true)
+	m.tileGrid.hasSelection = true
+	m.rotateSelection()
+
+	covered := beam.Positions{{X: 2, Y: 0}, {X: 2, Y: 1}}
+	for _, pos := range covered {
+		anchor, tex, ok := m.tileGrid.SpanAt(pos)
+		if !ok || anchor != (beam.Position{X: 2, Y: 0}) {
+			t.Fatalf("Expected %v to be covered by the log anchored at (2, 0), got %v (%v)", pos, anchor, ok)
+		}
+		if w, h := tex.Span(); w != 1 || h != 2 {
+			t.Errorf("Expected the log to span 1x2, got %dx%d", w, h)
+		}
+	}
+	uncovered := beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 2}}
+	for _, pos := range uncovered {
+		if _, _, ok := m.tileGrid.SpanAt(pos); ok {
+			t.Errorf("Expected %v not to be covered after the rotation", pos)
+		}
+	}
+}
+
+// TestRotateSelectionNotSquare checks a selection that isn't square is left alone.
+func TestRotateSelectionNotSquare(t *testing.T) {
+	m := newTestMapMaker(3, 3)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+	m.tileGrid.selectedTiles = rectPositions(beam.Position{X: 0, Y: 0}, beam.Position{X: 2, Y: 1}, true)
+	m.tileGrid.hasSelection = true
+	m.rotateSelection()
+
+	if tile := m.tileGrid.Tiles[0][0]; len(tile.Textures) != 1 || tile.Textures[0].Frames[0].Rotation != 0 {
+		t.Errorf("Expected the tiles to be left alone, got %+v", tile)
+	}
+	if m.history.Undo() && m.history.Undo() {
+		t.Errorf("Expected no undo step for the refused rotation")
+	}
+}
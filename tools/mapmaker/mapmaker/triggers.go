@@ -0,0 +1,211 @@
+package mapmaker
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam"
+)
+
+// triggerLocationMode is the location tool mode that places triggers, after the four fixed locations.
+const triggerLocationMode = 4
+
+// locationModeNames are the location tool's modes, in the order a long right click steps through them.
+var locationModeNames = []string{"Player Start", "Dungeon Entrance", "Respawn", "Exit", "Trigger"}
+
+// TriggerEditorState names a new trigger over the selected tiles, and lists the triggers already on them.
+type TriggerEditorState struct {
+	positions   beam.Positions
+	name        string
+	payload     string // key=value pairs, separated by commas
+	activeField string // "name", "payload" or ""
+}
+
+// openTriggerEditor opens the trigger editor for the selected tiles.
+func (m *MapMaker) openTriggerEditor(positions beam.Positions) {
+	m.uiState.triggerEditor = &TriggerEditorState{
+		positions:   append(beam.Positions(nil), positions...),
+		activeField: "name",
+	}
+	m.uiState.activeInput = "trigger_editor"
+}
+
+func (m *MapMaker) closeTriggerEditor() {
+	m.uiState.triggerEditor = nil
+	m.uiState.activeInput = ""
+}
+
+// parseTriggerPayload reads "key=value" pairs separated by commas, e.g. "cutscene=boss, damage=5".
+// Returns nil for empty text.
+func parseTriggerPayload(text string) (map[string]string, error) {
+	var payload map[string]string
+	for _, pair := range strings.Split(text, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("payload %q should be key=value", strings.TrimSpace(pair))
+		}
+		if payload == nil {
+			payload = make(map[string]string)
+		}
+		payload[key] = strings.TrimSpace(value)
+	}
+	return payload, nil
+}
+
+// formatTriggerPayload writes a payload the way parseTriggerPayload reads it, sorted by key.
+func formatTriggerPayload(payload map[string]string) string {
+	pairs := make([]string, 0, len(payload))
+	for _, key := range slices.Sorted(maps.Keys(payload)) {
+		pairs = append(pairs, key+"="+payload[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// addTrigger adds a trigger over the positions, as one undo step.
+func (m *MapMaker) addTrigger(name, payloadText string, positions beam.Positions) error {
+	payload, err := parseTriggerPayload(payloadText)
+	if err != nil {
+		return err
+	}
+	trigger := beam.Trigger{Name: strings.TrimSpace(name), Positions: append(beam.Positions(nil), positions...), Payload: payload}
+	m.recordLocationChange(func() {
+		err = m.tileGrid.AddTrigger(trigger)
+	})
+	return err
+}
+
+// removeTrigger removes the named trigger, as one undo step.
+func (m *MapMaker) removeTrigger(name string) {
+	m.recordLocationChange(func() {
+		m.tileGrid.RemoveTrigger(name)
+	})
+}
+
+// triggersOn returns the triggers covering any of the positions, in the order they were added.
+func (m *MapMaker) triggersOn(positions beam.Positions) []beam.Trigger {
+	var triggers []beam.Trigger
+	for _, trigger := range m.tileGrid.Triggers {
+		if slices.ContainsFunc(positions, trigger.Contains) {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// copyTriggers deep copies triggers, so undo snapshots don't share positions or payloads with the map.
+func copyTriggers(triggers []beam.Trigger) []beam.Trigger {
+	if triggers == nil {
+		return nil
+	}
+	copied := make([]beam.Trigger, len(triggers))
+	for i, trigger := range triggers {
+		trigger.Positions = append(beam.Positions(nil), trigger.Positions...)
+		trigger.Payload = maps.Clone(trigger.Payload)
+		copied[i] = trigger
+	}
+	return copied
+}
+
+// renderTriggerEditor draws the triggers on the selected tiles, with inputs to add a new one over them.
+// Tab switches between the name and payload, and Enter adds the trigger.
+func (m *MapMaker) renderTriggerEditor() {
+	editor := m.uiState.triggerEditor
+	existing := m.triggersOn(editor.positions)
+
+	const rowHeight = 28
+	dialogWidth := 460
+	dialogHeight := 170 + max(1, len(existing))*rowHeight
+	dialogX := (rl.GetScreenWidth() - dialogWidth) / 2
+	dialogY := (rl.GetScreenHeight() - dialogHeight) / 2
+	mousePos := rl.GetMousePosition()
+	clicked := rl.IsMouseButtonPressed(rl.MouseLeftButton)
+
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(rl.Black, 0.7))
+	dialogRect := rl.Rectangle{X: float32(dialogX), Y: float32(dialogY), Width: float32(dialogWidth), Height: float32(dialogHeight)}
+	rl.DrawRectangleRec(dialogRect, rl.RayWhite)
+	rl.DrawRectangleLinesEx(dialogRect, 1, rl.Gray)
+	rl.DrawText(fmt.Sprintf("Triggers (%d tiles)", len(editor.positions)), int32(dialogX+20), int32(dialogY+20), 24, rl.Black)
+
+	closeBtn := rl.Rectangle{X: float32(dialogX + dialogWidth - 40), Y: float32(dialogY + 10), Width: 30, Height: 30}
+	rl.DrawRectangleRec(closeBtn, rl.LightGray)
+	rl.DrawText("X", int32(closeBtn.X+10), int32(closeBtn.Y+5), 20, rl.Black)
+	if clicked && rl.CheckCollisionPointRec(mousePos, closeBtn) {
+		m.closeTriggerEditor()
+		return
+	}
+
+	// Triggers already on the selected tiles, which may cover other tiles too
+	y := dialogY + 60
+	if len(existing) == 0 {
+		rl.DrawText("No triggers on these tiles", int32(dialogX+20), int32(y+6), 16, rl.DarkGray)
+	}
+	for _, trigger := range existing {
+		deleteBtn := rl.Rectangle{X: float32(dialogX + dialogWidth - 80), Y: float32(y), Width: 60, Height: rowHeight - 2}
+		rl.DrawText(truncateName(trigger.Name, 20), int32(dialogX+26), int32(y+6), 16, rl.Black)
+		rl.DrawText(fitText(formatTriggerPayload(trigger.Payload), 180, 16), int32(dialogX+200), int32(y+6), 16, rl.DarkGray)
+		rl.DrawRectangleRec(deleteBtn, rl.Red)
+		rl.DrawText("Delete", int32(deleteBtn.X+8), int32(deleteBtn.Y+6), 14, rl.White)
+		if clicked && rl.CheckCollisionPointRec(mousePos, deleteBtn) {
+			m.removeTrigger(trigger.Name)
+		}
+		y += rowHeight
+	}
+
+	// Name and payload inputs, with a button to add the trigger over every selected tile
+	inputY := float32(dialogY + dialogHeight - 90)
+	nameRect := rl.Rectangle{X: float32(dialogX + 20), Y: inputY, Width: 160, Height: 30}
+	payloadRect := rl.Rectangle{X: float32(dialogX + 190), Y: inputY, Width: 160, Height: 30}
+	addBtn := rl.Rectangle{X: float32(dialogX + 360), Y: inputY, Width: 80, Height: 30}
+	rl.DrawText("Name", int32(nameRect.X), int32(inputY-18), 14, rl.DarkGray)
+	rl.DrawText("Payload (key=value, ...)", int32(payloadRect.X), int32(inputY-18), 14, rl.DarkGray)
+	for _, field := range []struct {
+		name string
+		rect rl.Rectangle
+		text string
+	}{{"name", nameRect, editor.name}, {"payload", payloadRect, editor.payload}} {
+		if clicked && rl.CheckCollisionPointRec(mousePos, field.rect) {
+			editor.activeField = field.name
+		}
+		rl.DrawRectangleRec(field.rect, rl.White)
+		borderColor := rl.Gray
+		if editor.activeField == field.name {
+			borderColor = rl.Blue
+		}
+		rl.DrawRectangleLinesEx(field.rect, 2, borderColor)
+		rl.DrawText(fitText(field.text, int32(field.rect.Width-10), 16), int32(field.rect.X+5), int32(field.rect.Y+7), 16, rl.Black)
+	}
+	rl.DrawRectangleRec(addBtn, rl.Gray)
+	rl.DrawText("Add", int32(addBtn.X+26), int32(addBtn.Y+7), 16, rl.White)
+	rl.DrawText("Tab switches fields, Enter adds the trigger", int32(dialogX+20), int32(dialogY+dialogHeight-40), 14, rl.DarkGray)
+
+	// Type into the active field
+	field := &editor.name
+	if editor.activeField == "payload" {
+		field = &editor.payload
+	}
+	m.textInput(field, true, false)
+	if rl.IsKeyPressed(rl.KeyTab) {
+		if editor.activeField == "name" {
+			editor.activeField = "payload"
+		} else {
+			editor.activeField = "name"
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeyEnter) || (clicked && rl.CheckCollisionPointRec(mousePos, addBtn)) {
+		if err := m.addTrigger(editor.name, editor.payload, editor.positions); err != nil {
+			m.showToast("Can't add trigger: "+err.Error(), ToastError)
+			return
+		}
+		m.showToast("Added trigger "+strings.TrimSpace(editor.name), ToastSuccess)
+		editor.name, editor.payload = "", ""
+		editor.activeField = "name"
+	}
+}
@@ -0,0 +1,66 @@
+package mapmaker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+// TestParseTriggerPayload checks payload text is read into key/value pairs, and badly formed pairs are rejected.
+func TestParseTriggerPayload(t *testing.T) {
+	payload, err := parseTriggerPayload(" cutscene=boss, damage = 5,, note=")
+	want := map[string]string{"cutscene": "boss", "damage": "5", "note": ""}
+	if err != nil || !reflect.DeepEqual(payload, want) {
+		t.Errorf("Expected %v, got %v (%v)", want, payload, err)
+	}
+	if got := formatTriggerPayload(payload); got != "cutscene=boss, damage=5, note=" {
+		t.Errorf("Expected the payload formatted back sorted by key, got %q", got)
+	}
+	if payload, err := parseTriggerPayload("  "); err != nil || payload != nil {
+		t.Errorf("Expected no payload for empty text, got %v (%v)", payload, err)
+	}
+	for _, text := range []string{"boss", "=5", "a=1, b"} {
+		if _, err := parseTriggerPayload(text); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+}
+
+// TestAddTriggerUndo adds overlapping triggers from the mapmaker, and checks each is its own undo step.
+func TestAddTriggerUndo(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	if err := m.addTrigger("trap", "damage=5", beam.Positions{{X: 1, Y: 1}, {X: 2, Y: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.addTrigger("zone", "", beam.Positions{{X: 2, Y: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.addTrigger("trap", "", beam.Positions{{X: 3, Y: 3}}); err == nil {
+		t.Error("Expected a second trap to be rejected")
+	}
+	if err := m.addTrigger("bad", "oops", beam.Positions{{X: 3, Y: 3}}); err == nil {
+		t.Error("Expected a bad payload to be rejected")
+	}
+
+	if got := m.triggersOn(beam.Positions{{X: 2, Y: 1}}); len(got) != 2 {
+		t.Errorf("Expected both triggers on the shared tile, got %d", len(got))
+	}
+	if got := m.triggersOn(beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 1}}); len(got) != 1 || got[0].Payload["damage"] != "5" {
+		t.Errorf("Expected the trap with its payload, got %v", got)
+	}
+
+	m.removeTrigger("trap")
+	if !m.history.Undo() || len(m.tileGrid.Triggers) != 2 {
+		t.Fatalf("Expected undo to bring the trap back, got %d triggers", len(m.tileGrid.Triggers))
+	}
+	m.history.Undo()
+	if len(m.tileGrid.Triggers) != 1 || m.tileGrid.Triggers[0].Name != "trap" {
+		t.Errorf("Expected undo to remove the zone, got %v", m.tileGrid.Triggers)
+	}
+	// Only the two successful adds and the removal were recorded
+	m.history.Undo()
+	if len(m.tileGrid.Triggers) != 0 || m.history.Undo() {
+		t.Errorf("Expected three undo steps, got %v left", m.tileGrid.Triggers)
+	}
+}
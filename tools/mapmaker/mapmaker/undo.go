@@ -0,0 +1,304 @@
+package mapmaker
+
+import (
+	"maps"
+	"reflect"
+
+	"github.com/ztkent/beam"
+)
+
+// MaxUndoDepth is the number of actions kept in the undo history.
+const MaxUndoDepth = 100
+
+// UndoableAction is an edit to the map that can be reverted and reapplied.
+type UndoableAction interface {
+	Undo()
+	Redo()
+}
+
+// TileChange is the state of a single tile before and after an edit.
+type TileChange struct {
+	Pos    beam.Position
+	Before beam.Tile
+	After  beam.Tile
+}
+
+// TileChangeAction restores a set of tiles to their state before or after an edit.
+type TileChangeAction struct {
+	grid    *TileGrid
+	Changes []TileChange
+}
+
+func (a *TileChangeAction) Undo() {
+	for _, change := range a.Changes {
+		a.setTile(change.Pos, change.Before)
+	}
+}
+
+func (a *TileChangeAction) Redo() {
+	for _, change := range a.Changes {
+		a.setTile(change.Pos, change.After)
+	}
+}
+
+// setTile restores a tile, skipping any that were cut off by a grid resize.
+func (a *TileChangeAction) setTile(pos beam.Position, tile beam.Tile) {
+	if pos.Y < 0 || pos.Y >= len(a.grid.Tiles) || pos.X < 0 || pos.X >= len(a.grid.Tiles[pos.Y]) {
+		return
+	}
+	a.grid.Tiles[pos.Y][pos.X] = copyTile(tile)
+	a.grid.minimapDirty = true
+}
+
+// MapLocations are the special positions and triggers placed with the location tool.
+type MapLocations struct {
+	Start        beam.Position
+	Respawn      beam.Position
+	Exit         beam.Positions
+	DungeonEntry beam.Positions
+	Triggers     []beam.Trigger
+}
+
+// LocationChangeAction restores the map's locations to their state before or after an edit.
+type LocationChangeAction struct {
+	grid   *TileGrid
+	Before MapLocations
+	After  MapLocations
+}
+
+func (a *LocationChangeAction) Undo() {
+	a.grid.setLocations(a.Before)
+}
+
+func (a *LocationChangeAction) Redo() {
+	a.grid.setLocations(a.After)
+}
+
+// locations copies the grid's current locations.
+func (g *TileGrid) locations() MapLocations {
+	return MapLocations{
+		Start:        g.Start,
+		Respawn:      g.Respawn,
+		Exit:         append(beam.Positions(nil), g.Exit...),
+		DungeonEntry: append(beam.Positions(nil), g.DungeonEntry...),
+		Triggers:     copyTriggers(g.Triggers),
+	}
+}
+
+func (g *TileGrid) setLocations(locations MapLocations) {
+	g.Start = locations.Start
+	g.Respawn = locations.Respawn
+	g.Exit = append(beam.Positions{}, locations.Exit...)
+	g.DungeonEntry = append(beam.Positions{}, locations.DungeonEntry...)
+	g.Triggers = copyTriggers(locations.Triggers)
+}
+
+// MapContents are the NPCs, items and locations on the map, copied so later edits don't change them.
+type MapContents struct {
+	NPCs      []beam.NPC
+	Items     []beam.Item
+	Locations MapLocations
+}
+
+// ResizeAction restores the grid's size and contents to their state before or after a resize,
+// putting back the tiles, NPCs, items and locations it cut off.
+type ResizeAction struct {
+	grid                *TileGrid
+	OldWidth, OldHeight int
+	NewWidth, NewHeight int
+	Dropped             []TileChange // The tiles cut off by the resize, as they were before it
+	Before, After       MapContents
+}
+
+func (a *ResizeAction) Undo() {
+	a.grid.Resize(a.OldWidth, a.OldHeight, beam.AnchorTopLeft)
+	for _, change := range a.Dropped {
+		a.grid.Tiles[change.Pos.Y][change.Pos.X] = copyTile(change.Before)
+	}
+	a.grid.setContents(a.Before)
+}
+
+func (a *ResizeAction) Redo() {
+	a.grid.Resize(a.NewWidth, a.NewHeight, beam.AnchorTopLeft)
+	a.grid.setContents(a.After)
+}
+
+// contents copies the grid's current NPCs, items and locations.
+func (g *TileGrid) contents() MapContents {
+	contents := MapContents{Locations: g.locations()}
+	for _, npc := range g.NPCs {
+		contents.NPCs = append(contents.NPCs, *npc)
+	}
+	for _, item := range g.Items {
+		contents.Items = append(contents.Items, *item)
+	}
+	return contents
+}
+
+func (g *TileGrid) setContents(contents MapContents) {
+	g.NPCs = make(beam.NPCs, len(contents.NPCs))
+	for i, npc := range contents.NPCs {
+		g.NPCs[i] = &npc
+	}
+	g.Items = make([]*beam.Item, len(contents.Items))
+	for i, item := range contents.Items {
+		g.Items[i] = &item
+	}
+	g.setLocations(contents.Locations)
+	g.Width, g.Height = len(g.Tiles[0]), len(g.Tiles)
+	g.minimapDirty = true
+}
+
+// UndoStack holds the undo and redo history, up to a max depth.
+type UndoStack struct {
+	undo     []UndoableAction
+	redo     []UndoableAction
+	maxDepth int
+}
+
+func NewUndoStack(maxDepth int) *UndoStack {
+	return &UndoStack{
+		undo:     make([]UndoableAction, 0),
+		redo:     make([]UndoableAction, 0),
+		maxDepth: maxDepth,
+	}
+}
+
+// Push adds a new action to the history, clearing anything that could be redone.
+func (s *UndoStack) Push(action UndoableAction) {
+	s.undo = append(s.undo, action)
+	if len(s.undo) > s.maxDepth {
+		s.undo = s.undo[len(s.undo)-s.maxDepth:]
+	}
+	s.redo = s.redo[:0]
+}
+
+// Undo reverts the last action, returns false if there was nothing to undo.
+func (s *UndoStack) Undo() bool {
+	if len(s.undo) == 0 {
+		return false
+	}
+	action := s.undo[len(s.undo)-1]
+	s.undo = s.undo[:len(s.undo)-1]
+	action.Undo()
+	s.redo = append(s.redo, action)
+	return true
+}
+
+// Redo reapplies the last undone action, returns false if there was nothing to redo.
+func (s *UndoStack) Redo() bool {
+	if len(s.redo) == 0 {
+		return false
+	}
+	action := s.redo[len(s.redo)-1]
+	s.redo = s.redo[:len(s.redo)-1]
+	action.Redo()
+	s.undo = append(s.undo, action)
+	return true
+}
+
+// Clear drops all history, used when the map is replaced.
+func (s *UndoStack) Clear() {
+	s.undo = s.undo[:0]
+	s.redo = s.redo[:0]
+}
+
+// undo reverts the active map's last action, returns false if there was nothing to undo.
+func (m *MapMaker) undo() bool {
+	if !m.history.Undo() {
+		return false
+	}
+	m.afterHistoryChange()
+	return true
+}
+
+// redo reapplies the active map's last undone action, returns false if there was nothing to redo.
+func (m *MapMaker) redo() bool {
+	if !m.history.Redo() {
+		return false
+	}
+	m.afterHistoryChange()
+	return true
+}
+
+// afterHistoryChange marks the map changed by an undo or redo, and shows the grid size it left.
+func (m *MapMaker) afterHistoryChange() {
+	m.markDirty()
+	m.uiState.gridWidth, m.uiState.gridHeight = m.tileGrid.Width, m.tileGrid.Height
+}
+
+// recordTileChanges snapshots the tiles at positions, runs the edit, and pushes
+// any tiles that changed onto the undo stack as a single action.
+func (m *MapMaker) recordTileChanges(positions beam.Positions, edit func()) {
+	before := make(map[beam.Position]beam.Tile, len(positions))
+	for _, pos := range positions {
+		if pos.X < 0 || pos.X >= m.tileGrid.Width || pos.Y < 0 || pos.Y >= m.tileGrid.Height {
+			continue
+		}
+		if _, ok := before[pos]; !ok {
+			before[pos] = copyTile(m.tileGrid.Tiles[pos.Y][pos.X])
+		}
+	}
+
+	edit()
+	m.tileGrid.minimapDirty = true
+
+	action := &TileChangeAction{grid: m.tileGrid}
+	for _, pos := range positions {
+		tile, ok := before[pos]
+		if !ok {
+			continue
+		}
+		delete(before, pos)
+		after := m.tileGrid.Tiles[pos.Y][pos.X]
+		if reflect.DeepEqual(tile, after) {
+			continue
+		}
+		action.Changes = append(action.Changes, TileChange{Pos: pos, Before: tile, After: copyTile(after)})
+	}
+	if len(action.Changes) > 0 {
+		m.history.Push(action)
+		m.markDirty()
+	}
+}
+
+// recordLocationChange snapshots the map's locations, runs the edit, and pushes
+// the change onto the undo stack if any location moved.
+func (m *MapMaker) recordLocationChange(edit func()) {
+	before := m.tileGrid.locations()
+	edit()
+	after := m.tileGrid.locations()
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	m.history.Push(&LocationChangeAction{grid: m.tileGrid, Before: before, After: after})
+	m.markDirty()
+}
+
+// copyTile deep copies a tile, so later edits to its textures or properties don't change the copy.
+func copyTile(tile beam.Tile) beam.Tile {
+	tile.Properties = maps.Clone(tile.Properties)
+	if tile.Walkable != nil {
+		walkable := *tile.Walkable
+		tile.Walkable = &walkable
+	}
+	if tile.Textures == nil {
+		return tile
+	}
+	textures := make([]*beam.AnimatedTexture, len(tile.Textures))
+	for i, tex := range tile.Textures {
+		textures[i] = copyAnimatedTexture(tex)
+	}
+	tile.Textures = textures
+	return tile
+}
+
+// copyAnimatedTexture deep copies a texture and its frames, returns nil for a nil texture.
+func copyAnimatedTexture(tex *beam.AnimatedTexture) *beam.AnimatedTexture {
+	if tex == nil {
+		return nil
+	}
+	texCopy := *tex
+	texCopy.Frames = append([]beam.Texture(nil), tex.Frames...)
+	return &texCopy
+}
@@ -0,0 +1,144 @@
+package mapmaker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztkent/beam"
+)
+
+func newTestMapMaker(width, height int) *MapMaker {
+	m := &MapMaker{
+		uiState:  &UIState{gridWidth: width, gridHeight: height},
+		tileGrid: &TileGrid{},
+		history:  NewUndoStack(MaxUndoDepth),
+	}
+	m.updateGridSize()
+	m.initTileGrid()
+	return m
+}
+
+func snapshotTiles(tiles [][]beam.Tile) [][]beam.Tile {
+	snapshot := make([][]beam.Tile, len(tiles))
+	for y := range tiles {
+		snapshot[y] = make([]beam.Tile, len(tiles[y]))
+		for x := range tiles[y] {
+			snapshot[y][x] = copyTile(tiles[y][x])
+		}
+	}
+	return snapshot
+}
+
+// TestUndoPaint paints tiles, undoes the paint, and checks the grid matches the pre-paint state.
+func TestUndoPaint(t *testing.T) {
+	m := newTestMapMaker(4, 3)
+	m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "dirt")
+	beforePaint := snapshotTiles(m.tileGrid.Tiles)
+
+	positions := beam.Positions{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 2}}
+	m.paintTiles(positions, "grass")
+	afterPaint := snapshotTiles(m.tileGrid.Tiles)
+	if reflect.DeepEqual(beforePaint, afterPaint) {
+		t.Fatal("Expected painting to change the grid")
+	}
+
+	if !m.history.Undo() {
+		t.Fatal("Expected an action to undo")
+	}
+	if !reflect.DeepEqual(m.tileGrid.Tiles, beforePaint) {
+		t.Errorf("Expected tiles to match the pre-paint state after undo")
+	}
+
+	if !m.history.Redo() {
+		t.Fatal("Expected an action to redo")
+	}
+	if !reflect.DeepEqual(m.tileGrid.Tiles, afterPaint) {
+		t.Errorf("Expected tiles to match the painted state after redo")
+	}
+
+	// Editing a tile after undo shouldn't change the recorded history
+	m.history.Undo()
+	m.tileGrid.Tiles[0][0].Textures[0].Frames[0].Name = "changed"
+	m.history.Redo()
+	if got := m.tileGrid.Tiles[0][0].Textures[0].Frames[0].Name; got != "dirt" {
+		t.Errorf("Expected redo to restore the recorded texture, got %s", got)
+	}
+}
+
+// TestUndoStackDepth checks the history is capped, dropping the oldest actions.
+func TestUndoStackDepth(t *testing.T) {
+	m := newTestMapMaker(2, 2)
+	for i := 0; i < MaxUndoDepth+5; i++ {
+		m.paintTiles(beam.Positions{{X: 0, Y: 0}}, "grass")
+	}
+	undone := 0
+	for m.history.Undo() {
+		undone++
+	}
+	if undone != MaxUndoDepth {
+		t.Errorf("Expected %d actions in history, got %d", MaxUndoDepth, undone)
+	}
+	if got := len(m.tileGrid.Tiles[0][0].Textures); got != 5 {
+		t.Errorf("Expected the 5 oldest paints to remain, got %d layers", got)
+	}
+}
+
+// TestUndoLocation checks undo and redo restore the map's locations, and unchanged locations aren't recorded.
+func TestUndoLocation(t *testing.T) {
+	m := newTestMapMaker(4, 4)
+	m.tileGrid.Start = beam.Position{X: 1, Y: 1}
+	m.tileGrid.Exit = beam.Positions{{X: 3, Y: 3}}
+
+	m.recordLocationChange(func() {
+		m.tileGrid.Start = beam.Position{X: 2, Y: 0}
+		m.tileGrid.Exit = beam.Positions{{X: 0, Y: 3}, {X: 1, Y: 3}}
+	})
+	m.recordLocationChange(func() {})
+	if len(m.history.undo) != 1 {
+		t.Fatalf("Expected a single recorded action, got %d", len(m.history.undo))
+	}
+
+	m.history.Undo()
+	if m.tileGrid.Start != (beam.Position{X: 1, Y: 1}) || !reflect.DeepEqual(m.tileGrid.Exit, beam.Positions{{X: 3, Y: 3}}) {
+		t.Errorf("Expected the original locations after undo, got start %v and exits %v", m.tileGrid.Start, m.tileGrid.Exit)
+	}
+	m.history.Redo()
+	if m.tileGrid.Start != (beam.Position{X: 2, Y: 0}) || len(m.tileGrid.Exit) != 2 {
+		t.Errorf("Expected the edited locations after redo, got start %v and exits %v", m.tileGrid.Start, m.tileGrid.Exit)
+	}
+}
+
+// TestUndoResize shrinks the grid past an NPC, an item and a painted tile, and checks undo puts them all back
+// and redo cuts them off again.
+func TestUndoResize(t *testing.T) {
+	m := newTestMapMaker(12, 12)
+	m.paintTiles(beam.Positions{{X: 11, Y: 0}}, "grass")
+	m.tileGrid.NPCs = beam.NPCs{{Data: beam.NPCData{Name: "Goblin", SpawnPos: beam.Position{X: 11, Y: 5}}, Pos: beam.Position{X: 11, Y: 5}}}
+	m.tileGrid.Items = []*beam.Item{{Name: "Potion", Pos: beam.Position{X: 10, Y: 2}}}
+	before := snapshotTiles(m.tileGrid.Tiles)
+
+	m.uiState.gridWidth = 10
+	m.updateGridSize()
+	m.resizeGrid()
+	if m.tileGrid.Width != 10 || len(m.tileGrid.NPCs) != 0 || len(m.tileGrid.Items) != 0 {
+		t.Fatalf("Expected a 10 wide grid without the NPC or item, got %d wide with %d NPCs and %d items",
+			m.tileGrid.Width, len(m.tileGrid.NPCs), len(m.tileGrid.Items))
+	}
+
+	if !m.undo() {
+		t.Fatal("Expected the resize to be undoable")
+	}
+	if m.tileGrid.Width != 12 || m.uiState.gridWidth != 12 || !reflect.DeepEqual(m.tileGrid.Tiles, before) {
+		t.Errorf("Expected the 12 wide grid and its tiles back, got %d wide", m.tileGrid.Width)
+	}
+	if len(m.tileGrid.NPCs) != 1 || m.tileGrid.NPCs[0].Pos != (beam.Position{X: 11, Y: 5}) {
+		t.Errorf("Expected the NPC back at (11, 5), got %v", m.tileGrid.NPCs)
+	}
+	if len(m.tileGrid.Items) != 1 || m.tileGrid.Items[0].Pos != (beam.Position{X: 10, Y: 2}) {
+		t.Errorf("Expected the item back at (10, 2), got %v", m.tileGrid.Items)
+	}
+
+	if !m.redo() || m.tileGrid.Width != 10 || m.uiState.gridWidth != 10 || len(m.tileGrid.NPCs) != 0 {
+		t.Errorf("Expected redo to cut the grid back to 10 wide, got %d wide with %d NPCs", m.tileGrid.Width, len(m.tileGrid.NPCs))
+	}
+}
@@ -0,0 +1,40 @@
+package mapmaker
+
+import (
+	"fmt"
+
+	"github.com/ztkent/beam"
+)
+
+// validateButton runs Map.Validate, it sits after the tool icons in the menu bar.
+func (m *MapMaker) validateButton() Button {
+	return m.NewButton(875, 20, 70, 20, "Validate")
+}
+
+// validateMap checks the map for unreachable objectives, NPCs on walls, positions off the map
+// and invisible walls. The first issue is shown as a toast, and every issue is outlined on the grid
+// until Escape is pressed.
+func (m *MapMaker) validateMap() {
+	issues := m.tileGrid.Validate()
+	m.tileGrid.validationIssues = issues
+	if len(issues) == 0 {
+		m.showToast("Map is valid, every objective is reachable!", ToastSuccess)
+		return
+	}
+
+	errors := 0
+	for _, issue := range issues {
+		if issue.Severity == beam.SeverityError {
+			errors++
+		}
+	}
+	toastType := ToastError
+	if errors == 0 {
+		toastType = ToastInfo
+	}
+	message := issues[0].String()
+	if len(issues) > 1 {
+		message = fmt.Sprintf("%s (+%d more: %d errors, %d warnings)", message, len(issues)-1, errors, len(issues)-errors)
+	}
+	m.showToast(message, toastType)
+}
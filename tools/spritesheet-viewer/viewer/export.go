@@ -0,0 +1,74 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ztkent/beam/resources"
+)
+
+// exportMapping saves the current sprite names and regions as a resources.Resource,
+// so the game can load the sheet with exactly those names via AddResource.
+func (s *UIState) exportMapping() {
+	if s.Sheet == nil {
+		s.LoadError = "Load a spritesheet before exporting"
+		return
+	}
+	res := s.mappingResource()
+	path := saveFileDialog(res.Name + ".json")
+	if path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		s.LoadError = fmt.Sprintf("Failed to export mapping: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		s.LoadError = fmt.Sprintf("Failed to export mapping: %v", err)
+		return
+	}
+	s.LoadError = ""
+	s.DebugInfo = fmt.Sprintf("Exported %d sprites to %s", len(res.SheetData), filepath.Base(path))
+}
+
+// mappingResource returns the loaded sheet as a resource named after its file.
+// Trimmed cells stay out, and sprites tightened to their bounds keep their exact regions.
+func (s *UIState) mappingResource() resources.Resource {
+	res := s.Sheet.Resource()
+	name := strings.TrimSuffix(filepath.Base(s.CurrentFile), filepath.Ext(s.CurrentFile))
+	sheetData := make(map[string][]int32, len(res.SheetData))
+	for spriteName, pos := range res.SheetData {
+		renamed, _ := resources.RenamedSpriteName(res.Name, name, spriteName)
+		sheetData[renamed] = pos
+	}
+	res.Name = name
+	res.Path = s.CurrentFile
+	res.SheetData = sheetData
+	return res
+}
+
+func saveFileDialog(defaultName string) string {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf(`POSIX path of (choose file name with prompt "Export sprite mapping as:" default name %q)`, defaultName))
+	case "linux":
+		cmd = exec.Command("zenity", "--file-selection", "--save", "--confirm-overwrite", "--filename="+defaultName, "--file-filter=JSON (*.json)")
+	default:
+		return ""
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
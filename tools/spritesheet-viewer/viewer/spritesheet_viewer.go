@@ -35,8 +35,33 @@ type UIState struct {
 	ScrollOffset   float32
 	LoadError      string
 	DebugInfo      string
+
+	// The last sprite name copied to the clipboard, and when
+	CopiedName string
+	CopiedAt   float64
+
+	// The size sprites are previewed at, this doesn't change how the sheet is sliced
+	DisplaySize int32
+
+	// Trim skips grid cells that are fully transparent, TrimBounds also shrinks each sprite to its opaque pixels
+	Trim       bool
+	TrimBounds bool
+	TrimInfo   string
 }
 
+// copiedLabelDuration is how long the copied confirmation is shown, in seconds.
+const copiedLabelDuration = 1.5
+
+// Preview zoom limits, and how much each step of the zoom buttons changes it.
+const (
+	minDisplaySize  = 16
+	maxDisplaySize  = 128
+	displaySizeStep = 8
+)
+
+// sessionDisplaySize is the last zoom used, so reopening the viewer keeps it.
+var sessionDisplaySize int32
+
 type Config struct {
 	DisplaySize    int32
 	Padding        int32
@@ -66,13 +91,23 @@ func InitConfig() Config {
 
 func InitUI() *UIState {
 	ui := &UIState{
-		Margin:    1,
-		GridSizeX: 16,
-		GridSizeY: 16,
+		Margin:      1,
+		GridSizeX:   16,
+		GridSizeY:   16,
+		DisplaySize: sessionDisplaySize,
+	}
+	if ui.DisplaySize == 0 {
+		ui.DisplaySize = InitConfig().DisplaySize
 	}
 	return ui
 }
 
+// zoom changes the preview size by steps of displaySizeStep, within the zoom limits.
+func (s *UIState) zoom(steps int32) {
+	s.DisplaySize = min(max(s.DisplaySize+steps*displaySizeStep, minDisplaySize), maxDisplaySize)
+	sessionDisplaySize = s.DisplaySize
+}
+
 // reload attempts to load or reload the current sprite sheet with the specified
 // margin and grid size settings. It updates the internal state with any errors
 // or debug information.
@@ -113,8 +148,15 @@ func (s *UIState) reload() {
 		return
 	}
 
+	s.TrimInfo = ""
+	if s.Trim {
+		s.trimSprites()
+	}
 	s.updateSpriteNames()
 	s.DebugInfo = fmt.Sprintf("Loaded %d sprites", len(s.SpriteNames))
+	if s.TrimInfo != "" {
+		s.DebugInfo += ", " + s.TrimInfo
+	}
 	s.LoadError = ""
 }
 
@@ -129,6 +171,12 @@ func (s *UIState) HandleInput(showSettings *bool) {
 	if rl.IsKeyPressed(rl.KeyEscape) && *showSettings {
 		*showSettings = false
 	}
+	if rl.IsKeyPressed(rl.KeyEqual) || rl.IsKeyPressed(rl.KeyKpAdd) {
+		s.zoom(1)
+	}
+	if rl.IsKeyPressed(rl.KeyMinus) || rl.IsKeyPressed(rl.KeyKpSubtract) {
+		s.zoom(-1)
+	}
 	s.ScrollOffset -= rl.GetMouseWheelMove() * 30
 }
 
@@ -155,25 +203,23 @@ func (s *UIState) RenderSprites(cfg Config) {
 		return
 	}
 
-	spritesPerRow := (800 - cfg.StartX*2) / (cfg.DisplaySize + cfg.Padding)
-	totalRows := len(s.SpriteNames) / int(spritesPerRow)
-	if len(s.SpriteNames)%int(spritesPerRow) != 0 {
+	// Lay the grid out at the current zoom
+	if s.DisplaySize != 0 {
+		cfg.DisplaySize = s.DisplaySize
+	}
+	spritesPerRow := spritesPerRow(cfg)
+	totalRows := len(s.SpriteNames) / spritesPerRow
+	if len(s.SpriteNames)%spritesPerRow != 0 {
 		totalRows++
 	}
 
 	contentHeight := float32(cfg.StartY) + float32(totalRows*(int(cfg.DisplaySize)+int(cfg.Padding)+20))
 	s.HandleScrolling(contentHeight, cfg.ViewportHeight)
 
-	x, y := cfg.StartX, cfg.StartY
-	for _, name := range s.SpriteNames {
+	for i, name := range s.SpriteNames {
+		x, y := spriteCell(cfg, i, spritesPerRow)
 		yPos := float32(y) - s.ScrollOffset
-
 		if yPos+float32(cfg.DisplaySize) < 0 || yPos > float32(600) {
-			x += cfg.DisplaySize + cfg.Padding
-			if x > 700 {
-				x = cfg.StartX
-				y += cfg.DisplaySize + cfg.Padding + 20
-			}
 			continue
 		}
 
@@ -186,22 +232,29 @@ func (s *UIState) RenderSprites(cfg Config) {
 			Height: float32(rect.Height),
 		}
 
+		// Fit the sprite in its cell, so tall sprites don't run into the next row
+		scale := float32(cfg.DisplaySize) / float32(max(rect.Width, rect.Height))
 		dest := rl.Rectangle{
 			X:      float32(x),
 			Y:      yPos,
-			Width:  float32(cfg.DisplaySize),
-			Height: float32(cfg.DisplaySize) * float32(rect.Height) / float32(rect.Width),
+			Width:  float32(rect.Width) * scale,
+			Height: float32(rect.Height) * scale,
 		}
 		rl.DrawTexturePro(s.Sheet.Texture, source, dest, rl.Vector2{}, 0, rl.White)
 
-		rl.DrawRectangleLinesEx(dest, 1, rl.Gray)
-		rl.DrawText(name, int32(x), int32(yPos+dest.Height+2), 10, rl.DarkGray)
-
-		x += cfg.DisplaySize + cfg.Padding
-		if x > 700 {
-			x = cfg.StartX
-			y += int32(dest.Height) + cfg.Padding + 20
+		// Click a sprite to copy its name
+		mousePos := rl.GetMousePosition()
+		if mousePos.Y > float32(cfg.HeaderHeight) && rl.CheckCollisionPointRec(mousePos, dest) {
+			rl.DrawRectangleLinesEx(dest, 2, rl.Blue)
+			if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+				rl.SetClipboardText(name)
+				s.CopiedName = name
+				s.CopiedAt = rl.GetTime()
+			}
+		} else {
+			rl.DrawRectangleLinesEx(dest, 1, rl.Gray)
 		}
+		rl.DrawText(name, int32(x), int32(yPos+dest.Height+2), 10, rl.DarkGray)
 	}
 
 	if contentHeight > float32(cfg.ViewportHeight) {
@@ -220,6 +273,67 @@ func (s *UIState) RenderSprites(cfg Config) {
 				rl.Gray)
 		}
 	}
+
+	s.renderCopiedLabel()
+}
+
+// spritesPerRow is how many sprites fit across the window at the current zoom, at least one.
+func spritesPerRow(cfg Config) int {
+	return max(1, int((800-cfg.StartX*2)/(cfg.DisplaySize+cfg.Padding)))
+}
+
+// spriteCell returns the top left corner of the i-th sprite's cell, wrapping after every perRow sprites.
+// Each row leaves room under the sprites for their names.
+func spriteCell(cfg Config, i, perRow int) (x, y int32) {
+	col, row := int32(i%perRow), int32(i/perRow)
+	return cfg.StartX + col*(cfg.DisplaySize+cfg.Padding), cfg.StartY + row*(cfg.DisplaySize+cfg.Padding+20)
+}
+
+// renderTrimToggles draws the buttons that switch trimming and tight bounds on and off.
+// Returns true if either changed, so the sheet can be reloaded.
+func (s *UIState) renderTrimToggles(x, y float32) bool {
+	onOff := func(on bool) string {
+		if on {
+			return "On"
+		}
+		return "Off"
+	}
+	changed := false
+	if drawButton(rl.Rectangle{X: x, Y: y, Width: 100, Height: 20}, "Trim: "+onOff(s.Trim)) {
+		s.Trim = !s.Trim
+		changed = true
+	}
+	if drawButton(rl.Rectangle{X: x + 120, Y: y, Width: 100, Height: 20}, "Bounds: "+onOff(s.TrimBounds)) {
+		s.TrimBounds = !s.TrimBounds
+		changed = s.Trim
+	}
+	return changed
+}
+
+// renderZoomControls draws the zoom out and in buttons, with the current preview size between them.
+func (s *UIState) renderZoomControls(x, y float32) {
+	if drawButton(rl.Rectangle{X: x, Y: y, Width: 25, Height: 25}, "-") {
+		s.zoom(-1)
+	}
+	label := fmt.Sprintf("%dpx", s.DisplaySize)
+	labelWidth := float32(rl.MeasureText(label, 10))
+	rl.DrawText(label, int32(x+25+(40-labelWidth)/2), int32(y+8), 10, rl.DarkGray)
+	if drawButton(rl.Rectangle{X: x + 65, Y: y, Width: 25, Height: 25}, "+") {
+		s.zoom(1)
+	}
+}
+
+// renderCopiedLabel briefly confirms which sprite name was copied, at the bottom of the window.
+func (s *UIState) renderCopiedLabel() {
+	if s.CopiedName == "" || rl.GetTime()-s.CopiedAt > copiedLabelDuration {
+		return
+	}
+	text := "Copied " + s.CopiedName
+	textWidth := rl.MeasureText(text, 16)
+	x := (int32(rl.GetScreenWidth()) - textWidth) / 2
+	y := int32(rl.GetScreenHeight()) - 40
+	rl.DrawRectangle(x-10, y-6, textWidth+20, 28, rl.Fade(rl.Black, 0.75))
+	rl.DrawText(text, x, y, 16, rl.White)
 }
 
 // renderUI draws the application interface including header, buttons, and settings panel.
@@ -228,6 +342,8 @@ func (s *UIState) RenderUI(cfg Config, showSettings *bool) {
 	rl.DrawLine(0, cfg.HeaderHeight, 800, cfg.HeaderHeight, rl.LightGray)
 	rl.DrawText("Sprite Sheet Viewer", 10, 10, 20, rl.Black)
 
+	s.renderZoomControls(240, 8)
+
 	if drawButton(rl.Rectangle{X: 600, Y: 8, Width: 80, Height: 25}, "Settings") {
 		*showSettings = !*showSettings
 	}
@@ -248,7 +364,7 @@ func (s *UIState) RenderUI(cfg Config, showSettings *bool) {
 	}
 
 	if *showSettings {
-		panelHeight := int32(100)
+		panelHeight := int32(160)
 		panelWidth := int32(300)
 
 		settingsRect := rl.Rectangle{X: 400 - float32(panelWidth/2), Y: float32(cfg.HeaderHeight + 5)}
@@ -320,7 +436,12 @@ func (s *UIState) RenderUI(cfg Config, showSettings *bool) {
 		helpX := settingsRect.X + float32(panelWidth/2) - float32(helpWidth)/2
 		rl.DrawText(helpText, int32(helpX), int32(marginInput.Y+30), 10, rl.DarkGray)
 
-		if oldMargin != s.Margin || oldGridSizeX != s.GridSizeX || oldGridSizeY != s.GridSizeY {
+		trimChanged := s.renderTrimToggles(startX, marginInput.Y+50)
+		if drawButton(rl.Rectangle{X: startX, Y: marginInput.Y + 80, Width: 220, Height: 20}, "Export Mapping") {
+			s.exportMapping()
+		}
+
+		if oldMargin != s.Margin || oldGridSizeX != s.GridSizeX || oldGridSizeY != s.GridSizeY || trimChanged {
 			s.reload()
 		}
 	}
@@ -332,6 +453,8 @@ func (s *UIState) RenderViewerUI(cfg Config, showSettings *bool) error {
 	rl.DrawLine(0, cfg.HeaderHeight, 800, cfg.HeaderHeight, rl.LightGray)
 	rl.DrawText("Sprite Sheet Viewer", 10, 10, 20, rl.Black)
 
+	s.renderZoomControls(240, 8)
+
 	if drawButton(rl.Rectangle{X: 520, Y: 8, Width: 80, Height: 25}, "Settings") {
 		*showSettings = !*showSettings
 	}
@@ -353,7 +476,7 @@ func (s *UIState) RenderViewerUI(cfg Config, showSettings *bool) error {
 	}
 
 	if *showSettings {
-		panelHeight := int32(100)
+		panelHeight := int32(160)
 		panelWidth := int32(300)
 
 		settingsRect := rl.Rectangle{X: 400 - float32(panelWidth/2), Y: float32(cfg.HeaderHeight + 5)}
@@ -425,7 +548,12 @@ func (s *UIState) RenderViewerUI(cfg Config, showSettings *bool) error {
 		helpX := settingsRect.X + float32(panelWidth/2) - float32(helpWidth)/2
 		rl.DrawText(helpText, int32(helpX), int32(marginInput.Y+30), 10, rl.DarkGray)
 
-		if oldMargin != s.Margin || oldGridSizeX != s.GridSizeX || oldGridSizeY != s.GridSizeY {
+		trimChanged := s.renderTrimToggles(startX, marginInput.Y+50)
+		if drawButton(rl.Rectangle{X: startX, Y: marginInput.Y + 80, Width: 220, Height: 20}, "Export Mapping") {
+			s.exportMapping()
+		}
+
+		if oldMargin != s.Margin || oldGridSizeX != s.GridSizeX || oldGridSizeY != s.GridSizeY || trimChanged {
 			s.reload()
 		}
 	}
@@ -448,9 +576,9 @@ func openFileDialog() string {
 
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file with prompt "Choose a sprite sheet:" of type {"png","jpg","jpeg"})`)
+		cmd = exec.Command("osascript", "-e", `POSIX path of (choose file with prompt "Choose a sprite sheet:" of type {"png","jpg","jpeg","bmp","tga","qoi","gif"})`)
 	case "linux":
-		cmd = exec.Command("zenity", "--file-selection", "--file-filter=Images (*.png *.jpg *.jpeg)")
+		cmd = exec.Command("zenity", "--file-selection", "--file-filter=Images (*.png *.jpg *.jpeg *.bmp *.tga *.qoi *.gif)")
 	default:
 		return ""
 	}
@@ -0,0 +1,24 @@
+package viewer
+
+import "testing"
+
+// TestSpriteCellWraps checks at every zoom each row wraps after spritesPerRow sprites, inside the 800px window.
+func TestSpriteCellWraps(t *testing.T) {
+	cfg := InitConfig()
+	for size := int32(minDisplaySize); size <= maxDisplaySize; size += displaySizeStep {
+		cfg.DisplaySize = size
+		perRow := spritesPerRow(cfg)
+		for i := 0; i < perRow*2; i++ {
+			x, y := spriteCell(cfg, i, perRow)
+			if x+size > 800 {
+				t.Errorf("Expected sprite %d at %dpx to fit in the window, got right edge %d", i, size, x+size)
+			}
+			if expectedRow := int32(i / perRow); y != cfg.StartY+expectedRow*(size+cfg.Padding+20) {
+				t.Errorf("Expected sprite %d at %dpx on row %d, got y %d", i, size, expectedRow, y)
+			}
+		}
+		if x, _ := spriteCell(cfg, perRow, perRow); x != cfg.StartX {
+			t.Errorf("Expected the sprite after a full row at %dpx to start the next row, got x %d", size, x)
+		}
+	}
+}
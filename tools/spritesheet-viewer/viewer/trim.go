@@ -0,0 +1,59 @@
+package viewer
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/ztkent/beam/resources"
+)
+
+// trimSprites drops grid cells without any opaque pixels from the sheet.
+// With TrimBounds set, the remaining sprites are also shrunk to their opaque pixels.
+func (s *UIState) trimSprites() {
+	img := rl.LoadImageFromTexture(s.Sheet.Texture)
+	defer rl.UnloadImage(img)
+	if img.Width <= 0 || img.Height <= 0 {
+		return
+	}
+	// Read the pixels back once, rather than a cgo call per pixel
+	pixels := rl.LoadImageColors(img)
+	defer rl.UnloadImageColors(pixels)
+	alphaAt := func(x, y int32) uint8 {
+		if x < 0 || y < 0 || x >= img.Width || y >= img.Height {
+			return 0
+		}
+		return pixels[y*img.Width+x].A
+	}
+
+	skipped := 0
+	for name, cell := range s.Sheet.Sprites {
+		bounds, ok := opaqueBounds(alphaAt, cell)
+		if !ok {
+			delete(s.Sheet.Sprites, name)
+			skipped++
+		} else if s.TrimBounds {
+			s.Sheet.Sprites[name] = bounds
+		}
+	}
+	s.TrimInfo = fmt.Sprintf("Skipped %d empty cells", skipped)
+}
+
+// opaqueBounds returns the smallest rectangle within cell that holds every pixel that isn't fully transparent.
+// Returns false if the whole cell is transparent.
+func opaqueBounds(alphaAt func(x, y int32) uint8, cell resources.Rectangle) (resources.Rectangle, bool) {
+	minX, minY := cell.X+cell.Width, cell.Y+cell.Height
+	maxX, maxY := cell.X-1, cell.Y-1
+	for y := cell.Y; y < cell.Y+cell.Height; y++ {
+		for x := cell.X; x < cell.X+cell.Width; x++ {
+			if alphaAt(x, y) == 0 {
+				continue
+			}
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	if maxX < minX {
+		return resources.Rectangle{}, false
+	}
+	return resources.Rectangle{X: minX, Y: minY, Width: maxX - minX + 1, Height: maxY - minY + 1}, true
+}
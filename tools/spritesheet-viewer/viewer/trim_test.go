@@ -0,0 +1,42 @@
+package viewer
+
+import (
+	"testing"
+
+	"github.com/ztkent/beam/resources"
+)
+
+// newTestImage returns the alpha of a size x size image with an opaque square of side n in the middle.
+func newTestImage(size, n int32) func(x, y int32) uint8 {
+	start := (size - n) / 2
+	return func(x, y int32) uint8 {
+		if x >= start && x < start+n && y >= start && y < start+n {
+			return 255
+		}
+		return 0
+	}
+}
+
+func TestOpaqueBounds(t *testing.T) {
+	alphaAt := newTestImage(16, 4)
+
+	bounds, ok := opaqueBounds(alphaAt, resources.Rectangle{X: 0, Y: 0, Width: 16, Height: 16})
+	if !ok {
+		t.Fatal("expected the cell to have opaque pixels")
+	}
+	want := resources.Rectangle{X: 6, Y: 6, Width: 4, Height: 4}
+	if bounds != want {
+		t.Errorf("expected bounds %+v, got %+v", want, bounds)
+	}
+
+	// A cell over part of the square only covers the overlap
+	bounds, ok = opaqueBounds(alphaAt, resources.Rectangle{X: 8, Y: 0, Width: 8, Height: 8})
+	want = resources.Rectangle{X: 8, Y: 6, Width: 2, Height: 2}
+	if !ok || bounds != want {
+		t.Errorf("expected bounds %+v, got %+v (ok %v)", want, bounds, ok)
+	}
+
+	if _, ok := opaqueBounds(alphaAt, resources.Rectangle{X: 0, Y: 0, Width: 4, Height: 4}); ok {
+		t.Error("expected a fully transparent cell to have no bounds")
+	}
+}